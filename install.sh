@@ -43,6 +43,7 @@ OCSERV_PRE_LOGIN_BANNER="Welcome"                             # Default Ocserv p
 LANGUAGES="en:English,it:Italiano,zh-cn:中文(简体),zh-tw:中文(繁體),ru:Русский,fa:فارسی,ar:العربية"  # Supported languages
 SECRET_KEY=$(openssl rand -hex 32)                            # Secret key for app encryption (32 hex chars)
 JWT_SECRET=$(openssl rand -hex 32)                            # JWT signing secret (32 hex chars)
+WEBHOOK_SECRET=$(openssl rand -hex 32)                        # ocserv webhook request signing secret
 SSL_C=US                                                      # SSL Country iso2
 SSL_ST=CA                                                     # SSL State name
 SSL_L=SanFrancisco                                            # SSl City name
@@ -438,6 +439,7 @@ set_environment() {
 HOST="${HOST}"
 SECRET_KEY="${SECRET_KEY}"
 JWT_SECRET="${JWT_SECRET}"
+WEBHOOK_SECRET="${WEBHOOK_SECRET}"
 LANGUAGES="${LANGUAGES}"
 ALLOW_ORIGINS="https://${HOST}:3443"
 SSL_CN="${SSL_CN}"
@@ -792,6 +794,7 @@ main() {
         # shellcheck disable=SC1090
         source "$ENV_FILE"
         set +o allexport
+        ensure_webhook_secret "$ENV_FILE"
         print_message success "✅ Environment loaded"
     else
         print_message info "⚡ No .env found. Running interactive setup..."
@@ -39,7 +39,12 @@ WEBHOOK_PID=$!
 # Start ocserv as root
 # -----------------------------
 echo "[INFO] Starting ocserv..."
-/usr/sbin/ocserv --foreground --debug=999 --config=/etc/ocserv/ocserv.conf &
+if [ -n "${AGENT_LOG_FILE}" ]; then
+    # keep the container log and let the webhook agent stream the file to a remote dashboard
+    /usr/sbin/ocserv --foreground --debug=999 --config=/etc/ocserv/ocserv.conf > >(tee -a "${AGENT_LOG_FILE}") 2>&1 &
+else
+    /usr/sbin/ocserv --foreground --debug=999 --config=/etc/ocserv/ocserv.conf &
+fi
 OCSERV_PID=$!
 
 # -----------------------------
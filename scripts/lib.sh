@@ -123,6 +123,33 @@ get_latest_release_tag() {
     echo "$latest_tag"
 }
 
+# ==============================================================
+# Function: ensure_webhook_secret
+# Description:
+#   Generates WEBHOOK_SECRET in the env file when it is missing or
+#   empty. Installations older than the signed webhook have none, and
+#   the webhook refuses every request without it.
+# Parameters:
+#   $1 - env file path
+# ==============================================================
+ensure_webhook_secret() {
+    local env_file="$1"
+
+    [[ -f "${env_file}" ]] || return 0
+    if grep -Eq '^WEBHOOK_SECRET="?[^"[:space:]]+' "${env_file}"; then
+        return 0
+    fi
+
+    WEBHOOK_SECRET=$(openssl rand -hex 32)
+    if grep -q '^WEBHOOK_SECRET=' "${env_file}"; then
+        sed -i "s/^WEBHOOK_SECRET=.*/WEBHOOK_SECRET=\"${WEBHOOK_SECRET}\"/" "${env_file}"
+    else
+        echo "WEBHOOK_SECRET=\"${WEBHOOK_SECRET}\"" >> "${env_file}"
+    fi
+    export WEBHOOK_SECRET
+    print_message info "Generated WEBHOOK_SECRET in ${env_file}"
+}
+
 # ==============================================================
 # Notes:
 #   - All deployment scripts should source this file at the top:
@@ -45,6 +45,9 @@ else
     warn ".env not found at ${ROOT_DIR}/.env — proceeding with defaults"
 fi
 
+# the webhook needs a signing secret since it verifies every request
+ensure_webhook_secret "${ROOT_DIR}/.env"
+
 # Detect or get deployment mode
 detect_deployment_mode() {
     # Check if user specified mode
@@ -312,7 +312,7 @@ const docTemplate = `{
         },
         "/customers/setup/cisco": {
             "post": {
-                "description": "Create Cisco Secure Client certificate import and connection creation URIs using ocserv username/password",
+                "description": "Create Cisco Secure Client certificate import and connection creation URIs using ocserv username/password. The certificate import URI and password are empty for password-only accounts.",
                 "consumes": [
                     "application/json"
                 ],
@@ -443,19 +443,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/home": {
+        "/drift": {
             "get": {
-                "description": "Content of home",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Compare database users and groups with ocpasswd and the ocserv config files",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Home"
+                    "System(Drift)"
                 ],
-                "summary": "Content of home",
+                "summary": "Drift report",
                 "parameters": [
                     {
                         "type": "string",
@@ -469,7 +466,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/home.GetHomeResponse"
+                            "$ref": "#/definitions/drift.Report"
                         }
                     },
                     "400": {
@@ -483,13 +480,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/home/container-stats": {
-            "get": {
-                "description": "Content of docker system usage stats (cpu, ram, swap)",
+        "/drift/fix": {
+            "post": {
+                "description": "Fix the drift of the given kinds using source as the source of truth. Without kinds only the fixes that delete nothing run: deleting users, groups or config files (missing_in_database, stale_config and orphan_group from database, missing_in_ocpasswd and missing_group_file from ocserv) needs the kind listed",
                 "consumes": [
                     "application/json"
                 ],
@@ -497,9 +500,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Home"
+                    "System(Drift)"
                 ],
-                "summary": "Content of docker system usage stats",
+                "summary": "Fix drift",
                 "parameters": [
                     {
                         "type": "string",
@@ -507,16 +510,22 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "source of truth and drift kinds to fix",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/drift.FixData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/home.DockerService"
-                            }
+                            "$ref": "#/definitions/drift.FixResponse"
                         }
                     },
                     "400": {
@@ -530,13 +539,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/home/ocserv-stats": {
+        "/home": {
             "get": {
-                "description": "Content of ocserv server stats",
+                "description": "Content of home",
                 "consumes": [
                     "application/json"
                 ],
@@ -546,7 +561,7 @@ const docTemplate = `{
                 "tags": [
                     "Home"
                 ],
-                "summary": "Content of ocserv server stats",
+                "summary": "Content of home",
                 "parameters": [
                     {
                         "type": "string",
@@ -560,7 +575,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/home.OcservStatusResponse"
+                            "$ref": "#/definitions/home.GetHomeResponse"
                         }
                     },
                     "400": {
@@ -578,9 +593,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/home/system-stats": {
+        "/home/container-stats": {
             "get": {
-                "description": "Content of os system usage stats (cpu, ram, swap)",
+                "description": "Content of docker system usage stats (cpu, ram, swap)",
                 "consumes": [
                     "application/json"
                 ],
@@ -590,7 +605,7 @@ const docTemplate = `{
                 "tags": [
                     "Home"
                 ],
-                "summary": "Content of os system usage stats",
+                "summary": "Content of docker system usage stats",
                 "parameters": [
                     {
                         "type": "string",
@@ -604,7 +619,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/home.ServerStatusResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/home.DockerService"
+                            }
                         }
                     },
                     "400": {
@@ -622,9 +640,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/occtl/commands": {
+        "/home/ocserv-stats": {
             "get": {
-                "description": "Occtl Commands",
+                "description": "Content of ocserv server stats",
                 "consumes": [
                     "application/json"
                 ],
@@ -632,9 +650,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OCCTL"
+                    "Home"
                 ],
-                "summary": "Occtl Commands",
+                "summary": "Content of ocserv server stats",
                 "parameters": [
                     {
                         "type": "string",
@@ -642,26 +660,13 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Command Action ID (1 to 15)",
-                        "name": "action",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Optional parameter depending on command",
-                        "name": "value",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/home.OcservStatusResponse"
                         }
                     },
                     "400": {
@@ -679,9 +684,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/occtl/server_info": {
+        "/home/system-stats": {
             "get": {
-                "description": "Server information",
+                "description": "Content of os system usage stats (cpu, ram, swap)",
                 "consumes": [
                     "application/json"
                 ],
@@ -689,14 +694,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "OCCTL"
+                    "Home"
+                ],
+                "summary": "Content of os system usage stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Server information",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservInfo"
+                            "$ref": "#/definitions/home.ServerStatusResponse"
                         }
                     },
                     "400": {
@@ -704,55 +718,27 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/request.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/groups": {
+        "/ip_bans": {
             "get": {
-                "description": "List of Ocserv groups",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the IP and CIDR bans currently in force",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "System(IP Bans)"
                 ],
-                "summary": "List of Ocserv groups",
+                "summary": "Active IP bans",
                 "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Page number, starting from 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to order by",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "ASC",
-                            "DESC"
-                        ],
-                        "type": "string",
-                        "description": "Sort order, either ASC or DESC",
-                        "name": "sort",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
@@ -765,7 +751,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ocserv_group.OcservGroupsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OcservIPBan"
+                            }
                         }
                     },
                     "400": {
@@ -779,11 +768,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Ocserv Group creation",
+                "description": "Ban an IP or CIDR until unbanned or until expires_at",
                 "consumes": [
                     "application/json"
                 ],
@@ -791,9 +786,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "System(IP Bans)"
                 ],
-                "summary": "Ocserv Group creation",
+                "summary": "Ban IP",
                 "parameters": [
                     {
                         "type": "string",
@@ -803,12 +798,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "ocserv group create data",
+                        "description": "ban data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ocserv_group.CreateOcservGroupData"
+                            "$ref": "#/definitions/ip_ban.BanData"
                         }
                     }
                 ],
@@ -816,7 +811,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservGroup"
+                            "$ref": "#/definitions/models.OcservIPBan"
                         }
                     },
                     "400": {
@@ -830,23 +825,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/groups/defaults": {
+        "/ip_bans/history": {
             "get": {
-                "description": "Ocserv Defaults Group config",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List all bans, including ended and expired ones",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "System(IP Bans)"
                 ],
-                "summary": "Ocserv Defaults Group config",
+                "summary": "IP ban history",
                 "parameters": [
                     {
                         "type": "string",
@@ -854,14 +852,50 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by ip or cidr",
+                        "name": "ip",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ip_ban.HistoryResponse"
                         }
                     },
                     "400": {
@@ -875,11 +909,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update Ocserv Defaults Group",
+            }
+        },
+        "/ip_bans/{id}/unban": {
+            "post": {
+                "description": "End an active ban. The ban is kept in the history",
                 "consumes": [
                     "application/json"
                 ],
@@ -887,9 +929,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "System(IP Bans)"
                 ],
-                "summary": "Update Ocserv Defaults Group",
+                "summary": "Unban IP",
                 "parameters": [
                     {
                         "type": "string",
@@ -899,18 +941,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "ocserv group default data",
+                        "type": "integer",
+                        "description": "Ban ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "unban data",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ocserv_group.UpdateOcservGroupData"
+                            "$ref": "#/definitions/ip_ban.UnbanData"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservIPBan"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -923,13 +974,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/groups/lookup": {
+        "/occtl/commands": {
             "get": {
-                "description": "List of Ocserv group names",
+                "description": "Occtl Commands",
                 "consumes": [
                     "application/json"
                 ],
@@ -937,9 +994,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "OCCTL"
                 ],
-                "summary": "List of Ocserv group names",
+                "summary": "Occtl Commands",
                 "parameters": [
                     {
                         "type": "string",
@@ -947,16 +1004,38 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Command Action ID (1 to 15)",
+                        "name": "action",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Optional parameter depending on command",
+                        "name": "value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Virtual host the online users (1), sessions (5, 6) and iroutes (12) are limited to",
+                        "name": "vhost",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node to run the command on, online users (1) of all nodes by default",
+                        "name": "node",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -974,9 +1053,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/ocserv/groups/sync": {
-            "post": {
-                "description": "Ocserv Groups from file",
+        "/occtl/events": {
+            "get": {
+                "description": "Server-Sent Events stream of user connect and disconnect events (event name is the event type, data is the JSON encoded event)",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "OCCTL"
+                ],
+                "summary": "Live Occtl Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token, for clients that cannot set the Authorization header (EventSource)",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream the events of a virtual host",
+                        "name": "vhost",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream the events of a node, local for the ocserv of the dashboard",
+                        "name": "node",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcctlEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/occtl/server_info": {
+            "get": {
+                "description": "Server information, including the occtl capabilities of the installed ocserv release so clients can hide unsupported actions",
                 "consumes": [
                     "application/json"
                 ],
@@ -984,9 +1115,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(UnsyncedGroup)"
+                    "OCCTL"
                 ],
-                "summary": "Ocserv Groups from file",
+                "summary": "Server information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/ca": {
+            "get": {
+                "description": "List the CAs ocserv trusts and the users whose certificate is not issued by the active CA yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(CA)"
+                ],
+                "summary": "Certificate authority status",
                 "parameters": [
                     {
                         "type": "string",
@@ -994,25 +1151,13 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "list of groups with config to sync in db",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/ocserv_group.SyncGroupRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/user.CAStatus"
                         }
                     },
                     "400": {
@@ -1026,23 +1171,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/groups/unsynced": {
+        "/ocserv/ca/ca-cert.pem": {
             "get": {
-                "description": "list of Unsynced Groups",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the PEM bundle of the CAs ocserv trusts, active CA first",
                 "produces": [
-                    "application/json"
+                    "application/x-pem-file"
                 ],
                 "tags": [
-                    "Ocserv(UnsyncedGroup)"
+                    "Ocserv(CA)"
                 ],
-                "summary": "list of Unsynced Groups",
+                "summary": "Download CA certificate",
                 "parameters": [
                     {
                         "type": "string",
@@ -1054,12 +1202,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "ca-cert.pem",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/group.UnsyncedGroup"
-                            }
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -1073,44 +1218,72 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/groups/{id}": {
+        "/ocserv/ca/certificates": {
             "get": {
-                "description": "Ocserv group detail",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the active, suspended and revoked user certificates with their serial, subject, issuer and validity",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "Ocserv(CA)"
                 ],
-                "summary": "Ocserv group detail",
+                "summary": "Certificate inventory",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Ocserv Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "suspended",
+                            "revoked"
+                        ],
+                        "type": "string",
+                        "description": "filter by state",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by username or serial",
+                        "name": "q",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservGroup"
+                            "$ref": "#/definitions/certificate_authority.CertificatesResponse"
                         }
                     },
                     "400": {
@@ -1124,21 +1297,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Ocserv Group delete",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/ocserv/ca/crl.pem": {
+            "get": {
+                "description": "Download the current CRLs in PEM, one per trusted CA",
                 "produces": [
-                    "application/json"
+                    "application/x-pem-file"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "Ocserv(CA)"
                 ],
-                "summary": "Ocserv Group delete",
+                "summary": "Download certificate revocation list",
                 "parameters": [
                     {
                         "type": "string",
@@ -1146,18 +1324,14 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Ocserv Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "crl.pem",
+                        "schema": {
+                            "type": "file"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1170,11 +1344,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
-            },
-            "patch": {
-                "description": "Ocserv Group update",
+            }
+        },
+        "/ocserv/ca/reissue": {
+            "post": {
+                "description": "Reissue the certificates of the next batch of users still on a retiring CA. Call again until remaining is 0. Users whose reissue fails are parked and skipped until retry_failed is set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1182,9 +1364,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Groups)"
+                    "Ocserv(CA)"
                 ],
-                "summary": "Ocserv Group update",
+                "summary": "Reissue certificates under the active CA",
                 "parameters": [
                     {
                         "type": "string",
@@ -1194,27 +1376,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Ocserv Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "ocserv group create data",
+                        "description": "batch size",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ocserv_group.UpdateOcservGroupData"
+                            "$ref": "#/definitions/certificate_authority.ReissueData"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservGroup"
+                            "$ref": "#/definitions/certificate_authority.ReissueResponse"
                         }
                     },
                     "400": {
@@ -1228,13 +1402,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/users": {
-            "get": {
-                "description": "List of Ocserv Users",
+        "/ocserv/ca/retire": {
+            "post": {
+                "description": "Stop trusting the retiring CAs and end the rotation. Refused while users still have a certificate from a retiring CA, unless force is set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1242,79 +1422,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(CA)"
                 ],
-                "summary": "List of Ocserv Users",
+                "summary": "Retire certificate authority",
                 "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Page number, starting from 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to order by",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "ASC",
-                            "DESC"
-                        ],
-                        "type": "string",
-                        "description": "Sort order, either ASC or DESC",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "minLength": 2,
-                        "type": "string",
-                        "description": "ocserv username q search",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "online",
-                            "active",
-                            "deactivated",
-                            "locked"
-                        ],
-                        "type": "string",
-                        "description": "filter ocserv user by statues",
-                        "name": "filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter ocserv user by group name",
-                        "name": "group",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "retire options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/certificate_authority.RetireData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ocserv_user.OcservUsersResponse"
+                            "$ref": "#/definitions/user.CAStatus"
                         }
                     },
                     "400": {
@@ -1328,21 +1460,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/ocserv/ca/rotate": {
             "post": {
-                "description": "Ocserv User creation",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Create a new active CA. The current CA stays trusted as retiring until it is retired, so existing certificates keep working while they are reissued.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(CA)"
                 ],
-                "summary": "Ocserv User creation",
+                "summary": "Rotate certificate authority",
                 "parameters": [
                     {
                         "type": "string",
@@ -1350,22 +1487,13 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "ocserv user create data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/ocserv_user.CreateOcservUserData"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservUser"
+                            "$ref": "#/definitions/user.CAStatus"
                         }
                     },
                     "400": {
@@ -1379,24 +1507,51 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/users/ocpasswd": {
+        "/ocserv/config/revisions": {
             "get": {
-                "description": "Ocserv Users from ocpasswd file",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the versions of the configs of groups, the defaults group and users",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Ocpasswd)"
+                    "Ocserv(Config Revisions)"
                 ],
-                "summary": "Ocserv Users from ocpasswd file",
+                "summary": "Group and user config history",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "group",
+                            "defaults",
+                            "user"
+                        ],
+                        "type": "string",
+                        "description": "Config kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name or username",
+                        "name": "target",
+                        "in": "query"
+                    },
                     {
                         "minimum": 1,
                         "type": "integer",
@@ -1427,20 +1582,13 @@ const docTemplate = `{
                         "description": "Sort order, either ASC or DESC",
                         "name": "sort",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Bearer TOKEN",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ocserv_user.OcservUsersSyncResponse"
+                            "$ref": "#/definitions/config_revision.RevisionsResponse"
                         }
                     },
                     "400": {
@@ -1454,23 +1602,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/users/ocpasswd/sync": {
-            "post": {
-                "description": "Ocserv Users from ocpasswd file to db",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/config/revisions/diff": {
+            "get": {
+                "description": "Changed directives between two revisions of the same group, defaults group or user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Ocpasswd)"
+                    "Ocserv(Config Revisions)"
                 ],
-                "summary": "Ocserv Users from ocpasswd file to db",
+                "summary": "Diff two config revisions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1480,23 +1631,25 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "list of users with config to sync in db",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/ocserv_user.SyncOcpasswdRequest"
-                        }
+                        "type": "integer",
+                        "description": "Revision ID to compare from",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision ID to compare to",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/repository.ConfigRevisionDiff"
                         }
                     },
                     "400": {
@@ -1510,23 +1663,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/users/{id}/disconnect_by_id": {
-            "post": {
-                "description": "Disconnect Ocserv User Session BY ID",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/config/revisions/{id}": {
+            "get": {
+                "description": "Get a version of the config of a group, the defaults group or a user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Config Revisions)"
                 ],
-                "summary": "Disconnect Ocserv User Session BY ID",
+                "summary": "Group or user config revision",
                 "parameters": [
                     {
                         "type": "string",
@@ -1536,8 +1692,8 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User Session ID",
+                        "type": "integer",
+                        "description": "Revision ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1545,7 +1701,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservConfigRevision"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1558,23 +1717,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/users/{id}/terminate_by_id": {
+        "/ocserv/config/revisions/{id}/restore": {
             "post": {
-                "description": "Terminate Ocserv User Session BY ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Rewrite the config file of the group, defaults group or user with the revision config and reload ocserv. The previous file is put back when ocserv refuses the reload. The restore is recorded as a new revision.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Config Revisions)"
                 ],
-                "summary": "Terminate Ocserv User Session BY ID",
+                "summary": "Restore a config revision",
                 "parameters": [
                     {
                         "type": "string",
@@ -1584,8 +1746,8 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User Session ID",
+                        "type": "integer",
+                        "description": "Revision ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1593,7 +1755,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservConfigRevision"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1606,13 +1771,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/ocserv/users/{uid}": {
+        "/ocserv/groups": {
             "get": {
-                "description": "Ocserv user detail",
+                "description": "List of Ocserv groups",
                 "consumes": [
                     "application/json"
                 ],
@@ -1620,16 +1791,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Ocserv user detail",
+                "summary": "List of Ocserv groups",
                 "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter groups by virtual host",
+                        "name": "vhost",
+                        "in": "query"
                     },
                     {
                         "type": "string",
@@ -1643,7 +1844,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservUser"
+                            "$ref": "#/definitions/ocserv_group.OcservGroupsResponse"
                         }
                     },
                     "400": {
@@ -1660,8 +1861,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Ocserv User delete",
+            "post": {
+                "description": "Ocserv Group creation",
                 "consumes": [
                     "application/json"
                 ],
@@ -1669,9 +1870,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Ocserv User delete",
+                "summary": "Ocserv Group creation",
                 "parameters": [
                     {
                         "type": "string",
@@ -1681,16 +1882,21 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
+                        "description": "ocserv group create data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_group.CreateOcservGroupData"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservGroup"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1705,9 +1911,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Ocserv User update",
+            }
+        },
+        "/ocserv/groups/defaults": {
+            "get": {
+                "description": "Ocserv Defaults Group config",
                 "consumes": [
                     "application/json"
                 ],
@@ -1715,9 +1923,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Ocserv User update",
+                "summary": "Ocserv Defaults Group config",
                 "parameters": [
                     {
                         "type": "string",
@@ -1725,29 +1933,14 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "ocserv user update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/ocserv_user.UpdateOcservUserData"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OcservUser"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1763,11 +1956,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/ocserv/users/{uid}/activate": {
-            "post": {
-                "description": "Restore and activate expired Ocserv User accounts",
+            },
+            "patch": {
+                "description": "Update Ocserv Defaults Group",
                 "consumes": [
                     "application/json"
                 ],
@@ -1775,9 +1966,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Restore and activate expired Ocserv User accounts",
+                "summary": "Update Ocserv Defaults Group",
                 "parameters": [
                     {
                         "type": "string",
@@ -1787,19 +1978,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "list of ocserv users and expire time",
+                        "description": "ocserv group default data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ocserv_user.ActivateUserData"
+                            "$ref": "#/definitions/ocserv_group.UpdateOcservGroupData"
                         }
                     }
                 ],
@@ -1822,37 +2006,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/ocserv/users/{uid}/certificate": {
+        "/ocserv/groups/lookup": {
             "get": {
-                "description": "Download the user's .p12 certificate bundle",
+                "description": "List of Ocserv group names",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "application/x-pkcs12"
+                    "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Download ocserv user certificate",
+                "summary": "List of Ocserv group names",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer TOKEN",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "filter groups by virtual host",
+                        "name": "vhost",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user.p12",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1868,9 +2057,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/ocserv/groups/sync": {
             "post": {
-                "description": "Create certificate for an existing ocserv user using the currently stored password",
+                "description": "Ocserv Groups from file",
                 "consumes": [
                     "application/json"
                 ],
@@ -1878,9 +2069,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(UnsyncedGroup)"
                 ],
-                "summary": "Create certificate for ocserv user",
+                "summary": "Ocserv Groups from file",
                 "parameters": [
                     {
                         "type": "string",
@@ -1890,16 +2081,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
+                        "description": "list of groups with config to sync in db",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_group.SyncGroupRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1916,9 +2115,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/ocserv/users/{uid}/lock": {
-            "post": {
-                "description": "Ocserv User locking",
+        "/ocserv/groups/unsynced": {
+            "get": {
+                "description": "list of Unsynced Groups",
                 "consumes": [
                     "application/json"
                 ],
@@ -1926,9 +2125,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(UnsyncedGroup)"
                 ],
-                "summary": "Ocserv User locking",
+                "summary": "list of Unsynced Groups",
                 "parameters": [
                     {
                         "type": "string",
@@ -1936,18 +2135,17 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/group.UnsyncedGroup"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1964,9 +2162,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/ocserv/users/{uid}/session_logs": {
+        "/ocserv/groups/{id}": {
             "get": {
-                "description": "Ocserv User session logs",
+                "description": "Ocserv group detail",
                 "consumes": [
                     "application/json"
                 ],
@@ -1974,73 +2172,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Ocserv User session logs",
+                "summary": "Ocserv group detail",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bearer TOKEN",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Page number, starting from 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
                         "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to order by",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "ASC",
-                            "DESC"
-                        ],
-                        "type": "string",
-                        "description": "Sort order, either ASC or DESC",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
+                        "description": "Ocserv Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "date_start",
-                        "name": "date_start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_end",
-                        "name": "date_end",
-                        "in": "query"
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ocserv_user.SessionLogsResponse"
+                            "$ref": "#/definitions/models.OcservGroup"
                         }
                     },
                     "400": {
@@ -2056,11 +2211,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/ocserv/users/{uid}/statistics": {
-            "get": {
-                "description": "Ocserv User Statistics",
+            },
+            "delete": {
+                "description": "Ocserv Group delete",
                 "consumes": [
                     "application/json"
                 ],
@@ -2068,9 +2221,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Ocserv User Statistics",
+                "summary": "Ocserv Group delete",
                 "parameters": [
                     {
                         "type": "string",
@@ -2080,31 +2233,16 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
+                        "type": "integer",
+                        "description": "Ocserv Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_start",
-                        "name": "date_start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_end",
-                        "name": "date_end",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/ocserv_user.StatisticsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2119,11 +2257,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/ocserv/users/{uid}/unlock": {
-            "post": {
-                "description": "Ocserv User unlocking",
+            },
+            "patch": {
+                "description": "Ocserv Group update",
                 "consumes": [
                     "application/json"
                 ],
@@ -2131,9 +2267,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Ocserv User unlocking",
+                "summary": "Ocserv Group update",
                 "parameters": [
                     {
                         "type": "string",
@@ -2143,16 +2279,28 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Ocserv User UID",
-                        "name": "uid",
+                        "type": "integer",
+                        "description": "Ocserv Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "ocserv group create data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_group.UpdateOcservGroupData"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservGroup"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2169,9 +2317,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/ocserv/users/{username}/disconnect": {
-            "post": {
-                "description": "Disconnect Ocserv User (All Sessions)",
+        "/ocserv/groups/{id}/effective": {
+            "get": {
+                "description": "Config written to the group file, the group templates merged in order with the group config, with the source of each value",
                 "consumes": [
                     "application/json"
                 ],
@@ -2179,28 +2327,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(Groups)"
                 ],
-                "summary": "Disconnect Ocserv User (All Sessions)",
+                "summary": "Ocserv group effective config",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bearer TOKEN",
-                        "name": "Authorization",
-                        "in": "header",
+                        "type": "integer",
+                        "description": "Ocserv Group ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Ocserv User username",
-                        "name": "username",
-                        "in": "path",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/repository.EffectiveGroupConfig"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2217,19 +2368,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/ocserv/users/{username}/terminate": {
-            "post": {
-                "description": "Terminate Ocserv User (All Sessions)",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/ipam/addresses": {
+            "get": {
+                "description": "The explicit-ipv4 addresses of users and groups and the addresses of live sessions in a network",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Ocserv(Users)"
+                    "Ocserv(IPAM)"
                 ],
-                "summary": "Terminate Ocserv User (All Sessions)",
+                "summary": "Addresses of a pool",
                 "parameters": [
                     {
                         "type": "string",
@@ -2240,15 +2388,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Ocserv User username",
-                        "name": "username",
-                        "in": "path",
+                        "description": "Pool network, e.g. 192.168.1.0/24",
+                        "name": "network",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.IPAMAddress"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2261,23 +2415,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/reports/session_logs": {
+        "/ocserv/ipam/conflicts": {
             "get": {
-                "description": "Ocserv session logs",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "The explicit-ipv4 addresses assigned more than once, outside the pool of their user or held by a live session of another user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Ocserv(IPAM)"
                 ],
-                "summary": "Ocserv session logs",
+                "summary": "Address conflicts",
                 "parameters": [
                     {
                         "type": "string",
@@ -2285,56 +2442,16 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Page number, starting from 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to order by",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "ASC",
-                            "DESC"
-                        ],
-                        "type": "string",
-                        "description": "Sort order, either ASC or DESC",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_start",
-                        "name": "date_start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_end",
-                        "name": "date_end",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/report.SessionLogsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.IPAMConflict"
+                            }
                         }
                     },
                     "400": {
@@ -2348,23 +2465,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/reports/statistics": {
+        "/ocserv/ipam/pools": {
             "get": {
-                "description": "Ocserv Users Statistics",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "The ipv4-network pools of ocserv.conf, the defaults group, groups and users, with the static and session addresses in use",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Ocserv(IPAM)"
                 ],
-                "summary": "Ocserv Users Statistics",
+                "summary": "IPv4 address pools",
                 "parameters": [
                     {
                         "type": "string",
@@ -2372,20 +2492,6 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_start",
-                        "name": "date_start",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_end",
-                        "name": "date_end",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -2394,7 +2500,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.DailyTraffic"
+                                "$ref": "#/definitions/repository.IPAMPool"
                             }
                         }
                     },
@@ -2409,23 +2515,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/reports/total-bandwidth": {
-            "get": {
-                "description": "Ocserv Users TotalBandwidth calculating",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/ipam/users/{uid}/allocate": {
+            "post": {
+                "description": "Give the user the lowest free address of its pool as explicit-ipv4, replacing the one it has, and reload ocserv. Only users of the local node get addresses.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Ocserv(IPAM)"
                 ],
-                "summary": "Ocserv Users TotalBandwidth calculating",
+                "summary": "Allocate a static address",
                 "parameters": [
                     {
                         "type": "string",
@@ -2436,16 +2545,9 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "date_start",
-                        "name": "date_start",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "date_end",
-                        "name": "date_end",
-                        "in": "query",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -2453,7 +2555,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/repository.TotalBandwidths"
+                            "$ref": "#/definitions/models.OcservUser"
                         }
                     },
                     "400": {
@@ -2467,23 +2569,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/reports/users": {
+        "/ocserv/nodes": {
             "get": {
-                "description": "Result of all user reports",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List of the registered VPN nodes. The local node, the ocserv this dashboard runs with, is not listed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Ocserv(Nodes)"
                 ],
-                "summary": "Result of all user reports",
+                "summary": "List of nodes",
                 "parameters": [
                     {
                         "type": "string",
@@ -2491,57 +2596,44 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/report.OcservUserReportResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/request.ErrorResponse"
-                        }
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/middlewares.Unauthorized"
-                        }
-                    }
-                }
-            }
-        },
-        "/system": {
-            "get": {
-                "description": "Get panel System Config",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "System"
-                ],
-                "summary": "Get panel System Config",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer TOKEN",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.GetSystemResponse"
+                            "$ref": "#/definitions/node.NodesResponse"
                         }
                     },
                     "400": {
@@ -2558,8 +2650,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Update panel System Config",
+            "post": {
+                "description": "Register a VPN node running the agent. The agent is checked right away; an unreachable agent is reported in last_error.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2567,19 +2659,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Ocserv(Nodes)"
                 ],
-                "summary": "Update panel System Config",
+                "summary": "Register node",
                 "parameters": [
                     {
-                        "description": "update system config data",
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "node data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system.PatchSystemUpdateData"
+                            "$ref": "#/definitions/node.CreateNodeData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservNode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/nodes/lookup": {
+            "get": {
+                "description": "Names of the enabled nodes users can be provisioned on, the local one first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Nodes)"
+                ],
+                "summary": "Node names",
+                "parameters": [
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
@@ -2592,7 +2731,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.GetSystemResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -2610,24 +2752,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/system/init": {
+        "/ocserv/nodes/{id}": {
             "get": {
-                "description": "Get panel System init Config",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Node detail",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Ocserv(Nodes)"
+                ],
+                "summary": "Node detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get panel System init Config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.GetSystemInitResponse"
+                            "$ref": "#/definitions/models.OcservNode"
                         }
                     },
                     "400": {
@@ -2635,42 +2790,66 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/request.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
                     }
                 }
-            }
-        },
-        "/system/release": {
-            "get": {
-                "description": "Get Dashboard current and latest release",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Delete a node no user is provisioned on",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Ocserv(Nodes)"
+                ],
+                "summary": "Delete node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Dashboard the current and latest release",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/system.DashboardRelease"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/request.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
-            }
-        },
-        "/system/setup": {
-            "post": {
-                "description": "Setup user and system config",
+            },
+            "patch": {
+                "description": "Update a node. Users of a disabled node stay in the database but can not be changed until it is enabled again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2678,25 +2857,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Ocserv(Nodes)"
                 ],
-                "summary": "Setup user and system config",
+                "summary": "Update node",
                 "parameters": [
                     {
-                        "description": "system setup data",
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "node data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system.SetupSystem"
+                            "$ref": "#/definitions/node.UpdateNodeData"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.SetupSystemResponse"
+                            "$ref": "#/definitions/models.OcservNode"
                         }
                     },
                     "400": {
@@ -2704,39 +2897,53 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/request.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/system/user/reset-password": {
-            "post": {
-                "description": "Reset admin password by secret key",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/nodes/{id}/status": {
+            "get": {
+                "description": "Call the agent of the node and report its ocserv release and status. The result is kept in last_seen_at, version and last_error of the node.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System(User)"
+                    "Ocserv(Nodes)"
                 ],
-                "summary": "Reset admin password by secret key",
+                "summary": "Node status",
                 "parameters": [
                     {
-                        "description": "Reset admin password data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/system.ResetAdminPassword"
-                        }
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.ResetPasswordResponse"
+                            "$ref": "#/definitions/node.NodeStatusResponse"
                         }
                     },
                     "400": {
@@ -2744,55 +2951,27 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/request.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
                     }
                 }
             }
         },
-        "/system/users": {
+        "/ocserv/server/config": {
             "get": {
-                "description": "List of Admin or simple users",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Typed directives of the main ocserv.conf",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Server Config)"
                 ],
-                "summary": "List of Admin or simple users",
+                "summary": "Ocserv server config",
                 "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Page number, starting from 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to order by",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "ASC",
-                            "DESC"
-                        ],
-                        "type": "string",
-                        "description": "Sort order, either ASC or DESC",
-                        "name": "sort",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
@@ -2805,7 +2984,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.UsersResponse"
+                            "$ref": "#/definitions/models.OcservServerConfig"
                         }
                     },
                     "400": {
@@ -2828,8 +3007,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create user Admin or simple",
+            "put": {
+                "description": "Write ocserv.conf and reload ocserv. Changes to directives read at startup only restart ocserv under systemd; in docker the revision action is restart_required and the container has to be restarted. The previous ocserv.conf is restored when ocserv refuses the reload or fails to restart.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2837,9 +3016,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Server Config)"
                 ],
-                "summary": "Create user",
+                "summary": "Apply ocserv server config",
                 "parameters": [
                     {
                         "type": "string",
@@ -2849,20 +3028,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "create user data",
+                        "description": "server config",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system.CreateUserData"
+                            "$ref": "#/definitions/models.OcservServerConfig"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.OcservServerConfigRevision"
                         }
                     },
                     "400": {
@@ -2886,9 +3065,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/system/users/login": {
+        "/ocserv/server/config/preview": {
             "post": {
-                "description": "Admin users login with Google captcha(captcha site key required in get config api)",
+                "description": "Render ocserv.conf for the given config without applying it, with the changed directives and whether applying needs an ocserv restart",
                 "consumes": [
                     "application/json"
                 ],
@@ -2896,17 +3075,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Server Config)"
                 ],
-                "summary": "Admin users login",
+                "summary": "Preview ocserv server config",
                 "parameters": [
                     {
-                        "description": "login data",
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "server config",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system.LoginData"
+                            "$ref": "#/definitions/models.OcservServerConfig"
                         }
                     }
                 ],
@@ -2914,7 +3100,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.UserLoginResponse"
+                            "$ref": "#/definitions/repository.ServerConfigPreview"
                         }
                     },
                     "400": {
@@ -2922,23 +3108,32 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/request.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/system/users/lookup": {
+        "/ocserv/server/config/revisions": {
             "get": {
-                "description": "List of Users Lookup",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the versions of ocserv.conf applied from the dashboard",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Server Config)"
                 ],
-                "summary": "List of Users Lookup",
+                "summary": "Ocserv server config history",
                 "parameters": [
                     {
                         "type": "string",
@@ -2946,16 +3141,44 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UsersLookup"
-                            }
+                            "$ref": "#/definitions/server_config.RevisionsResponse"
                         }
                     },
                     "400": {
@@ -2979,42 +3202,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/system/users/password": {
-            "post": {
-                "description": "Change user password by self",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/server/config/revisions/{id}": {
+            "get": {
+                "description": "Get a version of ocserv.conf applied from the dashboard",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Server Config)"
                 ],
-                "summary": "Change user password by self",
+                "summary": "Ocserv server config revision",
                 "parameters": [
-                    {
-                        "description": "user new password",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/system.ChangeUserPasswordBySelf"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.UsersResponse"
+                            "$ref": "#/definitions/models.OcservServerConfigRevision"
                         }
                     },
                     "400": {
@@ -3028,23 +3246,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
                     }
                 }
             }
         },
-        "/system/users/profile": {
+        "/ocserv/templates": {
             "get": {
-                "description": "Get User Profile",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List of the reusable group config templates",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Config Templates)"
                 ],
-                "summary": "Get User Profile",
+                "summary": "List of config templates",
                 "parameters": [
                     {
                         "type": "string",
@@ -3052,13 +3273,44 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/config_template.TemplatesResponse"
                         }
                     },
                     "400": {
@@ -3074,11 +3326,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/system/users/{uid}": {
-            "delete": {
-                "description": "Delete simple user",
+            },
+            "post": {
+                "description": "Create a reusable group config template",
                 "consumes": [
                     "application/json"
                 ],
@@ -3086,28 +3336,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Config Templates)"
                 ],
-                "summary": "Delete simple user",
+                "summary": "Create config template",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "config template data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config_template.CreateTemplateData"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservConfigTemplate"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3130,49 +3385,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/system/users/{uid}/password": {
-            "post": {
-                "description": "Change user password by admin",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/templates/{id}": {
+            "get": {
+                "description": "Config template detail",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System(Users)"
+                    "Ocserv(Config Templates)"
                 ],
-                "summary": "Change user password by admin",
+                "summary": "Config template detail",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User UID",
-                        "name": "uid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "user new password",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/system.ChangeUserPassword"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/system.UsersResponse"
+                            "$ref": "#/definitions/models.OcservConfigTemplate"
                         }
                     },
                     "400": {
@@ -3186,29 +3429,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/middlewares.PermissionDenied"
-                        }
                     }
                 }
-            }
-        },
-        "/systemd/disable": {
-            "post": {
-                "description": "Disable ocserv systemd service (remove from auto start)",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Delete a config template no group uses",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Systemd"
+                    "Ocserv(Config Templates)"
                 ],
-                "summary": "Disable ocserv service",
+                "summary": "Delete config template",
                 "parameters": [
                     {
                         "type": "string",
@@ -3216,14 +3448,18 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/systemd.ActionResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3237,18 +3473,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
                     },
-                    "429": {
-                        "description": "Too Many Requests",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
                         }
                     }
                 }
-            }
-        },
-        "/systemd/enable": {
-            "post": {
-                "description": "Enable ocserv systemd service (auto start on boot)",
+            },
+            "patch": {
+                "description": "Update a config template and re-render the config files of the groups that use it. The previous files are put back when ocserv refuses the reload.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3256,9 +3490,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Systemd"
+                    "Ocserv(Config Templates)"
                 ],
-                "summary": "Enable ocserv service",
+                "summary": "Update config template",
                 "parameters": [
                     {
                         "type": "string",
@@ -3266,13 +3500,29 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "config template data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config_template.UpdateTemplateData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/systemd.ActionResponse"
+                            "$ref": "#/definitions/models.OcservConfigTemplate"
                         }
                     },
                     "400": {
@@ -3287,28 +3537,25 @@ const docTemplate = `{
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
                     },
-                    "429": {
-                        "description": "Too Many Requests",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
                         }
                     }
                 }
             }
         },
-        "/systemd/restart": {
-            "post": {
-                "description": "Restart ocserv systemd service",
-                "consumes": [
-                    "application/json"
-                ],
+        "/ocserv/templates/{id}/groups": {
+            "get": {
+                "description": "Groups that use the config template",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Systemd"
+                    "Ocserv(Config Templates)"
                 ],
-                "summary": "Restart ocserv service",
+                "summary": "Groups of a config template",
                 "parameters": [
                     {
                         "type": "string",
@@ -3316,13 +3563,23 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/systemd.ActionResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OcservGroup"
+                            }
                         }
                     },
                     "400": {
@@ -3336,19 +3593,13 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
-                    },
-                    "429": {
-                        "description": "Too Many Requests",
-                        "schema": {
-                            "$ref": "#/definitions/middlewares.TooManyRequests"
-                        }
                     }
                 }
             }
         },
-        "/systemd/status": {
+        "/ocserv/users": {
             "get": {
-                "description": "Ocserv systemctl status",
+                "description": "List of Ocserv Users",
                 "consumes": [
                     "application/json"
                 ],
@@ -3356,10 +3607,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Systemd"
+                    "Ocserv(Users)"
                 ],
-                "summary": "Ocserv systemctl status",
+                "summary": "List of Ocserv Users",
                 "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "minLength": 2,
+                        "type": "string",
+                        "description": "ocserv username q search",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "online",
+                            "active",
+                            "deactivated",
+                            "locked"
+                        ],
+                        "type": "string",
+                        "description": "filter ocserv user by statues",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter ocserv user by group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter ocserv user and online sessions by virtual host",
+                        "name": "vhost",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter ocserv user and online sessions by node",
+                        "name": "node",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Bearer TOKEN",
@@ -3372,7 +3691,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/systemd.OcservSystemdStatus"
+                            "$ref": "#/definitions/ocserv_user.OcservUsersResponse"
                         }
                     },
                     "400": {
@@ -3386,77 +3705,2991 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/middlewares.Unauthorized"
                         }
+                    }
+                }
+            },
+            "post": {
+                "description": "Ocserv User creation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User creation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     },
-                    "429": {
-                        "description": "Too Many Requests",
+                    {
+                        "description": "ocserv user create data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                            "$ref": "#/definitions/ocserv_user.CreateOcservUserData"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "backup.RestoreResponse": {
-            "type": "object",
-            "properties": {
-                "existing": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "inserted": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
                     }
                 }
             }
         },
-        "customer.CiscoSetupResponse": {
-            "type": "object",
-            "required": [
-                "certificate_import_uri",
-                "certificate_password",
-                "connection_create_uri",
-                "connection_name",
-                "expires_at",
-                "server_address",
-                "server_port"
-            ],
-            "properties": {
-                "certificate_import_uri": {
-                    "type": "string"
-                },
-                "certificate_password": {
-                    "type": "string"
-                },
-                "connection_create_uri": {
-                    "type": "string"
-                },
-                "connection_name": {
-                    "type": "string"
-                },
-                "expires_at": {
-                    "type": "string"
-                },
-                "server_address": {
-                    "type": "string"
-                },
-                "server_port": {
-                    "type": "integer"
-                }
-            }
-        },
-        "customer.ModelCustomer": {
-            "type": "object",
-            "required": [
-                "certificate_available",
-                "certificate_enabled",
-                "deactivated_at",
-                "expire_at",
+        "/ocserv/users/ocpasswd": {
+            "get": {
+                "description": "Ocserv Users from ocpasswd file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Ocpasswd)"
+                ],
+                "summary": "Ocserv Users from ocpasswd file",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_user.OcservUsersSyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/ocpasswd/sync": {
+            "post": {
+                "description": "Ocserv Users from ocpasswd file to db",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Ocpasswd)"
+                ],
+                "summary": "Ocserv Users from ocpasswd file to db",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "list of users with config to sync in db",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_user.SyncOcpasswdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{id}/disconnect_by_id": {
+            "post": {
+                "description": "Disconnect Ocserv User Session BY ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Disconnect Ocserv User Session BY ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node of the session, the local one by default",
+                        "name": "node",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{id}/terminate_by_id": {
+            "post": {
+                "description": "Terminate Ocserv User Session BY ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Terminate Ocserv User Session BY ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node of the session, the local one by default",
+                        "name": "node",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}": {
+            "get": {
+                "description": "Ocserv user detail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv user detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Ocserv User delete",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User delete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Ocserv User update",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User update",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ocserv user update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_user.UpdateOcservUserData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/activate": {
+            "post": {
+                "description": "Restore and activate expired Ocserv User accounts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Restore and activate expired Ocserv User accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "list of ocserv users and expire time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_user.ActivateUserData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/certificate": {
+            "get": {
+                "description": "Download the user's .p12 certificate bundle",
+                "produces": [
+                    "application/x-pkcs12"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Download ocserv user certificate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user.p12",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create certificate for an existing ocserv user using the currently stored password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Create certificate for ocserv user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/certificate/renew": {
+            "post": {
+                "description": "Issue a new certificate using the currently stored password and revoke the current one through the CRL. The customer is notified through the Telegram bot when linked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Renew certificate of ocserv user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/effective": {
+            "get": {
+                "description": "Config ocserv applies to the user, merged from ocserv.conf, the group (or defaults group) file and the user file, with the file each directive comes from and the files it overrides",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv user effective config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/repository.EffectiveUserConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/lock": {
+            "post": {
+                "description": "Ocserv User locking",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User locking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/session_logs": {
+            "get": {
+                "description": "Ocserv User session logs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User session logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_start",
+                        "name": "date_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_end",
+                        "name": "date_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_user.SessionLogsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/statistics": {
+            "get": {
+                "description": "Ocserv User Statistics",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User Statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_start",
+                        "name": "date_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_end",
+                        "name": "date_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ocserv_user.StatisticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{uid}/unlock": {
+            "post": {
+                "description": "Ocserv User unlocking",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Ocserv User unlocking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{username}/disconnect": {
+            "post": {
+                "description": "Disconnect Ocserv User (All Sessions)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Disconnect Ocserv User (All Sessions)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/users/{username}/terminate": {
+            "post": {
+                "description": "Terminate Ocserv User (All Sessions)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Users)"
+                ],
+                "summary": "Terminate Ocserv User (All Sessions)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ocserv User username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/vhosts": {
+            "get": {
+                "description": "List of the ocserv virtual hosts. The default virtual host is the global section of ocserv.conf and is not listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Virtual Hosts)"
+                ],
+                "summary": "List of virtual hosts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/vhost.VHostsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a virtual host and add its section to ocserv.conf. ocserv.conf is put back when ocserv refuses the reload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Virtual Hosts)"
+                ],
+                "summary": "Create virtual host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "virtual host data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/vhost.CreateVHostData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservVHost"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/vhosts/lookup": {
+            "get": {
+                "description": "Names of the virtual hosts groups and users can be listed under, the default one first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Virtual Hosts)"
+                ],
+                "summary": "Virtual host names",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/ocserv/vhosts/{id}": {
+            "get": {
+                "description": "Virtual host detail",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Virtual Hosts)"
+                ],
+                "summary": "Virtual host detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Virtual host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservVHost"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a virtual host no group or user belongs to, and its section of ocserv.conf",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Virtual Hosts)"
+                ],
+                "summary": "Delete virtual host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Virtual host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a virtual host and rewrite its section of ocserv.conf. ocserv.conf is put back when ocserv refuses the reload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ocserv(Virtual Hosts)"
+                ],
+                "summary": "Update virtual host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Virtual host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "virtual host data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/vhost.UpdateVHostData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OcservVHost"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/auth_alerts": {
+            "get": {
+                "description": "Bursts of failed ocserv logins per username or source IP, and the lock or ban applied",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Failed login alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "ip"
+                        ],
+                        "type": "string",
+                        "description": "filter by alert kind",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.AuthAlertsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/expiring_certificates": {
+            "get": {
+                "description": "Active users whose client certificate expires within days, including already expired ones, soonest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Expiring user certificates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "maximum": 825,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "look ahead window in days, default 30",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.ExpiringCertificate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/repeated_ip_bans": {
+            "get": {
+                "description": "Addresses banned at least min times in the last days, most banned first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "IPs banned repeatedly",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "maximum": 365,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "look back window in days, default 30",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 2,
+                        "type": "integer",
+                        "description": "minimum number of bans, default 2",
+                        "name": "min",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.RepeatedIPBan"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/session_logs": {
+            "get": {
+                "description": "Ocserv session logs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Ocserv session logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_start",
+                        "name": "date_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_end",
+                        "name": "date_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.SessionLogsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/statistics": {
+            "get": {
+                "description": "Ocserv Users Statistics",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Ocserv Users Statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_start",
+                        "name": "date_start",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_end",
+                        "name": "date_end",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DailyTraffic"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/total-bandwidth": {
+            "get": {
+                "description": "Ocserv Users TotalBandwidth calculating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Ocserv Users TotalBandwidth calculating",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_start",
+                        "name": "date_start",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_end",
+                        "name": "date_end",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/repository.TotalBandwidths"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/users": {
+            "get": {
+                "description": "Result of all user reports",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Result of all user reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/report.OcservUserReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/vhosts": {
+            "get": {
+                "description": "Groups, users, online users and traffic (GiB) of each virtual host, the default one first. Traffic is limited to the dates when given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Report per virtual host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_start",
+                        "name": "date_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_end",
+                        "name": "date_end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/repository.VHostUsage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/system": {
+            "get": {
+                "description": "Get panel System Config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get panel System Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.GetSystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update panel System Config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Update panel System Config",
+                "parameters": [
+                    {
+                        "description": "update system config data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.PatchSystemUpdateData"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.GetSystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/init": {
+            "get": {
+                "description": "Get panel System init Config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get panel System init Config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.GetSystemInitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/release": {
+            "get": {
+                "description": "Get Dashboard current and latest release",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Dashboard the current and latest release",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.DashboardRelease"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/setup": {
+            "post": {
+                "description": "Setup user and system config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Setup user and system config",
+                "parameters": [
+                    {
+                        "description": "system setup data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.SetupSystem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/system.SetupSystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/user/reset-password": {
+            "post": {
+                "description": "Reset admin password by secret key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(User)"
+                ],
+                "summary": "Reset admin password by secret key",
+                "parameters": [
+                    {
+                        "description": "Reset admin password data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.ResetAdminPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.ResetPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users": {
+            "get": {
+                "description": "List of Admin or simple users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "List of Admin or simple users",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to order by",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ASC",
+                            "DESC"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, either ASC or DESC",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.UsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create user Admin or simple",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "create user data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.CreateUserData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users/login": {
+            "post": {
+                "description": "Admin users login with Google captcha(captcha site key required in get config api)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "Admin users login",
+                "parameters": [
+                    {
+                        "description": "login data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.LoginData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.UserLoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users/lookup": {
+            "get": {
+                "description": "List of Users Lookup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "List of Users Lookup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UsersLookup"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users/password": {
+            "post": {
+                "description": "Change user password by self",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "Change user password by self",
+                "parameters": [
+                    {
+                        "description": "user new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.ChangeUserPasswordBySelf"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.UsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users/profile": {
+            "get": {
+                "description": "Get User Profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "Get User Profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users/{uid}": {
+            "delete": {
+                "description": "Delete simple user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "Delete simple user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/users/{uid}/password": {
+            "post": {
+                "description": "Change user password by admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System(Users)"
+                ],
+                "summary": "Change user password by admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "user new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.ChangeUserPassword"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/system.UsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.PermissionDenied"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/disable": {
+            "post": {
+                "description": "Disable ocserv systemd service (remove from auto start)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Systemd"
+                ],
+                "summary": "Disable ocserv service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/systemd.ActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/enable": {
+            "post": {
+                "description": "Enable ocserv systemd service (auto start on boot)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Systemd"
+                ],
+                "summary": "Enable ocserv service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/systemd.ActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/restart": {
+            "post": {
+                "description": "Restart ocserv systemd service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Systemd"
+                ],
+                "summary": "Restart ocserv service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/systemd.ActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/status": {
+            "get": {
+                "description": "Ocserv systemctl status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Systemd"
+                ],
+                "summary": "Ocserv systemctl status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/systemd.OcservSystemdStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/request.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.Unauthorized"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middlewares.TooManyRequests"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "backup.RestoreResponse": {
+            "type": "object",
+            "properties": {
+                "existing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "inserted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "certificate_authority.CertificatesResponse": {
+            "type": "object",
+            "required": [
+                "meta",
+                "result"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.CertificateRecord"
+                    }
+                }
+            }
+        },
+        "certificate_authority.ReissueData": {
+            "type": "object",
+            "properties": {
+                "batch": {
+                    "description": "Batch is the number of users to reissue, 50 when empty.",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "retry_failed": {
+                    "description": "RetryFailed retries the users parked after a failed reissue.",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "certificate_authority.ReissueResponse": {
+            "type": "object",
+            "required": [
+                "parked",
+                "remaining",
+                "results"
+            ],
+            "properties": {
+                "parked": {
+                    "description": "Parked lists the users skipped after a failed reissue.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.CAReissueResult"
+                    }
+                }
+            }
+        },
+        "certificate_authority.RetireData": {
+            "type": "object",
+            "properties": {
+                "force": {
+                    "description": "Force retires the CA even though some users still have a\ncertificate it issued; those certificates stop working.",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "config_revision.RevisionsResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservConfigRevision"
+                    }
+                }
+            }
+        },
+        "config_template.CreateTemplateData": {
+            "type": "object",
+            "required": [
+                "config",
+                "name"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservGroupConfig"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "config_template.TemplatesResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservConfigTemplate"
+                    }
+                }
+            }
+        },
+        "config_template.UpdateTemplateData": {
+            "type": "object",
+            "required": [
+                "config"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservGroupConfig"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "customer.CiscoSetupResponse": {
+            "type": "object",
+            "required": [
+                "auth_mode",
+                "connection_create_uri",
+                "connection_name",
+                "expires_at",
+                "server_address",
+                "server_port"
+            ],
+            "properties": {
+                "auth_mode": {
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
+                "certificate_import_uri": {
+                    "description": "empty in password mode",
+                    "type": "string"
+                },
+                "certificate_password": {
+                    "description": "empty in password mode",
+                    "type": "string"
+                },
+                "connection_create_uri": {
+                    "type": "string"
+                },
+                "connection_name": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "server_address": {
+                    "type": "string"
+                },
+                "server_port": {
+                    "type": "integer"
+                }
+            }
+        },
+        "customer.ModelCustomer": {
+            "type": "object",
+            "required": [
+                "auth_mode",
+                "certificate_available",
+                "certificate_enabled",
+                "deactivated_at",
+                "expire_at",
                 "is_locked",
                 "owner",
                 "rx",
@@ -3466,141 +6699,730 @@ const docTemplate = `{
                 "username"
             ],
             "properties": {
-                "certificate_available": {
-                    "type": "boolean"
+                "auth_mode": {
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
+                "certificate_available": {
+                    "type": "boolean"
+                },
+                "certificate_enabled": {
+                    "type": "boolean"
+                },
+                "deactivated_at": {
+                    "type": "string"
+                },
+                "expire_at": {
+                    "type": "string"
+                },
+                "is_locked": {
+                    "type": "boolean"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "rx": {
+                    "description": "Receive in bytes",
+                    "type": "integer"
+                },
+                "traffic_size": {
+                    "description": "in GiB  \u003e\u003e x * 1024 ** 3",
+                    "type": "integer"
+                },
+                "traffic_type": {
+                    "type": "string",
+                    "enum": [
+                        "Free",
+                        "MonthlyTransmit",
+                        "MonthlyReceive",
+                        "MonthlyRxTx",
+                        "TotallyTransmit",
+                        "TotallyReceive",
+                        "TotallyRxTx"
+                    ]
+                },
+                "tx": {
+                    "description": "Transmit in bytes",
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "customer.SummaryData": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 2
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 2
+                }
+            }
+        },
+        "customer.SummaryResponse": {
+            "type": "object",
+            "required": [
+                "ocserv_user",
+                "usage"
+            ],
+            "properties": {
+                "ocserv_user": {
+                    "$ref": "#/definitions/customer.ModelCustomer"
+                },
+                "usage": {
+                    "$ref": "#/definitions/customer.UsageResponse"
+                }
+            }
+        },
+        "customer.UsageResponse": {
+            "type": "object",
+            "required": [
+                "bandwidths",
+                "date_end",
+                "date_start"
+            ],
+            "properties": {
+                "bandwidths": {
+                    "$ref": "#/definitions/repository.TotalBandwidths"
+                },
+                "date_end": {
+                    "type": "string"
+                },
+                "date_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "drift.FixData": {
+            "type": "object",
+            "required": [
+                "source"
+            ],
+            "properties": {
+                "kinds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "database",
+                        "ocserv"
+                    ]
+                }
+            }
+        },
+        "drift.FixResponse": {
+            "type": "object",
+            "required": [
+                "results"
+            ],
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.DriftFixResult"
+                    }
+                }
+            }
+        },
+        "drift.Item": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "database": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "missing_in_ocpasswd",
+                        "missing_in_database",
+                        "lock_mismatch",
+                        "group_mismatch",
+                        "stale_config",
+                        "missing_config",
+                        "orphan_group",
+                        "missing_group_file"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ocserv": {
+                    "type": "string"
+                }
+            }
+        },
+        "drift.Report": {
+            "type": "object",
+            "required": [
+                "checked_at",
+                "items",
+                "summary"
+            ],
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/drift.Item"
+                    }
+                },
+                "summary": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "group.UnsyncedGroup": {
+            "type": "object",
+            "required": [
+                "config",
+                "name"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservGroupConfig"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "home.CPU": {
+            "type": "object",
+            "properties": {
+                "avg_percent": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "used_units": {
+                    "type": "number"
+                }
+            }
+        },
+        "home.CurrentStats": {
+            "type": "object",
+            "properties": {
+                "Authentication failures": {
+                    "type": "integer"
+                },
+                "Average auth time": {
+                    "type": "string"
+                },
+                "Average session time": {
+                    "type": "string"
+                },
+                "Closed due to error sessions": {
+                    "type": "integer"
+                },
+                "Last stats reset": {
+                    "type": "string"
+                },
+                "Max auth time": {
+                    "type": "string"
+                },
+                "Max session time": {
+                    "type": "string"
+                },
+                "RX": {
+                    "type": "string"
+                },
+                "Sessions handled": {
+                    "type": "integer"
+                },
+                "TX": {
+                    "type": "string"
+                },
+                "Timed out (idle) sessions": {
+                    "type": "integer"
+                },
+                "Timed out sessions": {
+                    "type": "integer"
+                },
+                "_Last stats reset": {
+                    "type": "string"
+                },
+                "raw_avg_auth_time": {
+                    "type": "integer"
+                },
+                "raw_avg_session_time": {
+                    "type": "integer"
+                },
+                "raw_last_stats_reset": {
+                    "type": "integer"
+                },
+                "raw_max_auth_time": {
+                    "type": "integer"
+                },
+                "raw_max_session_time": {
+                    "type": "integer"
+                },
+                "raw_rx": {
+                    "description": "raw fields if you want",
+                    "type": "integer"
+                },
+                "raw_tx": {
+                    "type": "integer"
+                }
+            }
+        },
+        "home.Disk": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "number"
+                },
+                "used": {
+                    "type": "number"
+                },
+                "used_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "home.DockerService": {
+            "type": "object",
+            "required": [
+                "log_stream",
+                "ocserv",
+                "postgres",
+                "user_expiry",
+                "web"
+            ],
+            "properties": {
+                "log_stream": {
+                    "$ref": "#/definitions/home.DockerStats"
+                },
+                "ocserv": {
+                    "$ref": "#/definitions/home.DockerStats"
+                },
+                "postgres": {
+                    "$ref": "#/definitions/home.DockerStats"
+                },
+                "telegram_bot": {
+                    "$ref": "#/definitions/home.DockerStats"
+                },
+                "user_expiry": {
+                    "$ref": "#/definitions/home.DockerStats"
+                },
+                "web": {
+                    "$ref": "#/definitions/home.DockerStats"
+                }
+            }
+        },
+        "home.DockerStats": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "cpu": {
+                    "$ref": "#/definitions/home.CPU"
                 },
-                "certificate_enabled": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 },
-                "deactivated_at": {
+                "ram": {
+                    "$ref": "#/definitions/home.RAM"
+                }
+            }
+        },
+        "home.GeneralInfo": {
+            "type": "object",
+            "properties": {
+                "Active sessions": {
+                    "type": "integer"
+                },
+                "IPs in ban list": {
+                    "type": "integer"
+                },
+                "Median latency": {
                     "type": "string"
                 },
-                "expire_at": {
+                "STDEV latency": {
                     "type": "string"
                 },
-                "is_locked": {
-                    "type": "boolean"
+                "Sec-mod PID": {
+                    "type": "integer"
                 },
-                "owner": {
+                "Sec-mod instance count": {
+                    "type": "integer"
+                },
+                "Server PID": {
+                    "type": "integer"
+                },
+                "Status": {
                     "type": "string"
                 },
-                "rx": {
-                    "description": "Receive in bytes",
+                "Total authentication failures": {
                     "type": "integer"
                 },
-                "traffic_size": {
-                    "description": "in GiB  \u003e\u003e x * 1024 ** 3",
+                "Total sessions": {
                     "type": "integer"
                 },
-                "traffic_type": {
-                    "type": "string",
-                    "enum": [
-                        "Free",
-                        "MonthlyTransmit",
-                        "MonthlyReceive",
-                        "MonthlyRxTx",
-                        "TotallyTransmit",
-                        "TotallyReceive",
-                        "TotallyRxTx"
+                "Up since": {
+                    "type": "string"
+                },
+                "_Up since": {
+                    "type": "string"
+                },
+                "raw_median_latency": {
+                    "description": "raw fields if you want",
+                    "type": "integer"
+                },
+                "raw_stdev_latency": {
+                    "type": "integer"
+                },
+                "raw_up_since": {
+                    "type": "integer"
+                },
+                "uptime": {
+                    "type": "integer"
+                }
+            }
+        },
+        "home.GetHomeResponse": {
+            "type": "object",
+            "properties": {
+                "ip_bans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IPBanPoints"
+                    }
+                },
+                "statistics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyTraffic"
+                    }
+                },
+                "telegram_service": {
+                    "description": "TelegramService is a lightweight snapshot for the admin dashboard (no outbound Telegram calls).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/home.TelegramServiceStatus"
+                        }
                     ]
                 },
-                "tx": {
-                    "description": "Transmit in bytes",
+                "top_bandwidth_user": {
+                    "$ref": "#/definitions/repository.TopBandwidthUsers"
+                },
+                "total_bandwidth": {
+                    "$ref": "#/definitions/repository.TotalBandwidths"
+                },
+                "users": {
+                    "$ref": "#/definitions/home.GetHomeUser"
+                }
+            }
+        },
+        "home.GetHomeUser": {
+            "type": "object",
+            "properties": {
+                "online_users_session": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OnlineUserSession"
+                    }
+                },
+                "total": {
                     "type": "integer"
+                }
+            }
+        },
+        "home.OcservStatusResponse": {
+            "type": "object",
+            "properties": {
+                "current_stats": {
+                    "$ref": "#/definitions/home.CurrentStats"
                 },
-                "username": {
+                "general_info": {
+                    "$ref": "#/definitions/home.GeneralInfo"
+                }
+            }
+        },
+        "home.RAM": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "number"
+                },
+                "used": {
+                    "type": "number"
+                },
+                "used_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "home.ServerStatusResponse": {
+            "type": "object",
+            "properties": {
+                "cpu": {
+                    "$ref": "#/definitions/home.CPU"
+                },
+                "disk": {
+                    "$ref": "#/definitions/home.Disk"
+                },
+                "ram": {
+                    "$ref": "#/definitions/home.RAM"
+                },
+                "swap": {
+                    "$ref": "#/definitions/home.Swap"
+                }
+            }
+        },
+        "home.Swap": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "number"
+                },
+                "used": {
+                    "type": "number"
+                },
+                "used_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "home.TelegramServiceStatus": {
+            "type": "object",
+            "properties": {
+                "bot_username": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "has_bot_token": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "ip_ban.BanData": {
+            "type": "object",
+            "required": [
+                "ip"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-31T00:00:00Z"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1024
+                }
+            }
+        },
+        "ip_ban.HistoryResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservIPBan"
+                    }
+                }
+            }
+        },
+        "ip_ban.UnbanData": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1024
+                }
+            }
+        },
+        "middlewares.PermissionDenied": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "middlewares.TooManyRequests": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 }
             }
         },
-        "customer.SummaryData": {
+        "middlewares.Unauthorized": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 2
-                },
-                "username": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 2
+                "error": {
+                    "type": "string"
                 }
             }
         },
-        "customer.SummaryResponse": {
+        "models.AgentInfo": {
             "type": "object",
-            "required": [
-                "ocserv_user",
-                "usage"
-            ],
             "properties": {
-                "ocserv_user": {
-                    "$ref": "#/definitions/customer.ModelCustomer"
+                "capabilities": {
+                    "$ref": "#/definitions/models.OcctlCapabilities"
                 },
-                "usage": {
-                    "$ref": "#/definitions/customer.UsageResponse"
+                "hostname": {
+                    "type": "string"
+                },
+                "node": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OcctlServerStatus"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "version": {
+                    "$ref": "#/definitions/models.ServerVersion"
                 }
             }
         },
-        "customer.UsageResponse": {
+        "models.DailyTraffic": {
             "type": "object",
-            "required": [
-                "bandwidths",
-                "date_end",
-                "date_start"
-            ],
             "properties": {
-                "bandwidths": {
-                    "$ref": "#/definitions/repository.TotalBandwidths"
-                },
-                "date_end": {
+                "date": {
+                    "description": "Format: YYYY-MM-DD",
                     "type": "string"
                 },
-                "date_start": {
-                    "type": "string"
+                "rx": {
+                    "description": "in GiB",
+                    "type": "number"
+                },
+                "tx": {
+                    "description": "in GiB",
+                    "type": "number"
                 }
             }
         },
-        "group.UnsyncedGroup": {
+        "models.IPBanPoints": {
             "type": "object",
-            "required": [
-                "config",
-                "name"
-            ],
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/models.OcservGroupConfig"
+                "IP": {
+                    "type": "string"
                 },
-                "name": {
+                "Score": {
+                    "type": "integer"
+                },
+                "Since": {
                     "type": "string"
                 },
-                "path": {
+                "_Since": {
                     "type": "string"
                 }
             }
         },
-        "home.CPU": {
+        "models.OcctlCapabilities": {
             "type": "object",
+            "additionalProperties": {
+                "type": "boolean"
+            }
+        },
+        "models.OcctlEvent": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
             "properties": {
-                "avg_percent": {
-                    "type": "number"
+                "device": {
+                    "type": "string"
                 },
-                "total": {
+                "group": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "used_units": {
-                    "type": "number"
+                "ipv4": {
+                    "type": "string"
+                },
+                "node": {
+                    "description": "node whose ocserv sent the event",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "remote_ip": {
+                    "type": "string"
+                },
+                "rx": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "tx": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "connect",
+                        "disconnect"
+                    ]
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "vhost": {
+                    "type": "string"
                 }
             }
         },
-        "home.CurrentStats": {
+        "models.OcctlServerStatus": {
             "type": "object",
             "properties": {
+                "Active sessions": {
+                    "type": "integer"
+                },
                 "Authentication failures": {
                     "type": "integer"
                 },
@@ -3613,6 +7435,9 @@ const docTemplate = `{
                 "Closed due to error sessions": {
                     "type": "integer"
                 },
+                "IPs in ban list": {
+                    "type": "integer"
+                },
                 "Last stats reset": {
                     "type": "string"
                 },
@@ -3622,12 +7447,36 @@ const docTemplate = `{
                 "Max session time": {
                     "type": "string"
                 },
+                "Median latency": {
+                    "type": "string"
+                },
                 "RX": {
                     "type": "string"
                 },
+                "STDEV latency": {
+                    "type": "string"
+                },
+                "Sec-mod PID": {
+                    "type": "integer"
+                },
+                "Sec-mod client entries": {
+                    "type": "integer"
+                },
+                "Sec-mod instance count": {
+                    "type": "integer"
+                },
+                "Server PID": {
+                    "type": "integer"
+                },
                 "Sessions handled": {
                     "type": "integer"
                 },
+                "Status": {
+                    "type": "string"
+                },
+                "TLS DB entries": {
+                    "type": "integer"
+                },
                 "TX": {
                     "type": "string"
                 },
@@ -3637,358 +7486,566 @@ const docTemplate = `{
                 "Timed out sessions": {
                     "type": "integer"
                 },
-                "_Last stats reset": {
+                "Total authentication failures": {
+                    "type": "integer"
+                },
+                "Total sessions": {
+                    "type": "integer"
+                },
+                "Up since": {
+                    "type": "string"
+                },
+                "_Last stats reset": {
+                    "type": "string"
+                },
+                "_Up since": {
+                    "type": "string"
+                },
+                "raw_avg_auth_time": {
+                    "type": "integer"
+                },
+                "raw_avg_session_time": {
+                    "type": "integer"
+                },
+                "raw_last_stats_reset": {
+                    "type": "integer"
+                },
+                "raw_max_auth_time": {
+                    "type": "integer"
+                },
+                "raw_max_session_time": {
+                    "type": "integer"
+                },
+                "raw_median_latency": {
+                    "type": "integer"
+                },
+                "raw_rx": {
+                    "type": "integer"
+                },
+                "raw_stdev_latency": {
+                    "type": "integer"
+                },
+                "raw_tx": {
+                    "type": "integer"
+                },
+                "raw_up_since": {
+                    "type": "integer"
+                },
+                "uptime": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OcservAuthAlert": {
+            "type": "object",
+            "required": [
+                "actions",
+                "created_at",
+                "failures",
+                "kind",
+                "target",
+                "window_seconds"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "ip"
+                    ]
+                },
+                "notified_at": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "window_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OcservConfigChange": {
+            "type": "object",
+            "required": [
+                "key",
+                "restart_required"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "old": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "restart_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.OcservConfigRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "update",
+                        "restore"
+                    ]
+                },
+                "actor": {
                     "type": "string"
                 },
-                "raw_avg_auth_time": {
-                    "type": "integer"
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservConfigChange"
+                    }
                 },
-                "raw_avg_session_time": {
-                    "type": "integer"
+                "config": {
+                    "$ref": "#/definitions/models.OcservConfigSnapshot"
                 },
-                "raw_last_stats_reset": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "raw_max_auth_time": {
+                "id": {
                     "type": "integer"
                 },
-                "raw_max_session_time": {
-                    "type": "integer"
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "group",
+                        "defaults",
+                        "user"
+                    ]
                 },
-                "raw_rx": {
-                    "description": "raw fields if you want",
+                "restored_from": {
                     "type": "integer"
                 },
-                "raw_tx": {
-                    "type": "integer"
+                "target": {
+                    "type": "string"
                 }
             }
         },
-        "home.Disk": {
+        "models.OcservConfigSnapshot": {
             "type": "object",
-            "properties": {
-                "total": {
-                    "type": "number"
-                },
-                "used": {
-                    "type": "number"
-                },
-                "used_percent": {
-                    "type": "number"
-                }
-            }
+            "additionalProperties": true
         },
-        "home.DockerService": {
+        "models.OcservConfigTemplate": {
             "type": "object",
             "required": [
-                "log_stream",
-                "ocserv",
-                "postgres",
-                "user_expiry",
-                "web"
+                "name",
+                "owner"
             ],
             "properties": {
-                "log_stream": {
-                    "$ref": "#/definitions/home.DockerStats"
+                "config": {
+                    "$ref": "#/definitions/models.OcservGroupConfig"
                 },
-                "ocserv": {
-                    "$ref": "#/definitions/home.DockerStats"
+                "created_at": {
+                    "type": "string"
                 },
-                "postgres": {
-                    "$ref": "#/definitions/home.DockerStats"
+                "description": {
+                    "type": "string"
                 },
-                "telegram_bot": {
-                    "$ref": "#/definitions/home.DockerStats"
+                "id": {
+                    "type": "integer"
                 },
-                "user_expiry": {
-                    "$ref": "#/definitions/home.DockerStats"
+                "name": {
+                    "type": "string"
                 },
-                "web": {
-                    "$ref": "#/definitions/home.DockerStats"
+                "owner": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "home.DockerStats": {
+        "models.OcservGroup": {
             "type": "object",
             "required": [
-                "name"
+                "name",
+                "owner",
+                "vhost"
             ],
             "properties": {
-                "cpu": {
-                    "$ref": "#/definitions/home.CPU"
+                "auth_mode": {
+                    "description": "empty means both",
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
+                "config": {
+                    "$ref": "#/definitions/models.OcservGroupConfig"
+                },
+                "id": {
+                    "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "ram": {
-                    "$ref": "#/definitions/home.RAM"
+                "owner": {
+                    "type": "string"
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "vhost": {
+                    "type": "string"
                 }
             }
         },
-        "home.GeneralInfo": {
+        "models.OcservGroupConfig": {
             "type": "object",
             "properties": {
-                "Active sessions": {
+                "cgroup": {
+                    "description": "Linux control group to assign the VPN worker process to. Format: 'controller,subsystem:name'. Example: 'cpuset,cpu:test'",
+                    "type": "string"
+                },
+                "deny-roaming": {
+                    "description": "Disconnect client if its IP changes (e.g., due to network switch). Example: true",
+                    "type": "boolean"
+                },
+                "dns": {
+                    "description": "Comma-separated list of DNS servers to assign to the client. Example: '8.8.8.8,1.1.1.1'",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dpd": {
+                    "description": "Dead Peer Detection timeout in seconds. Example: 90",
                     "type": "integer"
                 },
-                "IPs in ban list": {
+                "explicit-ipv4": {
+                    "description": "Static IPv4 address to assign to client. Example: '192.168.100.10'",
+                    "type": "string"
+                },
+                "idle-timeout": {
+                    "description": "Time in seconds before disconnecting idle clients. Example: 600",
                     "type": "integer"
                 },
-                "Median latency": {
+                "ipv4-network": {
+                    "description": "The pool of addresses that leases will be given from. Example: '192.168.1.0/24'",
                     "type": "string"
                 },
-                "STDEV latency": {
+                "iroute": {
+                    "description": "Internal route available only via VPN. Format: 'IP/prefix'. Example: '10.0.0.0/8'",
                     "type": "string"
                 },
-                "Sec-mod PID": {
+                "keepalive": {
+                    "description": "Interval in seconds to send keep-alive pings. Example: 60",
                     "type": "integer"
                 },
-                "Sec-mod instance count": {
+                "max-same-clients": {
+                    "description": "Maximum simultaneous logins per user. Example: 2",
                     "type": "integer"
                 },
-                "Server PID": {
+                "mobile-dpd": {
+                    "description": "DPD timeout specifically for mobile clients. Example: 300",
                     "type": "integer"
                 },
-                "Status": {
-                    "type": "string"
-                },
-                "Total authentication failures": {
+                "mobile-idle-timeout": {
+                    "description": "Idle timeout for mobile clients. Example: 900",
                     "type": "integer"
                 },
-                "Total sessions": {
+                "mtu": {
+                    "description": "Tunnel interface MTU to avoid fragmentation. Example: 1400",
                     "type": "integer"
                 },
-                "Up since": {
-                    "type": "string"
-                },
-                "_Up since": {
+                "nbns": {
+                    "description": "NetBIOS Name Servers (WINS) for Windows clients. Example: '192.168.1.1'",
                     "type": "string"
                 },
-                "raw_median_latency": {
-                    "description": "raw fields if you want",
-                    "type": "integer"
-                },
-                "raw_stdev_latency": {
-                    "type": "integer"
-                },
-                "raw_up_since": {
+                "net-priority": {
+                    "description": "Priority for routes; lower is higher priority. Example: 1",
                     "type": "integer"
                 },
-                "uptime": {
-                    "type": "integer"
-                }
-            }
-        },
-        "home.GetHomeResponse": {
-            "type": "object",
-            "properties": {
-                "ip_bans": {
+                "no-route": {
+                    "description": "List of networks to exclude from VPN routing. Each entry should be in 'IP/prefix' format. Example: ['192.168.0.0/16', '10.0.0.0/8']",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.IPBanPoints"
+                        "type": "string"
                     }
                 },
-                "statistics": {
+                "no-udp": {
+                    "description": "Disables UDP, enforcing TCP-only VPN connection. Example: true",
+                    "type": "boolean"
+                },
+                "restrict-user-to-ports": {
+                    "description": "Comma-separated list of allowed (or blocked, if negated) protocols and ports. Supports 'tcp(port)', 'udp(port)', 'icmp()', 'icmpv6()', and negation with '!()'. Example: 'tcp(443), tcp(80), udp(53)', or '!(tcp(22), udp(1194))'",
+                    "type": "string"
+                },
+                "restrict-user-to-routes": {
+                    "description": "Allow client access only to defined routes. Example: true",
+                    "type": "boolean"
+                },
+                "route": {
+                    "description": "Routes pushed to the client for routing traffic. Example: ['0.0.0.0/0', '10.10.0.0/16']",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.DailyTraffic"
+                        "type": "string"
                     }
                 },
-                "telegram_service": {
-                    "description": "TelegramService is a lightweight snapshot for the admin dashboard (no outbound Telegram calls).",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/home.TelegramServiceStatus"
-                        }
-                    ]
-                },
-                "top_bandwidth_user": {
-                    "$ref": "#/definitions/repository.TopBandwidthUsers"
+                "rx-data-per-sec": {
+                    "description": "Maximum receive bandwidth in bytes per second. Example: '100000' for 100 KB/s",
+                    "type": "integer"
                 },
-                "total_bandwidth": {
-                    "$ref": "#/definitions/repository.TotalBandwidths"
+                "session-timeout": {
+                    "description": "Max session time in seconds before forced disconnect. Example: 3600",
+                    "type": "integer"
                 },
-                "users": {
-                    "$ref": "#/definitions/home.GetHomeUser"
-                }
-            }
-        },
-        "home.GetHomeUser": {
-            "type": "object",
-            "properties": {
-                "online_users_session": {
+                "split-dns": {
+                    "description": "List of domains over which the provided DNS servers should be used. Example: ['example.com', 'internal.company.com']",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.OnlineUserSession"
+                        "type": "string"
                     }
                 },
-                "total": {
+                "stats-report-time": {
+                    "description": "Interval in seconds for stats reporting. Example: 300",
                     "type": "integer"
-                }
-            }
-        },
-        "home.OcservStatusResponse": {
-            "type": "object",
-            "properties": {
-                "current_stats": {
-                    "$ref": "#/definitions/home.CurrentStats"
-                },
-                "general_info": {
-                    "$ref": "#/definitions/home.GeneralInfo"
-                }
-            }
-        },
-        "home.RAM": {
-            "type": "object",
-            "properties": {
-                "total": {
-                    "type": "number"
                 },
-                "used": {
-                    "type": "number"
+                "tunnel-all-dns": {
+                    "description": "Force all DNS traffic through the VPN tunnel. Example: true",
+                    "type": "boolean"
                 },
-                "used_percent": {
-                    "type": "number"
+                "tx-data-per-sec": {
+                    "description": "Maximum transmit bandwidth in bytes per second. Example: '200000' for 200 KB/s",
+                    "type": "integer"
                 }
             }
         },
-        "home.ServerStatusResponse": {
+        "models.OcservIPBan": {
             "type": "object",
+            "required": [
+                "created_at",
+                "ip",
+                "node",
+                "source"
+            ],
             "properties": {
-                "cpu": {
-                    "$ref": "#/definitions/home.CPU"
+                "created_at": {
+                    "type": "string"
                 },
-                "disk": {
-                    "$ref": "#/definitions/home.Disk"
+                "expires_at": {
+                    "type": "string"
                 },
-                "ram": {
-                    "$ref": "#/definitions/home.RAM"
+                "id": {
+                    "type": "integer"
                 },
-                "swap": {
-                    "$ref": "#/definitions/home.Swap"
-                }
-            }
-        },
-        "home.Swap": {
-            "type": "object",
-            "properties": {
-                "total": {
-                    "type": "number"
+                "ip": {
+                    "type": "string"
                 },
-                "used": {
-                    "type": "number"
+                "node": {
+                    "type": "string"
                 },
-                "used_percent": {
-                    "type": "number"
-                }
-            }
-        },
-        "home.TelegramServiceStatus": {
-            "type": "object",
-            "properties": {
-                "bot_username": {
+                "owner": {
                     "type": "string"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "reason": {
+                    "type": "string"
                 },
-                "has_bot_token": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "middlewares.PermissionDenied": {
-            "type": "object",
-            "properties": {
-                "error": {
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "manual",
+                        "auto"
+                    ]
+                },
+                "unban_reason": {
                     "type": "string"
-                }
-            }
-        },
-        "middlewares.TooManyRequests": {
-            "type": "object",
-            "properties": {
-                "error": {
+                },
+                "unbanned_at": {
                     "type": "string"
-                }
-            }
-        },
-        "middlewares.Unauthorized": {
-            "type": "object",
-            "properties": {
-                "error": {
+                },
+                "unbanned_by": {
                     "type": "string"
                 }
             }
         },
-        "models.DailyTraffic": {
+        "models.OcservInfo": {
             "type": "object",
+            "required": [
+                "capabilities",
+                "status",
+                "version"
+            ],
             "properties": {
-                "date": {
-                    "description": "Format: YYYY-MM-DD",
-                    "type": "string"
+                "capabilities": {
+                    "$ref": "#/definitions/models.OcctlCapabilities"
                 },
-                "rx": {
-                    "description": "in GiB",
-                    "type": "number"
+                "status": {
+                    "type": "string"
                 },
-                "tx": {
-                    "description": "in GiB",
-                    "type": "number"
+                "version": {
+                    "$ref": "#/definitions/models.ServerVersion"
                 }
             }
         },
-        "models.IPBanPoints": {
+        "models.OcservNode": {
             "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
             "properties": {
-                "IP": {
+                "created_at": {
                     "type": "string"
                 },
-                "Score": {
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "Since": {
+                "last_error": {
                     "type": "string"
                 },
-                "_Since": {
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "version": {
                     "type": "string"
                 }
             }
         },
-        "models.OcservGroup": {
+        "models.OcservServerConfig": {
             "type": "object",
             "required": [
-                "name",
-                "owner"
+                "auth",
+                "dns",
+                "enable-auth",
+                "no-route",
+                "route",
+                "split-dns"
             ],
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/models.OcservGroupConfig"
+                "auth": {
+                    "description": "Authentication methods, the first one is the primary. Example: ['certificate']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
-                    "type": "integer"
+                "auth-timeout": {
+                    "description": "Seconds a client has to authenticate. Example: 40",
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "name": {
+                "ban-reset-time": {
+                    "description": "Seconds after which the ban score of an IP is reset. Example: 300",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "banner": {
+                    "description": "Banner shown to the clients after login. Example: 'Welcome'",
                     "type": "string"
                 },
-                "owner": {
+                "ca-cert": {
+                    "description": "Path of the CA bundle client certificates are checked against. Example: '/etc/ocserv/ssl/ca-cert.pem'",
                     "type": "string"
-                }
-            }
-        },
-        "models.OcservGroupConfig": {
-            "type": "object",
-            "properties": {
-                "cgroup": {
-                    "description": "Linux control group to assign the VPN worker process to. Format: 'controller,subsystem:name'. Example: 'cpuset,cpu:test'",
+                },
+                "camouflage": {
+                    "description": "Hide the server behind a fake web server unless the secret is in the URL. Example: true",
+                    "type": "boolean"
+                },
+                "camouflage_realm": {
+                    "description": "Realm of the camouflage authentication prompt. Example: 'Restricted Content'",
+                    "type": "string"
+                },
+                "camouflage_secret": {
+                    "description": "Secret clients add to the URL when camouflage is on. Example: 'mysecretkey'",
+                    "type": "string"
+                },
+                "cert-user-oid": {
+                    "description": "Certificate field holding the username. Example: '2.5.4.3'",
+                    "type": "string"
+                },
+                "cisco-client-compat": {
+                    "description": "Compatibility with the Cisco AnyConnect clients. Example: true",
+                    "type": "boolean"
+                },
+                "config-per-group": {
+                    "description": "Directory of the per group config files. Example: '/etc/ocserv/groups/'",
+                    "type": "string"
+                },
+                "config-per-user": {
+                    "description": "Directory of the per user config files. Example: '/etc/ocserv/users/'",
+                    "type": "string"
+                },
+                "cookie-timeout": {
+                    "description": "Lifetime in seconds of the session cookie. Example: 86400",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "crl": {
+                    "description": "Path of the certificate revocation list. Example: '/etc/ocserv/ssl/crl.pem'",
+                    "type": "string"
+                },
+                "default-domain": {
+                    "description": "Domain pushed to the clients. Example: 'example.com'",
+                    "type": "string"
+                },
+                "default-group-config": {
+                    "description": "Config file applied to users without a group file. Example: '/etc/ocserv/defaults/group.conf'",
                     "type": "string"
                 },
                 "deny-roaming": {
-                    "description": "Disconnect client if its IP changes (e.g., due to network switch). Example: true",
+                    "description": "Disconnect client if its IP changes. Example: false",
                     "type": "boolean"
                 },
+                "device": {
+                    "description": "Name prefix of the tun devices. Example: 'vpns'",
+                    "type": "string"
+                },
                 "dns": {
-                    "description": "Comma-separated list of DNS servers to assign to the client. Example: '8.8.8.8,1.1.1.1'",
+                    "description": "DNS servers pushed to the clients. Example: ['8.8.8.8', '1.1.1.1']",
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -3996,119 +8053,236 @@ const docTemplate = `{
                 },
                 "dpd": {
                     "description": "Dead Peer Detection timeout in seconds. Example: 90",
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "explicit-ipv4": {
-                    "description": "Static IPv4 address to assign to client. Example: '192.168.100.10'",
-                    "type": "string"
+                "dtls-legacy": {
+                    "description": "Support the legacy DTLS negotiation of old clients. Example: true",
+                    "type": "boolean"
+                },
+                "enable-auth": {
+                    "description": "Additional authentication methods a client may use instead. Example: ['plain[passwd=/etc/ocserv/ocpasswd]']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "idle-timeout": {
-                    "description": "Time in seconds before disconnecting idle clients. Example: 600",
-                    "type": "integer"
+                    "description": "Time in seconds before disconnecting idle clients. Example: 1200",
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "ipv4-network": {
                     "description": "The pool of addresses that leases will be given from. Example: '192.168.1.0/24'",
                     "type": "string"
                 },
-                "iroute": {
-                    "description": "Internal route available only via VPN. Format: 'IP/prefix'. Example: '10.0.0.0/8'",
+                "ipv6-network": {
+                    "description": "The pool of IPv6 addresses. Example: 'fda9:4efe:7e3b:03ea::/48'",
                     "type": "string"
                 },
+                "ipv6-subnet-prefix": {
+                    "description": "Prefix of the IPv6 subnet given to each client. Example: 64",
+                    "type": "integer",
+                    "maximum": 128,
+                    "minimum": 1
+                },
+                "isolate-workers": {
+                    "description": "Run each worker in a restricted sandbox. Example: true",
+                    "type": "boolean"
+                },
                 "keepalive": {
-                    "description": "Interval in seconds to send keep-alive pings. Example: 60",
-                    "type": "integer"
+                    "description": "Interval in seconds to send keep-alive pings. Example: 32400",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "listen-host": {
+                    "description": "Address to listen on, all addresses when empty. Example: '0.0.0.0'",
+                    "type": "string"
+                },
+                "listen-proxy-proto": {
+                    "description": "Expect the PROXY protocol header from a load balancer. Example: false",
+                    "type": "boolean"
+                },
+                "log-level": {
+                    "description": "Log verbosity, from 0 to 9. Example: 3",
+                    "type": "integer",
+                    "maximum": 9,
+                    "minimum": 0
+                },
+                "max-ban-score": {
+                    "description": "Ban score at which a client IP is banned, 0 disables banning. Example: 50",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max-clients": {
+                    "description": "Maximum number of connected clients, 0 for no limit. Example: 1024",
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "max-same-clients": {
-                    "description": "Maximum simultaneous logins per user. Example: 2",
-                    "type": "integer"
+                    "description": "Maximum simultaneous logins per user, 0 for no limit. Example: 2",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min-reauth-time": {
+                    "description": "Seconds a client must wait before reauthenticating after a failure. Example: 300",
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "mobile-dpd": {
-                    "description": "DPD timeout specifically for mobile clients. Example: 300",
-                    "type": "integer"
+                    "description": "DPD timeout for mobile clients. Example: 1800",
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "mobile-idle-timeout": {
-                    "description": "Idle timeout for mobile clients. Example: 900",
-                    "type": "integer"
+                    "description": "Idle timeout for mobile clients. Example: 2400",
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "mtu": {
-                    "description": "Tunnel interface MTU to avoid fragmentation. Example: 1400",
-                    "type": "integer"
-                },
-                "nbns": {
-                    "description": "NetBIOS Name Servers (WINS) for Windows clients. Example: '192.168.1.1'",
-                    "type": "string"
-                },
-                "net-priority": {
-                    "description": "Priority for routes; lower is higher priority. Example: 1",
-                    "type": "integer"
+                    "description": "Tunnel interface MTU. Example: 1420",
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 576
                 },
                 "no-route": {
-                    "description": "List of networks to exclude from VPN routing. Each entry should be in 'IP/prefix' format. Example: ['192.168.0.0/16', '10.0.0.0/8']",
+                    "description": "Networks excluded from the VPN routing. Example: ['192.168.0.0/16']",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "no-udp": {
-                    "description": "Disables UDP, enforcing TCP-only VPN connection. Example: true",
+                "pid-file": {
+                    "description": "Path of the server pid file. Example: '/var/run/ocserv.pid'",
+                    "type": "string"
+                },
+                "ping-leases": {
+                    "description": "Ping an address before leasing it. Example: false",
                     "type": "boolean"
                 },
-                "restrict-user-to-ports": {
-                    "description": "Comma-separated list of allowed (or blocked, if negated) protocols and ports. Supports 'tcp(port)', 'udp(port)', 'icmp()', 'icmpv6()', and negation with '!()'. Example: 'tcp(443), tcp(80), udp(53)', or '!(tcp(22), udp(1194))'",
+                "pre-login-banner": {
+                    "description": "Banner shown to the clients before login. Example: 'Authorized users only'",
                     "type": "string"
                 },
-                "restrict-user-to-routes": {
-                    "description": "Allow client access only to defined routes. Example: true",
+                "predictable-ips": {
+                    "description": "Give each user the same IP on every connection. Example: true",
                     "type": "boolean"
                 },
+                "rate-limit-ms": {
+                    "description": "Minimum time in milliseconds between two connections from the same IP. Example: 100",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "rekey-method": {
+                    "description": "Rekey method. Example: 'ssl'",
+                    "type": "string",
+                    "enum": [
+                        "ssl",
+                        "new-tunnel"
+                    ]
+                },
+                "rekey-time": {
+                    "description": "Seconds between TLS rekeys. Example: 172800",
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "route": {
-                    "description": "Routes pushed to the client for routing traffic. Example: ['0.0.0.0/0', '10.10.0.0/16']",
+                    "description": "Routes pushed to the clients, 'default' routes everything. Example: ['default']",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "rx-data-per-sec": {
-                    "description": "Maximum receive bandwidth in bytes per second. Example: '100000' for 100 KB/s",
-                    "type": "integer"
+                "run-as-group": {
+                    "description": "Group the workers run as. Example: 'daemon'",
+                    "type": "string"
+                },
+                "run-as-user": {
+                    "description": "User the workers run as. Example: 'nobody'",
+                    "type": "string"
+                },
+                "server-cert": {
+                    "description": "Path of the server certificate. Example: '/etc/ocserv/certs/cert.pem'",
+                    "type": "string"
+                },
+                "server-key": {
+                    "description": "Path of the server certificate key. Example: '/etc/ocserv/certs/cert.key'",
+                    "type": "string"
                 },
-                "session-timeout": {
-                    "description": "Max session time in seconds before forced disconnect. Example: 3600",
-                    "type": "integer"
+                "socket-file": {
+                    "description": "Path of the socket between the main process and the workers. Example: '/var/run/ocserv-socket'",
+                    "type": "string"
                 },
                 "split-dns": {
-                    "description": "List of domains over which the provided DNS servers should be used. Example: ['example.com', 'internal.company.com']",
+                    "description": "Domains over which the provided DNS servers should be used. Example: ['internal.company.com']",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "stats-report-time": {
-                    "description": "Interval in seconds for stats reporting. Example: 300",
-                    "type": "integer"
+                "switch-to-tcp-timeout": {
+                    "description": "Seconds without UDP traffic before switching to TCP. Example: 5",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "tcp-port": {
+                    "description": "TCP port to listen on. Example: 443",
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "tls-priorities": {
+                    "description": "GnuTLS priority string. Example: 'NORMAL:%SERVER_PRECEDENCE:%COMPAT:-VERS-SSL3.0'",
+                    "type": "string"
+                },
+                "try-mtu-discovery": {
+                    "description": "Discover the path MTU of each client. Example: true",
+                    "type": "boolean"
                 },
                 "tunnel-all-dns": {
                     "description": "Force all DNS traffic through the VPN tunnel. Example: true",
                     "type": "boolean"
                 },
-                "tx-data-per-sec": {
-                    "description": "Maximum transmit bandwidth in bytes per second. Example: '200000' for 200 KB/s",
-                    "type": "integer"
+                "udp-port": {
+                    "description": "UDP port to listen on for DTLS. Example: 443",
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "use-occtl": {
+                    "description": "Allow occtl to control the server. Example: true",
+                    "type": "boolean"
                 }
             }
         },
-        "models.OcservInfo": {
+        "models.OcservServerConfigRevision": {
             "type": "object",
-            "required": [
-                "status",
-                "version"
-            ],
             "properties": {
-                "status": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "reload",
+                        "restart",
+                        "restart_required"
+                    ]
+                },
+                "actor": {
                     "type": "string"
                 },
-                "version": {
-                    "$ref": "#/definitions/models.ServerVersion"
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservConfigChange"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
                 }
             }
         },
@@ -4119,6 +8293,7 @@ const docTemplate = `{
                 "group",
                 "is_locked",
                 "is_online",
+                "node",
                 "online_sessions",
                 "owner",
                 "password",
@@ -4127,9 +8302,23 @@ const docTemplate = `{
                 "traffic_type",
                 "tx",
                 "uid",
-                "username"
+                "username",
+                "vhost"
             ],
             "properties": {
+                "auth_locked_until": {
+                    "description": "temporary lock after failed logins",
+                    "type": "string"
+                },
+                "auth_mode": {
+                    "description": "empty inherits the group mode",
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
                 "certificate": {
                     "$ref": "#/definitions/models.OcservUserCertificateBackup"
                 },
@@ -4139,6 +8328,9 @@ const docTemplate = `{
                 "certificate_enabled": {
                     "type": "boolean"
                 },
+                "certificate_expires_at": {
+                    "type": "string"
+                },
                 "config": {
                     "$ref": "#/definitions/models.OcservUserConfig"
                 },
@@ -4163,6 +8355,9 @@ const docTemplate = `{
                 "is_online": {
                     "type": "boolean"
                 },
+                "node": {
+                    "type": "string"
+                },
                 "online_sessions": {
                     "type": "array",
                     "items": {
@@ -4207,6 +8402,9 @@ const docTemplate = `{
                 },
                 "username": {
                     "type": "string"
+                },
+                "vhost": {
+                    "type": "string"
                 }
             }
         },
@@ -4333,6 +8531,144 @@ const docTemplate = `{
                 }
             }
         },
+        "models.OcservVHost": {
+            "type": "object",
+            "required": [
+                "name",
+                "owner"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservVHostConfig"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OcservVHostConfig": {
+            "type": "object",
+            "required": [
+                "auth",
+                "enable-auth"
+            ],
+            "properties": {
+                "auth": {
+                    "description": "Authentication methods, the first one is the primary. Password logins\nmust use the ocpasswd file of the dashboard. Example: ['plain[passwd=/etc/ocserv/ocpasswd]', 'certificate']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "banner": {
+                    "description": "Message shown to the clients after login. Example: 'Welcome to corp'",
+                    "type": "string"
+                },
+                "ca-cert": {
+                    "description": "Path of the CA bundle client certificates are checked against. Example: '/etc/ocserv/ssl/corp-ca.pem'",
+                    "type": "string"
+                },
+                "cert-user-oid": {
+                    "description": "Certificate field holding the username. Example: '2.5.4.3'",
+                    "type": "string"
+                },
+                "default-domain": {
+                    "description": "Domain suffix pushed to the clients. Example: 'corp.example.com'",
+                    "type": "string"
+                },
+                "dns": {
+                    "description": "DNS servers pushed to the clients. Example: ['10.1.0.53']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enable-auth": {
+                    "description": "Additional authentication methods a client may use instead. Example: ['certificate']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "idle-timeout": {
+                    "description": "Time in seconds before disconnecting idle clients. Example: 600",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "ipv4-network": {
+                    "description": "The pool of addresses leases are given from. Example: '10.1.0.0/24'",
+                    "type": "string"
+                },
+                "max-clients": {
+                    "description": "Maximum number of connected clients, 0 for no limit. Example: 256",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max-same-clients": {
+                    "description": "Maximum simultaneous logins per user, 0 for no limit. Example: 2",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "mobile-idle-timeout": {
+                    "description": "Idle timeout in seconds for mobile clients. Example: 900",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "no-route": {
+                    "description": "Networks excluded from the VPN routes. Example: ['192.168.0.0/16']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "route": {
+                    "description": "Routes pushed to the clients. Example: ['10.1.0.0/16']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "server-cert": {
+                    "description": "Path of the server certificate of the virtual host. Example: '/etc/ocserv/certs/corp.pem'",
+                    "type": "string"
+                },
+                "server-key": {
+                    "description": "Path of the server certificate key. Example: '/etc/ocserv/certs/corp.key'",
+                    "type": "string"
+                },
+                "session-timeout": {
+                    "description": "Max session time in seconds before forced disconnect. Example: 3600",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "split-dns": {
+                    "description": "Domains resolved with the pushed DNS servers. Example: ['corp.example.com']",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tunnel-all-dns": {
+                    "description": "Force all DNS traffic through the VPN tunnel. Example: true",
+                    "type": "boolean"
+                }
+            }
+        },
         "models.OnlineUserSession": {
             "type": "object",
             "required": [
@@ -4370,6 +8706,10 @@ const docTemplate = `{
                 "_Last connected at": {
                     "type": "string"
                 },
+                "node": {
+                    "description": "Node is the node of the session, set by the dashboard",
+                    "type": "string"
+                },
                 "vhost": {
                     "type": "string"
                 }
@@ -4383,6 +8723,10 @@ const docTemplate = `{
                 },
                 "ocserv_version": {
                     "type": "string"
+                },
+                "semantic": {
+                    "description": "Semantic is the parsed ocserv release (e.g. 1.2.4), empty when unknown",
+                    "type": "string"
                 }
             }
         },
@@ -4459,18 +8803,140 @@ const docTemplate = `{
                 }
             }
         },
+        "node.CreateNodeData": {
+            "type": "object",
+            "required": [
+                "name",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Frankfurt"
+                },
+                "name": {
+                    "description": "Name must match AGENT_NODE_NAME of the agent, or its hostname.",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "de-fra-1"
+                },
+                "secret": {
+                    "description": "Secret is WEBHOOK_SECRET of the agent.",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "description": "URL of the agent, the webhook service of the node.",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "https://fra1.vpn.example.com:8888"
+                }
+            }
+        },
+        "node.NodeStatusResponse": {
+            "type": "object",
+            "required": [
+                "node"
+            ],
+            "properties": {
+                "agent": {
+                    "$ref": "#/definitions/models.AgentInfo"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "node": {
+                    "$ref": "#/definitions/models.OcservNode"
+                }
+            }
+        },
+        "node.NodesResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservNode"
+                    }
+                }
+            }
+        },
+        "node.UpdateNodeData": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
         "ocserv_group.CreateOcservGroupData": {
             "type": "object",
             "required": [
                 "config",
-                "name"
+                "name",
+                "templates"
             ],
             "properties": {
+                "auth_mode": {
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
                 "config": {
                     "$ref": "#/definitions/models.OcservGroupConfig"
                 },
                 "name": {
                     "type": "string"
+                },
+                "templates": {
+                    "description": "config template names, lowest precedence first",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "vhost": {
+                    "description": "virtual host the group and its users are listed under, the default one when empty",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "default"
                 }
             }
         },
@@ -4508,11 +8974,34 @@ const docTemplate = `{
         "ocserv_group.UpdateOcservGroupData": {
             "type": "object",
             "required": [
-                "config"
+                "config",
+                "templates"
             ],
             "properties": {
+                "auth_mode": {
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
                 "config": {
                     "$ref": "#/definitions/models.OcservGroupConfig"
+                },
+                "templates": {
+                    "description": "config template names, lowest precedence first",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "vhost": {
+                    "description": "lists the group and its users under another virtual host",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "default"
                 }
             }
         },
@@ -4535,6 +9024,14 @@ const docTemplate = `{
                 "username"
             ],
             "properties": {
+                "auth_mode": {
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
                 "config": {
                     "$ref": "#/definitions/models.OcservUserConfig"
                 },
@@ -4550,6 +9047,12 @@ const docTemplate = `{
                 "group": {
                     "type": "string"
                 },
+                "node": {
+                    "description": "Node the user is provisioned on, the local one by default.",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "local"
+                },
                 "password": {
                     "type": "string",
                     "maxLength": 32,
@@ -4582,6 +9085,12 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 32,
                     "minLength": 2
+                },
+                "vhost": {
+                    "description": "VHost the user is listed under, defaults to the virtual host of the\ngroup and must match it unless the group is defaults. It does not\nlimit the virtual hosts the user can connect to.",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "default"
                 }
             }
         },
@@ -4700,86 +9209,376 @@ const docTemplate = `{
                 }
             }
         },
-        "ocserv_user.UpdateOcservUserData": {
+        "ocserv_user.UpdateOcservUserData": {
+            "type": "object",
+            "properties": {
+                "auth_mode": {
+                    "description": "AuthMode set to an empty string inherits the group mode again.",
+                    "type": "string",
+                    "enum": [
+                        "password",
+                        "certificate",
+                        "both"
+                    ]
+                },
+                "config": {
+                    "$ref": "#/definitions/models.OcservUserConfig"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024,
+                    "example": "User for testing VPN access"
+                },
+                "expire_at": {
+                    "type": "string",
+                    "example": "2025-12-31"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 2
+                },
+                "traffic_size": {
+                    "description": "10 GiB",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10737418240
+                },
+                "traffic_type": {
+                    "type": "string",
+                    "enum": [
+                        "Free",
+                        "MonthlyTransmit",
+                        "MonthlyReceive",
+                        "MonthlyRxTx",
+                        "TotallyTransmit",
+                        "TotallyReceive",
+                        "TotallyRxTx"
+                    ]
+                },
+                "unlimited": {
+                    "type": "boolean",
+                    "default": false,
+                    "example": false
+                },
+                "vhost": {
+                    "description": "VHost lists the user under another virtual host. Without it, a user\nmoved to another group follows the group.",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "default"
+                }
+            }
+        },
+        "report.AuthAlertsResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservAuthAlert"
+                    }
+                }
+            }
+        },
+        "report.OcservUserReportResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "integer"
+                },
+                "deactivated": {
+                    "type": "integer"
+                },
+                "locked": {
+                    "type": "integer"
+                },
+                "online": {
+                    "type": "integer"
+                }
+            }
+        },
+        "report.SessionLogsResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservUserSessionLog"
+                    }
+                }
+            }
+        },
+        "repository.CAReissueResult": {
+            "type": "object",
+            "required": [
+                "reissued",
+                "username"
+            ],
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "reissued": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.ConfigRevisionDiff": {
+            "type": "object",
+            "required": [
+                "changes",
+                "from",
+                "to"
+            ],
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservConfigChange"
+                    }
+                },
+                "from": {
+                    "$ref": "#/definitions/models.OcservConfigRevision"
+                },
+                "to": {
+                    "$ref": "#/definitions/models.OcservConfigRevision"
+                }
+            }
+        },
+        "repository.DriftFixResult": {
+            "type": "object",
+            "required": [
+                "fixed",
+                "item"
+            ],
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fixed": {
+                    "type": "boolean"
+                },
+                "item": {
+                    "$ref": "#/definitions/drift.Item"
+                }
+            }
+        },
+        "repository.EffectiveGroupConfig": {
+            "type": "object",
+            "required": [
+                "config",
+                "values"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservGroupConfig"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.EffectiveConfigValue"
+                    }
+                }
+            }
+        },
+        "repository.EffectiveUserConfig": {
+            "type": "object",
+            "required": [
+                "group",
+                "sources",
+                "username",
+                "values"
+            ],
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.EffectiveConfigValue"
+                    }
+                }
+            }
+        },
+        "repository.ExpiringCertificate": {
+            "type": "object",
+            "required": [
+                "certificate_expires_at",
+                "owner",
+                "uid",
+                "username"
+            ],
+            "properties": {
+                "certificate_expires_at": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.IPAMAddress": {
+            "type": "object",
+            "required": [
+                "address",
+                "kind",
+                "owner"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "static",
+                        "session"
+                    ]
+                },
+                "owner": {
+                    "description": "username, or group:name for the explicit-ipv4 of a group",
+                    "type": "string"
+                }
+            }
+        },
+        "repository.IPAMConflict": {
+            "type": "object",
+            "required": [
+                "address",
+                "owners",
+                "reason"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "owners": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.IPAMPool": {
             "type": "object",
+            "required": [
+                "free",
+                "network",
+                "sessions",
+                "size",
+                "sources",
+                "static",
+                "utilization"
+            ],
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/models.OcservUserConfig"
-                },
-                "description": {
-                    "type": "string",
-                    "maxLength": 1024,
-                    "example": "User for testing VPN access"
+                "free": {
+                    "type": "integer"
                 },
-                "expire_at": {
-                    "type": "string",
-                    "example": "2025-12-31"
+                "network": {
+                    "type": "string"
                 },
-                "group": {
-                    "type": "string",
-                    "example": "default"
+                "sessions": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 2
+                "size": {
+                    "type": "integer"
                 },
-                "traffic_size": {
-                    "description": "10 GiB",
-                    "type": "integer",
-                    "minimum": 0,
-                    "example": 10737418240
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "traffic_type": {
-                    "type": "string",
-                    "enum": [
-                        "Free",
-                        "MonthlyTransmit",
-                        "MonthlyReceive",
-                        "MonthlyRxTx",
-                        "TotallyTransmit",
-                        "TotallyReceive",
-                        "TotallyRxTx"
-                    ]
+                "static": {
+                    "type": "integer"
                 },
-                "unlimited": {
-                    "type": "boolean",
-                    "default": false,
-                    "example": false
+                "utilization": {
+                    "description": "percent of the usable addresses in use",
+                    "type": "number"
                 }
             }
         },
-        "report.OcservUserReportResponse": {
+        "repository.RepeatedIPBan": {
             "type": "object",
+            "required": [
+                "active",
+                "bans",
+                "ip",
+                "last_banned_at"
+            ],
             "properties": {
                 "active": {
-                    "type": "integer"
+                    "type": "boolean"
                 },
-                "deactivated": {
+                "bans": {
                     "type": "integer"
                 },
-                "locked": {
-                    "type": "integer"
+                "ip": {
+                    "type": "string"
                 },
-                "online": {
-                    "type": "integer"
+                "last_banned_at": {
+                    "type": "string"
                 }
             }
         },
-        "report.SessionLogsResponse": {
+        "repository.ServerConfigPreview": {
             "type": "object",
             "required": [
-                "meta"
+                "changes",
+                "content",
+                "restart_required"
             ],
             "properties": {
-                "meta": {
-                    "$ref": "#/definitions/request.Meta"
-                },
-                "result": {
+                "changes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.OcservUserSessionLog"
+                        "$ref": "#/definitions/models.OcservConfigChange"
                     }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "restart_required": {
+                    "type": "boolean"
                 }
             }
         },
@@ -4815,6 +9614,49 @@ const docTemplate = `{
                 }
             }
         },
+        "repository.VHostUsage": {
+            "type": "object",
+            "required": [
+                "active",
+                "deactivated",
+                "groups",
+                "locked",
+                "online",
+                "rx",
+                "tx",
+                "users",
+                "vhost"
+            ],
+            "properties": {
+                "active": {
+                    "type": "integer"
+                },
+                "deactivated": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "type": "integer"
+                },
+                "locked": {
+                    "type": "integer"
+                },
+                "online": {
+                    "type": "integer"
+                },
+                "rx": {
+                    "type": "number"
+                },
+                "tx": {
+                    "type": "number"
+                },
+                "users": {
+                    "type": "integer"
+                },
+                "vhost": {
+                    "type": "string"
+                }
+            }
+        },
         "request.ErrorResponse": {
             "type": "object",
             "required": [
@@ -4855,6 +9697,23 @@ const docTemplate = `{
                 }
             }
         },
+        "server_config.RevisionsResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservServerConfigRevision"
+                    }
+                }
+            }
+        },
         "system.ChangeUserPassword": {
             "type": "object",
             "required": [
@@ -5193,16 +10052,200 @@ const docTemplate = `{
                 }
             }
         },
+        "user.CAInfo": {
+            "type": "object",
+            "required": [
+                "certificates",
+                "not_after",
+                "not_before",
+                "serial",
+                "state",
+                "subject"
+            ],
+            "properties": {
+                "certificates": {
+                    "description": "Certificates counts the active and suspended user certificates the\nCA issued.",
+                    "type": "integer"
+                },
+                "not_after": {
+                    "type": "string"
+                },
+                "not_before": {
+                    "type": "string"
+                },
+                "serial": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "retiring"
+                    ]
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.CAStatus": {
+            "type": "object",
+            "required": [
+                "cas",
+                "in_progress",
+                "pending"
+            ],
+            "properties": {
+                "cas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.CAInfo"
+                    }
+                },
+                "in_progress": {
+                    "type": "boolean"
+                },
+                "pending": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "user.CertificateRecord": {
+            "type": "object",
+            "required": [
+                "expired",
+                "expires_at",
+                "issued_at",
+                "serial",
+                "state",
+                "subject",
+                "username"
+            ],
+            "properties": {
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "issuer_serial": {
+                    "description": "IssuerSerial is the serial of the trusted CA that issued the\ncertificate, empty when that CA was retired.",
+                    "type": "string"
+                },
+                "serial": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "revoked"
+                    ]
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "user.Ocpasswd": {
             "type": "object",
             "properties": {
                 "group": {
                     "type": "string"
                 },
+                "locked": {
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 }
             }
+        },
+        "utils.EffectiveConfigValue": {
+            "type": "object",
+            "required": [
+                "key",
+                "source",
+                "value"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "overrides": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "vhost.CreateVHostData": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservVHostConfig"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "name": {
+                    "description": "Name is the host name clients connect to, matched against the TLS SNI.",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "vpn.corp.example.com"
+                }
+            }
+        },
+        "vhost.UpdateVHostData": {
+            "type": "object",
+            "required": [
+                "config"
+            ],
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.OcservVHostConfig"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024
+                }
+            }
+        },
+        "vhost.VHostsResponse": {
+            "type": "object",
+            "required": [
+                "meta"
+            ],
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/request.Meta"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OcservVHost"
+                    }
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration012 = &gormigrate.Migration{
+	ID: "012_create_ocserv_actions",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_actions (
+				id BIGSERIAL PRIMARY KEY,
+				action VARCHAR(16) NOT NULL,
+				username VARCHAR(255) NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT DEFAULT '',
+				next_attempt_at TIMESTAMPTZ NOT NULL,
+				claimed_until TIMESTAMPTZ NULL,
+				failed_at TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_actions_username ON ocserv_actions (username);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_actions_next_attempt_at ON ocserv_actions (next_attempt_at);
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 012 (ocserv_actions) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS ocserv_actions;`).Error
+	},
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration013 = &gormigrate.Migration{
+	ID: "013_create_ocserv_ip_bans",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_ip_bans (
+				id BIGSERIAL PRIMARY KEY,
+				ip VARCHAR(64) NOT NULL,
+				reason TEXT DEFAULT '',
+				source VARCHAR(16) NOT NULL DEFAULT 'manual',
+				owner VARCHAR(255) DEFAULT '',
+				expires_at TIMESTAMPTZ NULL,
+				unbanned_at TIMESTAMPTZ NULL,
+				unbanned_by VARCHAR(255) DEFAULT '',
+				unban_reason TEXT DEFAULT '',
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_ip_bans_ip ON ocserv_ip_bans (ip);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_ip_bans_unbanned_at ON ocserv_ip_bans (unbanned_at);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_ocserv_ip_bans_active_ip ON ocserv_ip_bans (ip) WHERE unbanned_at IS NULL;
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 013 (ocserv_ip_bans) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS ocserv_ip_bans;`).Error
+	},
+}
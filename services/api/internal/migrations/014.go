@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration014 = &gormigrate.Migration{
+	ID: "014_create_ocserv_auth_alerts",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_users ADD COLUMN IF NOT EXISTS auth_locked_until TIMESTAMPTZ NULL;
+
+			CREATE TABLE IF NOT EXISTS ocserv_auth_alerts (
+				id BIGSERIAL PRIMARY KEY,
+				kind VARCHAR(8) NOT NULL,
+				target VARCHAR(255) NOT NULL,
+				failures INTEGER NOT NULL,
+				window_seconds INTEGER NOT NULL,
+				actions TEXT DEFAULT '',
+				notify BOOLEAN NOT NULL DEFAULT FALSE,
+				notified_at TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_auth_alerts_target ON ocserv_auth_alerts (target);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_auth_alerts_pending ON ocserv_auth_alerts (id) WHERE notify AND notified_at IS NULL;
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 014 (ocserv_auth_alerts) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP TABLE IF EXISTS ocserv_auth_alerts;
+			ALTER TABLE ocserv_users DROP COLUMN IF EXISTS auth_locked_until;
+		`).Error
+	},
+}
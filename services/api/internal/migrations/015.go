@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration015 = &gormigrate.Migration{
+	ID: "015_add_certificate_expiry",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_users ADD COLUMN IF NOT EXISTS certificate_expires_at TIMESTAMPTZ NULL;
+			CREATE INDEX IF NOT EXISTS idx_ocserv_users_certificate_expires_at ON ocserv_users (certificate_expires_at);
+
+			ALTER TABLE telegram_accounts ADD COLUMN IF NOT EXISTS certificate_expires_at TIMESTAMPTZ NULL;
+			ALTER TABLE telegram_accounts ADD COLUMN IF NOT EXISTS last_certificate_notified_at TIMESTAMPTZ NULL;
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 015 (certificate expiry) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE telegram_accounts DROP COLUMN IF EXISTS last_certificate_notified_at;
+			ALTER TABLE telegram_accounts DROP COLUMN IF EXISTS certificate_expires_at;
+			DROP INDEX IF EXISTS idx_ocserv_users_certificate_expires_at;
+			ALTER TABLE ocserv_users DROP COLUMN IF EXISTS certificate_expires_at;
+		`).Error
+	},
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration016 = &gormigrate.Migration{
+	ID: "016_add_auth_mode",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_users ADD COLUMN IF NOT EXISTS auth_mode VARCHAR(16) NOT NULL DEFAULT '';
+			ALTER TABLE ocserv_groups ADD COLUMN IF NOT EXISTS auth_mode VARCHAR(16) NOT NULL DEFAULT '';
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 016 (auth mode) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE ocserv_groups DROP COLUMN IF EXISTS auth_mode;
+			ALTER TABLE ocserv_users DROP COLUMN IF EXISTS auth_mode;
+		`).Error
+	},
+}
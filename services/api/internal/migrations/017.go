@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration017 = &gormigrate.Migration{
+	ID: "017_create_ocserv_server_config_revisions",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_server_config_revisions (
+				id BIGSERIAL PRIMARY KEY,
+				content TEXT NOT NULL,
+				changes JSON,
+				action VARCHAR(16) NOT NULL,
+				actor VARCHAR(32) NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 017 (ocserv_server_config_revisions) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS ocserv_server_config_revisions;`).Error
+	},
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration018 = &gormigrate.Migration{
+	ID: "018_create_ocserv_config_revisions",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_config_revisions (
+				id BIGSERIAL PRIMARY KEY,
+				kind VARCHAR(16) NOT NULL,
+				target VARCHAR(255) NOT NULL,
+				config JSON,
+				changes JSON,
+				action VARCHAR(16) NOT NULL,
+				restored_from BIGINT,
+				actor VARCHAR(32) NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+		`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			CREATE INDEX IF NOT EXISTS idx_ocserv_config_revisions_kind_target
+			ON ocserv_config_revisions(kind, target);
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 018 (ocserv_config_revisions) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS ocserv_config_revisions;`).Error
+	},
+}
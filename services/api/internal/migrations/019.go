@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration019 = &gormigrate.Migration{
+	ID: "019_create_ocserv_config_templates",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_config_templates (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL UNIQUE,
+				description TEXT NOT NULL DEFAULT '',
+				owner VARCHAR(32) NOT NULL DEFAULT '',
+				config JSON,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+		`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_groups ADD COLUMN IF NOT EXISTS templates JSON NOT NULL DEFAULT '[]';
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 019 (ocserv_config_templates) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE ocserv_groups DROP COLUMN IF EXISTS templates;
+			DROP TABLE IF EXISTS ocserv_config_templates;
+		`).Error
+	},
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration020 = &gormigrate.Migration{
+	ID: "020_create_ocserv_vhosts",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_vhosts (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(64) NOT NULL UNIQUE,
+				description TEXT NOT NULL DEFAULT '',
+				owner VARCHAR(32) NOT NULL DEFAULT '',
+				config JSON,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+		`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_groups ADD COLUMN IF NOT EXISTS vhost VARCHAR(64) NOT NULL DEFAULT 'default';
+			ALTER TABLE ocserv_users ADD COLUMN IF NOT EXISTS vhost VARCHAR(64) NOT NULL DEFAULT 'default';
+		`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			CREATE INDEX IF NOT EXISTS idx_ocserv_groups_vhost ON ocserv_groups (vhost);
+			CREATE INDEX IF NOT EXISTS idx_ocserv_users_vhost ON ocserv_users (vhost);
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 020 (ocserv_vhosts) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP INDEX IF EXISTS idx_ocserv_users_vhost;
+			DROP INDEX IF EXISTS idx_ocserv_groups_vhost;
+			ALTER TABLE ocserv_users DROP COLUMN IF EXISTS vhost;
+			ALTER TABLE ocserv_groups DROP COLUMN IF EXISTS vhost;
+			DROP TABLE IF EXISTS ocserv_vhosts;
+		`).Error
+	},
+}
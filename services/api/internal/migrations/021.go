@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration021 = &gormigrate.Migration{
+	ID: "021_create_ocserv_nodes",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS ocserv_nodes (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(64) NOT NULL UNIQUE,
+				location VARCHAR(128) NOT NULL DEFAULT '',
+				description TEXT NOT NULL DEFAULT '',
+				url VARCHAR(255) NOT NULL,
+				secret VARCHAR(255) NOT NULL,
+				enabled BOOLEAN NOT NULL DEFAULT TRUE,
+				version VARCHAR(64) NOT NULL DEFAULT '',
+				last_seen_at TIMESTAMPTZ,
+				last_error TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);
+		`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_users ADD COLUMN IF NOT EXISTS node VARCHAR(64) NOT NULL DEFAULT 'local';
+			CREATE INDEX IF NOT EXISTS idx_ocserv_users_node ON ocserv_users (node);
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 021 (ocserv_nodes) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP INDEX IF EXISTS idx_ocserv_users_node;
+			ALTER TABLE ocserv_users DROP COLUMN IF EXISTS node;
+			DROP TABLE IF EXISTS ocserv_nodes;
+		`).Error
+	},
+}
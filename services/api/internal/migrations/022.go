@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var Migration022 = &gormigrate.Migration{
+	ID: "022_add_ocserv_ip_bans_node",
+
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			ALTER TABLE ocserv_ip_bans ADD COLUMN IF NOT EXISTS node VARCHAR(64) NOT NULL DEFAULT 'local';
+			CREATE INDEX IF NOT EXISTS idx_ocserv_ip_bans_node ON ocserv_ip_bans (node);
+		`).Error; err != nil {
+			return err
+		}
+
+		logger.Info("migration 022 (ocserv_ip_bans node) complete successfully")
+		return nil
+	},
+
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP INDEX IF EXISTS idx_ocserv_ip_bans_node;
+			ALTER TABLE ocserv_ip_bans DROP COLUMN IF EXISTS node;
+		`).Error
+	},
+}
@@ -3,15 +3,24 @@ package routing
 import (
 	"github.com/labstack/echo/v4"
 	backupRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/backup"
+	caRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/certificate_authority"
+	configRevisionRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/config_revision"
+	configTemplateRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/config_template"
 	customerRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/customer"
+	driftRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/drift"
 	homeRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/home"
+	ipBanRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/ip_ban"
+	ipamRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/ipam"
+	nodeRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/node"
 	occtlRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/occtl"
 	ocservGroupRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/ocserv_group"
 	ocservUserRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/ocserv_user"
 	reportRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/report"
+	serverConfigRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/server_config"
 	systemRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/system"
 	systemdRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/systemd"
 	telegramRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/telegram"
+	vhostRoutes "github.com/mmtaee/ocserv-dashboard/api/internal/services/vhost"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/config"
 	"os"
 )
@@ -28,6 +37,33 @@ func Register(e *echo.Echo) {
 	// backup
 	backupRoutes.Routes(group)
 
+	// certificate authority
+	caRoutes.Routes(group)
+
+	// ocserv.conf
+	serverConfigRoutes.Routes(group)
+
+	// group and user config history
+	configRevisionRoutes.Routes(group)
+
+	// group config templates
+	configTemplateRoutes.Routes(group)
+
+	// virtual hosts
+	vhostRoutes.Routes(group)
+
+	// ip address management
+	ipamRoutes.Routes(group)
+
+	// vpn nodes
+	nodeRoutes.Routes(group)
+
+	// ip bans
+	ipBanRoutes.Routes(group)
+
+	// drift
+	driftRoutes.Routes(group)
+
 	// customers
 	customerRoutes.Routes(group)
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/group"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
@@ -18,6 +19,7 @@ type BackupRepository struct {
 	db                    *gorm.DB
 	commonOcservGroupRepo group.OcservGroupInterface
 	commonOcservUserRepo  user.OcservUserInterface
+	nodes                 *occtlDocker.NodeClients
 }
 
 type BackupRepositoryInterface interface {
@@ -28,10 +30,12 @@ type BackupRepositoryInterface interface {
 }
 
 func NewBackupRepository() *BackupRepository {
+	db := database.GetConnection()
 	return &BackupRepository{
-		db:                    database.GetConnection(),
+		db:                    db,
 		commonOcservGroupRepo: group.NewOcservGroup(),
-		commonOcservUserRepo:  user.NewOcservUser(),
+		commonOcservUserRepo:  occtlDocker.NewOcservUserClient(),
+		nodes:                 occtlDocker.NewNodeClients(db),
 	}
 }
 
@@ -164,11 +168,12 @@ func (b *BackupRepository) OcservGroupRestore(ctx context.Context, owner string,
 					return nil
 				}
 
-				if err = b.commonOcservGroupRepo.Create(g.Name, g.Config); err != nil {
+				// templated groups need their templates restored first
+				effective, err := groupEffectiveConfig(tx, &g)
+				if err != nil {
 					return err
 				}
-
-				return nil
+				return b.commonOcservGroupRepo.Create(g.Name, effective.Config)
 			})
 
 			if txErr != nil {
@@ -215,7 +220,11 @@ func (b *BackupRepository) OcservUserBackup(ctx context.Context, writer io.Write
 			return err
 		}
 
-		cert, certErr := b.commonOcservUserRepo.CertificateBackup(user.Username)
+		userClient, _, certErr := nodeClients(ctx, b.nodes, user.Node, b.commonOcservUserRepo, nil)
+		if certErr != nil {
+			return certErr
+		}
+		cert, certErr := userClient.CertificateBackup(user.Username)
 		if certErr != nil {
 			return certErr
 		}
@@ -298,6 +307,13 @@ func (b *BackupRepository) OcservUserRestore(ctx context.Context, owner string,
 			if u.Owner == "" {
 				u.Owner = owner
 			}
+			u.Node = models.NodeName(u.Node)
+
+			userClient, _, clientErr := nodeClients(ctx, b.nodes, u.Node, b.commonOcservUserRepo, nil)
+			if clientErr != nil {
+				errCh <- fmt.Errorf("user %s: %w", u.Username, clientErr)
+				return
+			}
 
 			txErr := b.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 				res := tx.Create(&u)
@@ -308,13 +324,13 @@ func (b *BackupRepository) OcservUserRestore(ctx context.Context, owner string,
 					return nil
 				}
 
-				if err = b.commonOcservUserRepo.Create(u.Group, u.Username, u.Password, u.Config); err != nil {
+				if err := userClient.Create(u.Group, u.Username, u.Password, u.Config); err != nil {
 					return err
 				}
 
 				if u.Certificate != nil {
-					if err = b.commonOcservUserRepo.RestoreCertificateBackup(u.Username, u.Certificate); err != nil {
-						_, _ = b.commonOcservUserRepo.Delete(u.Username)
+					if err := userClient.RestoreCertificateBackup(u.Username, u.Certificate); err != nil {
+						_, _ = userClient.Delete(u.Username)
 						return err
 					}
 				}
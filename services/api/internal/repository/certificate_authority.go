@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"gorm.io/gorm"
+)
+
+type CertificateAuthorityRepository struct {
+	db                   *gorm.DB
+	commonOcservUserRepo user.OcservUserInterface
+	ocservUserRepo       *OcservUserRepository
+}
+
+// CAReissueResult is the outcome of reissuing the certificate of a user.
+type CAReissueResult struct {
+	Username string `json:"username" validate:"required"`
+	Reissued bool   `json:"reissued" validate:"required"`
+	Error    string `json:"error,omitempty" validate:"omitempty"`
+}
+
+// reissueFailures parks the users whose reissue failed under the active CA
+// of the time, so a user that keeps failing does not hold up the batches
+// behind it. The map is keyed by username and holds the active CA serial.
+var reissueFailures = struct {
+	sync.Mutex
+	users map[string]string
+}{users: make(map[string]string)}
+
+type CertificateAuthorityRepositoryInterface interface {
+	Status(ctx context.Context) (*user.CAStatus, error)
+	Rotate(ctx context.Context) (*user.CAStatus, error)
+	Reissue(ctx context.Context, batch int, retryFailed bool) ([]CAReissueResult, int, []string, error)
+	Retire(ctx context.Context, force bool) (*user.CAStatus, error)
+	Certificates(ctx context.Context, pagination *request.Pagination, state, q string) ([]user.CertificateRecord, int, error)
+	CACertificate(ctx context.Context) ([]byte, error)
+	CRL(ctx context.Context) ([]byte, error)
+}
+
+func NewCertificateAuthorityRepository() *CertificateAuthorityRepository {
+	return &CertificateAuthorityRepository{
+		db:                   database.GetConnection(),
+		commonOcservUserRepo: occtlDocker.NewOcservUserClient(),
+		ocservUserRepo:       NewtOcservUserRepository(),
+	}
+}
+
+func (r *CertificateAuthorityRepository) Status(_ context.Context) (*user.CAStatus, error) {
+	return r.commonOcservUserRepo.CAStatus()
+}
+
+// Rotate starts a CA rollover.
+func (r *CertificateAuthorityRepository) Rotate(ctx context.Context) (*user.CAStatus, error) {
+	if err := r.commonOcservUserRepo.RotateCA(); err != nil {
+		return nil, err
+	}
+	return r.Status(ctx)
+}
+
+// Reissue moves up to batch users still on a retiring CA to the active CA.
+// Users whose reissue failed are parked and skipped until retryFailed is
+// set. It returns the outcome per user, the number of users left besides
+// the parked ones and the parked users.
+func (r *CertificateAuthorityRepository) Reissue(ctx context.Context, batch int, retryFailed bool) ([]CAReissueResult, int, []string, error) {
+	status, err := r.commonOcservUserRepo.CAStatus()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	active := status.CAs[0].Serial
+
+	reissueFailures.Lock()
+	defer reissueFailures.Unlock()
+
+	if retryFailed {
+		clear(reissueFailures.users)
+	}
+
+	var pending []string
+	for _, username := range status.Pending {
+		if serial, ok := reissueFailures.users[username]; ok && serial == active {
+			continue
+		}
+		pending = append(pending, username)
+	}
+
+	remaining := len(pending)
+	if len(pending) > batch {
+		pending = pending[:batch]
+	}
+
+	results := make([]CAReissueResult, 0, len(pending))
+	for _, username := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+
+		result := CAReissueResult{Username: username}
+		if err = r.reissue(ctx, username, &result); err != nil {
+			result.Error = err.Error()
+			reissueFailures.users[username] = active
+		} else {
+			delete(reissueFailures.users, username)
+		}
+		// a failed user is parked, so it no longer counts either
+		remaining--
+		results = append(results, result)
+	}
+
+	parked := make([]string, 0)
+	for _, username := range status.Pending {
+		if serial, ok := reissueFailures.users[username]; ok && serial == active {
+			parked = append(parked, username)
+		}
+	}
+	sort.Strings(parked)
+	return results, remaining, parked, nil
+}
+
+func (r *CertificateAuthorityRepository) reissue(ctx context.Context, username string, result *CAReissueResult) error {
+	var ocservUser models.OcservUser
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&ocservUser).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found in the database")
+		}
+		return err
+	}
+
+	reissued, err := r.commonOcservUserRepo.ReissueCertificate(ocservUser.Username, ocservUser.Password)
+	result.Reissued = reissued
+	if err != nil {
+		return err
+	}
+
+	// the customer is told about the new certificate through the bot
+	r.ocservUserRepo.recordCertificateExpiry(ctx, &ocservUser)
+	return nil
+}
+
+// Retire ends the rollover, see user.OcservUser.RetireCA.
+func (r *CertificateAuthorityRepository) Retire(ctx context.Context, force bool) (*user.CAStatus, error) {
+	if err := r.commonOcservUserRepo.RetireCA(force); err != nil {
+		return nil, err
+	}
+	return r.Status(ctx)
+}
+
+// Certificates lists the issued certificates, optionally only those in
+// state or whose username or serial contains q.
+func (r *CertificateAuthorityRepository) Certificates(
+	_ context.Context,
+	pagination *request.Pagination,
+	state, q string,
+) ([]user.CertificateRecord, int, error) {
+	records, err := r.commonOcservUserRepo.CertificateInventory()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = strings.ToLower(strings.TrimSpace(q))
+	filtered := make([]user.CertificateRecord, 0, len(records))
+	for _, record := range records {
+		if state != "" && record.State != state {
+			continue
+		}
+		if q != "" && !strings.Contains(strings.ToLower(record.Username), q) && !strings.Contains(record.Serial, q) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+
+	total := len(filtered)
+	start := (pagination.Page - 1) * pagination.PageSize
+	if start >= total {
+		return []user.CertificateRecord{}, total, nil
+	}
+
+	end := start + pagination.PageSize
+	if end > total {
+		end = total
+	}
+	return filtered[start:end], total, nil
+}
+
+func (r *CertificateAuthorityRepository) CACertificate(_ context.Context) ([]byte, error) {
+	return r.commonOcservUserRepo.CACertificate()
+}
+
+func (r *CertificateAuthorityRepository) CRL(_ context.Context) ([]byte, error) {
+	return r.commonOcservUserRepo.CRL()
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// ErrConfigRevisionTarget is returned when two revisions of different
+// groups or users are compared.
+var ErrConfigRevisionTarget = errors.New("the revisions belong to different targets")
+
+// ConfigRevisionDiff is the change of a config from one revision to another.
+type ConfigRevisionDiff struct {
+	From    models.OcservConfigRevision `json:"from" validate:"required"`
+	To      models.OcservConfigRevision `json:"to" validate:"required"`
+	Changes models.OcservConfigChanges  `json:"changes" validate:"required"`
+}
+
+// ConfigChange names the admin or staff member changing a group, defaults
+// group or user config and, for a restore, the revision restored. The
+// repositories writing the config record it as a revision in the same
+// transaction.
+type ConfigChange struct {
+	Actor        string
+	RestoredFrom *uint
+}
+
+type ConfigRevisionRepository struct {
+	db              *gorm.DB
+	ocservGroupRepo OcservGroupRepositoryInterface
+	ocservUserRepo  OcservUserRepositoryInterface
+}
+
+type ConfigRevisionRepositoryInterface interface {
+	Revisions(ctx context.Context, pagination *request.Pagination, kind, target string) (*[]models.OcservConfigRevision, int64, error)
+	Revision(ctx context.Context, id uint) (*models.OcservConfigRevision, error)
+	Diff(ctx context.Context, fromID, toID uint) (*ConfigRevisionDiff, error)
+	Restore(ctx context.Context, id uint, actor string) (*models.OcservConfigRevision, error)
+}
+
+func NewConfigRevisionRepository() *ConfigRevisionRepository {
+	return &ConfigRevisionRepository{
+		db:              database.GetConnection(),
+		ocservGroupRepo: NewOcservGroupRepository(),
+		ocservUserRepo:  NewtOcservUserRepository(),
+	}
+}
+
+// Revisions lists the config revisions, newest first by default, optionally
+// of one kind and target only.
+func (r *ConfigRevisionRepository) Revisions(
+	ctx context.Context, pagination *request.Pagination, kind, target string,
+) (*[]models.OcservConfigRevision, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservConfigRevision{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []models.OcservConfigRevision
+	if err := request.Paginator(ctx, query, pagination).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return &revisions, totalRecords, nil
+}
+
+func (r *ConfigRevisionRepository) Revision(ctx context.Context, id uint) (*models.OcservConfigRevision, error) {
+	var revision models.OcservConfigRevision
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// Diff compares two revisions of the same group, defaults group or user.
+func (r *ConfigRevisionRepository) Diff(ctx context.Context, fromID, toID uint) (*ConfigRevisionDiff, error) {
+	from, err := r.Revision(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := r.Revision(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Kind != to.Kind || from.Target != to.Target {
+		return nil, ErrConfigRevisionTarget
+	}
+
+	return &ConfigRevisionDiff{
+		From:    *from,
+		To:      *to,
+		Changes: utils.ConfigChanges(from.Config, to.Config),
+	}, nil
+}
+
+// Restore applies the config of a revision to its target again: the config
+// file is rewritten and ocserv reloaded, or the previous file is put back
+// when ocserv refuses the reload. The restore is recorded as a new revision.
+func (r *ConfigRevisionRepository) Restore(ctx context.Context, id uint, actor string) (*models.OcservConfigRevision, error) {
+	revision, err := r.Revision(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change := ConfigChange{Actor: actor, RestoredFrom: &revision.ID}
+
+	switch revision.Kind {
+	case models.OcservConfigRevisionGroup:
+		var ocservGroup models.OcservGroup
+		if err = r.db.WithContext(ctx).Where("name = ?", revision.Target).First(&ocservGroup).Error; err != nil {
+			return nil, err
+		}
+		ocservGroup.Config = nil
+		if revision.Config != nil {
+			ocservGroup.Config = &models.OcservGroupConfig{}
+			if err = utils.DecodeConfig(revision.Config, ocservGroup.Config); err != nil {
+				return nil, err
+			}
+		}
+		if _, err = r.ocservGroupRepo.Update(ctx, &ocservGroup, change); err != nil {
+			return nil, err
+		}
+
+	case models.OcservConfigRevisionDefaults:
+		groupConfig := &models.OcservGroupConfig{}
+		if err = utils.DecodeConfig(revision.Config, groupConfig); err != nil {
+			return nil, err
+		}
+		if err = r.ocservGroupRepo.UpdateDefaultGroup(ctx, groupConfig, change); err != nil {
+			return nil, err
+		}
+
+	case models.OcservConfigRevisionUser:
+		ocservUser, err := r.ocservUserRepo.GetByUsername(ctx, revision.Target)
+		if err != nil {
+			return nil, err
+		}
+		ocservUser.Config = nil
+		if revision.Config != nil {
+			ocservUser.Config = &models.OcservUserConfig{}
+			if err = utils.DecodeConfig(revision.Config, ocservUser.Config); err != nil {
+				return nil, err
+			}
+		}
+		// only the config file is restored, rewriting the credentials
+		// would unlock a locked user
+		if _, err = r.ocservUserRepo.UpdateConfig(ctx, ocservUser, change); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown config revision kind %q", revision.Kind)
+	}
+
+	// the restore always stores a revision, the newest one of the target
+	return latestConfigRevision(r.db.WithContext(ctx), revision.Kind, revision.Target)
+}
+
+// recordConfigRevision stores config as a new revision of target in tx,
+// with its changes against the latest revision. An update that changes
+// nothing is not stored, the latest revision is returned instead; a
+// restore always is. The revisions of a target are serialized until tx
+// ends, so concurrent saves each see the revision stored before them.
+func recordConfigRevision(
+	tx *gorm.DB, kind, target string, config interface{}, change ConfigChange,
+) (*models.OcservConfigRevision, error) {
+	revision := &models.OcservConfigRevision{
+		Kind:   kind,
+		Target: target,
+		Config: utils.ToMap(config),
+		Action: models.OcservConfigRevisionUpdate,
+		Actor:  change.Actor,
+	}
+	if change.RestoredFrom != nil {
+		revision.Action = models.OcservConfigRevisionRestore
+		revision.RestoredFrom = change.RestoredFrom
+	}
+
+	// a row lock can not cover the first revision of a target, the
+	// advisory lock exists whether or not the target has revisions
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "config_revision:"+kind+":"+target).Error; err != nil {
+		return nil, err
+	}
+
+	latest, err := latestConfigRevision(tx, kind, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous models.OcservConfigSnapshot
+	if latest != nil {
+		previous = latest.Config
+	}
+	revision.Changes = utils.ConfigChanges(previous, revision.Config)
+	if latest != nil && len(revision.Changes) == 0 && change.RestoredFrom == nil {
+		return latest, nil
+	}
+
+	if err = tx.Create(revision).Error; err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// latestConfigRevision returns the newest revision of target, or nil when
+// it has none.
+func latestConfigRevision(db *gorm.DB, kind, target string) (*models.OcservConfigRevision, error) {
+	var revision models.OcservConfigRevision
+	err := db.
+		Where("kind = ? AND target = ?", kind, target).
+		Order("id DESC").
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
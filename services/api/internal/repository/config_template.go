@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/group"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// EffectiveGroupConfig is the config written to the file of a group, its
+// templates merged with its own config, with the source of each value.
+type EffectiveGroupConfig struct {
+	Config *models.OcservGroupConfig    `json:"config" validate:"required"`
+	Values []utils.EffectiveConfigValue `json:"values" validate:"required"`
+}
+
+type ConfigTemplateRepository struct {
+	db                    *gorm.DB
+	commonOcservGroupRepo group.OcservGroupInterface
+	commonOcservOcctlRepo occtl.OcservOcctlInterface
+	nodes                 *occtlDocker.NodeClients
+}
+
+type ConfigTemplateRepositoryInterface interface {
+	Templates(ctx context.Context, pagination *request.Pagination) (*[]models.OcservConfigTemplate, int64, error)
+	GetByID(ctx context.Context, id string) (*models.OcservConfigTemplate, error)
+	Create(ctx context.Context, template *models.OcservConfigTemplate) (*models.OcservConfigTemplate, error)
+	Update(ctx context.Context, template *models.OcservConfigTemplate) (*models.OcservConfigTemplate, error)
+	Delete(ctx context.Context, id string) error
+	Groups(ctx context.Context, name string) ([]models.OcservGroup, error)
+}
+
+func NewConfigTemplateRepository() *ConfigTemplateRepository {
+	db := database.GetConnection()
+	return &ConfigTemplateRepository{
+		db:                    db,
+		commonOcservGroupRepo: group.NewOcservGroup(),
+		commonOcservOcctlRepo: occtlDocker.NewOcctlClient(),
+		nodes:                 occtlDocker.NewNodeClients(db),
+	}
+}
+
+func (r *ConfigTemplateRepository) Templates(ctx context.Context, pagination *request.Pagination) (*[]models.OcservConfigTemplate, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservConfigTemplate{})
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var templates []models.OcservConfigTemplate
+	if err := request.Paginator(ctx, query, pagination).Find(&templates).Error; err != nil {
+		return nil, 0, err
+	}
+	return &templates, totalRecords, nil
+}
+
+func (r *ConfigTemplateRepository) GetByID(ctx context.Context, id string) (*models.OcservConfigTemplate, error) {
+	var template models.OcservConfigTemplate
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *ConfigTemplateRepository) Create(ctx context.Context, template *models.OcservConfigTemplate) (*models.OcservConfigTemplate, error) {
+	if err := r.db.WithContext(ctx).Create(template).Error; err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Update saves template and re-renders the config files of the groups that
+// use it. When ocserv refuses the reload, the previous files are put back
+// and the template is not changed.
+func (r *ConfigTemplateRepository) Update(ctx context.Context, template *models.OcservConfigTemplate) (*models.OcservConfigTemplate, error) {
+	var groups []models.OcservGroup
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(template).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if groups, err = templateGroups(tx, template.Name); err != nil {
+			return err
+		}
+		return renderGroups(tx, r.commonOcservGroupRepo, r.commonOcservOcctlRepo, groups)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		if effective, err := groupEffectiveConfig(r.db.WithContext(ctx), &groups[i]); err == nil {
+			syncGroupToNodes(ctx, r.nodes, groups[i].Name, effective.Config)
+		}
+	}
+	return template, nil
+}
+
+// Delete removes a template no group uses.
+func (r *ConfigTemplateRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var template models.OcservConfigTemplate
+		if err := tx.Where("id = ?", id).First(&template).Error; err != nil {
+			return err
+		}
+
+		groups, err := templateGroups(tx, template.Name)
+		if err != nil {
+			return err
+		}
+		if len(groups) > 0 {
+			names := make([]string, 0, len(groups))
+			for _, g := range groups {
+				names = append(names, g.Name)
+			}
+			return fmt.Errorf("template %s is used by groups: %s", template.Name, strings.Join(names, ", "))
+		}
+
+		return tx.Delete(&template).Error
+	})
+}
+
+// Groups lists the groups that use the template name.
+func (r *ConfigTemplateRepository) Groups(ctx context.Context, name string) ([]models.OcservGroup, error) {
+	return templateGroups(r.db.WithContext(ctx), name)
+}
+
+// templateGroups returns the groups of db that use the template name.
+func templateGroups(db *gorm.DB, name string) ([]models.OcservGroup, error) {
+	var groups []models.OcservGroup
+	if err := db.Where("templates IS NOT NULL").Order("name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.OcservGroup, 0, len(groups))
+	for _, g := range groups {
+		if slices.Contains(g.Templates, name) {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+// groupEffectiveConfig merges the templates of ocservGroup, in order, with
+// its own config.
+func groupEffectiveConfig(db *gorm.DB, ocservGroup *models.OcservGroup) (*EffectiveGroupConfig, error) {
+	layers := make([]utils.ConfigLayer, 0, len(ocservGroup.Templates)+1)
+
+	if len(ocservGroup.Templates) > 0 {
+		var templates []models.OcservConfigTemplate
+		if err := db.Where("name IN ?", []string(ocservGroup.Templates)).Find(&templates).Error; err != nil {
+			return nil, err
+		}
+
+		byName := make(map[string]models.OcservConfigTemplate, len(templates))
+		for _, t := range templates {
+			byName[t.Name] = t
+		}
+		for _, name := range ocservGroup.Templates {
+			t, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("config template %s not found", name)
+			}
+			layers = append(layers, utils.ConfigLayer{Source: "template:" + t.Name, Config: t.Config})
+		}
+	}
+	layers = append(layers, utils.ConfigLayer{Source: "group:" + ocservGroup.Name, Config: ocservGroup.Config})
+
+	merged, values := utils.MergeConfigs(layers...)
+
+	config := &models.OcservGroupConfig{}
+	if err := utils.DecodeConfig(merged, config); err != nil {
+		return nil, err
+	}
+	return &EffectiveGroupConfig{Config: config, Values: values}, nil
+}
+
+// renderGroups writes the effective config files of groups and reloads
+// ocserv once. When a write fails or ocserv refuses the reload, the files
+// already written are put back.
+func renderGroups(
+	db *gorm.DB, groupRepo group.OcservGroupInterface, occtlRepo occtl.OcservOcctlInterface, groups []models.OcservGroup,
+) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var written []string
+	restore := func() error {
+		var errs []error
+		for _, name := range written {
+			if err := groupRepo.RestoreConfig(name); err != nil {
+				errs = append(errs, fmt.Errorf("group %s: %w", name, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	for i := range groups {
+		effective, err := groupEffectiveConfig(db, &groups[i])
+		if err == nil {
+			err = groupRepo.Create(groups[i].Name, effective.Config)
+		}
+		if err != nil {
+			_ = restore()
+			return fmt.Errorf("group %s: %w", groups[i].Name, err)
+		}
+		written = append(written, groups[i].Name)
+	}
+
+	return reloadOrRestore(occtlRepo, restore)
+}
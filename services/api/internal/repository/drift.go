@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/drift"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/group"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ocpasswdImportedPassword marks users imported from ocpasswd, whose
+// plain password is unknown. Same value as the ocpasswd sync endpoint.
+const ocpasswdImportedPassword = "Secret-Ocpasswd"
+
+// ErrImportedUserPassword is returned when recreating the ocpasswd entry of
+// a user imported from ocpasswd, whose password is unknown.
+var ErrImportedUserPassword = errors.New("the user was imported from ocpasswd and has no known password, set one before recreating it")
+
+type DriftRepository struct {
+	db                    *gorm.DB
+	commonOcservUserRepo  user.OcservUserInterface
+	commonOcservGroupRepo group.OcservGroupInterface
+	commonOcservOcctlRepo occtl.OcservOcctlInterface
+}
+
+// DriftFixResult is the outcome of fixing a single drift item.
+type DriftFixResult struct {
+	Item  drift.Item `json:"item" validate:"required"`
+	Fixed bool       `json:"fixed" validate:"required"`
+	Error string     `json:"error,omitempty" validate:"omitempty"`
+}
+
+type DriftRepositoryInterface interface {
+	Report(ctx context.Context) (*drift.Report, error)
+	Fix(ctx context.Context, source, owner string, kinds []string) ([]DriftFixResult, error)
+}
+
+func NewDriftRepository() *DriftRepository {
+	return &DriftRepository{
+		db:                    database.GetConnection(),
+		commonOcservUserRepo:  occtlDocker.NewOcservUserClient(),
+		commonOcservGroupRepo: group.NewOcservGroup(),
+		commonOcservOcctlRepo: occtlDocker.NewOcctlClient(),
+	}
+}
+
+func (d *DriftRepository) databaseState(ctx context.Context) (drift.DatabaseState, error) {
+	var state drift.DatabaseState
+
+	// users of registered nodes are not in the local ocserv files
+	db := d.db.WithContext(ctx)
+	if err := db.Select("username", "group", "is_locked", "config", "auth_mode").
+		Where("node = ?", models.LocalNode).
+		Find(&state.Users).Error; err != nil {
+		return state, err
+	}
+
+	var groups []models.OcservGroup
+	if err := db.Select("name", "auth_mode").Find(&groups).Error; err != nil {
+		return state, err
+	}
+	groupModes := make(map[string]string, len(groups))
+	for _, g := range groups {
+		state.Groups = append(state.Groups, g.Name)
+		groupModes[g.Name] = g.AuthMode
+	}
+
+	for i := range state.Users {
+		u := &state.Users[i]
+		u.AuthMode = models.ResolveAuthMode(u.AuthMode, groupModes[u.Group])
+	}
+	return state, nil
+}
+
+func (d *DriftRepository) ocservState(ctx context.Context) (drift.OcservState, error) {
+	var state drift.OcservState
+
+	entries, _, err := d.commonOcservUserRepo.Ocpasswd(ctx)
+	if err != nil {
+		return state, fmt.Errorf("read ocpasswd: %w", err)
+	}
+	if entries != nil {
+		state.Ocpasswd = *entries
+	}
+
+	if state.UserConfigs, err = d.commonOcservUserRepo.ConfigList(ctx); err != nil {
+		return state, fmt.Errorf("list user configs: %w", err)
+	}
+
+	groups, err := d.commonOcservGroupRepo.GroupList(ctx)
+	if err != nil {
+		return state, fmt.Errorf("list group configs: %w", err)
+	}
+	for _, g := range groups {
+		state.Groups = append(state.Groups, g.Name)
+	}
+	return state, nil
+}
+
+// Report compares the database with the ocserv files of the local node.
+func (d *DriftRepository) Report(ctx context.Context) (*drift.Report, error) {
+	dbState, err := d.databaseState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ocState, err := d.ocservState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := drift.Detect(dbState, ocState)
+	return &report, nil
+}
+
+// Fix repairs the drift of the given kinds making the other side match
+// source. Empty kinds fixes every kind that deletes nothing; kinds that
+// delete users, groups or config files from source (drift.Destructive)
+// must be listed. Imported users and groups are owned by owner. Fixes of
+// the ocserv files are followed by a reload, and put back when ocserv
+// refuses it.
+func (d *DriftRepository) Fix(ctx context.Context, source, owner string, kinds []string) ([]DriftFixResult, error) {
+	if source != drift.SourceDatabase && source != drift.SourceOcserv {
+		return nil, fmt.Errorf("unknown source of truth: %s", source)
+	}
+
+	report, err := d.Report(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		selected[k] = true
+	}
+
+	results := make([]DriftFixResult, 0, len(report.Items))
+	var restores []func() error
+	for _, item := range report.Items {
+		if len(selected) > 0 && !selected[item.Kind] {
+			continue
+		}
+		if len(selected) == 0 && drift.Destructive(source, item.Kind) {
+			continue
+		}
+
+		if source == drift.SourceDatabase {
+			var restore func() error
+			restore, err = d.fixFromDatabase(ctx, item)
+			switch {
+			case restore == nil:
+			case err != nil:
+				if restoreErr := restore(); restoreErr != nil {
+					err = fmt.Errorf("%w; restoring the previous files failed: %v", err, restoreErr)
+				}
+			default:
+				restores = append(restores, restore)
+			}
+		} else {
+			err = d.fixFromOcserv(ctx, item, owner)
+		}
+
+		result := DriftFixResult{Item: item, Fixed: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if len(restores) == 0 {
+		return results, nil
+	}
+
+	err = reloadOrRestore(d.commonOcservOcctlRepo, func() error {
+		var errs []error
+		for i := len(restores) - 1; i >= 0; i-- {
+			errs = append(errs, restores[i]())
+		}
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (d *DriftRepository) findUser(ctx context.Context, username string) (*models.OcservUser, error) {
+	var u models.OcservUser
+	if err := d.db.WithContext(ctx).Where("username = ?", username).First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// authMode returns the auth mode of u, inherited from its group when unset.
+// A failed group lookup is returned, not read as the default mode.
+func (d *DriftRepository) authMode(ctx context.Context, u *models.OcservUser) (string, error) {
+	var groupMode string
+	if err := d.db.WithContext(ctx).
+		Model(&models.OcservGroup{}).
+		Where("name = ?", u.Group).
+		Limit(1).
+		Pluck("auth_mode", &groupMode).Error; err != nil {
+		return "", fmt.Errorf("resolve auth mode of %s: %w", u.Username, err)
+	}
+	return models.ResolveAuthMode(u.AuthMode, groupMode), nil
+}
+
+// saveUser returns how to put back the ocserv files of username.
+func (d *DriftRepository) saveUser(username string) (func() error, error) {
+	state, err := d.commonOcservUserRepo.SaveState(username)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		return d.commonOcservUserRepo.RestoreState(username, state)
+	}, nil
+}
+
+// fixFromDatabase rewrites the ocserv files to match the database. It
+// returns how to put back what it changed, also when the fix failed half
+// way.
+func (d *DriftRepository) fixFromDatabase(ctx context.Context, item drift.Item) (func() error, error) {
+	switch item.Kind {
+	case drift.KindOrphanGroup:
+		groups, err := d.commonOcservGroupRepo.GroupList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			if g.Name == item.Name {
+				restore := func() error {
+					return d.commonOcservGroupRepo.Create(g.Name, g.Config)
+				}
+				return restore, d.commonOcservGroupRepo.Delete(item.Name)
+			}
+		}
+		return nil, fmt.Errorf("group %s not found", item.Name)
+
+	case drift.KindMissingGroupFile:
+		var g models.OcservGroup
+		if err := d.db.WithContext(ctx).Where("name = ?", item.Name).First(&g).Error; err != nil {
+			return nil, err
+		}
+		effective, err := groupEffectiveConfig(d.db.WithContext(ctx), &g)
+		if err != nil {
+			return nil, err
+		}
+		restore := func() error {
+			return d.commonOcservGroupRepo.Delete(g.Name)
+		}
+		return restore, d.commonOcservGroupRepo.Create(g.Name, effective.Config)
+	}
+
+	var u *models.OcservUser
+	var mode string
+	switch item.Kind {
+	case drift.KindMissingInOcpasswd, drift.KindMissingConfig:
+		var err error
+		if u, err = d.findUser(ctx, item.Name); err != nil {
+			return nil, err
+		}
+		if item.Kind == drift.KindMissingInOcpasswd {
+			if u.Password == ocpasswdImportedPassword {
+				return nil, ErrImportedUserPassword
+			}
+			if mode, err = d.authMode(ctx, u); err != nil {
+				return nil, err
+			}
+		}
+	case drift.KindMissingInDatabase, drift.KindLockMismatch, drift.KindGroupMismatch, drift.KindStaleConfig:
+	default:
+		return nil, fmt.Errorf("unknown drift kind: %s", item.Kind)
+	}
+
+	restore, err := d.saveUser(item.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch item.Kind {
+	case drift.KindMissingInOcpasswd:
+		if err = d.commonOcservUserRepo.ApplyAuthMode(
+			u.Group, u.Username, u.Password, mode, u.Config,
+		); err == nil && u.IsLocked {
+			_, err = d.commonOcservUserRepo.Lock(u.Username)
+		}
+
+	case drift.KindMissingInDatabase:
+		_, err = d.commonOcservUserRepo.Delete(item.Name)
+
+	case drift.KindLockMismatch:
+		if item.Database == "true" {
+			_, err = d.commonOcservUserRepo.Lock(item.Name)
+		} else {
+			_, err = d.commonOcservUserRepo.UnLock(item.Name)
+		}
+
+	case drift.KindGroupMismatch:
+		err = d.commonOcservUserRepo.SetGroup(item.Name, item.Database)
+
+	case drift.KindStaleConfig:
+		err = d.commonOcservUserRepo.DeleteConfig(item.Name)
+
+	case drift.KindMissingConfig:
+		err = d.commonOcservUserRepo.SyncConfig(u.Username, u.Group, u.Config)
+	}
+	return restore, err
+}
+
+// fixFromOcserv updates the database to match the ocserv files.
+func (d *DriftRepository) fixFromOcserv(ctx context.Context, item drift.Item, owner string) error {
+	db := d.db.WithContext(ctx)
+
+	switch item.Kind {
+	case drift.KindMissingInOcpasswd:
+		return db.Where("username = ?", item.Name).Delete(&models.OcservUser{}).Error
+
+	case drift.KindMissingInDatabase:
+		entries, _, err := d.commonOcservUserRepo.Ocpasswd(ctx)
+		if err != nil {
+			return err
+		}
+		for _, e := range *entries {
+			if e.Username != item.Name {
+				continue
+			}
+			return db.Create(&models.OcservUser{
+				Owner:       owner,
+				Username:    e.Username,
+				Group:       item.Ocserv,
+				Password:    ocpasswdImportedPassword,
+				IsLocked:    e.Locked,
+				TrafficType: models.Free,
+			}).Error
+		}
+		return fmt.Errorf("user %s not found in ocpasswd", item.Name)
+
+	case drift.KindLockMismatch:
+		return db.Model(&models.OcservUser{}).
+			Where("username = ?", item.Name).
+			Update("is_locked", item.Ocserv == "true").Error
+
+	case drift.KindGroupMismatch:
+		return db.Model(&models.OcservUser{}).
+			Where("username = ?", item.Name).
+			Update("group", item.Ocserv).Error
+
+	case drift.KindStaleConfig:
+		return fmt.Errorf("%s has no database user to attach the config to", item.Name)
+
+	case drift.KindMissingConfig:
+		return db.Model(&models.OcservUser{}).
+			Where("username = ?", item.Name).
+			Update("config", nil).Error
+
+	case drift.KindOrphanGroup:
+		groups, err := d.commonOcservGroupRepo.GroupList(ctx)
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			if g.Name == item.Name {
+				return db.Create(&models.OcservGroup{Name: g.Name, Owner: owner, Config: g.Config}).Error
+			}
+		}
+		return fmt.Errorf("group %s not found", item.Name)
+
+	case drift.KindMissingGroupFile:
+		return db.Where("name = ?", item.Name).Delete(&models.OcservGroup{}).Error
+	}
+	return fmt.Errorf("unknown drift kind: %s", item.Kind)
+}
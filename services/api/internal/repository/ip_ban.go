@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/ipban"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type IPBanRepository struct {
+	db      *gorm.DB
+	manager *ipban.Manager
+	nodes   *occtlDocker.NodeClients
+}
+
+// RepeatedIPBan is an address banned several times.
+type RepeatedIPBan struct {
+	IP           string    `json:"ip" validate:"required"`
+	Bans         int64     `json:"bans" validate:"required"`
+	LastBannedAt time.Time `json:"last_banned_at" validate:"required"`
+	Active       bool      `json:"active" validate:"required"`
+}
+
+type IPBanRepositoryInterface interface {
+	Active(ctx context.Context) ([]models.OcservIPBan, error)
+	History(ctx context.Context, pagination *request.Pagination, ip string) (*[]models.OcservIPBan, int64, error)
+	Ban(ctx context.Context, req ipban.BanRequest) (*models.OcservIPBan, error)
+	Unban(ctx context.Context, id uint, actor, reason string) (*models.OcservIPBan, error)
+	Repeated(ctx context.Context, since time.Time, minBans int) ([]RepeatedIPBan, error)
+}
+
+func NewIPBanRepository() *IPBanRepository {
+	db := database.GetConnection()
+	return &IPBanRepository{
+		db:      db,
+		manager: ipban.NewManager(db, models.LocalNode, occtlDocker.NewFirewallClient(), occtlDocker.NewOcctlClient()),
+		nodes:   occtlDocker.NewNodeClients(db),
+	}
+}
+
+// managerFor returns the ban manager of node, applying its bans through the
+// firewall of the agent of a registered node.
+func (r *IPBanRepository) managerFor(ctx context.Context, node string) (*ipban.Manager, error) {
+	if models.NodeName(node) == models.LocalNode {
+		return r.manager, nil
+	}
+	client, err := r.nodes.Client(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return ipban.NewManager(r.db, node, client, client), nil
+}
+
+// Active returns the bans in force on every node.
+func (r *IPBanRepository) Active(ctx context.Context) ([]models.OcservIPBan, error) {
+	var bans []models.OcservIPBan
+	err := r.db.WithContext(ctx).
+		Where("unbanned_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now()).
+		Order("id ASC").
+		Find(&bans).Error
+	return bans, err
+}
+
+// History lists every ban, newest first by default, optionally of a single
+// address or prefix.
+func (r *IPBanRepository) History(ctx context.Context, pagination *request.Pagination, ip string) (*[]models.OcservIPBan, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservIPBan{})
+	if ip != "" {
+		cidr, err := firewall.NormalizeCIDR(ip)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("ip = ?", cidr)
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var bans []models.OcservIPBan
+	if err := request.Paginator(ctx, query, pagination).Find(&bans).Error; err != nil {
+		return nil, 0, err
+	}
+	return &bans, totalRecords, nil
+}
+
+func (r *IPBanRepository) Ban(ctx context.Context, req ipban.BanRequest) (*models.OcservIPBan, error) {
+	return r.manager.Ban(ctx, req)
+}
+
+// Unban ends the active ban id on the node it was applied to.
+func (r *IPBanRepository) Unban(ctx context.Context, id uint, actor, reason string) (*models.OcservIPBan, error) {
+	var nodes []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.OcservIPBan{}).
+		Where("id = ? AND unbanned_at IS NULL", id).
+		Pluck("node", &nodes).Error; err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, ipban.ErrNotBanned
+	}
+
+	manager, err := r.managerFor(ctx, nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	return manager.Unban(ctx, id, actor, reason)
+}
+
+// Reapply expires the outdated bans and re-applies the active ones on the
+// local node and on every enabled registered node, each through its own
+// firewall. A failing node does not hold up the others.
+func (r *IPBanRepository) Reapply(ctx context.Context) error {
+	err := r.manager.Reapply(ctx)
+
+	nodes, nErr := r.nodes.Enabled(ctx)
+	if nErr != nil {
+		return errors.Join(err, nErr)
+	}
+	for i := range nodes {
+		client := r.nodes.For(&nodes[i])
+		manager := ipban.NewManager(r.db, nodes[i].Name, client, client)
+		if rErr := manager.Reapply(ctx); rErr != nil {
+			err = errors.Join(err, fmt.Errorf("node %s: %w", nodes[i].Name, rErr))
+		}
+	}
+	return err
+}
+
+// Run re-applies the bans of every node right away and then every interval
+// until ctx is done.
+func (r *IPBanRepository) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Reapply(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to re-apply ip bans: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Repeated returns the addresses banned at least minBans times since since,
+// most banned first.
+func (r *IPBanRepository) Repeated(ctx context.Context, since time.Time, minBans int) ([]RepeatedIPBan, error) {
+	var results []RepeatedIPBan
+	err := r.db.WithContext(ctx).
+		Model(&models.OcservIPBan{}).
+		Select(`
+		ip,
+		COUNT(*) AS bans,
+		MAX(created_at) AS last_banned_at,
+		BOOL_OR(unbanned_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())) AS active
+	`).
+		Where("created_at >= ?", since).
+		Group("ip").
+		Having("COUNT(*) >= ?", minBans).
+		Order("bans DESC, last_banned_at DESC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,459 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/group"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/ipam"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/server"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// ErrIPAMRemoteUser is returned when an address is allocated to a user of a
+// registered node, whose pools and sessions are not known here.
+var ErrIPAMRemoteUser = errors.New("addresses are only allocated to users of the local node")
+
+// ipamAllocateMu serializes Allocate, so concurrent calls never pick the
+// same free address.
+var ipamAllocateMu sync.Mutex
+
+const (
+	IPAMAddressStatic  = "static"
+	IPAMAddressSession = "session"
+)
+
+// IPAMPool is an ipv4-network with its utilization. Sources are the config
+// files that declare it.
+type IPAMPool struct {
+	Network     string   `json:"network" validate:"required"`
+	Sources     []string `json:"sources" validate:"required"`
+	Size        int      `json:"size" validate:"required"`
+	Static      int      `json:"static" validate:"required"`
+	Sessions    int      `json:"sessions" validate:"required"`
+	Free        int      `json:"free" validate:"required"`
+	Utilization float64  `json:"utilization" validate:"required"` // percent of the usable addresses in use
+}
+
+// IPAMAddress is an address in use: the explicit-ipv4 of a user or group,
+// or the address of a live session.
+type IPAMAddress struct {
+	Address string `json:"address" validate:"required"`
+	Kind    string `json:"kind" validate:"required" enums:"static,session"`
+	Owner   string `json:"owner" validate:"required"` // username, or group:name for the explicit-ipv4 of a group
+}
+
+// IPAMConflict is a static address that ocserv cannot give as configured.
+type IPAMConflict struct {
+	Address string   `json:"address" validate:"required"`
+	Owners  []string `json:"owners" validate:"required"`
+	Reason  string   `json:"reason" validate:"required"`
+}
+
+type IPAMRepository struct {
+	db                    *gorm.DB
+	commonOcservGroupRepo group.OcservGroupInterface
+	commonOcservOcctlRepo occtl.OcservOcctlInterface
+	ocservUserRepo        OcservUserRepositoryInterface
+}
+
+type IPAMRepositoryInterface interface {
+	Pools(ctx context.Context) ([]IPAMPool, error)
+	Addresses(ctx context.Context, network string) ([]IPAMAddress, error)
+	Conflicts(ctx context.Context) ([]IPAMConflict, error)
+	CheckAddress(ctx context.Context, ocservUser *models.OcservUser) error
+	Allocate(ctx context.Context, uid, actor string) (*models.OcservUser, error)
+}
+
+func NewIPAMRepository() *IPAMRepository {
+	return &IPAMRepository{
+		db:                    database.GetConnection(),
+		commonOcservGroupRepo: group.NewOcservGroup(),
+		commonOcservOcctlRepo: occtlDocker.NewOcctlClient(),
+		ocservUserRepo:        NewtOcservUserRepository(),
+	}
+}
+
+// ipamState is what the address pools are built from.
+type ipamState struct {
+	serverNetwork   string
+	defaultsNetwork string
+	vhostNetworks   map[string]string // virtual host name to the ipv4-network of its section
+	groupNetworks   map[string]string // group name to its effective ipv4-network
+	users           []models.OcservUser
+	static          []IPAMAddress
+	sessions        []IPAMAddress
+}
+
+// load reads the pools from ocserv.conf and its virtual hosts, the defaults
+// group and the group configs, the static addresses of the users of the
+// local node and of groups and the addresses of live sessions. Sessions are
+// left out when occtl is not available.
+func (r *IPAMRepository) load(ctx context.Context) (*ipamState, error) {
+	state := &ipamState{vhostNetworks: make(map[string]string), groupNetworks: make(map[string]string)}
+
+	doc, err := utils.ReadConfigDocument(server.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if values := doc.Values("ipv4-network"); len(values) > 0 {
+		state.serverNetwork = values[0]
+		if netmask := doc.Values("ipv4-netmask"); len(netmask) > 0 && !strings.Contains(values[0], "/") {
+			state.serverNetwork = values[0] + "/" + netmask[0]
+		}
+	}
+	for _, section := range doc.Sections() {
+		name, ok := strings.CutPrefix(section, "vhost:")
+		if !ok {
+			continue
+		}
+		if values := doc.SectionDirectives(section)["ipv4-network"]; len(values) > 0 {
+			state.vhostNetworks[name] = values[0]
+		}
+	}
+
+	if defaults, err := r.commonOcservGroupRepo.DefaultsGroup(); err == nil && defaults.IPv4Network != nil {
+		state.defaultsNetwork = *defaults.IPv4Network
+	}
+
+	var groups []models.OcservGroup
+	if err = r.db.WithContext(ctx).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		effective, err := groupEffectiveConfig(r.db.WithContext(ctx), &groups[i])
+		if err != nil {
+			return nil, err
+		}
+		state.groupNetworks[groups[i].Name] = stringValue(effective.Config.IPv4Network)
+		if explicit := stringValue(effective.Config.ExplicitIPv4); explicit != "" {
+			state.static = append(state.static, IPAMAddress{
+				Address: explicit, Kind: IPAMAddressStatic, Owner: "group:" + groups[i].Name,
+			})
+		}
+	}
+
+	// users of registered nodes get addresses from the pools of their node
+	if err = r.db.WithContext(ctx).
+		Select("id", "username", "group", "vhost", "config").
+		Where("node = ?", models.LocalNode).
+		Find(&state.users).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range state.users {
+		if u.Config == nil {
+			continue
+		}
+		if explicit := stringValue(u.Config.ExplicitIPv4); explicit != "" {
+			state.static = append(state.static, IPAMAddress{Address: explicit, Kind: IPAMAddressStatic, Owner: u.Username})
+		}
+	}
+
+	if sessions, err := r.commonOcservOcctlRepo.OnlineSessions(); err == nil {
+		for _, s := range sessions {
+			if s.IPv4 != "" {
+				state.sessions = append(state.sessions, IPAMAddress{Address: s.IPv4, Kind: IPAMAddressSession, Owner: s.Username})
+			}
+		}
+	}
+	return state, nil
+}
+
+// userNetwork resolves the ipv4-network ocserv gives ocservUser addresses
+// from: the user config, else the group file, else the defaults group when
+// the user has no group file, else the section of its virtual host, else
+// ocserv.conf.
+func (s *ipamState) userNetwork(ocservUser *models.OcservUser) (string, string) {
+	if ocservUser.Config != nil {
+		if network := stringValue(ocservUser.Config.IPv4Network); network != "" {
+			return network, "user:" + ocservUser.Username
+		}
+	}
+	if network, ok := s.groupNetworks[ocservUser.Group]; ok && ocservUser.Group != "defaults" {
+		if network != "" {
+			return network, "group:" + ocservUser.Group
+		}
+	} else if s.defaultsNetwork != "" {
+		return s.defaultsNetwork, utils.DefaultGroupFile
+	}
+	if network := s.vhostNetworks[ocservUser.VHost]; network != "" {
+		return network, "vhost:" + ocservUser.VHost
+	}
+	return s.serverNetwork, server.ConfigPath
+}
+
+// pools returns the pools by network, with the config files declaring them.
+func (s *ipamState) pools() (map[string]*ipam.Pool, map[string][]string) {
+	pools := make(map[string]*ipam.Pool)
+	sources := make(map[string][]string)
+
+	add := func(network, source string) {
+		if network == "" {
+			return
+		}
+		pool, err := ipam.NewPool(network)
+		if err != nil {
+			return
+		}
+		key := pool.String()
+		pools[key] = pool
+		if !slices.Contains(sources[key], source) {
+			sources[key] = append(sources[key], source)
+		}
+	}
+
+	add(s.serverNetwork, server.ConfigPath)
+	add(s.defaultsNetwork, utils.DefaultGroupFile)
+	vhosts := make([]string, 0, len(s.vhostNetworks))
+	for name := range s.vhostNetworks {
+		vhosts = append(vhosts, name)
+	}
+	sort.Strings(vhosts)
+	for _, name := range vhosts {
+		add(s.vhostNetworks[name], "vhost:"+name)
+	}
+	names := make([]string, 0, len(s.groupNetworks))
+	for name := range s.groupNetworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(s.groupNetworks[name], "group:"+name)
+	}
+	for i := range s.users {
+		if s.users[i].Config != nil {
+			add(stringValue(s.users[i].Config.IPv4Network), "user:"+s.users[i].Username)
+		}
+	}
+	return pools, sources
+}
+
+// Pools lists the address pools with their utilization.
+func (r *IPAMRepository) Pools(ctx context.Context) ([]IPAMPool, error) {
+	state, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pools, sources := state.pools()
+	result := make([]IPAMPool, 0, len(pools))
+	for key, pool := range pools {
+		item := IPAMPool{Network: key, Sources: sources[key], Size: pool.Size()}
+
+		used := make(map[string]bool)
+		for _, a := range state.static {
+			if pool.Usable(a.Address) && !used[a.Address] {
+				used[a.Address] = true
+				item.Static++
+			}
+		}
+		for _, a := range state.sessions {
+			if pool.Usable(a.Address) && !used[a.Address] {
+				used[a.Address] = true
+				item.Sessions++
+			}
+		}
+
+		item.Free = item.Size - len(used)
+		if item.Size > 0 {
+			item.Utilization = math.Round(float64(len(used))/float64(item.Size)*10000) / 100
+		}
+		result = append(result, item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Network < result[j].Network
+	})
+	return result, nil
+}
+
+// Addresses lists the static and session addresses in network, in address
+// order.
+func (r *IPAMRepository) Addresses(ctx context.Context, network string) ([]IPAMAddress, error) {
+	pool, err := ipam.NewPool(network)
+	if err != nil {
+		return nil, err
+	}
+	state, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]IPAMAddress, 0)
+	for _, a := range append(append([]IPAMAddress{}, state.static...), state.sessions...) {
+		if pool.Contains(a.Address) {
+			result = append(result, a)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return bytes.Compare(net.ParseIP(result[i].Address).To16(), net.ParseIP(result[j].Address).To16()) < 0
+	})
+	return result, nil
+}
+
+// Conflicts lists the static addresses assigned more than once, outside the
+// usable addresses of the pool of their user, or held by a live session of
+// another user.
+func (r *IPAMRepository) Conflicts(ctx context.Context) ([]IPAMConflict, error) {
+	state, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []IPAMConflict
+
+	owners := make(map[string][]string)
+	var addresses []string
+	for _, a := range state.static {
+		if _, ok := owners[a.Address]; !ok {
+			addresses = append(addresses, a.Address)
+		}
+		owners[a.Address] = append(owners[a.Address], a.Owner)
+	}
+	for _, address := range addresses {
+		if len(owners[address]) > 1 {
+			conflicts = append(conflicts, IPAMConflict{
+				Address: address,
+				Owners:  owners[address],
+				Reason:  "assigned more than once",
+			})
+		}
+	}
+
+	for i := range state.users {
+		u := &state.users[i]
+		if u.Config == nil || stringValue(u.Config.ExplicitIPv4) == "" {
+			continue
+		}
+		for _, reason := range state.addressProblems(u) {
+			conflicts = append(conflicts, IPAMConflict{
+				Address: *u.Config.ExplicitIPv4,
+				Owners:  []string{u.Username},
+				Reason:  reason,
+			})
+		}
+	}
+
+	if conflicts == nil {
+		conflicts = []IPAMConflict{}
+	}
+	return conflicts, nil
+}
+
+// addressProblems checks the explicit-ipv4 of ocservUser against its pool
+// and the live sessions of other users.
+func (s *ipamState) addressProblems(ocservUser *models.OcservUser) []string {
+	address := *ocservUser.Config.ExplicitIPv4
+
+	// without a known pool, e.g. ocserv.conf out of reach, only sessions
+	// are checked
+	var problems []string
+	network, source := s.userNetwork(ocservUser)
+	if pool, err := ipam.NewPool(network); err == nil && !pool.Usable(address) {
+		problems = append(problems, fmt.Sprintf("not a usable address of %s from %s", pool, source))
+	}
+
+	for _, session := range s.sessions {
+		if session.Address == address && session.Owner != ocservUser.Username {
+			problems = append(problems, "in use by a live session of "+session.Owner)
+			break
+		}
+	}
+	return problems
+}
+
+// CheckAddress reports the conflicts the explicit-ipv4 of ocservUser would
+// have, before the user is saved.
+func (r *IPAMRepository) CheckAddress(ctx context.Context, ocservUser *models.OcservUser) error {
+	if ocservUser.Config == nil || stringValue(ocservUser.Config.ExplicitIPv4) == "" {
+		return nil
+	}
+	// the pools and sessions of registered nodes are not known here
+	if models.NodeName(ocservUser.Node) != models.LocalNode {
+		return nil
+	}
+	address := *ocservUser.Config.ExplicitIPv4
+
+	state, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, a := range state.static {
+		if a.Address == address && a.Owner != ocservUser.Username {
+			errs = append(errs, fmt.Errorf("explicit-ipv4 %s is assigned to %s", address, a.Owner))
+		}
+	}
+	for _, problem := range state.addressProblems(ocservUser) {
+		errs = append(errs, fmt.Errorf("explicit-ipv4 %s: %s", address, problem))
+	}
+	return errors.Join(errs...)
+}
+
+// Allocate gives the user with uid the lowest free address of its pool as
+// explicit-ipv4, replacing the one it has, and rewrites its config file.
+func (r *IPAMRepository) Allocate(ctx context.Context, uid, actor string) (*models.OcservUser, error) {
+	ipamAllocateMu.Lock()
+	defer ipamAllocateMu.Unlock()
+
+	ocservUser, err := r.ocservUserRepo.GetByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if models.NodeName(ocservUser.Node) != models.LocalNode {
+		return nil, ErrIPAMRemoteUser
+	}
+
+	state, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	network, _ := state.userNetwork(ocservUser)
+	if network == "" {
+		return nil, errors.New("no ipv4-network applies to the user")
+	}
+	pool, err := ipam.NewPool(network)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, a := range append(append([]IPAMAddress{}, state.static...), state.sessions...) {
+		if a.Owner != ocservUser.Username {
+			used[a.Address] = true
+		}
+	}
+	address, err := pool.Next(used)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pool, err)
+	}
+
+	if ocservUser.Config == nil {
+		ocservUser.Config = &models.OcservUserConfig{}
+	}
+	ocservUser.Config.ExplicitIPv4 = &address
+	return r.ocservUserRepo.Update(ctx, ocservUser, ConfigChange{Actor: actor})
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
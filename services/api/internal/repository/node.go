@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// ErrLocalNode is returned when a node is registered under the name of the
+// local node, the ocserv this dashboard runs with.
+var ErrLocalNode = errors.New("the local node is the ocserv of this dashboard and is not registered")
+
+type NodeRepository struct {
+	db    *gorm.DB
+	nodes *occtlDocker.NodeClients
+}
+
+type NodeRepositoryInterface interface {
+	Nodes(ctx context.Context, pagination *request.Pagination) (*[]models.OcservNode, int64, error)
+	Lookup(ctx context.Context) ([]string, error)
+	GetByID(ctx context.Context, id string) (*models.OcservNode, error)
+	Create(ctx context.Context, node *models.OcservNode) (*models.OcservNode, error)
+	Update(ctx context.Context, node *models.OcservNode) (*models.OcservNode, error)
+	Delete(ctx context.Context, id string) error
+	Check(ctx context.Context, node *models.OcservNode) (*models.AgentInfo, error)
+	Exists(ctx context.Context, name string) error
+}
+
+func NewNodeRepository() *NodeRepository {
+	db := database.GetConnection()
+	return &NodeRepository{
+		db:    db,
+		nodes: occtlDocker.NewNodeClients(db),
+	}
+}
+
+func (r *NodeRepository) Nodes(ctx context.Context, pagination *request.Pagination) (*[]models.OcservNode, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservNode{})
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var nodes []models.OcservNode
+	if err := request.Paginator(ctx, query, pagination).Find(&nodes).Error; err != nil {
+		return nil, 0, err
+	}
+	return &nodes, totalRecords, nil
+}
+
+// Lookup returns the names of the nodes users can be provisioned on, the
+// local one first.
+func (r *NodeRepository) Lookup(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.OcservNode{}).
+		Where("enabled = ?", true).
+		Order("name").
+		Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+	return append([]string{models.LocalNode}, names...), nil
+}
+
+func (r *NodeRepository) GetByID(ctx context.Context, id string) (*models.OcservNode, error) {
+	var node models.OcservNode
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&node).Error; err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// Create registers the node and checks its agent. An unreachable agent
+// does not fail the registration, it is kept in LastError.
+func (r *NodeRepository) Create(ctx context.Context, node *models.OcservNode) (*models.OcservNode, error) {
+	if node.Name == models.LocalNode {
+		return nil, ErrLocalNode
+	}
+
+	if err := r.db.WithContext(ctx).Create(node).Error; err != nil {
+		return nil, err
+	}
+	_, _ = r.Check(ctx, node)
+	return node, nil
+}
+
+func (r *NodeRepository) Update(ctx context.Context, node *models.OcservNode) (*models.OcservNode, error) {
+	if err := r.db.WithContext(ctx).Save(node).Error; err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Delete removes a node no user is provisioned on.
+func (r *NodeRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var node models.OcservNode
+		if err := tx.Where("id = ?", id).First(&node).Error; err != nil {
+			return err
+		}
+
+		var users int64
+		if err := tx.Model(&models.OcservUser{}).Where("node = ?", node.Name).Count(&users).Error; err != nil {
+			return err
+		}
+		if users > 0 {
+			return fmt.Errorf("node %s still has %d users", node.Name, users)
+		}
+
+		return tx.Delete(&node).Error
+	})
+}
+
+// Check calls the agent of node and records the result: the ocserv
+// release and LastSeenAt when it answered, LastError otherwise. An agent
+// reporting another node name is an error, the URL most likely points to
+// the wrong server.
+func (r *NodeRepository) Check(ctx context.Context, node *models.OcservNode) (*models.AgentInfo, error) {
+	info, err := r.nodes.For(node).AgentInfo()
+	if err == nil && info.Node != node.Name {
+		err = fmt.Errorf("agent at %s reports node %s, not %s", node.URL, info.Node, node.Name)
+	}
+
+	updates := map[string]interface{}{}
+	if err != nil {
+		updates["last_error"] = err.Error()
+	} else {
+		now := time.Now()
+		updates["last_error"] = ""
+		updates["last_seen_at"] = &now
+		if info.Version != nil {
+			updates["version"] = info.Version.OcservVersion
+		}
+	}
+	if uErr := r.db.WithContext(ctx).Model(node).Updates(updates).Error; uErr != nil {
+		return nil, uErr
+	}
+	return info, err
+}
+
+// Exists returns occtl_docker.ErrNodeNotFound unless name is the local
+// node or an enabled registered one.
+func (r *NodeRepository) Exists(ctx context.Context, name string) error {
+	if models.NodeName(name) == models.LocalNode {
+		return nil
+	}
+	_, err := r.nodes.Client(ctx, name)
+	return err
+}
+
+// nodeClients returns the ocpasswd and occtl clients of node: localUser and
+// localOcctl for the local node, the agent of a registered node otherwise.
+func nodeClients(
+	ctx context.Context,
+	nodes *occtlDocker.NodeClients,
+	node string,
+	localUser user.OcservUserInterface,
+	localOcctl occtl.OcservOcctlInterface,
+) (user.OcservUserInterface, occtl.OcservOcctlInterface, error) {
+	if models.NodeName(node) == models.LocalNode {
+		return localUser, localOcctl, nil
+	}
+
+	client, err := nodes.Client(ctx, node)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, client, nil
+}
+
+// syncGroupToNode writes the effective config file of group to node before
+// a user of the group is provisioned there: the group files are only
+// written locally when groups change. The local node and the defaults
+// group, part of ocserv.conf, need nothing.
+func syncGroupToNode(ctx context.Context, db *gorm.DB, nodes *occtlDocker.NodeClients, node, group string) error {
+	if models.NodeName(node) == models.LocalNode || group == "" || group == "defaults" {
+		return nil
+	}
+
+	var ocservGroup models.OcservGroup
+	if err := db.WithContext(ctx).Where("name = ?", group).First(&ocservGroup).Error; err != nil {
+		return fmt.Errorf("group %s: %w", group, err)
+	}
+	effective, err := groupEffectiveConfig(db.WithContext(ctx), &ocservGroup)
+	if err != nil {
+		return err
+	}
+
+	client, err := nodes.Client(ctx, node)
+	if err != nil {
+		return err
+	}
+	if err = client.CreateGroup(group, effective.Config); err != nil {
+		return fmt.Errorf("sync group %s to node %s: %w", group, node, err)
+	}
+	return nil
+}
+
+// syncGroupToNodes pushes the config file of group, or its removal when
+// config is nil, to the enabled nodes. A node missing the file gets it
+// again before a user of the group is provisioned there, see
+// syncGroupToNode, so failures are only logged.
+func syncGroupToNodes(ctx context.Context, nodes *occtlDocker.NodeClients, group string, config *models.OcservGroupConfig) {
+	enabled, err := nodes.Enabled(ctx)
+	if err != nil {
+		logger.Warn("Failed to list the nodes to sync group %s to: %v", group, err)
+		return
+	}
+	if err = nodes.SyncGroup(enabled, group, config); err != nil {
+		logger.Warn("Failed to sync group %s to the nodes: %v", group, err)
+	}
+}
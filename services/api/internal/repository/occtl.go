@@ -1,27 +1,40 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
 )
 
 type OcctlRepository struct {
 	commonOcservOcctlRepo occtl.OcservOcctlInterface
+	nodes                 *occtlDocker.NodeClients
+	// node is empty on the repository of the whole dashboard, whose online
+	// sessions span every node
+	node string
 }
 
 type OcctlServerInfo interface {
 	Version() *models.ServerVersion
-	Status() (interface{}, error)
+	Capabilities() models.OcctlCapabilities
+	Status() (*models.OcctlServerStatus, error)
 	ShowEvent() string
 }
 
 type OcctlUserManager interface {
 	OnlineSessions() ([]models.OnlineUserSession, error)
-	ShowUserByUsername(username string) (models.OnlineUserSession, error)
-	ShowUserByID(uid string) (models.OnlineUserSession, error)
-	ShowSessionsAll() (*[]interface{}, error)
-	ShowSessionsValid() (*[]interface{}, error)
-	ShowSessionBySID(sid string) (map[string]interface{}, error)
+	ShowUserByUsername(username string) (*[]models.OcctlUserDetail, error)
+	ShowUserByID(uid string) (*models.OcctlUserDetail, error)
+	ShowSessionsAll() (*[]models.OcctlSession, error)
+	ShowSessionsValid() (*[]models.OcctlSession, error)
+	ShowSessionBySID(sid string) (*models.OcctlSession, error)
 
 	Disconnect(username string) (string, error)
 	DisconnectSession(id string) (string, error)
@@ -37,33 +50,98 @@ type OcctlSecurityManager interface {
 	Reload() (string, error)
 }
 
+type OcctlNodes interface {
+	ForNode(ctx context.Context, node string) (OcctlRepositoryInterface, error)
+}
+
 type OcctlRepositoryInterface interface {
 	OcctlServerInfo
 	OcctlUserManager
 	OcctlSecurityManager
+	OcctlNodes
 }
 
 func NewOcctlRepository() *OcctlRepository {
-	return &OcctlRepository{commonOcservOcctlRepo: occtl.NewOcservOcctl()}
+	return &OcctlRepository{
+		commonOcservOcctlRepo: occtlDocker.NewOcctlClient(),
+		nodes:                 occtlDocker.NewNodeClients(database.GetConnection()),
+	}
+}
+
+// ForNode returns the repository running occtl on node, the local one
+// when node is empty.
+func (o *OcctlRepository) ForNode(ctx context.Context, node string) (OcctlRepositoryInterface, error) {
+	node = models.NodeName(node)
+	if node == models.LocalNode {
+		return &OcctlRepository{commonOcservOcctlRepo: o.commonOcservOcctlRepo, nodes: o.nodes, node: node}, nil
+	}
+
+	client, err := o.nodes.Client(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return &OcctlRepository{commonOcservOcctlRepo: client, nodes: o.nodes, node: node}, nil
 }
 
 func (o *OcctlRepository) Version() *models.ServerVersion {
 	return o.commonOcservOcctlRepo.Version()
 }
 
-func (o *OcctlRepository) Status() (interface{}, error) {
-	status, err := o.commonOcservOcctlRepo.ShowStatus(false)
+func (o *OcctlRepository) Capabilities() models.OcctlCapabilities {
+	return o.commonOcservOcctlRepo.Capabilities()
+}
+
+func (o *OcctlRepository) Status() (*models.OcctlServerStatus, error) {
+	status, err := o.commonOcservOcctlRepo.ShowStatus()
 	if err != nil {
 		return nil, err
 	}
 	return status, nil
 }
 
+// OnlineSessions returns the online sessions of the node. On the
+// repository of the whole dashboard the sessions of every enabled
+// registered node follow the local ones; unreachable nodes are left out.
 func (o *OcctlRepository) OnlineSessions() ([]models.OnlineUserSession, error) {
 	users, err := o.commonOcservOcctlRepo.OnlineSessions()
 	if err != nil {
 		return nil, err
 	}
+	for i := range users {
+		users[i].Node = models.NodeName(o.node)
+	}
+	if o.node != "" {
+		return users, nil
+	}
+
+	nodes, err := o.nodes.Enabled(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for i := range nodes {
+		wg.Add(1)
+		go func(node *models.OcservNode) {
+			defer wg.Done()
+
+			sessions, nErr := o.nodes.For(node).OnlineSessions()
+			if nErr != nil {
+				return
+			}
+			for j := range sessions {
+				sessions[j].Node = node.Name
+			}
+
+			mu.Lock()
+			users = append(users, sessions...)
+			mu.Unlock()
+		}(&nodes[i])
+	}
+	wg.Wait()
 	return users, nil
 }
 
@@ -133,23 +211,23 @@ func (o *OcctlRepository) TerminateSession(id string) (string, error) {
 	return result, nil
 }
 
-func (o *OcctlRepository) ShowUserByUsername(username string) (models.OnlineUserSession, error) {
+func (o *OcctlRepository) ShowUserByUsername(username string) (*[]models.OcctlUserDetail, error) {
 	user, err := o.commonOcservOcctlRepo.ShowUser(username)
 	if err != nil {
-		return models.OnlineUserSession{}, err
+		return nil, err
 	}
 	return user, nil
 }
 
-func (o *OcctlRepository) ShowUserByID(uid string) (models.OnlineUserSession, error) {
+func (o *OcctlRepository) ShowUserByID(uid string) (*models.OcctlUserDetail, error) {
 	user, err := o.commonOcservOcctlRepo.ShowUserByID(uid)
 	if err != nil {
-		return models.OnlineUserSession{}, err
+		return nil, err
 	}
 	return user, nil
 }
 
-func (o *OcctlRepository) ShowSessionsAll() (*[]interface{}, error) {
+func (o *OcctlRepository) ShowSessionsAll() (*[]models.OcctlSession, error) {
 	res, err := o.commonOcservOcctlRepo.ShowSessionAll()
 	if err != nil {
 		return nil, err
@@ -157,7 +235,7 @@ func (o *OcctlRepository) ShowSessionsAll() (*[]interface{}, error) {
 	return res, nil
 }
 
-func (o *OcctlRepository) ShowSessionsValid() (*[]interface{}, error) {
+func (o *OcctlRepository) ShowSessionsValid() (*[]models.OcctlSession, error) {
 	res, err := o.commonOcservOcctlRepo.ShowSessionsValid()
 	if err != nil {
 		return nil, err
@@ -165,7 +243,7 @@ func (o *OcctlRepository) ShowSessionsValid() (*[]interface{}, error) {
 	return res, nil
 }
 
-func (o *OcctlRepository) ShowSessionBySID(sid string) (map[string]interface{}, error) {
+func (o *OcctlRepository) ShowSessionBySID(sid string) (*models.OcctlSession, error) {
 	res, err := o.commonOcservOcctlRepo.ShowSession(sid)
 	if err != nil {
 		return nil, err
@@ -184,3 +262,22 @@ func (o *OcctlRepository) UnbanIP(ip string) (string, error) {
 func (o *OcctlRepository) ShowEvent() string {
 	return o.commonOcservOcctlRepo.ShowEvent()
 }
+
+// reloadOrRestore reloads ocserv after a config file changed and, when
+// ocserv refuses the reload, puts the previous state back with restore.
+// When ocserv cannot be reached, e.g. while it is stopped, the change is
+// kept: ocserv reads it when it starts.
+func reloadOrRestore(occtlRepo occtl.OcservOcctlInterface, restore func() error) error {
+	_, err := occtlRepo.ReloadConfigs()
+	if errors.Is(err, occtl.ErrOcservUnreachable) {
+		logger.Warn("ocserv was not reloaded, the change applies when it starts: %v", err)
+		return nil
+	}
+	if err != nil {
+		if restoreErr := restore(); restoreErr != nil {
+			return fmt.Errorf("reload failed: %w; restoring the previous config failed: %v", err, restoreErr)
+		}
+		return fmt.Errorf("reload failed, the previous config was restored: %w", err)
+	}
+	return nil
+}
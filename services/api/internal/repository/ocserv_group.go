@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/group"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
@@ -14,20 +15,22 @@ type OcservGroupRepository struct {
 	db                    *gorm.DB
 	commonOcservGroupRepo group.OcservGroupInterface
 	commonOcservOcctlRepo occtl.OcservOcctlInterface
+	nodes                 *occtlDocker.NodeClients
 }
 
 type OcservGroupCRUD interface {
-	Groups(ctx context.Context, pagination *request.Pagination, owner string) ([]models.OcservGroup, int64, error)
-	GroupsLookup(ctx context.Context, owner string) ([]string, error)
+	Groups(ctx context.Context, pagination *request.Pagination, owner string, vhost string) ([]models.OcservGroup, int64, error)
+	GroupsLookup(ctx context.Context, owner string, vhost string) ([]string, error)
 	GetByID(ctx context.Context, id string) (*models.OcservGroup, error)
 	Create(ctx context.Context, ocservGroup *models.OcservGroup) (*models.OcservGroup, error)
-	Update(ctx context.Context, ocservGroup *models.OcservGroup) (*models.OcservGroup, error)
+	Update(ctx context.Context, ocservGroup *models.OcservGroup, change ConfigChange) (*models.OcservGroup, error)
 	Delete(ctx context.Context, id string) (*models.OcservGroup, error)
+	EffectiveConfig(ctx context.Context, ocservGroup *models.OcservGroup) (*EffectiveGroupConfig, error)
 }
 
 type OcservDefaultGroup interface {
 	DefaultGroup() (*models.OcservGroupConfig, error)
-	UpdateDefaultGroup(groupConfig *models.OcservGroupConfig) error
+	UpdateDefaultGroup(ctx context.Context, groupConfig *models.OcservGroupConfig, change ConfigChange) error
 }
 
 type OcservGroupSync interface {
@@ -42,14 +45,16 @@ type OcservGroupRepositoryInterface interface {
 }
 
 func NewOcservGroupRepository() *OcservGroupRepository {
+	db := database.GetConnection()
 	return &OcservGroupRepository{
-		db:                    database.GetConnection(),
+		db:                    db,
 		commonOcservGroupRepo: group.NewOcservGroup(),
-		commonOcservOcctlRepo: occtl.NewOcservOcctl(),
+		commonOcservOcctlRepo: occtlDocker.NewOcctlClient(),
+		nodes:                 occtlDocker.NewNodeClients(db),
 	}
 }
 
-func (o *OcservGroupRepository) Groups(ctx context.Context, pagination *request.Pagination, owner string) (
+func (o *OcservGroupRepository) Groups(ctx context.Context, pagination *request.Pagination, owner string, vhost string) (
 	[]models.OcservGroup, int64, error,
 ) {
 	var totalRecords int64
@@ -58,6 +63,9 @@ func (o *OcservGroupRepository) Groups(ctx context.Context, pagination *request.
 	if owner != "" {
 		totalQuery = totalQuery.Where("owner = ?", owner)
 	}
+	if vhost != "" {
+		totalQuery = totalQuery.Where("vhost = ?", vhost)
+	}
 	err := totalQuery.Count(&totalRecords).Error
 	if err != nil {
 		return nil, 0, err
@@ -70,6 +78,9 @@ func (o *OcservGroupRepository) Groups(ctx context.Context, pagination *request.
 	if owner != "" {
 		query = query.Where("owner = ?", owner)
 	}
+	if vhost != "" {
+		query = query.Where("vhost = ?", vhost)
+	}
 	err = query.Find(&ocservGroups).Error
 	if err != nil {
 		return nil, 0, err
@@ -77,13 +88,16 @@ func (o *OcservGroupRepository) Groups(ctx context.Context, pagination *request.
 	return ocservGroups, totalRecords, nil
 }
 
-func (o *OcservGroupRepository) GroupsLookup(ctx context.Context, owner string) ([]string, error) {
+func (o *OcservGroupRepository) GroupsLookup(ctx context.Context, owner string, vhost string) ([]string, error) {
 	var ocservGroups []models.OcservGroup
 
 	query := o.db.WithContext(ctx).Model(&models.OcservGroup{})
 	if owner != "" {
 		query = query.Where("owner = ?", owner)
 	}
+	if vhost != "" {
+		query = query.Where("vhost = ?", vhost)
+	}
 
 	err := query.Select("name").Find(&ocservGroups).Error
 	if err != nil {
@@ -107,45 +121,73 @@ func (o *OcservGroupRepository) GetByID(ctx context.Context, id string) (*models
 }
 
 func (o *OcservGroupRepository) Create(ctx context.Context, ocservGroup *models.OcservGroup) (*models.OcservGroup, error) {
+	var effective *EffectiveGroupConfig
 	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(ocservGroup).Error; err != nil {
 			return err
 		}
-		if err := o.commonOcservGroupRepo.Create(ocservGroup.Name, ocservGroup.Config); err != nil {
+		if _, err := recordConfigRevision(
+			tx, models.OcservConfigRevisionGroup, ocservGroup.Name, ocservGroup.Config, ConfigChange{Actor: ocservGroup.Owner},
+		); err != nil {
 			return err
 		}
-		return nil
+		var err error
+		effective, err = groupEffectiveConfig(tx, ocservGroup)
+		if err != nil {
+			return err
+		}
+		if err = o.commonOcservGroupRepo.Create(ocservGroup.Name, effective.Config); err != nil {
+			return err
+		}
+		return reloadOrRestore(o.commonOcservOcctlRepo, func() error {
+			return o.commonOcservGroupRepo.RestoreConfig(ocservGroup.Name)
+		})
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		_, _ = o.commonOcservOcctlRepo.ReloadConfigs()
-	}()
-
+	syncGroupToNodes(ctx, o.nodes, ocservGroup.Name, effective.Config)
 	return ocservGroup, nil
 }
 
-func (o *OcservGroupRepository) Update(ctx context.Context, ocservGroup *models.OcservGroup) (*models.OcservGroup, error) {
+// Update saves ocservGroup, records its config as a revision by change and
+// rewrites its config file. The users of the group follow it to its
+// virtual host.
+func (o *OcservGroupRepository) Update(ctx context.Context, ocservGroup *models.OcservGroup, change ConfigChange) (*models.OcservGroup, error) {
+	var effective *EffectiveGroupConfig
 	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Model(ocservGroup).Save(ocservGroup).Error; err != nil {
 			return err
 		}
-		if err := o.commonOcservGroupRepo.Create(ocservGroup.Name, ocservGroup.Config); err != nil {
+		if err := tx.Model(&models.OcservUser{}).
+			Where(`"group" = ? AND vhost <> ?`, ocservGroup.Name, ocservGroup.VHost).
+			Update("vhost", ocservGroup.VHost).Error; err != nil {
 			return err
 		}
-		return nil
+		if _, err := recordConfigRevision(
+			tx, models.OcservConfigRevisionGroup, ocservGroup.Name, ocservGroup.Config, change,
+		); err != nil {
+			return err
+		}
+		var err error
+		effective, err = groupEffectiveConfig(tx, ocservGroup)
+		if err != nil {
+			return err
+		}
+		if err = o.commonOcservGroupRepo.Create(ocservGroup.Name, effective.Config); err != nil {
+			return err
+		}
+		return reloadOrRestore(o.commonOcservOcctlRepo, func() error {
+			return o.commonOcservGroupRepo.RestoreConfig(ocservGroup.Name)
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		_, _ = o.commonOcservOcctlRepo.ReloadConfigs()
-	}()
-
+	syncGroupToNodes(ctx, o.nodes, ocservGroup.Name, effective.Config)
 	return ocservGroup, nil
 }
 
@@ -170,10 +212,19 @@ func (o *OcservGroupRepository) Delete(ctx context.Context, id string) (*models.
 
 		return nil
 	})
+	if err == nil {
+		syncGroupToNodes(ctx, o.nodes, ocservGroup.Name, nil)
+	}
 
 	return &ocservGroup, err
 }
 
+// EffectiveConfig merges the templates of ocservGroup with its own config,
+// as written to its config file.
+func (o *OcservGroupRepository) EffectiveConfig(ctx context.Context, ocservGroup *models.OcservGroup) (*EffectiveGroupConfig, error) {
+	return groupEffectiveConfig(o.db.WithContext(ctx), ocservGroup)
+}
+
 func (o *OcservGroupRepository) DefaultGroup() (*models.OcservGroupConfig, error) {
 	defaultsGroup, err := o.commonOcservGroupRepo.DefaultsGroup()
 	if err != nil {
@@ -182,16 +233,20 @@ func (o *OcservGroupRepository) DefaultGroup() (*models.OcservGroupConfig, error
 	return defaultsGroup, nil
 }
 
-func (o *OcservGroupRepository) UpdateDefaultGroup(groupConfig *models.OcservGroupConfig) error {
-	err := o.commonOcservGroupRepo.UpdateDefaultsGroup(groupConfig)
-	if err != nil {
-		return err
-	}
-
-	go func() {
-		_, _ = o.commonOcservOcctlRepo.ReloadConfigs()
-	}()
-	return nil
+// UpdateDefaultGroup records groupConfig as a revision of the defaults
+// group by change, rewrites its config file and reloads ocserv.
+func (o *OcservGroupRepository) UpdateDefaultGroup(ctx context.Context, groupConfig *models.OcservGroupConfig, change ConfigChange) error {
+	return o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := recordConfigRevision(
+			tx, models.OcservConfigRevisionDefaults, models.OcservConfigRevisionDefaults, groupConfig, change,
+		); err != nil {
+			return err
+		}
+		if err := o.commonOcservGroupRepo.UpdateDefaultsGroup(groupConfig); err != nil {
+			return err
+		}
+		return reloadOrRestore(o.commonOcservOcctlRepo, o.commonOcservGroupRepo.RestoreDefaultsGroup)
+	})
 }
 
 func (o *OcservGroupRepository) ListUnsyncedGroups(ctx context.Context) ([]group.UnsyncedGroup, error) {
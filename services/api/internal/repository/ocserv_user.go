@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
 	"gorm.io/gorm"
 	"strings"
 	"time"
@@ -23,25 +27,45 @@ type TotalBandwidths struct {
 	TX float64 `json:"tx" validate:"required"`
 }
 
+// EffectiveUserConfig is the config ocserv applies to a user: the
+// per-user directives of ocserv.conf and of the section of its virtual
+// host, overridden by the group file (or the defaults group file when the
+// user has no group file) and then by the user file. Sources are the files
+// and sections read, lowest precedence first.
+type EffectiveUserConfig struct {
+	Username string                       `json:"username" validate:"required"`
+	Group    string                       `json:"group" validate:"required"`
+	Sources  []string                     `json:"sources" validate:"required"`
+	Values   []utils.EffectiveConfigValue `json:"values" validate:"required"`
+}
+
 type OcpasswdUser struct {
 	Username string `json:"username" validate:"required"`
 	Group    string `json:"group" validate:"required"`
 }
 
+// ErrPasswordOnlyUser is returned for certificate operations on a user
+// whose auth mode is password.
+var ErrPasswordOnlyUser = errors.New("user authenticates with a password only")
+
 type OcservUserRepository struct {
 	db                    *gorm.DB
 	commonOcservUserRepo  user.OcservUserInterface
 	commonOcservOcctlRepo occtl.OcservOcctlInterface
+	nodes                 *occtlDocker.NodeClients
 }
 
 type OcservUserCRUD interface {
-	Users(ctx context.Context, pagination *request.Pagination, owner string, q string, filters string, group string) ([]models.OcservUser, int64, error)
-	UsersByUsername(ctx context.Context, pagination *request.Pagination, owner string, usernames []string, q string, group string) ([]models.OcservUser, int64, error)
+	Users(ctx context.Context, pagination *request.Pagination, owner string, q string, filters string, group string, vhost string, node string) ([]models.OcservUser, int64, error)
+	UsersByUsername(ctx context.Context, pagination *request.Pagination, owner string, usernames []string, q string, group string, vhost string, node string) ([]models.OcservUser, int64, error)
 	Create(ctx context.Context, user *models.OcservUser) (*models.OcservUser, error)
 	GetByUID(ctx context.Context, uid string) (*models.OcservUser, error)
 	GetByUsername(ctx context.Context, username string) (*models.OcservUser, error)
-	Update(ctx context.Context, ocservUser *models.OcservUser) (*models.OcservUser, error)
+	Update(ctx context.Context, ocservUser *models.OcservUser, change ConfigChange) (*models.OcservUser, error)
+	UpdateConfig(ctx context.Context, ocservUser *models.OcservUser, change ConfigChange) (*models.OcservUser, error)
 	Delete(ctx context.Context, uid string) (string, error)
+	AuthMode(ctx context.Context, ocservUser *models.OcservUser) (string, error)
+	EffectiveConfig(ctx context.Context, ocservUser *models.OcservUser) (*EffectiveUserConfig, error)
 }
 
 type OcservUserStats interface {
@@ -58,6 +82,7 @@ type OcservUserPassword interface {
 
 type OcservUserGroup interface {
 	UpdateUsersByDeleteGroup(ctx context.Context, groupName string) ([]models.OcservUser, error)
+	ReapplyAuthMode(ctx context.Context, group *models.OcservGroup) error
 }
 
 type OcservUserActions interface {
@@ -65,8 +90,10 @@ type OcservUserActions interface {
 	UnLock(ctx context.Context, uid string) error
 	RestoreExpired(ctx context.Context, uid string, expireAt *time.Time) error
 	CreateCertificate(ctx context.Context, uid string) error
-	CertificatePath(ctx context.Context, uid string) (string, string, error)
-	CertificatePathByUsername(ctx context.Context, username string) (string, error)
+	RenewCertificate(ctx context.Context, uid string) (*models.OcservUser, error)
+	SyncCertificateExpiry(ctx context.Context) error
+	Certificate(ctx context.Context, uid string) (string, []byte, error)
+	CertificateByUsername(ctx context.Context, username string) ([]byte, error)
 }
 
 type OcservUserRepositoryInterface interface {
@@ -78,15 +105,27 @@ type OcservUserRepositoryInterface interface {
 }
 
 func NewtOcservUserRepository() *OcservUserRepository {
+	db := database.GetConnection()
 	return &OcservUserRepository{
-		db:                    database.GetConnection(),
-		commonOcservUserRepo:  user.NewOcservUser(),
-		commonOcservOcctlRepo: occtl.NewOcservOcctl(),
+		db:                    db,
+		commonOcservUserRepo:  occtlDocker.NewOcservUserClient(),
+		commonOcservOcctlRepo: occtlDocker.NewOcctlClient(),
+		nodes:                 occtlDocker.NewNodeClients(db),
 	}
 }
 
+// clients returns the ocpasswd and occtl clients of the node users are
+// provisioned on.
+func (o *OcservUserRepository) clients(ctx context.Context, node string) (user.OcservUserInterface, occtl.OcservOcctlInterface, error) {
+	return nodeClients(ctx, o.nodes, node, o.commonOcservUserRepo, o.commonOcservOcctlRepo)
+}
+
 func (o *OcservUserRepository) applyCertificateStatus(ocservUser *models.OcservUser) {
-	status := o.commonOcservUserRepo.CertificateStatus(ocservUser.Username)
+	userClient, _, err := o.clients(context.Background(), ocservUser.Node)
+	if err != nil {
+		return
+	}
+	status := userClient.CertificateStatus(ocservUser.Username)
 	ocservUser.CertificateEnabled = status.Enabled
 	ocservUser.CertificateAvailable = status.Available
 }
@@ -98,6 +137,8 @@ func (o *OcservUserRepository) Users(
 	q string,
 	filter string,
 	group string,
+	vhost string,
+	node string,
 ) (
 	[]models.OcservUser, int64, error,
 ) {
@@ -113,6 +154,12 @@ func (o *OcservUserRepository) Users(
 		if group != "" {
 			db = db.Where(`"group" = ?`, group)
 		}
+		if vhost != "" {
+			db = db.Where("vhost = ?", vhost)
+		}
+		if node != "" {
+			db = db.Where("node = ?", node)
+		}
 
 		switch filter {
 		case "active":
@@ -154,6 +201,8 @@ func (o *OcservUserRepository) UsersByUsername(
 	usernames []string,
 	q string,
 	group string,
+	vhost string,
+	node string,
 ) ([]models.OcservUser, int64, error) {
 	applyFilters := func(db *gorm.DB) *gorm.DB {
 		if owner != "" {
@@ -168,6 +217,14 @@ func (o *OcservUserRepository) UsersByUsername(
 			db = db.Where(`"group" = ?`, group)
 		}
 
+		if vhost != "" {
+			db = db.Where("vhost = ?", vhost)
+		}
+
+		if node != "" {
+			db = db.Where("node = ?", node)
+		}
+
 		return db
 	}
 
@@ -199,28 +256,73 @@ func (o *OcservUserRepository) UsersByUsername(
 	return ocservUser, totalRecords, nil
 }
 
+// AuthMode resolves the auth mode of the user against its group. A failed
+// group lookup is returned rather than read as the default mode, which
+// would move certificate users to passwords.
+func (o *OcservUserRepository) AuthMode(ctx context.Context, ocservUser *models.OcservUser) (string, error) {
+	if ocservUser.AuthMode != "" {
+		return ocservUser.AuthMode, nil
+	}
+
+	var groupMode string
+	if err := o.db.WithContext(ctx).
+		Model(&models.OcservGroup{}).
+		Where("name = ?", ocservUser.Group).
+		Limit(1).
+		Pluck("auth_mode", &groupMode).Error; err != nil {
+		return "", fmt.Errorf("resolve auth mode of %s: %w", ocservUser.Username, err)
+	}
+	return models.ResolveAuthMode(groupMode), nil
+}
+
 func (o *OcservUserRepository) Create(ctx context.Context, ocservUser *models.OcservUser) (*models.OcservUser, error) {
-	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	mode, err := o.AuthMode(ctx, ocservUser)
+	if err != nil {
+		return nil, err
+	}
+
+	ocservUser.Node = models.NodeName(ocservUser.Node)
+	userClient, occtlClient, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = syncGroupToNode(ctx, o.db, o.nodes, ocservUser.Node, ocservUser.Group); err != nil {
+		return nil, err
+	}
+
+	// what was on disk before, e.g. an ocpasswd entry the database did not
+	// know about, is put back when ocserv rejects the new user
+	state, err := userClient.SaveState(ocservUser.Username)
+	if err != nil {
+		return nil, err
+	}
+	restore := func() error {
+		return userClient.RestoreState(ocservUser.Username, state)
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(ocservUser).Error; err != nil {
 			return err
 		}
-		if err := o.commonOcservUserRepo.Create(ocservUser.Group, ocservUser.Username, ocservUser.Password, ocservUser.Config); err != nil {
+		if _, err := recordConfigRevision(
+			tx, models.OcservConfigRevisionUser, ocservUser.Username, ocservUser.Config, ConfigChange{Actor: ocservUser.Owner},
+		); err != nil {
 			return err
 		}
-
-		if err := o.commonOcservUserRepo.CreateCertificate(ocservUser.Username, ocservUser.Password); err != nil {
-			_, _ = o.commonOcservUserRepo.Delete(ocservUser.Username)
+		if err := userClient.ApplyAuthMode(
+			ocservUser.Group, ocservUser.Username, ocservUser.Password, mode, ocservUser.Config,
+		); err != nil {
+			_ = restore()
 			return err
 		}
-		return nil
+		return reloadOrRestore(occtlClient, restore)
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		_, _ = o.commonOcservOcctlRepo.ReloadConfigs()
-	}()
+	o.recordCertificateExpiry(ctx, ocservUser)
 	o.applyCertificateStatus(ocservUser)
 	return ocservUser, err
 }
@@ -247,26 +349,152 @@ func (o *OcservUserRepository) GetByUsername(ctx context.Context, username strin
 	return &ocservUser, nil
 }
 
-func (o *OcservUserRepository) Update(ctx context.Context, ocservUser *models.OcservUser) (*models.OcservUser, error) {
-	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+// Update saves ocservUser, records its config as a revision by change and
+// rewrites its ocserv files.
+func (o *OcservUserRepository) Update(ctx context.Context, ocservUser *models.OcservUser, change ConfigChange) (*models.OcservUser, error) {
+	userClient, occtlClient, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = syncGroupToNode(ctx, o.db, o.nodes, ocservUser.Node, ocservUser.Group); err != nil {
+		return nil, err
+	}
+
+	mode, err := o.AuthMode(ctx, ocservUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// the password, ocpasswd entry, config file and certificate all change
+	// with the auth mode, so all of them are put back on a rejected reload
+	state, err := userClient.SaveState(ocservUser.Username)
+	if err != nil {
+		return nil, err
+	}
+	restore := func() error {
+		return userClient.RestoreState(ocservUser.Username, state)
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Save(&ocservUser).Error; err != nil {
 			return err
 		}
-		if err := o.commonOcservUserRepo.Create(ocservUser.Group, ocservUser.Username, ocservUser.Password, ocservUser.Config); err != nil {
+		if _, err := recordConfigRevision(
+			tx, models.OcservConfigRevisionUser, ocservUser.Username, ocservUser.Config, change,
+		); err != nil {
 			return err
 		}
-		return nil
+		if err := userClient.ApplyAuthMode(
+			ocservUser.Group, ocservUser.Username, ocservUser.Password, mode, ocservUser.Config,
+		); err != nil {
+			_ = restore()
+			return err
+		}
+		return reloadOrRestore(occtlClient, restore)
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		_, _ = o.commonOcservOcctlRepo.ReloadConfigs()
-	}()
+	o.recordCertificateExpiry(ctx, ocservUser)
 	return ocservUser, nil
 }
 
+// UpdateConfig stores the config of ocservUser and rewrites its config file
+// only, leaving the ocpasswd entry, lock state and certificate as they
+// are. The previous file is put back when ocserv refuses the reload.
+func (o *OcservUserRepository) UpdateConfig(ctx context.Context, ocservUser *models.OcservUser, change ConfigChange) (*models.OcservUser, error) {
+	userClient, occtlClient, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := func() error {
+		return userClient.RestoreConfig(ocservUser.Username)
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(ocservUser).Update("config", ocservUser.Config).Error; err != nil {
+			return err
+		}
+		if _, err := recordConfigRevision(
+			tx, models.OcservConfigRevisionUser, ocservUser.Username, ocservUser.Config, change,
+		); err != nil {
+			return err
+		}
+		if err := userClient.SyncConfig(ocservUser.Username, ocservUser.Group, ocservUser.Config); err != nil {
+			_ = restore()
+			return err
+		}
+		return reloadOrRestore(occtlClient, restore)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ocservUser, nil
+}
+
+// EffectiveConfig reads the config files ocserv merges for ocservUser on
+// the node it is provisioned on, and returns each directive with the file
+// it comes from.
+func (o *OcservUserRepository) EffectiveConfig(ctx context.Context, ocservUser *models.OcservUser) (*EffectiveUserConfig, error) {
+	userClient, _, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	effective, err := userClient.EffectiveConfig(ocservUser.Username, ocservUser.Group, ocservUser.VHost)
+	if err != nil {
+		return nil, err
+	}
+	return &EffectiveUserConfig{
+		Username: ocservUser.Username,
+		Group:    ocservUser.Group,
+		Sources:  effective.Sources,
+		Values:   effective.Values,
+	}, nil
+}
+
+// ReapplyAuthMode rewrites the ocserv credentials of the users of group
+// that inherit its auth mode, after the group mode changed.
+func (o *OcservUserRepository) ReapplyAuthMode(ctx context.Context, group *models.OcservGroup) error {
+	var users []models.OcservUser
+
+	if err := o.db.WithContext(ctx).
+		Where(`"group" = ? AND auth_mode = ''`, group.Name).
+		Find(&users).Error; err != nil {
+		return err
+	}
+
+	mode := models.ResolveAuthMode(group.AuthMode)
+
+	var errs []error
+	reload := make(map[string]occtl.OcservOcctlInterface)
+	for i := range users {
+		u := &users[i]
+		userClient, occtlClient, err := o.clients(ctx, u.Node)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", u.Username, err))
+			continue
+		}
+		if err = userClient.ApplyAuthMode(u.Group, u.Username, u.Password, mode, u.Config); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", u.Username, err))
+			continue
+		}
+		if u.IsLocked {
+			_, _ = userClient.Lock(u.Username)
+		}
+		o.recordCertificateExpiry(ctx, u)
+		reload[models.NodeName(u.Node)] = occtlClient
+	}
+
+	for _, occtlClient := range reload {
+		_, _ = occtlClient.ReloadConfigs()
+	}
+	return errors.Join(errs...)
+}
+
 func (o *OcservUserRepository) Lock(ctx context.Context, uid string) error {
 	var ocservUser models.OcservUser
 	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -276,11 +504,15 @@ func (o *OcservUserRepository) Lock(ctx context.Context, uid string) error {
 		if err := tx.
 			Model(&models.OcservUser{}).
 			Where("uid = ?", uid).
-			Updates(map[string]interface{}{"is_locked": true}).Error; err != nil {
+			Updates(map[string]interface{}{"is_locked": true, "auth_locked_until": nil}).Error; err != nil {
 			return err
 		}
 
-		if _, err := o.commonOcservUserRepo.Lock(ocservUser.Username); err != nil {
+		userClient, _, err := o.clients(ctx, ocservUser.Node)
+		if err != nil {
+			return err
+		}
+		if _, err = userClient.Lock(ocservUser.Username); err != nil {
 			return err
 		}
 		return nil
@@ -297,11 +529,15 @@ func (o *OcservUserRepository) UnLock(ctx context.Context, uid string) error {
 		if err := tx.
 			Model(&models.OcservUser{}).
 			Where("uid = ?", uid).
-			Updates(map[string]interface{}{"is_locked": false}).Error; err != nil {
+			Updates(map[string]interface{}{"is_locked": false, "auth_locked_until": nil}).Error; err != nil {
 			return err
 		}
 
-		if _, err := o.commonOcservUserRepo.UnLock(ocservUser.Username); err != nil {
+		userClient, _, err := o.clients(ctx, ocservUser.Node)
+		if err != nil {
+			return err
+		}
+		if _, err = userClient.UnLock(ocservUser.Username); err != nil {
 			return err
 		}
 		return nil
@@ -311,22 +547,30 @@ func (o *OcservUserRepository) UnLock(ctx context.Context, uid string) error {
 
 func (o *OcservUserRepository) Delete(ctx context.Context, uid string) (string, error) {
 	var ocservUser models.OcservUser
+	var occtlClient occtl.OcservOcctlInterface
 	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("uid = ?", uid).First(&ocservUser).Error; err != nil {
 			return err
 		}
-		if err := tx.Delete(&ocservUser).Error; err != nil {
+		userClient, nodeOcctl, err := o.clients(ctx, ocservUser.Node)
+		if err != nil {
+			return err
+		}
+		occtlClient = nodeOcctl
+		if err = tx.Delete(&ocservUser).Error; err != nil {
 			return err
 		}
-		if _, err := o.commonOcservUserRepo.Delete(ocservUser.Username); err != nil {
+		if _, err = userClient.Delete(ocservUser.Username); err != nil {
 			return err
 		}
 		return nil
 	})
 
-	go func() {
-		_, _ = o.commonOcservOcctlRepo.ReloadConfigs()
-	}()
+	if occtlClient != nil {
+		go func() {
+			_, _ = occtlClient.ReloadConfigs()
+		}()
+	}
 
 	return ocservUser.Username, err
 }
@@ -508,12 +752,17 @@ func (o *OcservUserRepository) RestoreExpired(ctx context.Context, uid string, e
 			return err
 		}
 
-		terminateOutput, err := o.commonOcservOcctlRepo.TerminateUser(u.Username)
+		userClient, occtlClient, err := o.clients(ctx, u.Node)
+		if err != nil {
+			return err
+		}
+
+		terminateOutput, err := occtlClient.TerminateUser(u.Username)
 		if err != nil && !isNoActiveOcctlUserError(terminateOutput, err) {
 			return fmt.Errorf("failed to terminate ocserv user %q: %s: %w", u.Username, strings.TrimSpace(terminateOutput), err)
 		}
 
-		unlockOutput, err := o.commonOcservUserRepo.UnLock(u.Username)
+		unlockOutput, err := userClient.UnLock(u.Username)
 		if err != nil && !isAlreadyUnlockedOcpasswdError(unlockOutput, err) {
 			return fmt.Errorf("failed to unlock ocserv user %q: %s: %w", u.Username, strings.TrimSpace(unlockOutput), err)
 		}
@@ -546,36 +795,153 @@ func (o *OcservUserRepository) CreateCertificate(ctx context.Context, uid string
 		return err
 	}
 
-	return o.commonOcservUserRepo.CreateCertificate(ocservUser.Username, ocservUser.Password)
+	mode, err := o.AuthMode(ctx, &ocservUser)
+	if err != nil {
+		return err
+	}
+	if !models.AuthModeUsesCertificate(mode) {
+		return ErrPasswordOnlyUser
+	}
+
+	userClient, _, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return err
+	}
+	if err = userClient.CreateCertificate(ocservUser.Username, ocservUser.Password); err != nil {
+		return err
+	}
+
+	o.recordCertificateExpiry(ctx, &ocservUser)
+	return nil
+}
+
+// RenewCertificate issues a new certificate for the user and revokes the
+// current one through the CRL.
+func (o *OcservUserRepository) RenewCertificate(ctx context.Context, uid string) (*models.OcservUser, error) {
+	var ocservUser models.OcservUser
+
+	if err := o.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		First(&ocservUser).Error; err != nil {
+		return nil, err
+	}
+
+	mode, err := o.AuthMode(ctx, &ocservUser)
+	if err != nil {
+		return nil, err
+	}
+	if !models.AuthModeUsesCertificate(mode) {
+		return nil, ErrPasswordOnlyUser
+	}
+
+	userClient, _, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return nil, err
+	}
+	if err = userClient.RenewCertificate(ocservUser.Username, ocservUser.Password); err != nil {
+		return nil, err
+	}
+
+	// the renewal itself succeeded, a missing expiry is backfilled on restart
+	o.recordCertificateExpiry(ctx, &ocservUser)
+	o.applyCertificateStatus(&ocservUser)
+	return &ocservUser, nil
+}
+
+// SyncCertificateExpiry stores the certificate expiry of the users created
+// before it was tracked.
+func (o *OcservUserRepository) SyncCertificateExpiry(ctx context.Context) error {
+	var users []models.OcservUser
+
+	if err := o.db.WithContext(ctx).
+		Select("id", "username", "node").
+		Where("certificate_expires_at IS NULL").
+		Find(&users).Error; err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := o.storeCertificateExpiry(ctx, &users[i]); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", users[i].Username, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func (o *OcservUserRepository) CertificatePath(ctx context.Context, uid string) (string, string, error) {
+// recordCertificateExpiry stores the certificate expiry of ocservUser
+// after its certificate changed. The change itself succeeded, so a failure
+// is only logged; SyncCertificateExpiry fills the date in later.
+func (o *OcservUserRepository) recordCertificateExpiry(ctx context.Context, ocservUser *models.OcservUser) {
+	if err := o.storeCertificateExpiry(ctx, ocservUser); err != nil {
+		logger.Warn("failed to store the certificate expiry of user %s: %v", ocservUser.Username, err)
+	}
+}
+
+// storeCertificateExpiry reads the not-after date of the user certificate
+// and saves it, or clears it when the user has no certificate.
+func (o *OcservUserRepository) storeCertificateExpiry(ctx context.Context, ocservUser *models.OcservUser) error {
+	userClient, _, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return err
+	}
+
+	notAfter, err := userClient.CertificateNotAfter(ocservUser.Username)
+	if err != nil {
+		return err
+	}
+
+	if err = o.db.WithContext(ctx).
+		Model(&models.OcservUser{}).
+		Where("id = ?", ocservUser.ID).
+		Update("certificate_expires_at", notAfter).Error; err != nil {
+		return err
+	}
+	ocservUser.CertificateExpiresAt = notAfter
+	return nil
+}
+
+// Certificate returns the username and the PKCS#12 bundle of the user,
+// read from the node the user is provisioned on.
+func (o *OcservUserRepository) Certificate(ctx context.Context, uid string) (string, []byte, error) {
 	var ocservUser models.OcservUser
 
 	if err := o.db.WithContext(ctx).
 		Where("uid = ?", uid).
 		First(&ocservUser).Error; err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 
-	path, err := o.commonOcservUserRepo.CertificatePath(ocservUser.Username)
+	userClient, _, err := o.clients(ctx, ocservUser.Node)
 	if err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 
-	return ocservUser.Username, path, nil
+	content, err := userClient.Certificate(ocservUser.Username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ocservUser.Username, content, nil
 }
 
-func (o *OcservUserRepository) CertificatePathByUsername(ctx context.Context, username string) (string, error) {
+func (o *OcservUserRepository) CertificateByUsername(ctx context.Context, username string) ([]byte, error) {
 	var ocservUser models.OcservUser
 
 	if err := o.db.WithContext(ctx).
 		Where("username = ?", username).
 		First(&ocservUser).Error; err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return o.commonOcservUserRepo.CertificatePath(ocservUser.Username)
+	userClient, _, err := o.clients(ctx, ocservUser.Node)
+	if err != nil {
+		return nil, err
+	}
+	return userClient.Certificate(ocservUser.Username)
 }
 
 func isAlreadyUnlockedOcpasswdError(output string, err error) bool {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
 	"gorm.io/gorm"
@@ -25,6 +26,32 @@ type ReportRepositoryInterface interface {
 	TotalBandWidthUser(ctx context.Context, uid string) (TotalBandwidths, error)
 	TenDaysStats(ctx context.Context) ([]models.DailyTraffic, error)
 	UsersStat(ctx context.Context) (UserStatsResult, error)
+	AuthAlerts(ctx context.Context, pagination *request.Pagination, kind string) (*[]models.OcservAuthAlert, int64, error)
+	ExpiringCertificates(ctx context.Context, before time.Time) ([]ExpiringCertificate, error)
+	VHostUsage(ctx context.Context, dateStart, dateEnd *time.Time) ([]VHostUsage, error)
+}
+
+// VHostUsage is the share of a virtual host in the groups, users and
+// traffic. RX and TX are in GiB.
+type VHostUsage struct {
+	VHost       string  `json:"vhost" validate:"required"`
+	Groups      int64   `json:"groups" validate:"required"`
+	Users       int64   `json:"users" validate:"required"`
+	Active      int64   `json:"active" validate:"required"`
+	Deactivated int64   `json:"deactivated" validate:"required"`
+	Locked      int64   `json:"locked" validate:"required"`
+	Online      int     `json:"online" validate:"required"`
+	RX          float64 `json:"rx" validate:"required"`
+	TX          float64 `json:"tx" validate:"required"`
+}
+
+// ExpiringCertificate is an active user whose client certificate expires
+// soon, or already expired.
+type ExpiringCertificate struct {
+	UID                  string    `json:"uid" validate:"required"`
+	Username             string    `json:"username" validate:"required"`
+	Owner                string    `json:"owner" validate:"required"`
+	CertificateExpiresAt time.Time `json:"certificate_expires_at" validate:"required"`
 }
 
 type UserStatsResult struct {
@@ -36,7 +63,7 @@ type UserStatsResult struct {
 func NewtReportRepository() *ReportRepository {
 	return &ReportRepository{
 		db:                   database.GetConnection(),
-		commonOcservUserRepo: user.NewOcservUser(),
+		commonOcservUserRepo: occtlDocker.NewOcservUserClient(),
 	}
 }
 
@@ -244,3 +271,146 @@ func (r *ReportRepository) TotalBandWidthUser(ctx context.Context, uid string) (
 	}
 	return total, nil
 }
+
+// AuthAlerts lists the failed login alerts raised by log_stream, optionally
+// of a single kind.
+func (r *ReportRepository) AuthAlerts(ctx context.Context, pagination *request.Pagination, kind string) (*[]models.OcservAuthAlert, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservAuthAlert{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var alerts []models.OcservAuthAlert
+	if err := request.Paginator(ctx, query, pagination).Find(&alerts).Error; err != nil {
+		return nil, 0, err
+	}
+	return &alerts, totalRecords, nil
+}
+
+// ExpiringCertificates returns the users of active accounts whose
+// certificate expires before before, soonest first.
+func (r *ReportRepository) ExpiringCertificates(ctx context.Context, before time.Time) ([]ExpiringCertificate, error) {
+	var results []ExpiringCertificate
+	err := r.db.WithContext(ctx).
+		Model(&models.OcservUser{}).
+		Select("uid, username, owner, certificate_expires_at").
+		Where("certificate_expires_at IS NOT NULL AND certificate_expires_at < ?", before).
+		Where("deactivated_at IS NULL").
+		Order("certificate_expires_at ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// VHostUsage breaks the groups, users and traffic down per virtual host,
+// the default one first. Traffic is limited to dateStart and dateEnd when
+// set. Online is left to the caller, it comes from occtl.
+func (r *ReportRepository) VHostUsage(ctx context.Context, dateStart, dateEnd *time.Time) ([]VHostUsage, error) {
+	var names []string
+	if err := r.db.WithContext(ctx).Model(&models.OcservVHost{}).Order("name").Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+
+	var order []string
+	byName := make(map[string]*VHostUsage)
+	entry := func(name string) *VHostUsage {
+		if u, ok := byName[name]; ok {
+			return u
+		}
+		u := &VHostUsage{VHost: name}
+		byName[name] = u
+		order = append(order, name)
+		return u
+	}
+	entry(models.DefaultVHost)
+	for _, name := range names {
+		entry(name)
+	}
+
+	var groups []struct {
+		VHost string `gorm:"column:vhost"`
+		Count int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.OcservGroup{}).
+		Select("vhost, COUNT(*) AS count").
+		Group("vhost").
+		Scan(&groups).Error; err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		entry(g.VHost).Groups = g.Count
+	}
+
+	var users []struct {
+		VHost       string `gorm:"column:vhost"`
+		Total       int64
+		Active      int64
+		Deactivated int64
+		Locked      int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.OcservUser{}).
+		Select(`
+			vhost,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE deactivated_at IS NULL AND is_locked = false) AS active,
+			COUNT(*) FILTER (WHERE deactivated_at IS NOT NULL) AS deactivated,
+			COUNT(*) FILTER (WHERE is_locked = true) AS locked
+		`).
+		Group("vhost").
+		Scan(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		e := entry(u.VHost)
+		e.Users = u.Total
+		e.Active = u.Active
+		e.Deactivated = u.Deactivated
+		e.Locked = u.Locked
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("ocserv_user_traffic_statistics AS t").
+		Joins("JOIN ocserv_users ou ON ou.id = t.oc_user_id").
+		Select(`
+			ou.vhost AS vhost,
+			COALESCE(SUM(t.rx),0) / 1073741824.0 AS rx,
+			COALESCE(SUM(t.tx),0) / 1073741824.0 AS tx
+		`).
+		Group("ou.vhost")
+	if dateStart != nil {
+		query = query.Where("t.created_at >= ?", *dateStart)
+	}
+	if dateEnd != nil {
+		query = query.Where("t.created_at <= ?", *dateEnd)
+	}
+
+	var traffic []struct {
+		VHost string `gorm:"column:vhost"`
+		RX    float64
+		TX    float64
+	}
+	if err := query.Scan(&traffic).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range traffic {
+		e := entry(t.VHost)
+		e.RX = t.RX
+		e.TX = t.TX
+	}
+
+	usage := make([]VHostUsage, 0, len(order))
+	for _, name := range order {
+		usage = append(usage, *byName[name])
+	}
+	return usage, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/server"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ErrServerConfigUnchanged is returned when an applied config does not
+// change ocserv.conf.
+var ErrServerConfigUnchanged = errors.New("the configuration has no changes")
+
+// serverConfigMu serializes the rendering and writing of ocserv.conf.
+var serverConfigMu sync.Mutex
+
+// ServerConfigPreview is ocserv.conf as a config would render it, with the
+// changes against the current file.
+type ServerConfigPreview struct {
+	Content         string                     `json:"content" validate:"required"`
+	Changes         models.OcservConfigChanges `json:"changes" validate:"required"`
+	RestartRequired bool                       `json:"restart_required" validate:"required"`
+}
+
+type ServerConfigRepository struct {
+	db                     *gorm.DB
+	commonOcservServerRepo server.OcservServerInterface
+	commonOcservOcctlRepo  occtl.OcservOcctlInterface
+	systemdRepo            SystemdRepositoryInterface
+}
+
+type ServerConfigRepositoryInterface interface {
+	Config(ctx context.Context) (*models.OcservServerConfig, error)
+	CheckAuthMode(ctx context.Context, mode string) error
+	Preview(ctx context.Context, config *models.OcservServerConfig) (*ServerConfigPreview, error)
+	Apply(ctx context.Context, config *models.OcservServerConfig, actor string) (*models.OcservServerConfigRevision, error)
+	Revisions(ctx context.Context, pagination *request.Pagination) (*[]models.OcservServerConfigRevision, int64, error)
+	Revision(ctx context.Context, id uint) (*models.OcservServerConfigRevision, error)
+}
+
+func NewServerConfigRepository() *ServerConfigRepository {
+	return &ServerConfigRepository{
+		db:                     database.GetConnection(),
+		commonOcservServerRepo: server.NewOcservServer(),
+		commonOcservOcctlRepo:  occtlDocker.NewOcctlClient(),
+		systemdRepo:            NewSystemdRepository("ocserv"),
+	}
+}
+
+func (r *ServerConfigRepository) Config(ctx context.Context) (*models.OcservServerConfig, error) {
+	return r.commonOcservServerRepo.Config()
+}
+
+// CheckAuthMode checks that ocserv.conf lets users in the auth mode log in.
+// In remote mode ocserv.conf is not here, the webhook checks it when the
+// mode is applied.
+func (r *ServerConfigRepository) CheckAuthMode(ctx context.Context, mode string) error {
+	if models.AuthModeUsesPassword(mode) || occtlDocker.RemoteMode() {
+		return nil
+	}
+	config, err := r.Config(ctx)
+	if err != nil {
+		return err
+	}
+	return server.CheckAuthMode(config, mode)
+}
+
+func (r *ServerConfigRepository) Preview(ctx context.Context, config *models.OcservServerConfig) (*ServerConfigPreview, error) {
+	current, err := r.commonOcservServerRepo.Content()
+	if err != nil {
+		return nil, err
+	}
+	content, err := r.commonOcservServerRepo.Render(config)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := server.Diff(current, content)
+	return &ServerConfigPreview{
+		Content:         string(content),
+		Changes:         changes,
+		RestartRequired: server.RestartRequired(changes),
+	}, nil
+}
+
+// Apply writes ocserv.conf, reloads ocserv and records the revision. When a
+// changed directive is only read at startup, ocserv is restarted under
+// systemd; in docker the revision is marked restart_required and the
+// container has to be restarted by hand. If ocserv refuses the reload or
+// fails to restart, the previous ocserv.conf is put back and no revision is
+// kept.
+func (r *ServerConfigRepository) Apply(ctx context.Context, config *models.OcservServerConfig, actor string) (*models.OcservServerConfigRevision, error) {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	preview, err := r.Preview(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(preview.Changes) == 0 {
+		return nil, ErrServerConfigUnchanged
+	}
+
+	action := models.OcservServerConfigReload
+	if preview.RestartRequired {
+		action = models.OcservServerConfigRestartRequired
+		if os.Getenv("SYSTEMD") == "true" {
+			action = models.OcservServerConfigRestart
+		}
+	}
+
+	revision := &models.OcservServerConfigRevision{
+		Content: preview.Content,
+		Changes: preview.Changes,
+		Action:  action,
+		Actor:   actor,
+	}
+
+	// the revision is inserted before ocserv picks the file up, so a failed
+	// apply leaves no revision and an applied config always has one
+	applied := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+
+		if err := r.commonOcservServerRepo.Write([]byte(preview.Content)); err != nil {
+			return err
+		}
+
+		switch action {
+		case models.OcservServerConfigReload:
+			if err := reloadOrRestore(r.commonOcservOcctlRepo, r.commonOcservServerRepo.Restore); err != nil {
+				return err
+			}
+		case models.OcservServerConfigRestart:
+			if err := r.systemdRepo.Restart(ctx); err != nil {
+				if restoreErr := r.commonOcservServerRepo.Restore(); restoreErr != nil {
+					return fmt.Errorf("restart failed: %w; restoring the previous config failed: %v", err, restoreErr)
+				}
+				_ = r.systemdRepo.Restart(ctx)
+				return fmt.Errorf("restart failed, the previous config was restored: %w", err)
+			}
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		if applied {
+			// the commit failed after ocserv took the new config
+			if restoreErr := r.commonOcservServerRepo.Restore(); restoreErr != nil {
+				return nil, fmt.Errorf("saving the revision failed: %w; restoring the previous config failed: %v", err, restoreErr)
+			}
+			switch action {
+			case models.OcservServerConfigReload:
+				_, _ = r.commonOcservOcctlRepo.ReloadConfigs()
+			case models.OcservServerConfigRestart:
+				_ = r.systemdRepo.Restart(ctx)
+			}
+			return nil, fmt.Errorf("saving the revision failed, the previous config was restored: %w", err)
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// Revisions lists the applied versions of ocserv.conf, newest first by
+// default.
+func (r *ServerConfigRepository) Revisions(ctx context.Context, pagination *request.Pagination) (*[]models.OcservServerConfigRevision, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservServerConfigRevision{})
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []models.OcservServerConfigRevision
+	if err := request.Paginator(ctx, query, pagination).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return &revisions, totalRecords, nil
+}
+
+func (r *ServerConfigRepository) Revision(ctx context.Context, id uint) (*models.OcservServerConfigRevision, error) {
+	var revision models.OcservServerConfigRevision
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/server"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ErrDefaultVHost is returned when the default virtual host, the global
+// section of ocserv.conf, is created, changed or deleted as a virtual host.
+var ErrDefaultVHost = errors.New("the default virtual host is the global section of ocserv.conf, change it in the server config")
+
+// ErrVHostNotFound is returned for a virtual host that is not defined.
+var ErrVHostNotFound = errors.New("virtual host not found")
+
+type VHostRepository struct {
+	db                     *gorm.DB
+	commonOcservServerRepo server.OcservServerInterface
+	commonOcservOcctlRepo  occtl.OcservOcctlInterface
+}
+
+type VHostRepositoryInterface interface {
+	VHosts(ctx context.Context, pagination *request.Pagination) (*[]models.OcservVHost, int64, error)
+	Lookup(ctx context.Context) ([]string, error)
+	GetByID(ctx context.Context, id string) (*models.OcservVHost, error)
+	Create(ctx context.Context, vhost *models.OcservVHost) (*models.OcservVHost, error)
+	Update(ctx context.Context, vhost *models.OcservVHost) (*models.OcservVHost, error)
+	Delete(ctx context.Context, id string) error
+	Exists(ctx context.Context, name string) error
+	ScopeUser(ctx context.Context, ocservUser *models.OcservUser) error
+}
+
+func NewVHostRepository() *VHostRepository {
+	return &VHostRepository{
+		db:                     database.GetConnection(),
+		commonOcservServerRepo: server.NewOcservServer(),
+		commonOcservOcctlRepo:  occtlDocker.NewOcctlClient(),
+	}
+}
+
+func (r *VHostRepository) VHosts(ctx context.Context, pagination *request.Pagination) (*[]models.OcservVHost, int64, error) {
+	var totalRecords int64
+
+	query := r.db.WithContext(ctx).Model(&models.OcservVHost{})
+	if err := query.Count(&totalRecords).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var vhosts []models.OcservVHost
+	if err := request.Paginator(ctx, query, pagination).Find(&vhosts).Error; err != nil {
+		return nil, 0, err
+	}
+	return &vhosts, totalRecords, nil
+}
+
+// Lookup returns the names of the virtual hosts, the default one first.
+func (r *VHostRepository) Lookup(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := r.db.WithContext(ctx).Model(&models.OcservVHost{}).Order("name").Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+	return append([]string{models.DefaultVHost}, names...), nil
+}
+
+func (r *VHostRepository) GetByID(ctx context.Context, id string) (*models.OcservVHost, error) {
+	var vhost models.OcservVHost
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&vhost).Error; err != nil {
+		return nil, err
+	}
+	return &vhost, nil
+}
+
+// Create adds the virtual host with its section in ocserv.conf and reloads
+// ocserv. When ocserv refuses the reload, the previous ocserv.conf is put
+// back and the virtual host is not created. Users are written to the one
+// ocpasswd file, so password logins of the virtual host must use it, see
+// server.CheckVHostAuth.
+func (r *VHostRepository) Create(ctx context.Context, vhost *models.OcservVHost) (*models.OcservVHost, error) {
+	if vhost.Name == models.DefaultVHost {
+		return nil, ErrDefaultVHost
+	}
+	if vhost.Config == nil {
+		vhost.Config = &models.OcservVHostConfig{}
+	}
+	if err := server.CheckVHostAuth(vhost.Config); err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(vhost).Error; err != nil {
+			return err
+		}
+		return r.writeSection(vhost.Name, vhost.Config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vhost, nil
+}
+
+// Update saves the virtual host and rewrites its section of ocserv.conf,
+// like Create.
+func (r *VHostRepository) Update(ctx context.Context, vhost *models.OcservVHost) (*models.OcservVHost, error) {
+	if vhost.Config == nil {
+		vhost.Config = &models.OcservVHostConfig{}
+	}
+	if err := server.CheckVHostAuth(vhost.Config); err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(vhost).Error; err != nil {
+			return err
+		}
+		return r.writeSection(vhost.Name, vhost.Config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vhost, nil
+}
+
+// Delete removes a virtual host no group or user belongs to, with its
+// section of ocserv.conf.
+func (r *VHostRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var vhost models.OcservVHost
+		if err := tx.Where("id = ?", id).First(&vhost).Error; err != nil {
+			return err
+		}
+
+		var groups, users int64
+		if err := tx.Model(&models.OcservGroup{}).Where("vhost = ?", vhost.Name).Count(&groups).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.OcservUser{}).Where("vhost = ?", vhost.Name).Count(&users).Error; err != nil {
+			return err
+		}
+		if groups > 0 || users > 0 {
+			return fmt.Errorf("virtual host %s still has %d groups and %d users", vhost.Name, groups, users)
+		}
+
+		if err := tx.Delete(&vhost).Error; err != nil {
+			return err
+		}
+		return r.writeSection(vhost.Name, nil)
+	})
+}
+
+// Exists returns ErrVHostNotFound unless name is the default virtual host
+// or a defined one.
+func (r *VHostRepository) Exists(ctx context.Context, name string) error {
+	if name == models.DefaultVHost {
+		return nil
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.OcservVHost{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s", ErrVHostNotFound, name)
+	}
+	return nil
+}
+
+// ScopeUser sets the virtual host of ocservUser from its group when it has
+// none, and checks both agree. Users of the defaults group may belong to any
+// virtual host, the default one unless set.
+func (r *VHostRepository) ScopeUser(ctx context.Context, ocservUser *models.OcservUser) error {
+	if ocservUser.Group != "" && ocservUser.Group != "defaults" {
+		var groupVHost string
+		if err := r.db.WithContext(ctx).
+			Model(&models.OcservGroup{}).
+			Where("name = ?", ocservUser.Group).
+			Limit(1).
+			Pluck("vhost", &groupVHost).Error; err != nil {
+			return err
+		}
+
+		if groupVHost != "" {
+			if ocservUser.VHost == "" {
+				ocservUser.VHost = groupVHost
+			}
+			if ocservUser.VHost != groupVHost {
+				return fmt.Errorf("group %s belongs to virtual host %s, not %s", ocservUser.Group, groupVHost, ocservUser.VHost)
+			}
+		}
+	}
+
+	ocservUser.VHost = models.VHostName(ocservUser.VHost)
+	return r.Exists(ctx, ocservUser.VHost)
+}
+
+// writeSection renders the section of the virtual host name into
+// ocserv.conf, or removes it when config is nil, and reloads ocserv. The
+// previous ocserv.conf is put back when ocserv refuses the reload.
+func (r *VHostRepository) writeSection(name string, config *models.OcservVHostConfig) error {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+
+	content, err := r.commonOcservServerRepo.RenderVHost(name, config)
+	if err != nil {
+		return err
+	}
+	if err = r.commonOcservServerRepo.Write(content); err != nil {
+		return err
+	}
+	return reloadOrRestore(r.commonOcservOcctlRepo, r.commonOcservServerRepo.Restore)
+}
@@ -132,7 +132,9 @@ func (ctl *Controller) OcservGroupRestore(c echo.Context) error {
 		return ctl.request.BadRequest(c, errors.New("invalid json EOF file"))
 	}
 
-	if err = ctl.ocservGroupRepo.UpdateDefaultGroup(groupData.DefaultGroup); err != nil {
+	if err = ctl.ocservGroupRepo.UpdateDefaultGroup(
+		c.Request().Context(), groupData.DefaultGroup, repository.ConfigChange{Actor: owner},
+	); err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 
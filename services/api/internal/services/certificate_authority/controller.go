@@ -0,0 +1,211 @@
+package certificate_authority
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	ocservUser "github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+const defaultReissueBatch = 50
+
+type Controller struct {
+	request request.CustomRequestInterface
+	caRepo  repository.CertificateAuthorityRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request: request.NewCustomRequest(),
+		caRepo:  repository.NewCertificateAuthorityRepository(),
+	}
+}
+
+// Status
+// @Summary      Certificate authority status
+// @Description  List the CAs ocserv trusts and the users whose certificate is not issued by the active CA yet
+// @Tags         Ocserv(CA)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} ocservUser.CAStatus
+// @Router       /ocserv/ca [get]
+func (ctl *Controller) Status(c echo.Context) error {
+	var (
+		status *ocservUser.CAStatus
+		err    error
+	)
+
+	if status, err = ctl.caRepo.Status(c.Request().Context()); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// Rotate
+// @Summary      Rotate certificate authority
+// @Description  Create a new active CA. The current CA stays trusted as retiring until it is retired, so existing certificates keep working while they are reissued.
+// @Tags         Ocserv(CA)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} ocservUser.CAStatus
+// @Router       /ocserv/ca/rotate [post]
+func (ctl *Controller) Rotate(c echo.Context) error {
+	status, err := ctl.caRepo.Rotate(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// Reissue
+// @Summary      Reissue certificates under the active CA
+// @Description  Reissue the certificates of the next batch of users still on a retiring CA. Call again until remaining is 0. Users whose reissue fails are parked and skipped until retry_failed is set.
+// @Tags         Ocserv(CA)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  ReissueData  false "batch size"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} ReissueResponse
+// @Router       /ocserv/ca/reissue [post]
+func (ctl *Controller) Reissue(c echo.Context) error {
+	var data ReissueData
+
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	if data.Batch == 0 {
+		data.Batch = defaultReissueBatch
+	}
+
+	results, remaining, parked, err := ctl.caRepo.Reissue(c.Request().Context(), data.Batch, data.RetryFailed)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, ReissueResponse{Results: results, Remaining: remaining, Parked: parked})
+}
+
+// Retire
+// @Summary      Retire certificate authority
+// @Description  Stop trusting the retiring CAs and end the rotation. Refused while users still have a certificate from a retiring CA, unless force is set.
+// @Tags         Ocserv(CA)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  RetireData  false "retire options"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} ocservUser.CAStatus
+// @Router       /ocserv/ca/retire [post]
+func (ctl *Controller) Retire(c echo.Context) error {
+	var data RetireData
+
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	status, err := ctl.caRepo.Retire(c.Request().Context(), data.Force)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// Certificates
+// @Summary      Certificate inventory
+// @Description  List the active, suspended and revoked user certificates with their serial, subject, issuer and validity
+// @Tags         Ocserv(CA)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 state query string false "filter by state" Enums(active, suspended, revoked)
+// @Param 		 q query string false "filter by username or serial"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} CertificatesResponse
+// @Router       /ocserv/ca/certificates [get]
+func (ctl *Controller) Certificates(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+
+	state := c.QueryParam("state")
+	if state != "" && !slices.Contains([]string{
+		ocservUser.CertificateStateActive,
+		ocservUser.CertificateStateSuspended,
+		ocservUser.CertificateStateRevoked,
+	}, state) {
+		return ctl.request.BadRequest(c, fmt.Errorf("invalid state: %s", state))
+	}
+
+	records, total, err := ctl.caRepo.Certificates(c.Request().Context(), pagination, state, c.QueryParam("q"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, CertificatesResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalRecords: int64(total),
+		},
+		Result: records,
+	})
+}
+
+// DownloadCACertificate
+// @Summary      Download CA certificate
+// @Description  Download the PEM bundle of the CAs ocserv trusts, active CA first
+// @Tags         Ocserv(CA)
+// @Produce      application/x-pem-file
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {file} file "ca-cert.pem"
+// @Router       /ocserv/ca/ca-cert.pem [get]
+func (ctl *Controller) DownloadCACertificate(c echo.Context) error {
+	content, err := ctl.caRepo.CACertificate(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return pemAttachment(c, "ca-cert.pem", content)
+}
+
+// DownloadCRL
+// @Summary      Download certificate revocation list
+// @Description  Download the current CRLs in PEM, one per trusted CA
+// @Tags         Ocserv(CA)
+// @Produce      application/x-pem-file
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {file} file "crl.pem"
+// @Router       /ocserv/ca/crl.pem [get]
+func (ctl *Controller) DownloadCRL(c echo.Context) error {
+	content, err := ctl.caRepo.CRL(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return pemAttachment(c, "crl.pem", content)
+}
+
+func pemAttachment(c echo.Context, name string, content []byte) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Blob(http.StatusOK, "application/x-pem-file", content)
+}
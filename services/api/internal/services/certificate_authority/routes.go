@@ -0,0 +1,20 @@
+package certificate_authority
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/ca", middlewares.AuthMiddleware(), middlewares.AdminPermission())
+
+	g.GET("", ctl.Status)
+	g.POST("/rotate", ctl.Rotate)
+	g.POST("/reissue", ctl.Reissue)
+	g.POST("/retire", ctl.Retire)
+
+	g.GET("/certificates", ctl.Certificates)
+	g.GET("/ca-cert.pem", ctl.DownloadCACertificate)
+	g.GET("/crl.pem", ctl.DownloadCRL)
+}
@@ -0,0 +1,32 @@
+package certificate_authority
+
+import (
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	ocservUser "github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+type ReissueData struct {
+	// Batch is the number of users to reissue, 50 when empty.
+	Batch int `json:"batch" validate:"omitempty,gte=1,lte=500" example:"50"`
+	// RetryFailed retries the users parked after a failed reissue.
+	RetryFailed bool `json:"retry_failed" validate:"omitempty" example:"false"`
+}
+
+type ReissueResponse struct {
+	Results   []repository.CAReissueResult `json:"results" validate:"required"`
+	Remaining int                          `json:"remaining" validate:"required"`
+	// Parked lists the users skipped after a failed reissue.
+	Parked []string `json:"parked" validate:"required"`
+}
+
+type RetireData struct {
+	// Force retires the CA even though some users still have a
+	// certificate it issued; those certificates stop working.
+	Force bool `json:"force" validate:"omitempty" example:"false"`
+}
+
+type CertificatesResponse struct {
+	Meta   request.Meta                   `json:"meta" validate:"required"`
+	Result []ocservUser.CertificateRecord `json:"result" validate:"required"`
+}
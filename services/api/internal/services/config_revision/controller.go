@@ -0,0 +1,148 @@
+package config_revision
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+)
+
+type Controller struct {
+	request            request.CustomRequestInterface
+	configRevisionRepo repository.ConfigRevisionRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:            request.NewCustomRequest(),
+		configRevisionRepo: repository.NewConfigRevisionRepository(),
+	}
+}
+
+// Revisions
+// @Summary      Group and user config history
+// @Description  List the versions of the configs of groups, the defaults group and users
+// @Tags         Ocserv(Config Revisions)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 kind query string false "Config kind" Enums(group, defaults, user)
+// @Param 		 target query string false "Group name or username"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} RevisionsResponse
+// @Router       /ocserv/config/revisions [get]
+func (ctl *Controller) Revisions(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+	if c.QueryParam("sort") == "" {
+		pagination.Sort = "DESC"
+	}
+
+	revisions, total, err := ctl.configRevisionRepo.Revisions(
+		c.Request().Context(), pagination, c.QueryParam("kind"), c.QueryParam("target"),
+	)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, RevisionsResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: revisions,
+	})
+}
+
+// Revision
+// @Summary      Group or user config revision
+// @Description  Get a version of the config of a group, the defaults group or a user
+// @Tags         Ocserv(Config Revisions)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Revision ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservConfigRevision
+// @Router       /ocserv/config/revisions/{id} [get]
+func (ctl *Controller) Revision(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return ctl.request.BadRequest(c, errors.New("invalid revision id"))
+	}
+
+	revision, err := ctl.configRevisionRepo.Revision(c.Request().Context(), uint(id))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, revision)
+}
+
+// Diff
+// @Summary      Diff two config revisions
+// @Description  Changed directives between two revisions of the same group, defaults group or user
+// @Tags         Ocserv(Config Revisions)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 from query int true "Revision ID to compare from"
+// @Param 		 to query int true "Revision ID to compare to"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} repository.ConfigRevisionDiff
+// @Router       /ocserv/config/revisions/diff [get]
+func (ctl *Controller) Diff(c echo.Context) error {
+	from, err := strconv.ParseUint(c.QueryParam("from"), 10, 64)
+	if err != nil {
+		return ctl.request.BadRequest(c, errors.New("invalid from revision id"))
+	}
+	to, err := strconv.ParseUint(c.QueryParam("to"), 10, 64)
+	if err != nil {
+		return ctl.request.BadRequest(c, errors.New("invalid to revision id"))
+	}
+
+	diff, err := ctl.configRevisionRepo.Diff(c.Request().Context(), uint(from), uint(to))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, diff)
+}
+
+// Restore
+// @Summary      Restore a config revision
+// @Description  Rewrite the config file of the group, defaults group or user with the revision config and reload ocserv. The previous file is put back when ocserv refuses the reload. The restore is recorded as a new revision.
+// @Tags         Ocserv(Config Revisions)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Revision ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservConfigRevision
+// @Router       /ocserv/config/revisions/{id}/restore [post]
+func (ctl *Controller) Restore(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return ctl.request.BadRequest(c, errors.New("invalid revision id"))
+	}
+
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	revision, err := ctl.configRevisionRepo.Restore(c.Request().Context(), uint(id), actor)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, revision)
+}
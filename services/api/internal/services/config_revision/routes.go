@@ -0,0 +1,16 @@
+package config_revision
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/config/revisions", middlewares.AuthMiddleware(), middlewares.AdminPermission())
+
+	g.GET("", ctl.Revisions)
+	g.GET("/diff", ctl.Diff)
+	g.GET("/:id", ctl.Revision)
+	g.POST("/:id/restore", ctl.Restore)
+}
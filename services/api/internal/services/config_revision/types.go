@@ -0,0 +1,11 @@
+package config_revision
+
+import (
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type RevisionsResponse struct {
+	Meta   request.Meta                   `json:"meta" validate:"required"`
+	Result *[]models.OcservConfigRevision `json:"result" validate:"omitempty"`
+}
@@ -0,0 +1,190 @@
+package config_template
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type Controller struct {
+	request            request.CustomRequestInterface
+	configTemplateRepo repository.ConfigTemplateRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:            request.NewCustomRequest(),
+		configTemplateRepo: repository.NewConfigTemplateRepository(),
+	}
+}
+
+// Templates
+// @Summary      List of config templates
+// @Description  List of the reusable group config templates
+// @Tags         Ocserv(Config Templates)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} TemplatesResponse
+// @Router       /ocserv/templates [get]
+func (ctl *Controller) Templates(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+
+	templates, total, err := ctl.configTemplateRepo.Templates(c.Request().Context(), pagination)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, TemplatesResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: templates,
+	})
+}
+
+// Template
+// @Summary      Config template detail
+// @Description  Config template detail
+// @Tags         Ocserv(Config Templates)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Template ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} models.OcservConfigTemplate
+// @Router       /ocserv/templates/{id} [get]
+func (ctl *Controller) Template(c echo.Context) error {
+	template, err := ctl.configTemplateRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// Groups
+// @Summary      Groups of a config template
+// @Description  Groups that use the config template
+// @Tags         Ocserv(Config Templates)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Template ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} []models.OcservGroup
+// @Router       /ocserv/templates/{id}/groups [get]
+func (ctl *Controller) Groups(c echo.Context) error {
+	template, err := ctl.configTemplateRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	groups, err := ctl.configTemplateRepo.Groups(c.Request().Context(), template.Name)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, groups)
+}
+
+// Create
+// @Summary      Create config template
+// @Description  Create a reusable group config template
+// @Tags         Ocserv(Config Templates)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  CreateTemplateData  true "config template data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      201 {object} models.OcservConfigTemplate
+// @Router       /ocserv/templates [post]
+func (ctl *Controller) Create(c echo.Context) error {
+	var data CreateTemplateData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	owner := c.Get("username").(string)
+	if owner == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	template, err := ctl.configTemplateRepo.Create(c.Request().Context(), &models.OcservConfigTemplate{
+		Name:        data.Name,
+		Description: data.Description,
+		Owner:       owner,
+		Config:      data.Config,
+	})
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusCreated, template)
+}
+
+// Update
+// @Summary      Update config template
+// @Description  Update a config template and re-render the config files of the groups that use it. The previous files are put back when ocserv refuses the reload.
+// @Tags         Ocserv(Config Templates)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Template ID"
+// @Param        request body  UpdateTemplateData  true "config template data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservConfigTemplate
+// @Router       /ocserv/templates/{id} [patch]
+func (ctl *Controller) Update(c echo.Context) error {
+	var data UpdateTemplateData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	template, err := ctl.configTemplateRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	template.Config = data.Config
+	if data.Description != nil {
+		template.Description = *data.Description
+	}
+
+	template, err = ctl.configTemplateRepo.Update(c.Request().Context(), template)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// Delete
+// @Summary      Delete config template
+// @Description  Delete a config template no group uses
+// @Tags         Ocserv(Config Templates)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Template ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      204 {object} nil
+// @Router       /ocserv/templates/{id} [delete]
+func (ctl *Controller) Delete(c echo.Context) error {
+	if err := ctl.configTemplateRepo.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,18 @@
+package config_template
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/templates", middlewares.AuthMiddleware())
+
+	g.GET("", ctl.Templates)
+	g.GET("/:id", ctl.Template)
+	g.GET("/:id/groups", ctl.Groups)
+	g.POST("", ctl.Create, middlewares.AdminPermission())
+	g.PATCH("/:id", ctl.Update, middlewares.AdminPermission())
+	g.DELETE("/:id", ctl.Delete, middlewares.AdminPermission())
+}
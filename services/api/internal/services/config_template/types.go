@@ -0,0 +1,22 @@
+package config_template
+
+import (
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type CreateTemplateData struct {
+	Name        string                    `json:"name" validate:"required"`
+	Description string                    `json:"description" validate:"omitempty"`
+	Config      *models.OcservGroupConfig `json:"config" validate:"required"`
+}
+
+type UpdateTemplateData struct {
+	Description *string                   `json:"description" validate:"omitempty"`
+	Config      *models.OcservGroupConfig `json:"config" validate:"required"`
+}
+
+type TemplatesResponse struct {
+	Meta   request.Meta                   `json:"meta" validate:"required"`
+	Result *[]models.OcservConfigTemplate `json:"result" validate:"omitempty"`
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
 	ocservUser "github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/config"
 )
@@ -22,7 +24,7 @@ const ciscoSetupCertificateTokenTTL = 10 * time.Minute
 // CiscoSetup creates Cisco Secure Client setup URIs for the customer.
 //
 // @Summary      Create customer Cisco Secure Client setup links
-// @Description  Create Cisco Secure Client certificate import and connection creation URIs using ocserv username/password
+// @Description  Create Cisco Secure Client certificate import and connection creation URIs using ocserv username/password. The certificate import URI and password are empty for password-only accounts.
 // @Tags         Customers
 // @Accept       json
 // @Produce      json
@@ -71,33 +73,42 @@ func (ctl *Controller) CiscoSetup(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	expiresAt := time.Now().Add(ciscoSetupCertificateTokenTTL)
-	token, err := createCiscoSetupCertificateToken(user.Username, expiresAt)
+	connectionCreateURI, err := ocservUser.BuildAnyConnectCreateURI(connectionName, serverAddress, serverPort, user.Username)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	certificateURL := publicAPIBaseURL(c) + "/api/customers/setup/cisco/certificate/" + url.PathEscape(token)
-
-	certificateImportURI, err := ocservUser.BuildAnyConnectImportURI(certificateURL)
+	authMode, err := ctl.ocservUserRepo.AuthMode(c.Request().Context(), user)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	connectionCreateURI, err := ocservUser.BuildAnyConnectCreateURI(connectionName, serverAddress, serverPort, user.Username)
-	if err != nil {
-		return ctl.request.BadRequest(c, err)
+	response := CiscoSetupResponse{
+		AuthMode:            authMode,
+		ConnectionCreateURI: connectionCreateURI,
+		ConnectionName:      connectionName,
+		ServerAddress:       serverAddress,
+		ServerPort:          serverPort,
+		ExpiresAt:           time.Now().Add(ciscoSetupCertificateTokenTTL),
+	}
+
+	// password-only accounts have no certificate to import
+	if models.AuthModeUsesCertificate(response.AuthMode) {
+		token, err := createCiscoSetupCertificateToken(user.Username, response.ExpiresAt)
+		if err != nil {
+			return ctl.request.BadRequest(c, err)
+		}
+
+		certificateURL := publicAPIBaseURL(c) + "/api/customers/setup/cisco/certificate/" + url.PathEscape(token)
+
+		response.CertificateImportURI, err = ocservUser.BuildAnyConnectImportURI(certificateURL)
+		if err != nil {
+			return ctl.request.BadRequest(c, err)
+		}
+		response.CertificatePassword = user.Password
 	}
 
-	return c.JSON(http.StatusOK, CiscoSetupResponse{
-		CertificateImportURI: certificateImportURI,
-		ConnectionCreateURI:  connectionCreateURI,
-		CertificatePassword:  user.Password,
-		ConnectionName:       connectionName,
-		ServerAddress:        serverAddress,
-		ServerPort:           serverPort,
-		ExpiresAt:            expiresAt,
-	})
+	return c.JSON(http.StatusOK, response)
 }
 
 // DownloadCiscoSetupCertificate downloads the customer's certificate through a short-lived signed setup token.
@@ -129,24 +140,37 @@ func (ctl *Controller) DownloadCiscoSetupCertificate(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	path, err := ctl.ocservUserRepo.CertificatePathByUsername(ctx, user.Username)
+	mode, err := ctl.ocservUserRepo.AuthMode(ctx, user)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	if !models.AuthModeUsesCertificate(mode) {
+		return ctl.request.BadRequest(c, repository.ErrPasswordOnlyUser)
+	}
+
+	content, err := ctl.ocservUserRepo.CertificateByUsername(ctx, user.Username)
 	if err != nil {
 		if err := ctl.ocservUserRepo.CreateCertificate(ctx, user.UID); err != nil {
 			return ctl.request.BadRequest(c, err)
 		}
 
-		path, err = ctl.ocservUserRepo.CertificatePathByUsername(ctx, user.Username)
+		content, err = ctl.ocservUserRepo.CertificateByUsername(ctx, user.Username)
 		if err != nil {
 			return ctl.request.BadRequest(c, err)
 		}
 	}
 
-	c.Response().Header().Set(echo.HeaderContentType, "application/x-pkcs12")
-	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
 	c.Response().Header().Set("Pragma", "no-cache")
 	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
 
-	return c.Attachment(path, user.Username+".p12")
+	return p12Attachment(c, user.Username+".p12", content)
+}
+
+// p12Attachment sends the PKCS#12 bundle content as the attachment name.
+func p12Attachment(c echo.Context, name string, content []byte) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Blob(http.StatusOK, "application/x-pkcs12", content)
 }
 
 func publicAPIBaseURL(c echo.Context) string {
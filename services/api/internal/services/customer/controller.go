@@ -9,6 +9,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
 )
 
 type Controller struct {
@@ -73,11 +74,17 @@ func (ctl *Controller) Summary(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
+	authMode, err := ctl.ocservUserRepo.AuthMode(c.Request().Context(), user)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
 	return c.JSON(http.StatusOK, SummaryResponse{
 		OcservUser: ModelCustomer{
 			Owner:                user.Owner,
 			Username:             user.Username,
 			IsLocked:             user.IsLocked,
+			AuthMode:             authMode,
 			CertificateEnabled:   user.CertificateEnabled,
 			CertificateAvailable: user.CertificateAvailable,
 			ExpireAt:             user.ExpireAt,
@@ -127,13 +134,20 @@ func (ctl *Controller) DownloadCertificate(c echo.Context) error {
 		return ctl.request.BadRequest(c, errors.New("invalid username or password"))
 	}
 
-	path, err := ctl.ocservUserRepo.CertificatePathByUsername(c.Request().Context(), user.Username)
+	mode, err := ctl.ocservUserRepo.AuthMode(c.Request().Context(), user)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	if !models.AuthModeUsesCertificate(mode) {
+		return ctl.request.BadRequest(c, repository.ErrPasswordOnlyUser)
+	}
+
+	content, err := ctl.ocservUserRepo.CertificateByUsername(c.Request().Context(), user.Username)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	c.Response().Header().Set(echo.HeaderContentType, "application/x-pkcs12")
-	return c.Attachment(path, user.Username+".p12")
+	return p12Attachment(c, user.Username+".p12", content)
 }
 
 // DisconnectSessions
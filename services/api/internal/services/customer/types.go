@@ -14,6 +14,7 @@ type ModelCustomer struct {
 	Owner                string     `json:"owner" gorm:"type:varchar(16);default:''" validate:"required"`
 	Username             string     `json:"username" gorm:"type:varchar(16);not null;uniqueIndex" validate:"required"`
 	IsLocked             bool       `json:"is_locked" gorm:"default(false)" validate:"required"`
+	AuthMode             string     `json:"auth_mode" enums:"password,certificate,both" validate:"required"`
 	CertificateEnabled   bool       `json:"certificate_enabled" validate:"required"`
 	CertificateAvailable bool       `json:"certificate_available" validate:"required"`
 	ExpireAt             *time.Time `json:"expire_at" gorm:"type:date" validate:"required"`
@@ -36,9 +37,10 @@ type SummaryResponse struct {
 }
 
 type CiscoSetupResponse struct {
-	CertificateImportURI string    `json:"certificate_import_uri" validate:"required"`
+	AuthMode             string    `json:"auth_mode" enums:"password,certificate,both" validate:"required"`
+	CertificateImportURI string    `json:"certificate_import_uri" validate:"omitempty"` // empty in password mode
 	ConnectionCreateURI  string    `json:"connection_create_uri" validate:"required"`
-	CertificatePassword  string    `json:"certificate_password" validate:"required"`
+	CertificatePassword  string    `json:"certificate_password" validate:"omitempty"` // empty in password mode
 	ConnectionName       string    `json:"connection_name" validate:"required"`
 	ServerAddress        string    `json:"server_address" validate:"required"`
 	ServerPort           int       `json:"server_port" validate:"required"`
@@ -0,0 +1,78 @@
+package drift
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	ocservDrift "github.com/mmtaee/ocserv-dashboard/common/ocserv/drift"
+)
+
+type Controller struct {
+	request   request.CustomRequestInterface
+	driftRepo repository.DriftRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:   request.NewCustomRequest(),
+		driftRepo: repository.NewDriftRepository(),
+	}
+}
+
+// Report
+// @Summary      Drift report
+// @Description  Compare database users and groups with ocpasswd and the ocserv config files
+// @Tags         System(Drift)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} ocservDrift.Report
+// @Router       /drift [get]
+func (ctl *Controller) Report(c echo.Context) error {
+	var (
+		report *ocservDrift.Report
+		err    error
+	)
+
+	if report, err = ctl.driftRepo.Report(c.Request().Context()); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Fix
+// @Summary      Fix drift
+// @Description  Fix the drift of the given kinds using source as the source of truth. Without kinds only the fixes that delete nothing run: deleting users, groups or config files (missing_in_database, stale_config and orphan_group from database, missing_in_ocpasswd and missing_group_file from ocserv) needs the kind listed
+// @Tags         System(Drift)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  FixData  true "source of truth and drift kinds to fix"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} FixResponse
+// @Router       /drift/fix [post]
+func (ctl *Controller) Fix(c echo.Context) error {
+	var data FixData
+
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	owner := c.Get("username").(string)
+	if owner == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	results, err := ctl.driftRepo.Fix(c.Request().Context(), data.Source, owner, data.Kinds)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, FixResponse{Results: results})
+}
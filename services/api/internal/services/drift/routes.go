@@ -0,0 +1,14 @@
+package drift
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/drift", middlewares.AuthMiddleware(), middlewares.AdminPermission())
+
+	g.GET("", ctl.Report)
+	g.POST("/fix", ctl.Fix)
+}
@@ -0,0 +1,12 @@
+package drift
+
+import "github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+
+type FixData struct {
+	Source string   `json:"source" validate:"required,oneof=database ocserv" enums:"database,ocserv"`
+	Kinds  []string `json:"kinds" validate:"omitempty,dive,oneof=missing_in_ocpasswd missing_in_database lock_mismatch group_mismatch stale_config missing_config orphan_group missing_group_file"`
+}
+
+type FixResponse struct {
+	Results []repository.DriftFixResult `json:"results" validate:"required"`
+}
@@ -212,9 +212,7 @@ func (ctl *Controller) OcservStats(c echo.Context) error {
 	if err != nil {
 		return nil
 	}
-	if serverStatusMap, ok := serverStatus.(map[string]interface{}); ok {
-		status = ParseServerStatus(serverStatusMap)
-	}
+	status = ParseServerStatus(serverStatus)
 
 	return c.JSON(http.StatusOK, status)
 }
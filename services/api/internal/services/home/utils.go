@@ -1,69 +1,51 @@
 package home
 
-func ParseServerStatus(flat map[string]interface{}) OcservStatusResponse {
-	// Helper funcs
-	getStr := func(key string) string {
-		if v, ok := flat[key].(string); ok {
-			return v
-		}
-		return ""
-	}
-	getInt := func(key string) int {
-		if v, ok := flat[key].(float64); ok {
-			return int(v)
-		}
-		return 0
-	}
-	getInt64 := func(key string) int64 {
-		if v, ok := flat[key].(float64); ok {
-			return int64(v)
-		}
-		return 0
-	}
+import "github.com/mmtaee/ocserv-dashboard/common/models"
 
+func ParseServerStatus(s *models.OcctlServerStatus) OcservStatusResponse {
 	return OcservStatusResponse{
 		GeneralInfo: GeneralInfo{
-			ServerPID:           getInt("Server PID"),
-			SecModPID:           getInt("Sec-mod PID"),
-			SecModInstanceCount: getInt("Sec-mod instance count"),
-			Status:              getStr("Status"),
-			UpSince:             getStr("Up since"),
-			UpSinceDuration:     getStr("_Up since"),
-			ActiveSessions:      getInt("Active sessions"),
-			TotalSessions:       getInt("Total sessions"),
-			TotalAuthFailures:   getInt("Total authentication failures"),
-			IPsInBanList:        getInt("IPs in ban list"),
-			MedianLatency:       getStr("Median latency"),
-			STDEVLatency:        getStr("STDEV latency"),
+			ServerPID:           s.ServerPID,
+			SecModPID:           s.SecModPID,
+			SecModInstanceCount: s.SecModInstanceCount,
+			Status:              s.Status,
+			UpSince:             s.UpSince,
+			UpSinceDuration:     s.UpSinceDuration,
+			ActiveSessions:      s.ActiveSessions,
+			TotalSessions:       s.TotalSessions,
+			TotalAuthFailures:   s.TotalAuthFailures,
+			IPsInBanList:        s.IPsInBanList,
+			MedianLatency:       s.MedianLatency,
+			STDEVLatency:        s.STDEVLatency,
 
-			RawMedianLatency: getInt64("raw_median_latency"),
-			RawSTDEVLatency:  getInt64("raw_stdev_latency"),
-			RawUpSince:       getInt64("raw_up_since"),
-			Uptime:           getInt64("uptime"),
+			RawMedianLatency: s.RawMedianLatency,
+			RawSTDEVLatency:  s.RawSTDEVLatency,
+			RawUpSince:       s.RawUpSince,
+			Uptime:           s.Uptime,
 		},
 
 		CurrentStats: CurrentStats{
-			LastStatsReset:           getStr("Last stats reset"),
-			LastStatsResetDuration:   getStr("_Last stats reset"),
-			SessionsHandled:          getInt("Sessions handled"),
-			TimedOutSessions:         getInt("Timed out sessions"),
-			TimedOutIdleSessions:     getInt("Timed out (idle) sessions"),
-			ClosedDueToErrorSessions: getInt("Closed due to error sessions"),
-			AuthenticationFailures:   getInt("Authentication failures"),
-			AverageAuthTime:          getStr("Average auth time"),
-			MaxAuthTime:              getStr("Max auth time"),
-			AverageSessionTime:       getStr("Average session time"),
-			MaxSessionTime:           getStr("Max session time"),
-			RX:                       getStr("RX"),
-			TX:                       getStr("TX"),
+			LastStatsReset:           s.LastStatsReset,
+			LastStatsResetDuration:   s.LastStatsResetDuration,
+			SessionsHandled:          s.SessionsHandled,
+			TimedOutSessions:         s.TimedOutSessions,
+			TimedOutIdleSessions:     s.TimedOutIdleSessions,
+			ClosedDueToErrorSessions: s.ClosedDueToErrorSessions,
+			AuthenticationFailures:   s.AuthenticationFailures,
+			AverageAuthTime:          s.AverageAuthTime,
+			MaxAuthTime:              s.MaxAuthTime,
+			AverageSessionTime:       s.AverageSessionTime,
+			MaxSessionTime:           s.MaxSessionTime,
+			RX:                       s.RX,
+			TX:                       s.TX,
 
-			RawRX:             getInt64("raw_rx"),
-			RawTX:             getInt64("raw_tx"),
-			RawAvgAuthTime:    getInt64("raw_avg_auth_time"),
-			RawMaxAuthTime:    getInt64("raw_max_auth_time"),
-			RawAvgSessionTime: getInt64("raw_avg_session_time"),
-			RawMaxSessionTime: getInt64("raw_max_session_time"),
-			RawLastStatsReset: getInt64("raw_last_stats_reset"),
+			RawRX:             s.RawRX,
+			RawTX:             s.RawTX,
+			RawAvgAuthTime:    s.RawAvgAuthTime,
+			RawMaxAuthTime:    s.RawMaxAuthTime,
+			RawAvgSessionTime: s.RawAvgSessionTime,
+			RawMaxSessionTime: s.RawMaxSessionTime,
+			RawLastStatsReset: s.RawLastStatsReset,
 		},
 	}
 }
@@ -0,0 +1,160 @@
+package ip_ban
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/ipban"
+)
+
+type Controller struct {
+	request   request.CustomRequestInterface
+	ipBanRepo repository.IPBanRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:   request.NewCustomRequest(),
+		ipBanRepo: repository.NewIPBanRepository(),
+	}
+}
+
+// Active 	 Active IP bans
+//
+// @Summary      Active IP bans
+// @Description  List the IP and CIDR bans currently in force
+// @Tags         System(IP Bans)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {array} models.OcservIPBan
+// @Router       /ip_bans [get]
+func (ctl *Controller) Active(c echo.Context) error {
+	bans, err := ctl.ipBanRepo.Active(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, bans)
+}
+
+// History 	 IP ban history
+//
+// @Summary      IP ban history
+// @Description  List all bans, including ended and expired ones
+// @Tags         System(IP Bans)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Param 		 ip query string false "filter by ip or cidr"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} HistoryResponse
+// @Router       /ip_bans/history [get]
+func (ctl *Controller) History(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+	if c.QueryParam("sort") == "" {
+		pagination.Sort = "DESC"
+	}
+
+	bans, total, err := ctl.ipBanRepo.History(c.Request().Context(), pagination, c.QueryParam("ip"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, HistoryResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: bans,
+	})
+}
+
+// Ban 	 Ban IP
+//
+// @Summary      Ban IP
+// @Description  Ban an IP or CIDR until unbanned or until expires_at
+// @Tags         System(IP Bans)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  BanData  true "ban data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      201 {object} models.OcservIPBan
+// @Router       /ip_bans [post]
+func (ctl *Controller) Ban(c echo.Context) error {
+	var data BanData
+
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	owner := c.Get("username").(string)
+	if owner == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	ban, err := ctl.ipBanRepo.Ban(c.Request().Context(), ipban.BanRequest{
+		IP:        data.IP,
+		Reason:    data.Reason,
+		Source:    models.IPBanSourceManual,
+		Owner:     owner,
+		ExpiresAt: data.ExpiresAt,
+	})
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusCreated, ban)
+}
+
+// Unban 	 Unban IP
+//
+// @Summary      Unban IP
+// @Description  End an active ban. The ban is kept in the history
+// @Tags         System(IP Bans)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Ban ID"
+// @Param        request body  UnbanData  false "unban data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservIPBan
+// @Router       /ip_bans/{id}/unban [post]
+func (ctl *Controller) Unban(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return ctl.request.BadRequest(c, errors.New("invalid ban id"))
+	}
+
+	var data UnbanData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	ban, err := ctl.ipBanRepo.Unban(c.Request().Context(), uint(id), actor, data.Reason)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, ban)
+}
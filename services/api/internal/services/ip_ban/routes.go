@@ -0,0 +1,17 @@
+package ip_ban
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ip_bans", middlewares.AuthMiddleware(), middlewares.AdminPermission())
+
+	g.GET("", ctl.Active)
+	g.GET("/history", ctl.History)
+
+	g.POST("", ctl.Ban)
+	g.POST("/:id/unban", ctl.Unban)
+}
@@ -0,0 +1,23 @@
+package ip_ban
+
+import (
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type BanData struct {
+	IP        string     `json:"ip" validate:"required" example:"203.0.113.7"`
+	Reason    string     `json:"reason" validate:"omitempty,max=1024"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty" example:"2026-01-31T00:00:00Z"`
+}
+
+type UnbanData struct {
+	Reason string `json:"reason" validate:"omitempty,max=1024"`
+}
+
+type HistoryResponse struct {
+	Meta   request.Meta          `json:"meta" validate:"required"`
+	Result *[]models.OcservIPBan `json:"result" validate:"omitempty"`
+}
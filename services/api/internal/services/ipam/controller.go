@@ -0,0 +1,111 @@
+package ipam
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+)
+
+type Controller struct {
+	request  request.CustomRequestInterface
+	ipamRepo repository.IPAMRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:  request.NewCustomRequest(),
+		ipamRepo: repository.NewIPAMRepository(),
+	}
+}
+
+// Pools
+// @Summary      IPv4 address pools
+// @Description  The ipv4-network pools of ocserv.conf, the defaults group, groups and users, with the static and session addresses in use
+// @Tags         Ocserv(IPAM)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} []repository.IPAMPool
+// @Router       /ocserv/ipam/pools [get]
+func (ctl *Controller) Pools(c echo.Context) error {
+	pools, err := ctl.ipamRepo.Pools(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, pools)
+}
+
+// Addresses
+// @Summary      Addresses of a pool
+// @Description  The explicit-ipv4 addresses of users and groups and the addresses of live sessions in a network
+// @Tags         Ocserv(IPAM)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 network query string true "Pool network, e.g. 192.168.1.0/24"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} []repository.IPAMAddress
+// @Router       /ocserv/ipam/addresses [get]
+func (ctl *Controller) Addresses(c echo.Context) error {
+	network := c.QueryParam("network")
+	if network == "" {
+		return ctl.request.BadRequest(c, errors.New("network is required"))
+	}
+
+	addresses, err := ctl.ipamRepo.Addresses(c.Request().Context(), network)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, addresses)
+}
+
+// Conflicts
+// @Summary      Address conflicts
+// @Description  The explicit-ipv4 addresses assigned more than once, outside the pool of their user or held by a live session of another user
+// @Tags         Ocserv(IPAM)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} []repository.IPAMConflict
+// @Router       /ocserv/ipam/conflicts [get]
+func (ctl *Controller) Conflicts(c echo.Context) error {
+	conflicts, err := ctl.ipamRepo.Conflicts(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, conflicts)
+}
+
+// Allocate
+// @Summary      Allocate a static address
+// @Description  Give the user the lowest free address of its pool as explicit-ipv4, replacing the one it has, and reload ocserv. Only users of the local node get addresses.
+// @Tags         Ocserv(IPAM)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 uid path string true "Ocserv User UID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservUser
+// @Router       /ocserv/ipam/users/{uid}/allocate [post]
+func (ctl *Controller) Allocate(c echo.Context) error {
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	ocservUser, err := ctl.ipamRepo.Allocate(c.Request().Context(), c.Param("uid"), actor)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ocservUser)
+}
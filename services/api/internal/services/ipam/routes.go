@@ -0,0 +1,16 @@
+package ipam
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/ipam", middlewares.AuthMiddleware(), middlewares.AdminPermission())
+
+	g.GET("/pools", ctl.Pools)
+	g.GET("/addresses", ctl.Addresses)
+	g.GET("/conflicts", ctl.Conflicts)
+	g.POST("/users/:uid/allocate", ctl.Allocate)
+}
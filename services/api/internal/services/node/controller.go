@@ -0,0 +1,228 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type Controller struct {
+	request  request.CustomRequestInterface
+	nodeRepo repository.NodeRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:  request.NewCustomRequest(),
+		nodeRepo: repository.NewNodeRepository(),
+	}
+}
+
+// Nodes
+// @Summary      List of nodes
+// @Description  List of the registered VPN nodes. The local node, the ocserv this dashboard runs with, is not listed.
+// @Tags         Ocserv(Nodes)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} NodesResponse
+// @Router       /ocserv/nodes [get]
+func (ctl *Controller) Nodes(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+
+	nodes, total, err := ctl.nodeRepo.Nodes(c.Request().Context(), pagination)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, NodesResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: nodes,
+	})
+}
+
+// Lookup
+// @Summary      Node names
+// @Description  Names of the enabled nodes users can be provisioned on, the local one first
+// @Tags         Ocserv(Nodes)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} []string
+// @Router       /ocserv/nodes/lookup [get]
+func (ctl *Controller) Lookup(c echo.Context) error {
+	names, err := ctl.nodeRepo.Lookup(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, names)
+}
+
+// Node
+// @Summary      Node detail
+// @Description  Node detail
+// @Tags         Ocserv(Nodes)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Node ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} models.OcservNode
+// @Router       /ocserv/nodes/{id} [get]
+func (ctl *Controller) Node(c echo.Context) error {
+	node, err := ctl.nodeRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, node)
+}
+
+// Status
+// @Summary      Node status
+// @Description  Call the agent of the node and report its ocserv release and status. The result is kept in last_seen_at, version and last_error of the node.
+// @Tags         Ocserv(Nodes)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Node ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} NodeStatusResponse
+// @Router       /ocserv/nodes/{id}/status [get]
+func (ctl *Controller) Status(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	node, err := ctl.nodeRepo.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	resp := NodeStatusResponse{Node: node}
+	resp.Agent, err = ctl.nodeRepo.Check(ctx, node)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	// reload the recorded result
+	if node, err = ctl.nodeRepo.GetByID(ctx, c.Param("id")); err == nil {
+		resp.Node = node
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Create
+// @Summary      Register node
+// @Description  Register a VPN node running the agent. The agent is checked right away; an unreachable agent is reported in last_error.
+// @Tags         Ocserv(Nodes)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  CreateNodeData  true "node data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      201 {object} models.OcservNode
+// @Router       /ocserv/nodes [post]
+func (ctl *Controller) Create(c echo.Context) error {
+	var data CreateNodeData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	node := &models.OcservNode{
+		Name:        data.Name,
+		Location:    data.Location,
+		Description: data.Description,
+		URL:         data.URL,
+		Secret:      data.Secret,
+		Enabled:     true,
+	}
+	if data.Enabled != nil {
+		node.Enabled = *data.Enabled
+	}
+
+	node, err := ctl.nodeRepo.Create(c.Request().Context(), node)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusCreated, node)
+}
+
+// Update
+// @Summary      Update node
+// @Description  Update a node. Users of a disabled node stay in the database but can not be changed until it is enabled again.
+// @Tags         Ocserv(Nodes)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Node ID"
+// @Param        request body  UpdateNodeData  true "node data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservNode
+// @Router       /ocserv/nodes/{id} [patch]
+func (ctl *Controller) Update(c echo.Context) error {
+	var data UpdateNodeData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	node, err := ctl.nodeRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if data.Location != nil {
+		node.Location = *data.Location
+	}
+	if data.Description != nil {
+		node.Description = *data.Description
+	}
+	if data.URL != nil {
+		node.URL = *data.URL
+	}
+	if data.Secret != nil {
+		node.Secret = *data.Secret
+	}
+	if data.Enabled != nil {
+		node.Enabled = *data.Enabled
+	}
+
+	node, err = ctl.nodeRepo.Update(c.Request().Context(), node)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, node)
+}
+
+// Delete
+// @Summary      Delete node
+// @Description  Delete a node no user is provisioned on
+// @Tags         Ocserv(Nodes)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Node ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      204 {object} nil
+// @Router       /ocserv/nodes/{id} [delete]
+func (ctl *Controller) Delete(c echo.Context) error {
+	if err := ctl.nodeRepo.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusNoContent, nil)
+}
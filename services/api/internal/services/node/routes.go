@@ -0,0 +1,19 @@
+package node
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/nodes", middlewares.AuthMiddleware())
+
+	g.GET("", ctl.Nodes)
+	g.GET("/lookup", ctl.Lookup)
+	g.GET("/:id", ctl.Node)
+	g.GET("/:id/status", ctl.Status)
+	g.POST("", ctl.Create, middlewares.AdminPermission())
+	g.PATCH("/:id", ctl.Update, middlewares.AdminPermission())
+	g.DELETE("/:id", ctl.Delete, middlewares.AdminPermission())
+}
@@ -0,0 +1,37 @@
+package node
+
+import (
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type CreateNodeData struct {
+	// Name must match AGENT_NODE_NAME of the agent, or its hostname.
+	Name        string `json:"name" validate:"required,max=64,hostname_rfc1123" example:"de-fra-1"`
+	Location    string `json:"location" validate:"omitempty,max=128" example:"Frankfurt"`
+	Description string `json:"description" validate:"omitempty,max=1024"`
+	// URL of the agent, the webhook service of the node.
+	URL string `json:"url" validate:"required,url,max=255" example:"https://fra1.vpn.example.com:8888"`
+	// Secret is WEBHOOK_SECRET of the agent.
+	Secret  string `json:"secret" validate:"required,min=16,max=255"`
+	Enabled *bool  `json:"enabled" validate:"omitempty"`
+}
+
+type UpdateNodeData struct {
+	Location    *string `json:"location" validate:"omitempty,max=128"`
+	Description *string `json:"description" validate:"omitempty,max=1024"`
+	URL         *string `json:"url" validate:"omitempty,url,max=255"`
+	Secret      *string `json:"secret" validate:"omitempty,min=16,max=255"`
+	Enabled     *bool   `json:"enabled" validate:"omitempty"`
+}
+
+type NodesResponse struct {
+	Meta   request.Meta         `json:"meta" validate:"required"`
+	Result *[]models.OcservNode `json:"result" validate:"omitempty"`
+}
+
+type NodeStatusResponse struct {
+	Node  *models.OcservNode `json:"node" validate:"required"`
+	Agent *models.AgentInfo  `json:"agent" validate:"omitempty"`
+	Error string             `json:"error,omitempty"`
+}
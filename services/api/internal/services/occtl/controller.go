@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
-	"github.com/mmtaee/ocserv-dashboard/api/internal/services/home"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/eventbus"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
 )
 
@@ -29,7 +30,7 @@ func New() *Controller {
 // ServerInfo 	 Server information
 //
 // @Summary      Server information
-// @Description  Server information
+// @Description  Server information, including the occtl capabilities of the installed ocserv release so clients can hide unsupported actions
 // @Tags         OCCTL
 // @Accept       json
 // @Produce      json
@@ -39,8 +40,9 @@ func New() *Controller {
 func (ctl *Controller) ServerInfo(c echo.Context) error {
 	serverVersion := ctl.occtlRepo.Version()
 	info := models.OcservInfo{
-		Version: serverVersion,
-		Status:  "error",
+		Version:      serverVersion,
+		Status:       "error",
+		Capabilities: ctl.occtlRepo.Capabilities(),
 	}
 
 	serverStatus, err := ctl.occtlRepo.Status()
@@ -50,16 +52,8 @@ func (ctl *Controller) ServerInfo(c echo.Context) error {
 		return c.JSON(http.StatusOK, info)
 	}
 
-	serverStatusMap, ok := serverStatus.(map[string]interface{})
-	if !ok {
-		logger.Error("Invalid server status format")
-		info.Status = "error"
-		return c.JSON(http.StatusOK, info)
-	}
-
-	status := home.ParseServerStatus(serverStatusMap)
-	if status.GeneralInfo.Status != "" {
-		info.Status = status.GeneralInfo.Status
+	if serverStatus.Status != "" {
+		info.Status = serverStatus.Status
 	}
 
 	return c.JSON(http.StatusOK, info)
@@ -75,6 +69,8 @@ func (ctl *Controller) ServerInfo(c echo.Context) error {
 // @Param        Authorization header string true "Bearer TOKEN"
 // @Param        action  query   int     true   "Command Action ID (1 to 15)"
 // @Param        value   query   string  false  "Optional parameter depending on command"
+// @Param        vhost   query   string  false  "Virtual host the online users (1), sessions (5, 6) and iroutes (12) are limited to"
+// @Param        node    query   string  false  "Node to run the command on, online users (1) of all nodes by default"
 // @Failure      400 {object} request.ErrorResponse
 // @Failure      401 {object} middlewares.Unauthorized
 // @Success      200  {object}  string
@@ -85,25 +81,34 @@ func (ctl *Controller) Commands(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
+	occtlRepo := ctl.occtlRepo
+	if data.Node != "" {
+		repo, err := ctl.occtlRepo.ForNode(c.Request().Context(), data.Node)
+		if err != nil {
+			return ctl.request.BadRequest(c, err)
+		}
+		occtlRepo = repo
+	}
+
 	var results []byte
 
 	actions := map[int]func(string) (interface{}, error){
-		1:  func(_ string) (interface{}, error) { return ctl.occtlRepo.OnlineSessions() },
-		2:  func(val string) (interface{}, error) { return ctl.occtlRepo.ShowUserByUsername(val) },
-		3:  func(val string) (interface{}, error) { return ctl.occtlRepo.ShowUserByID(val) },
-		4:  func(val string) (interface{}, error) { return ctl.occtlRepo.Disconnect(val) },
-		5:  func(_ string) (interface{}, error) { return ctl.occtlRepo.ShowSessionsAll() },
-		6:  func(_ string) (interface{}, error) { return ctl.occtlRepo.ShowSessionsValid() },
-		7:  func(val string) (interface{}, error) { return ctl.occtlRepo.ShowSessionBySID(val) },
-		8:  func(_ string) (interface{}, error) { return ctl.occtlRepo.IPBans() },
-		9:  func(val string) (interface{}, error) { return ctl.occtlRepo.UnbanIP(val) },
-		10: func(_ string) (interface{}, error) { return ctl.occtlRepo.Status() },
-		11: func(_ string) (interface{}, error) { return ctl.occtlRepo.ShowEvent(), nil },
-		12: func(_ string) (interface{}, error) { return ctl.occtlRepo.IRoutes() },
-		13: func(_ string) (interface{}, error) { return ctl.occtlRepo.Reload() },
-		14: func(val string) (interface{}, error) { return ctl.occtlRepo.DisconnectSession(val) },
-		15: func(val string) (interface{}, error) { return ctl.occtlRepo.Terminate(val) },
-		16: func(val string) (interface{}, error) { return ctl.occtlRepo.TerminateSession(val) },
+		1:  func(_ string) (interface{}, error) { return occtlRepo.OnlineSessions() },
+		2:  func(val string) (interface{}, error) { return occtlRepo.ShowUserByUsername(val) },
+		3:  func(val string) (interface{}, error) { return occtlRepo.ShowUserByID(val) },
+		4:  func(val string) (interface{}, error) { return occtlRepo.Disconnect(val) },
+		5:  func(_ string) (interface{}, error) { return occtlRepo.ShowSessionsAll() },
+		6:  func(_ string) (interface{}, error) { return occtlRepo.ShowSessionsValid() },
+		7:  func(val string) (interface{}, error) { return occtlRepo.ShowSessionBySID(val) },
+		8:  func(_ string) (interface{}, error) { return occtlRepo.IPBans() },
+		9:  func(val string) (interface{}, error) { return occtlRepo.UnbanIP(val) },
+		10: func(_ string) (interface{}, error) { return occtlRepo.Status() },
+		11: func(_ string) (interface{}, error) { return occtlRepo.ShowEvent(), nil },
+		12: func(_ string) (interface{}, error) { return occtlRepo.IRoutes() },
+		13: func(_ string) (interface{}, error) { return occtlRepo.Reload() },
+		14: func(val string) (interface{}, error) { return occtlRepo.DisconnectSession(val) },
+		15: func(val string) (interface{}, error) { return occtlRepo.Terminate(val) },
+		16: func(val string) (interface{}, error) { return occtlRepo.TerminateSession(val) },
 	}
 
 	var err error
@@ -118,6 +123,9 @@ func (ctl *Controller) Commands(c echo.Context) error {
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
+	if data.VHost != "" {
+		res = filterVHost(res, data.VHost)
+	}
 
 	results, err = json.Marshal(res)
 	if err != nil {
@@ -126,3 +134,104 @@ func (ctl *Controller) Commands(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, strings.TrimSpace(string(results)))
 }
+
+// Events 	 Live Occtl Events
+//
+// @Summary      Live Occtl Events
+// @Description  Server-Sent Events stream of user connect and disconnect events (event name is the event type, data is the JSON encoded event)
+// @Tags         OCCTL
+// @Produce      text/event-stream
+// @Param        Authorization header string false "Bearer TOKEN"
+// @Param        token query string false "Token, for clients that cannot set the Authorization header (EventSource)"
+// @Param        vhost query string false "Only stream the events of a virtual host"
+// @Param        node query string false "Only stream the events of a node, local for the ocserv of the dashboard"
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200  {object}  models.OcctlEvent
+// @Router       /occtl/events [get]
+func (ctl *Controller) Events(c echo.Context) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	sub := eventbus.Subscribe(32, eventbus.TopicOcctlEvent)
+	defer sub.Close()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	vhost := c.QueryParam("vhost")
+	node := c.QueryParam("node")
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			event, ok := e.Payload.(models.OcctlEvent)
+			if !ok || (vhost != "" && models.VHostName(event.VHost) != vhost) || (node != "" && models.NodeName(event.Node) != node) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.Error("Marshal occtl event error: %v", err)
+				continue
+			}
+			if _, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// filterVHost keeps the entries of the online users, sessions and iroutes
+// command results that belong to vhost. Other results are returned as they
+// are.
+func filterVHost(res interface{}, vhost string) interface{} {
+	switch list := res.(type) {
+	case []models.OnlineUserSession:
+		filtered := make([]models.OnlineUserSession, 0, len(list))
+		for _, session := range list {
+			if models.VHostName(session.VHost) == vhost {
+				filtered = append(filtered, session)
+			}
+		}
+		return filtered
+	case *[]models.OcctlSession:
+		if list == nil {
+			return list
+		}
+		filtered := make([]models.OcctlSession, 0, len(*list))
+		for _, session := range *list {
+			if models.VHostName(session.VHost) == vhost {
+				filtered = append(filtered, session)
+			}
+		}
+		return &filtered
+	case *[]models.IRoute:
+		if list == nil {
+			return list
+		}
+		filtered := make([]models.IRoute, 0, len(*list))
+		for _, route := range *list {
+			if models.VHostName(route.Vhost) == vhost {
+				filtered = append(filtered, route)
+			}
+		}
+		return &filtered
+	}
+	return res
+}
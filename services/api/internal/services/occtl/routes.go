@@ -10,4 +10,5 @@ func Routes(e *echo.Group) {
 	g := e.Group("/occtl")
 	g.GET("/server_info", ctl.ServerInfo)
 	g.GET("/commands", ctl.Commands, middlewares.AuthMiddleware())
+	g.GET("/events", ctl.Events, middlewares.StreamAuthMiddleware())
 }
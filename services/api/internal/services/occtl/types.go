@@ -1,6 +1,16 @@
 package occtl
 
+import "time"
+
+// eventsHeartbeat keeps idle event streams open through proxies.
+const eventsHeartbeat = 15 * time.Second
+
 type CommandParamsData struct {
 	Action int    `query:"action" validate:"required,min=1,max=16"`
 	Value  string `query:"value" validate:"omitempty"`
+	// VHost limits the online users, sessions and iroutes to a virtual host
+	VHost string `query:"vhost" validate:"omitempty"`
+	// Node runs the command on one node; without it online users (1) cover
+	// all nodes and the other commands run on the local one
+	Node string `query:"node" validate:"omitempty"`
 }
@@ -15,16 +15,20 @@ import (
 )
 
 type Controller struct {
-	request         request.CustomRequestInterface
-	ocservGroupRepo repository.OcservGroupRepositoryInterface
-	ocservUserRepo  repository.OcservUserRepositoryInterface
+	request          request.CustomRequestInterface
+	ocservGroupRepo  repository.OcservGroupRepositoryInterface
+	ocservUserRepo   repository.OcservUserRepositoryInterface
+	vhostRepo        repository.VHostRepositoryInterface
+	serverConfigRepo repository.ServerConfigRepositoryInterface
 }
 
 func New() *Controller {
 	return &Controller{
-		request:         request.NewCustomRequest(),
-		ocservGroupRepo: repository.NewOcservGroupRepository(),
-		ocservUserRepo:  repository.NewtOcservUserRepository(),
+		request:          request.NewCustomRequest(),
+		ocservGroupRepo:  repository.NewOcservGroupRepository(),
+		ocservUserRepo:   repository.NewtOcservUserRepository(),
+		vhostRepo:        repository.NewVHostRepository(),
+		serverConfigRepo: repository.NewServerConfigRepository(),
 	}
 }
 
@@ -35,6 +39,7 @@ func New() *Controller {
 // @Tags         Ocserv(Groups)
 // @Accept       json
 // @Produce      json
+// @Param 		 vhost query string false "filter groups by virtual host"
 // @Param        Authorization header string true "Bearer TOKEN"
 // @Failure      400 {object} request.ErrorResponse
 // @Failure      401 {object} middlewares.Unauthorized
@@ -50,7 +55,7 @@ func (ctl *Controller) OcservGroupsLookup(c echo.Context) error {
 		}
 		owner = usernameVal
 	}
-	groups, err := ctl.ocservGroupRepo.GroupsLookup(c.Request().Context(), owner)
+	groups, err := ctl.ocservGroupRepo.GroupsLookup(c.Request().Context(), owner, c.QueryParam("vhost"))
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
@@ -69,6 +74,7 @@ func (ctl *Controller) OcservGroupsLookup(c echo.Context) error {
 // @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
 // @Param 		 order query string false "Field to order by"
 // @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Param 		 vhost query string false "filter groups by virtual host"
 // @Param        Authorization header string true "Bearer TOKEN"
 // @Failure      400 {object} request.ErrorResponse
 // @Failure      401 {object} middlewares.Unauthorized
@@ -86,7 +92,7 @@ func (ctl *Controller) OcservGroups(c echo.Context) error {
 		owner = username
 	}
 
-	ocservGroup, total, err := ctl.ocservGroupRepo.Groups(c.Request().Context(), pagination, owner)
+	ocservGroup, total, err := ctl.ocservGroupRepo.Groups(c.Request().Context(), pagination, owner, c.QueryParam("vhost"))
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
@@ -127,6 +133,37 @@ func (ctl *Controller) OcservGroup(c echo.Context) error {
 	return c.JSON(http.StatusOK, group)
 }
 
+// EffectiveConfig 	 Ocserv group effective config
+//
+// @Summary      Ocserv group effective config
+// @Description  Config written to the group file, the group templates merged in order with the group config, with the source of each value
+// @Tags         Ocserv(Groups)
+// @Accept       json
+// @Produce      json
+// @Param 		 id path int true "Ocserv Group ID"
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200  {object}  repository.EffectiveGroupConfig
+// @Router       /ocserv/groups/{id}/effective [get]
+func (ctl *Controller) EffectiveConfig(c echo.Context) error {
+	groupID := c.Param("id")
+	if groupID == "" {
+		return ctl.request.BadRequest(c, errors.New("invalid group id"))
+	}
+
+	group, err := ctl.ocservGroupRepo.GetByID(c.Request().Context(), groupID)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	effective, err := ctl.ocservGroupRepo.EffectiveConfig(c.Request().Context(), group)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, effective)
+}
+
 // CreateOcservGroup 	     Ocserv Group creation
 //
 // @Summary      Ocserv Group creation
@@ -151,16 +188,29 @@ func (ctl *Controller) CreateOcservGroup(c echo.Context) error {
 		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
 	}
 
+	vhost := models.VHostName(data.VHost)
+	if err := ctl.vhostRepo.Exists(c.Request().Context(), vhost); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if err := ctl.serverConfigRepo.CheckAuthMode(c.Request().Context(), models.ResolveAuthMode(data.AuthMode)); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
 	ocservGroup := models.OcservGroup{
-		Name:   data.Name,
-		Owner:  owner,
-		Config: data.Config,
+		Name:      data.Name,
+		Owner:     owner,
+		Config:    data.Config,
+		AuthMode:  data.AuthMode,
+		Templates: data.Templates,
+		VHost:     vhost,
 	}
 
 	newOcservGroup, err := ctl.ocservGroupRepo.Create(c.Request().Context(), &ocservGroup)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
+
 	return c.JSON(http.StatusCreated, newOcservGroup)
 }
 
@@ -189,15 +239,51 @@ func (ctl *Controller) UpdateOcservGroup(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
 	ocservGroup, err := ctl.ocservGroupRepo.GetByID(c.Request().Context(), groupID)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 	ocservGroup.Config = data.Config
-	updatedOcservGroup, err := ctl.ocservGroupRepo.Update(c.Request().Context(), ocservGroup)
+	if data.Templates != nil {
+		ocservGroup.Templates = *data.Templates
+	}
+	if data.VHost != nil {
+		ocservGroup.VHost = models.VHostName(*data.VHost)
+		if err = ctl.vhostRepo.Exists(c.Request().Context(), ocservGroup.VHost); err != nil {
+			return ctl.request.BadRequest(c, err)
+		}
+	}
+
+	modeChanged := data.AuthMode != nil && *data.AuthMode != ocservGroup.AuthMode
+	if modeChanged {
+		ocservGroup.AuthMode = *data.AuthMode
+		if err = ctl.serverConfigRepo.CheckAuthMode(c.Request().Context(), models.ResolveAuthMode(ocservGroup.AuthMode)); err != nil {
+			return ctl.request.BadRequest(c, err)
+		}
+	}
+
+	updatedOcservGroup, err := ctl.ocservGroupRepo.Update(
+		c.Request().Context(), ocservGroup, repository.ConfigChange{Actor: actor},
+	)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
+
+	if modeChanged {
+		go func(g models.OcservGroup) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			if err2 := ctl.ocservUserRepo.ReapplyAuthMode(ctx, &g); err2 != nil {
+				logger.Warn("UpdateGroup: failed to apply auth mode of group %s: %v", g.Name, err2)
+			}
+		}(*updatedOcservGroup)
+	}
 	return c.JSON(http.StatusOK, updatedOcservGroup)
 }
 
@@ -220,6 +306,11 @@ func (ctl *Controller) DeleteOcservGroup(c echo.Context) error {
 		return ctl.request.BadRequest(c, errors.New("group id is empty"))
 	}
 
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
 	group, err := ctl.ocservGroupRepo.Delete(c.Request().Context(), groupID)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
@@ -245,7 +336,7 @@ func (ctl *Controller) DeleteOcservGroup(c echo.Context) error {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				if _, err2 := ctl.ocservUserRepo.Update(ctx, &ocservUser); err2 != nil {
+				if _, err2 := ctl.ocservUserRepo.Update(ctx, &ocservUser, repository.ConfigChange{Actor: actor}); err2 != nil {
 					logger.Warn("DeleteGroup: failed to update user %s: %v", ocservUser.Username, err2)
 				}
 			}()
@@ -296,10 +387,18 @@ func (ctl *Controller) UpdateDefaultsGroup(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	err := ctl.ocservGroupRepo.UpdateDefaultGroup(data.Config)
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	err := ctl.ocservGroupRepo.UpdateDefaultGroup(
+		c.Request().Context(), data.Config, repository.ConfigChange{Actor: actor},
+	)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
+
 	return c.JSON(http.StatusOK, nil)
 }
 
@@ -11,6 +11,7 @@ func Routes(e *echo.Group) {
 	g.GET("", ctl.OcservGroups)
 	g.GET("/lookup", ctl.OcservGroupsLookup)
 	g.GET("/:id", ctl.OcservGroup)
+	g.GET("/:id/effective", ctl.EffectiveConfig)
 	g.POST("", ctl.CreateOcservGroup)
 	g.PATCH("/:id", ctl.UpdateOcservGroup)
 	g.DELETE("/:id", ctl.DeleteOcservGroup)
@@ -7,12 +7,18 @@ import (
 )
 
 type CreateOcservGroupData struct {
-	Name   string                    `json:"name" validate:"required"`
-	Config *models.OcservGroupConfig `json:"config" validate:"required"`
+	Name      string                    `json:"name" validate:"required"`
+	Config    *models.OcservGroupConfig `json:"config" validate:"required"`
+	AuthMode  string                    `json:"auth_mode" validate:"omitempty,oneof=password certificate both" enums:"password,certificate,both"`
+	Templates []string                  `json:"templates" validate:"omitempty,unique,dive,required"` // config template names, lowest precedence first
+	VHost     string                    `json:"vhost" validate:"omitempty,max=64" example:"default"` // virtual host the group and its users are listed under, the default one when empty
 }
 
 type UpdateOcservGroupData struct {
-	Config *models.OcservGroupConfig `json:"config" validate:"required"`
+	Config    *models.OcservGroupConfig `json:"config" validate:"required"`
+	AuthMode  *string                   `json:"auth_mode" validate:"omitempty,oneof=password certificate both" enums:"password,certificate,both"`
+	Templates *[]string                 `json:"templates" validate:"omitempty,unique,dive,required"` // config template names, lowest precedence first
+	VHost     *string                   `json:"vhost" validate:"omitempty,max=64" example:"default"` // lists the group and its users under another virtual host
 }
 
 type OcservGroupsResponse struct {
@@ -21,19 +21,27 @@ import (
 )
 
 type Controller struct {
-	request         request.CustomRequestInterface
-	userRepo        repository.UserRepositoryInterface
-	ocservUserRepo  repository.OcservUserRepositoryInterface
-	ocservOcctlRepo repository.OcctlRepositoryInterface
-	reportRepo      repository.ReportRepositoryInterface
+	request          request.CustomRequestInterface
+	userRepo         repository.UserRepositoryInterface
+	ocservUserRepo   repository.OcservUserRepositoryInterface
+	ocservOcctlRepo  repository.OcctlRepositoryInterface
+	reportRepo       repository.ReportRepositoryInterface
+	ipamRepo         repository.IPAMRepositoryInterface
+	vhostRepo        repository.VHostRepositoryInterface
+	nodeRepo         repository.NodeRepositoryInterface
+	serverConfigRepo repository.ServerConfigRepositoryInterface
 }
 
 func New() *Controller {
 	return &Controller{
-		request:         request.NewCustomRequest(),
-		ocservUserRepo:  repository.NewtOcservUserRepository(),
-		ocservOcctlRepo: repository.NewOcctlRepository(),
-		reportRepo:      repository.NewtReportRepository(),
+		request:          request.NewCustomRequest(),
+		ocservUserRepo:   repository.NewtOcservUserRepository(),
+		ocservOcctlRepo:  repository.NewOcctlRepository(),
+		reportRepo:       repository.NewtReportRepository(),
+		ipamRepo:         repository.NewIPAMRepository(),
+		vhostRepo:        repository.NewVHostRepository(),
+		nodeRepo:         repository.NewNodeRepository(),
+		serverConfigRepo: repository.NewServerConfigRepository(),
 	}
 }
 
@@ -51,6 +59,8 @@ func New() *Controller {
 // @Param 		 q query string false "ocserv username q search" minLength(2)
 // @Param 		 filter query string false "filter ocserv user by statues" Enums(online, active, deactivated, locked)
 // @Param 		 group query string false "filter ocserv user by group name"
+// @Param 		 vhost query string false "filter ocserv user and online sessions by virtual host"
+// @Param 		 node query string false "filter ocserv user and online sessions by node"
 // @Param        Authorization header string true "Bearer TOKEN"
 // @Failure      400 {object} request.ErrorResponse
 // @Failure      401 {object} middlewares.Unauthorized
@@ -70,6 +80,8 @@ func (ctl *Controller) Users(c echo.Context) error {
 
 	q := c.QueryParam("q")
 	group := c.QueryParam("group")
+	vhost := c.QueryParam("vhost")
+	node := c.QueryParam("node")
 	pagination := ctl.request.Pagination(c)
 
 	filter := c.QueryParam("filter")
@@ -86,6 +98,12 @@ func (ctl *Controller) Users(c echo.Context) error {
 	onlineUsernames := make([]string, 0)
 
 	for _, u := range onlineUsers {
+		if vhost != "" && models.VHostName(u.VHost) != vhost {
+			continue
+		}
+		if node != "" && u.Node != node {
+			continue
+		}
 		if !slices.Contains(onlineUsernames, u.Username) {
 			onlineUsernames = append(onlineUsernames, u.Username)
 		}
@@ -101,6 +119,7 @@ func (ctl *Controller) Users(c echo.Context) error {
 			VHost:            u.VHost,
 			Device:           u.Device,
 			SessionStartedAt: u.SessionStartedAt,
+			Node:             u.Node,
 		})
 	}
 
@@ -119,6 +138,8 @@ func (ctl *Controller) Users(c echo.Context) error {
 			onlineUsernames,
 			q,
 			group,
+			vhost,
+			node,
 		)
 		if err != nil {
 			return ctl.request.BadRequest(c, err)
@@ -151,6 +172,8 @@ func (ctl *Controller) Users(c echo.Context) error {
 		q,
 		filter,
 		group,
+		vhost,
+		node,
 	)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
@@ -203,6 +226,37 @@ func (ctl *Controller) User(c echo.Context) error {
 	return c.JSON(http.StatusOK, u)
 }
 
+// EffectiveConfig 	 Ocserv user effective config
+//
+// @Summary      Ocserv user effective config
+// @Description  Config ocserv applies to the user, merged from ocserv.conf, the group (or defaults group) file and the user file, with the file each directive comes from and the files it overrides
+// @Tags         Ocserv(Users)
+// @Accept       json
+// @Produce      json
+// @Param 		 uid path string true "Ocserv User UID"
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200  {object}  repository.EffectiveUserConfig
+// @Router       /ocserv/users/{uid}/effective [get]
+func (ctl *Controller) EffectiveConfig(c echo.Context) error {
+	userUID := c.Param("uid")
+	if userUID == "" {
+		return ctl.request.BadRequest(c, errors.New("invalid user uid"))
+	}
+
+	u, err := ctl.ocservUserRepo.GetByUID(c.Request().Context(), userUID)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	effective, err := ctl.ocservUserRepo.EffectiveConfig(c.Request().Context(), u)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, effective)
+}
+
 // Create	     Ocserv User creation
 //
 // @Summary      Ocserv User creation
@@ -254,6 +308,25 @@ func (ctl *Controller) Create(c echo.Context) error {
 		TrafficSize: data.TrafficSize,
 		TrafficType: data.TrafficType,
 		Config:      data.Config,
+		AuthMode:    data.AuthMode,
+		VHost:       data.VHost,
+		Node:        models.NodeName(data.Node),
+	}
+
+	if err := ctl.vhostRepo.ScopeUser(c.Request().Context(), ocUser); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if err := ctl.nodeRepo.Exists(c.Request().Context(), ocUser.Node); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if err := ctl.validateAuthMode(c.Request().Context(), ocUser); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if err := ctl.ipamRepo.CheckAddress(c.Request().Context(), ocUser); err != nil {
+		return ctl.request.BadRequest(c, err)
 	}
 
 	u, err := ctl.ocservUserRepo.Create(c.Request().Context(), ocUser)
@@ -289,14 +362,29 @@ func (ctl *Controller) Update(c echo.Context) error {
 		return ctl.request.BadRequest(c, err)
 	}
 
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
 	ocservUser, err := ctl.ocservUserRepo.GetByUID(c.Request().Context(), userID)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
+	previousIPv4 := explicitIPv4(ocservUser.Config)
 
 	if data.Group != nil {
+		if *data.Group != ocservUser.Group && *data.Group != "defaults" {
+			ocservUser.VHost = ""
+		}
 		ocservUser.Group = *data.Group
 	}
+	if data.VHost != nil {
+		ocservUser.VHost = *data.VHost
+	}
+	if err = ctl.vhostRepo.ScopeUser(c.Request().Context(), ocservUser); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
 	if data.Password != nil {
 		ocservUser.Password = *data.Password
 	}
@@ -320,6 +408,13 @@ func (ctl *Controller) Update(c echo.Context) error {
 	if data.Config != nil {
 		ocservUser.Config = data.Config
 	}
+	if data.AuthMode != nil {
+		ocservUser.AuthMode = *data.AuthMode
+	}
+
+	if err = ctl.validateAuthMode(c.Request().Context(), ocservUser); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
 
 	if data.Unlimited {
 		ocservUser.ExpireAt = nil
@@ -329,10 +424,20 @@ func (ctl *Controller) Update(c echo.Context) error {
 		}
 	}
 
-	updatedOcservUser, err := ctl.ocservUserRepo.Update(c.Request().Context(), ocservUser)
+	// a conflict the user already had must not block editing the rest of it
+	if explicitIPv4(ocservUser.Config) != previousIPv4 {
+		if err = ctl.ipamRepo.CheckAddress(c.Request().Context(), ocservUser); err != nil {
+			return ctl.request.BadRequest(c, err)
+		}
+	}
+
+	updatedOcservUser, err := ctl.ocservUserRepo.Update(
+		c.Request().Context(), ocservUser, repository.ConfigChange{Actor: actor},
+	)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
+
 	return c.JSON(http.StatusOK, updatedOcservUser)
 }
 
@@ -355,13 +460,22 @@ func (ctl *Controller) Delete(c echo.Context) error {
 		return ctl.request.BadRequest(c, errors.New("user id is required"))
 	}
 
+	ocservUser, err := ctl.ocservUserRepo.GetByUID(c.Request().Context(), userID)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	occtlRepo, err := ctl.ocservOcctlRepo.ForNode(c.Request().Context(), ocservUser.Node)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
 	username, err := ctl.ocservUserRepo.Delete(c.Request().Context(), userID)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 
 	go func() {
-		_, _ = ctl.ocservOcctlRepo.Terminate(username)
+		_, _ = occtlRepo.Terminate(username)
 	}()
 
 	return c.JSON(http.StatusNoContent, nil)
@@ -398,8 +512,14 @@ func (ctl *Controller) Lock(c echo.Context) error {
 		u, err := ctl.ocservUserRepo.GetByUID(ctx, userID)
 		if err != nil {
 			logger.Error("failed to fetch ocserv user error: %v", err)
+			return
 		}
-		_, err = ctl.ocservOcctlRepo.Disconnect(u.Username)
+		occtlRepo, err := ctl.ocservOcctlRepo.ForNode(ctx, u.Node)
+		if err != nil {
+			logger.Error("failed to disconnect ocserv user error: %v", err)
+			return
+		}
+		_, err = occtlRepo.Disconnect(u.Username)
 		if err != nil {
 			logger.Error("failed to disconnect ocserv user error: %v", err)
 		}
@@ -712,6 +832,33 @@ func (ctl *Controller) CreateCertificate(c echo.Context) error {
 	return c.JSON(http.StatusOK, nil)
 }
 
+// RenewCertificate issues a new certificate for an ocserv user.
+//
+// @Summary      Renew certificate of ocserv user
+// @Description  Issue a new certificate using the currently stored password and revoke the current one through the CRL. The customer is notified through the Telegram bot when linked.
+// @Tags         Ocserv(Users)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 uid path string true "Ocserv User UID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} models.OcservUser
+// @Router       /ocserv/users/{uid}/certificate/renew [post]
+func (ctl *Controller) RenewCertificate(c echo.Context) error {
+	userID := c.Param("uid")
+	if userID == "" {
+		return ctl.request.BadRequest(c, errors.New("user id is required"))
+	}
+
+	ocservUser, err := ctl.ocservUserRepo.RenewCertificate(c.Request().Context(), userID)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ocservUser)
+}
+
 // DownloadCertificate downloads the user's PKCS#12 certificate bundle.
 //
 // @Summary      Download ocserv user certificate
@@ -730,13 +877,14 @@ func (ctl *Controller) DownloadCertificate(c echo.Context) error {
 		return ctl.request.BadRequest(c, errors.New("user id is required"))
 	}
 
-	username, path, err := ctl.ocservUserRepo.CertificatePath(c.Request().Context(), userID)
+	username, content, err := ctl.ocservUserRepo.Certificate(c.Request().Context(), userID)
 	if err != nil {
 		return ctl.request.BadRequest(c, err)
 	}
 
-	c.Response().Header().Set(echo.HeaderContentType, "application/x-pkcs12")
-	return c.Attachment(path, username+".p12")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", username+".p12"))
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Blob(http.StatusOK, "application/x-pkcs12", content)
 }
 
 // SessionLogs 	 Ocserv User session logs
@@ -831,7 +979,11 @@ func (ctl *Controller) Disconnect(c echo.Context) error {
 	if username == "" {
 		return ctl.request.BadRequest(c, errors.New("user id is required"))
 	}
-	_, err := ctl.ocservOcctlRepo.Disconnect(username)
+	occtlRepo, err := ctl.occtlFor(c.Request().Context(), username)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	_, err = occtlRepo.Disconnect(username)
 	if err != nil {
 		if !strings.Contains(err.Error(), "could not disconnect user") {
 			return ctl.request.BadRequest(c, err)
@@ -849,6 +1001,7 @@ func (ctl *Controller) Disconnect(c echo.Context) error {
 // @Produce      json
 // @Param        Authorization header string true "Bearer TOKEN"
 // @Param 		 id path string true "Ocserv User Session ID"
+// @Param 		 node query string false "Node of the session, the local one by default"
 // @Failure      400 {object} request.ErrorResponse
 // @Failure      401 {object} middlewares.Unauthorized
 // @Success      200  {object} nil
@@ -858,7 +1011,11 @@ func (ctl *Controller) DisconnectSessionById(c echo.Context) error {
 	if id == "" {
 		return ctl.request.BadRequest(c, errors.New("user id is required"))
 	}
-	_, err := ctl.ocservOcctlRepo.DisconnectSession(id)
+	occtlRepo, err := ctl.ocservOcctlRepo.ForNode(c.Request().Context(), c.QueryParam("node"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	_, err = occtlRepo.DisconnectSession(id)
 	if err != nil {
 		if !strings.Contains(err.Error(), "could not disconnect user") {
 			return ctl.request.BadRequest(c, err)
@@ -885,7 +1042,11 @@ func (ctl *Controller) Terminate(c echo.Context) error {
 	if username == "" {
 		return ctl.request.BadRequest(c, errors.New("user id is required"))
 	}
-	_, err := ctl.ocservOcctlRepo.Terminate(username)
+	occtlRepo, err := ctl.occtlFor(c.Request().Context(), username)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	_, err = occtlRepo.Terminate(username)
 	if err != nil {
 		if !strings.Contains(err.Error(), "could not terminate user") {
 			return ctl.request.BadRequest(c, err)
@@ -903,6 +1064,7 @@ func (ctl *Controller) Terminate(c echo.Context) error {
 // @Produce      json
 // @Param        Authorization header string true "Bearer TOKEN"
 // @Param 		 id path string true "Ocserv User Session ID"
+// @Param 		 node query string false "Node of the session, the local one by default"
 // @Failure      400 {object} request.ErrorResponse
 // @Failure      401 {object} middlewares.Unauthorized
 // @Success      200  {object} nil
@@ -912,7 +1074,11 @@ func (ctl *Controller) TerminateSessionById(c echo.Context) error {
 	if id == "" {
 		return ctl.request.BadRequest(c, errors.New("user id is required"))
 	}
-	_, err := ctl.ocservOcctlRepo.TerminateSession(id)
+	occtlRepo, err := ctl.ocservOcctlRepo.ForNode(c.Request().Context(), c.QueryParam("node"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	_, err = occtlRepo.TerminateSession(id)
 	if err != nil {
 		if !strings.Contains(err.Error(), "could not terminate user") {
 			return ctl.request.BadRequest(c, err)
@@ -920,3 +1086,39 @@ func (ctl *Controller) TerminateSessionById(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, nil)
 }
+
+// explicitIPv4 returns the explicit-ipv4 of config, empty when unset.
+func explicitIPv4(config *models.OcservUserConfig) string {
+	if config == nil || config.ExplicitIPv4 == nil {
+		return ""
+	}
+	return *config.ExplicitIPv4
+}
+
+// occtlFor returns the occtl repository of the node username is
+// provisioned on, the local one for users missing from the database.
+func (ctl *Controller) occtlFor(ctx context.Context, username string) (repository.OcctlRepositoryInterface, error) {
+	node := models.LocalNode
+	if u, err := ctl.ocservUserRepo.GetByUsername(ctx, username); err == nil {
+		node = u.Node
+	}
+	return ctl.ocservOcctlRepo.ForNode(ctx, node)
+}
+
+// validateAuthMode checks that the user can be created in its auth mode.
+// Usernames become the certificate common name and directory in
+// certificate modes, and certificate-only users need ocserv.conf to accept
+// them. The agent of a registered node checks its own ocserv.conf.
+func (ctl *Controller) validateAuthMode(ctx context.Context, ocservUser *models.OcservUser) error {
+	mode, err := ctl.ocservUserRepo.AuthMode(ctx, ocservUser)
+	if err != nil {
+		return err
+	}
+	if models.AuthModeUsesCertificate(mode) && !user.ValidCertificateUsername(ocservUser.Username) {
+		return fmt.Errorf("username %q is not valid for %s authentication", ocservUser.Username, mode)
+	}
+	if models.NodeName(ocservUser.Node) != models.LocalNode {
+		return nil
+	}
+	return ctl.serverConfigRepo.CheckAuthMode(ctx, mode)
+}
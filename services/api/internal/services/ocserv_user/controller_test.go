@@ -0,0 +1,81 @@
+package ocserv_user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+)
+
+// nodeUserRepo serves the certificate of a user provisioned on a node
+// through the node's agent client, as the repository does for node users.
+type nodeUserRepo struct {
+	repository.OcservUserRepositoryInterface
+	node *occtlDocker.OcservOcctlDocker
+}
+
+func (r *nodeUserRepo) Certificate(_ context.Context, uid string) (string, []byte, error) {
+	content, err := r.node.Certificate(uid)
+	return uid, content, err
+}
+
+func TestDownloadCertificateFromNode(t *testing.T) {
+	// not valid DER, only the bytes need to survive the round trip
+	p12 := []byte{0x30, 0x82, 0x00, 0xff, '\n', 0x00, 0x7f}
+
+	var gotMethod, gotUsername string
+	verifier := occtlDocker.NewVerifier("node-secret", 0)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if verifier.Verify(r, body) != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var params occtlDocker.RPCParams
+		_ = json.Unmarshal(body, &params)
+		gotMethod, gotUsername = strings.TrimPrefix(r.URL.Path, "/webhook/rpc/"), params.Username
+
+		result, _ := json.Marshal(p12)
+		_ = json.NewEncoder(w).Encode(occtlDocker.RPCResponse{Result: result})
+	}))
+	t.Cleanup(agent.Close)
+
+	ctl := &Controller{
+		request:        request.NewCustomRequest(),
+		ocservUserRepo: &nodeUserRepo{node: occtlDocker.NewNodeClient(agent.URL, "node-secret")},
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/ocserv/users/alice/certificate", nil), rec)
+	c.SetParamNames("uid")
+	c.SetParamValues("alice")
+
+	if err := ctl.DownloadCertificate(c); err != nil {
+		t.Fatalf("DownloadCertificate: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotMethod != occtlDocker.MethodCertificate || gotUsername != "alice" {
+		t.Errorf("unexpected agent call: %s %q", gotMethod, gotUsername)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), p12) {
+		t.Errorf("body %x, want the bytes of the agent %x", rec.Body.Bytes(), p12)
+	}
+	if ct := rec.Header().Get(echo.HeaderContentType); ct != "application/x-pkcs12" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if cd := rec.Header().Get(echo.HeaderContentDisposition); cd != `attachment; filename="alice.p12"` {
+		t.Errorf("unexpected content disposition %q", cd)
+	}
+}
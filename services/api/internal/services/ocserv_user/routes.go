@@ -11,6 +11,7 @@ func Routes(e *echo.Group) {
 
 	g.GET("", ctl.Users)
 	g.GET("/:uid", ctl.User)
+	g.GET("/:uid/effective", ctl.EffectiveConfig)
 
 	g.POST("", ctl.Create)
 	g.PATCH("/:uid", ctl.Update)
@@ -28,6 +29,7 @@ func Routes(e *echo.Group) {
 
 	g.POST("/:uid/certificate", ctl.CreateCertificate)
 	g.GET("/:uid/certificate", ctl.DownloadCertificate)
+	g.POST("/:uid/certificate/renew", ctl.RenewCertificate)
 
 	g.GET("/:uid/session_logs", ctl.SessionLogs)
 	g.GET("/:uid/statistics", ctl.Statistics)
@@ -17,6 +17,13 @@ type CreateOcservUserData struct {
 	TrafficSize int64                    `json:"traffic_size" validate:"omitempty,gte=0" example:"10737418240"` // 10 GiB
 	Description string                   `json:"description" validate:"omitempty,max=1024" example:"User for testing VPN access"`
 	Config      *models.OcservUserConfig `json:"config" validate:"required"`
+	AuthMode    string                   `json:"auth_mode" validate:"omitempty,oneof=password certificate both" enums:"password,certificate,both"`
+	// VHost the user is listed under, defaults to the virtual host of the
+	// group and must match it unless the group is defaults. It does not
+	// limit the virtual hosts the user can connect to.
+	VHost string `json:"vhost" validate:"omitempty,max=64" example:"default"`
+	// Node the user is provisioned on, the local one by default.
+	Node string `json:"node" validate:"omitempty,max=64" example:"local"`
 }
 
 type UpdateOcservUserData struct {
@@ -28,6 +35,11 @@ type UpdateOcservUserData struct {
 	TrafficSize *int64                   `json:"traffic_size" validate:"gte=0" example:"10737418240"` // 10 GiB
 	Description *string                  `json:"description" validate:"omitempty,max=1024" example:"User for testing VPN access"`
 	Config      *models.OcservUserConfig `json:"config" validate:"omitempty"`
+	// AuthMode set to an empty string inherits the group mode again.
+	AuthMode *string `json:"auth_mode" validate:"omitempty,oneof=password certificate both" enums:"password,certificate,both"`
+	// VHost lists the user under another virtual host. Without it, a user
+	// moved to another group follows the group.
+	VHost *string `json:"vhost" validate:"omitempty,max=64" example:"default"`
 }
 
 type OcservUsersResponse struct {
@@ -12,12 +12,14 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
 )
 
 type Controller struct {
 	request         request.CustomRequestInterface
 	reportRepo      repository.ReportRepositoryInterface
 	ocservOcctlRepo repository.OcctlRepositoryInterface
+	ipBanRepo       repository.IPBanRepositoryInterface
 }
 
 func New() *Controller {
@@ -25,6 +27,7 @@ func New() *Controller {
 		request:         request.NewCustomRequest(),
 		reportRepo:      repository.NewtReportRepository(),
 		ocservOcctlRepo: repository.NewOcctlRepository(),
+		ipBanRepo:       repository.NewIPBanRepository(),
 	}
 }
 
@@ -256,3 +259,175 @@ func (ctl *Controller) OcservUserReport(c echo.Context) error {
 		Locked:      result.Locked,
 	})
 }
+
+// RepeatedIPBans     IPs banned repeatedly
+//
+// @Summary      IPs banned repeatedly
+// @Description  Addresses banned at least min times in the last days, most banned first
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 days query int false "look back window in days, default 30" minimum(1) maximum(365)
+// @Param 		 min query int false "minimum number of bans, default 2" minimum(2)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {array} repository.RepeatedIPBan
+// @Router       /reports/repeated_ip_bans [get]
+func (ctl *Controller) RepeatedIPBans(c echo.Context) error {
+	var data RepeatedIPBansData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if data.Days == 0 {
+		data.Days = 30
+	}
+	if data.Min == 0 {
+		data.Min = 2
+	}
+
+	since := time.Now().AddDate(0, 0, -data.Days)
+
+	bans, err := ctl.ipBanRepo.Repeated(c.Request().Context(), since, data.Min)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, bans)
+}
+
+// AuthAlerts     Failed login alerts
+//
+// @Summary      Failed login alerts
+// @Description  Bursts of failed ocserv logins per username or source IP, and the lock or ban applied
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Param 		 kind query string false "filter by alert kind" Enums(user, ip)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} AuthAlertsResponse
+// @Router       /reports/auth_alerts [get]
+func (ctl *Controller) AuthAlerts(c echo.Context) error {
+	kind := c.QueryParam("kind")
+	switch kind {
+	case "", models.AuthAlertKindUser, models.AuthAlertKindIP:
+	default:
+		return ctl.request.BadRequest(c, fmt.Errorf("invalid kind: %s", kind))
+	}
+
+	pagination := ctl.request.Pagination(c)
+	if c.QueryParam("sort") == "" {
+		pagination.Sort = "DESC"
+	}
+
+	alerts, total, err := ctl.reportRepo.AuthAlerts(c.Request().Context(), pagination, kind)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, AuthAlertsResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: alerts,
+	})
+}
+
+// ExpiringCertificates     Expiring user certificates
+//
+// @Summary      Expiring user certificates
+// @Description  Active users whose client certificate expires within days, including already expired ones, soonest first
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 days query int false "look ahead window in days, default 30" minimum(1) maximum(825)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {array} repository.ExpiringCertificate
+// @Router       /reports/expiring_certificates [get]
+func (ctl *Controller) ExpiringCertificates(c echo.Context) error {
+	var data ExpiringCertificatesData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	if data.Days == 0 {
+		data.Days = 30
+	}
+
+	certificates, err := ctl.reportRepo.ExpiringCertificates(c.Request().Context(), time.Now().AddDate(0, 0, data.Days))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, certificates)
+}
+
+// VHosts     Report per virtual host
+//
+// @Summary      Report per virtual host
+// @Description  Groups, users, online users and traffic (GiB) of each virtual host, the default one first. Traffic is limited to the dates when given.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 date_start query string false "date_start"
+// @Param 		 date_end query string false "date_end"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} []repository.VHostUsage
+// @Router       /reports/vhosts [get]
+func (ctl *Controller) VHosts(c echo.Context) error {
+	var data VHostsData
+	if err := c.Bind(&data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	var startDate, endDate *time.Time
+
+	if data.DateStart != "" {
+		t, err := time.Parse("2006-01-02", data.DateStart)
+		if err != nil {
+			return ctl.request.BadRequest(c, fmt.Errorf("invalid date_start: %w", err))
+		}
+		startDate = &t
+	}
+
+	if data.DateEnd != "" {
+		t, err := time.Parse("2006-01-02", data.DateEnd)
+		if err != nil {
+			return ctl.request.BadRequest(c, fmt.Errorf("invalid date_end: %w", err))
+		}
+		t = t.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+		endDate = &t
+	}
+
+	usage, err := ctl.reportRepo.VHostUsage(c.Request().Context(), startDate, endDate)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	// online users are counted once per virtual host, like OcservUserReport
+	online := make(map[string][]string)
+	if sessions, err := ctl.ocservOcctlRepo.OnlineSessions(); err == nil {
+		for _, s := range sessions {
+			vhost := models.VHostName(s.VHost)
+			if !slices.Contains(online[vhost], s.Username) {
+				online[vhost] = append(online[vhost], s.Username)
+			}
+		}
+	}
+	for i := range usage {
+		usage[i].Online = len(online[usage[i].VHost])
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
@@ -13,4 +13,8 @@ func Routes(e *echo.Group) {
 	g.GET("/statistics", ctl.Statistics)
 	g.GET("/users", ctl.OcservUserReport)
 	g.GET("/total-bandwidth", ctl.TotalBandwidth)
+	g.GET("/repeated_ip_bans", ctl.RepeatedIPBans)
+	g.GET("/auth_alerts", ctl.AuthAlerts)
+	g.GET("/expiring_certificates", ctl.ExpiringCertificates)
+	g.GET("/vhosts", ctl.VHosts)
 }
@@ -37,3 +37,22 @@ type OcservUserReportResponse struct {
 	Deactivated int64 `json:"deactivated"`
 	Locked      int64 `json:"locked"`
 }
+
+type RepeatedIPBansData struct {
+	Days int `json:"days" query:"days" validate:"omitempty,min=1,max=365"`
+	Min  int `json:"min" query:"min" validate:"omitempty,min=2"`
+}
+
+type AuthAlertsResponse struct {
+	Meta   request.Meta              `json:"meta" validate:"required"`
+	Result *[]models.OcservAuthAlert `json:"result" validate:"omitempty"`
+}
+
+type VHostsData struct {
+	DateStart string `json:"date_start" query:"date_start" validate:"omitempty" example:"2025-1-31"`
+	DateEnd   string `json:"date_end" query:"date_end" validate:"omitempty" example:"2025-12-31"`
+}
+
+type ExpiringCertificatesData struct {
+	Days int `json:"days" query:"days" validate:"omitempty,min=1,max=825"`
+}
@@ -0,0 +1,170 @@
+package server_config
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/server"
+)
+
+type Controller struct {
+	request          request.CustomRequestInterface
+	serverConfigRepo repository.ServerConfigRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:          request.NewCustomRequest(),
+		serverConfigRepo: repository.NewServerConfigRepository(),
+	}
+}
+
+// Config
+// @Summary      Ocserv server config
+// @Description  Typed directives of the main ocserv.conf
+// @Tags         Ocserv(Server Config)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservServerConfig
+// @Router       /ocserv/server/config [get]
+func (ctl *Controller) Config(c echo.Context) error {
+	config, err := ctl.serverConfigRepo.Config(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, config)
+}
+
+// Preview
+// @Summary      Preview ocserv server config
+// @Description  Render ocserv.conf for the given config without applying it, with the changed directives and whether applying needs an ocserv restart
+// @Tags         Ocserv(Server Config)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  models.OcservServerConfig  true "server config"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} repository.ServerConfigPreview
+// @Router       /ocserv/server/config/preview [post]
+func (ctl *Controller) Preview(c echo.Context) error {
+	var data models.OcservServerConfig
+
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	if err := server.Validate(&data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	preview, err := ctl.serverConfigRepo.Preview(c.Request().Context(), &data)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, preview)
+}
+
+// Apply
+// @Summary      Apply ocserv server config
+// @Description  Write ocserv.conf and reload ocserv. Changes to directives read at startup only restart ocserv under systemd; in docker the revision action is restart_required and the container has to be restarted. The previous ocserv.conf is restored when ocserv refuses the reload or fails to restart.
+// @Tags         Ocserv(Server Config)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  models.OcservServerConfig  true "server config"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservServerConfigRevision
+// @Router       /ocserv/server/config [put]
+func (ctl *Controller) Apply(c echo.Context) error {
+	var data models.OcservServerConfig
+
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	if err := server.Validate(&data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	actor := c.Get("username").(string)
+	if actor == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	revision, err := ctl.serverConfigRepo.Apply(c.Request().Context(), &data, actor)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, revision)
+}
+
+// Revisions
+// @Summary      Ocserv server config history
+// @Description  List the versions of ocserv.conf applied from the dashboard
+// @Tags         Ocserv(Server Config)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} RevisionsResponse
+// @Router       /ocserv/server/config/revisions [get]
+func (ctl *Controller) Revisions(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+	if c.QueryParam("sort") == "" {
+		pagination.Sort = "DESC"
+	}
+
+	revisions, total, err := ctl.serverConfigRepo.Revisions(c.Request().Context(), pagination)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, RevisionsResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: revisions,
+	})
+}
+
+// Revision
+// @Summary      Ocserv server config revision
+// @Description  Get a version of ocserv.conf applied from the dashboard
+// @Tags         Ocserv(Server Config)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Revision ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservServerConfigRevision
+// @Router       /ocserv/server/config/revisions/{id} [get]
+func (ctl *Controller) Revision(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return ctl.request.BadRequest(c, errors.New("invalid revision id"))
+	}
+
+	revision, err := ctl.serverConfigRepo.Revision(c.Request().Context(), uint(id))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, revision)
+}
@@ -0,0 +1,17 @@
+package server_config
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/server/config", middlewares.AuthMiddleware(), middlewares.AdminPermission())
+
+	g.GET("", ctl.Config)
+	g.POST("/preview", ctl.Preview)
+	g.PUT("", ctl.Apply)
+	g.GET("/revisions", ctl.Revisions)
+	g.GET("/revisions/:id", ctl.Revision)
+}
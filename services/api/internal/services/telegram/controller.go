@@ -501,6 +501,12 @@ func (ctl *Controller) deliverNewAccount(
 		Description: fmt.Sprintf("created via telegram bot (request #%d)", req.ID),
 	}
 
+	// resolved before the user exists, the delivery message depends on it
+	authMode, err := ctl.ocservUserRepo.AuthMode(c.Request().Context(), user)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
 	created, err := ctl.ocservUserRepo.Create(c.Request().Context(), user)
 	if err != nil {
 		return ctl.request.BadRequest(c, fmt.Errorf("failed to create ocserv user: %w", err))
@@ -519,7 +525,7 @@ func (ctl *Controller) deliverNewAccount(
 		return ctl.request.BadRequest(c, err)
 	}
 
-	go ctl.notifyDelivery(req.ChatID, settings, formatNewAccountMessage(settings, created, password, expireAt))
+	go ctl.notifyDelivery(req.ChatID, settings, formatNewAccountMessage(settings, created, authMode, password, expireAt))
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"status":   "delivered",
 		"username": created.Username,
@@ -557,7 +563,7 @@ func (ctl *Controller) deliverRenewal(
 	user.TrafficType = pkg.TrafficType
 	user.TrafficSize = gigabytesToBytes(pkg.TrafficSizeGB)
 
-	if _, err := ctl.ocservUserRepo.Update(c.Request().Context(), user); err != nil {
+	if _, err := ctl.ocservUserRepo.Update(c.Request().Context(), user, repository.ConfigChange{Actor: "telegram"}); err != nil {
 		return ctl.request.BadRequest(c, fmt.Errorf("failed to renew ocserv user: %w", err))
 	}
 
@@ -717,13 +723,19 @@ func formatRejectedMessage(settings *models.TelegramSettings, adminNote string)
 	return msg
 }
 
-func formatNewAccountMessage(settings *models.TelegramSettings, user *models.OcservUser, plainPassword string, expireAt time.Time) string {
+// formatNewAccountMessage renders the credentials of a new account.
+// Certificate-only accounts get a hint that the password unlocks the
+// certificate from the customer portal.
+func formatNewAccountMessage(settings *models.TelegramSettings, user *models.OcservUser, authMode, plainPassword string, expireAt time.Time) string {
 	host := settings.OcservHost
 	if host == "" {
 		host = "—"
 	}
 	support := supportLine(settings)
 	lang := defaultNotifyLang(settings)
+	if !models.AuthModeUsesPassword(authMode) {
+		support = tg18n.T(lang, "certificate_login_hint") + support
+	}
 	return tg18n.T(lang, "new_account",
 		htmlEsc(host), htmlEsc(user.Username), htmlEsc(plainPassword),
 		expireAt.Format("2006-01-02"), bytesToGigabytes(user.TrafficSize), support,
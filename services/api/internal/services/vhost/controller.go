@@ -0,0 +1,184 @@
+package vhost
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type Controller struct {
+	request   request.CustomRequestInterface
+	vhostRepo repository.VHostRepositoryInterface
+}
+
+func New() *Controller {
+	return &Controller{
+		request:   request.NewCustomRequest(),
+		vhostRepo: repository.NewVHostRepository(),
+	}
+}
+
+// VHosts
+// @Summary      List of virtual hosts
+// @Description  List of the ocserv virtual hosts. The default virtual host is the global section of ocserv.conf and is not listed.
+// @Tags         Ocserv(Virtual Hosts)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 page query int false "Page number, starting from 1" minimum(1)
+// @Param 		 size query int false "Number of items per page" minimum(1) maximum(100) name(size)
+// @Param 		 order query string false "Field to order by"
+// @Param 		 sort query string false "Sort order, either ASC or DESC" Enums(ASC, DESC)
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} VHostsResponse
+// @Router       /ocserv/vhosts [get]
+func (ctl *Controller) VHosts(c echo.Context) error {
+	pagination := ctl.request.Pagination(c)
+
+	vhosts, total, err := ctl.vhostRepo.VHosts(c.Request().Context(), pagination)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	return c.JSON(http.StatusOK, VHostsResponse{
+		Meta: request.Meta{
+			Page:         pagination.Page,
+			TotalRecords: total,
+			PageSize:     pagination.PageSize,
+		},
+		Result: vhosts,
+	})
+}
+
+// Lookup
+// @Summary      Virtual host names
+// @Description  Names of the virtual hosts groups and users can be listed under, the default one first
+// @Tags         Ocserv(Virtual Hosts)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} []string
+// @Router       /ocserv/vhosts/lookup [get]
+func (ctl *Controller) Lookup(c echo.Context) error {
+	names, err := ctl.vhostRepo.Lookup(c.Request().Context())
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, names)
+}
+
+// VHost
+// @Summary      Virtual host detail
+// @Description  Virtual host detail
+// @Tags         Ocserv(Virtual Hosts)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Virtual host ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Success      200 {object} models.OcservVHost
+// @Router       /ocserv/vhosts/{id} [get]
+func (ctl *Controller) VHost(c echo.Context) error {
+	vhost, err := ctl.vhostRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, vhost)
+}
+
+// Create
+// @Summary      Create virtual host
+// @Description  Create a virtual host and add its section to ocserv.conf. ocserv.conf is put back when ocserv refuses the reload.
+// @Tags         Ocserv(Virtual Hosts)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param        request body  CreateVHostData  true "virtual host data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      201 {object} models.OcservVHost
+// @Router       /ocserv/vhosts [post]
+func (ctl *Controller) Create(c echo.Context) error {
+	var data CreateVHostData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	owner := c.Get("username").(string)
+	if owner == "" {
+		return ctl.request.BadRequest(c, errors.New("admin or staff username not found"))
+	}
+
+	vhost, err := ctl.vhostRepo.Create(c.Request().Context(), &models.OcservVHost{
+		Name:        data.Name,
+		Description: data.Description,
+		Owner:       owner,
+		Config:      data.Config,
+	})
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusCreated, vhost)
+}
+
+// Update
+// @Summary      Update virtual host
+// @Description  Update a virtual host and rewrite its section of ocserv.conf. ocserv.conf is put back when ocserv refuses the reload.
+// @Tags         Ocserv(Virtual Hosts)
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Virtual host ID"
+// @Param        request body  UpdateVHostData  true "virtual host data"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      200 {object} models.OcservVHost
+// @Router       /ocserv/vhosts/{id} [patch]
+func (ctl *Controller) Update(c echo.Context) error {
+	var data UpdateVHostData
+	if err := ctl.request.DoValidate(c, &data); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	vhost, err := ctl.vhostRepo.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+
+	vhost.Config = data.Config
+	if data.Description != nil {
+		vhost.Description = *data.Description
+	}
+
+	vhost, err = ctl.vhostRepo.Update(c.Request().Context(), vhost)
+	if err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusOK, vhost)
+}
+
+// Delete
+// @Summary      Delete virtual host
+// @Description  Delete a virtual host no group or user belongs to, and its section of ocserv.conf
+// @Tags         Ocserv(Virtual Hosts)
+// @Produce      json
+// @Param        Authorization header string true "Bearer TOKEN"
+// @Param 		 id path int true "Virtual host ID"
+// @Failure      400 {object} request.ErrorResponse
+// @Failure      401 {object} middlewares.Unauthorized
+// @Failure      403 {object} middlewares.PermissionDenied
+// @Success      204 {object} nil
+// @Router       /ocserv/vhosts/{id} [delete]
+func (ctl *Controller) Delete(c echo.Context) error {
+	if err := ctl.vhostRepo.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return ctl.request.BadRequest(c, err)
+	}
+	return c.JSON(http.StatusNoContent, nil)
+}
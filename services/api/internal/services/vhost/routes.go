@@ -0,0 +1,18 @@
+package vhost
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing/middlewares"
+)
+
+func Routes(e *echo.Group) {
+	ctl := New()
+	g := e.Group("/ocserv/vhosts", middlewares.AuthMiddleware())
+
+	g.GET("", ctl.VHosts)
+	g.GET("/lookup", ctl.Lookup)
+	g.GET("/:id", ctl.VHost)
+	g.POST("", ctl.Create, middlewares.AdminPermission())
+	g.PATCH("/:id", ctl.Update, middlewares.AdminPermission())
+	g.DELETE("/:id", ctl.Delete, middlewares.AdminPermission())
+}
@@ -0,0 +1,23 @@
+package vhost
+
+import (
+	"github.com/mmtaee/ocserv-dashboard/api/pkg/request"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+type CreateVHostData struct {
+	// Name is the host name clients connect to, matched against the TLS SNI.
+	Name        string                    `json:"name" validate:"required,max=64,hostname_rfc1123" example:"vpn.corp.example.com"`
+	Description string                    `json:"description" validate:"omitempty,max=1024"`
+	Config      *models.OcservVHostConfig `json:"config" validate:"omitempty"`
+}
+
+type UpdateVHostData struct {
+	Description *string                   `json:"description" validate:"omitempty,max=1024"`
+	Config      *models.OcservVHostConfig `json:"config" validate:"required"`
+}
+
+type VHostsResponse struct {
+	Meta   request.Meta          `json:"meta" validate:"required"`
+	Result *[]models.OcservVHost `json:"result" validate:"omitempty"`
+}
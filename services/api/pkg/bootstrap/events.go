@@ -0,0 +1,97 @@
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/eventbus"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+)
+
+// nodeRefreshInterval is how often the node registry is read again.
+const nodeRefreshInterval = 30 * time.Second
+
+// nodeWatcher is the event subscription of a registered node.
+type nodeWatcher struct {
+	client *occtlDocker.OcservOcctlDocker
+	cancel context.CancelFunc
+}
+
+// nodeWatchers runs one event subscription per enabled registered node.
+type nodeWatchers struct {
+	clients *occtlDocker.NodeClients
+	running map[string]nodeWatcher
+	start   func(ctx context.Context, node string, client *occtlDocker.OcservOcctlDocker)
+}
+
+// sync starts the subscriptions of nodes not watched yet, restarts the ones
+// whose URL or secret changed and stops the ones of nodes no longer
+// enabled.
+func (w *nodeWatchers) sync(ctx context.Context, nodes []models.OcservNode) {
+	enabled := make(map[string]bool, len(nodes))
+	for i := range nodes {
+		name := nodes[i].Name
+		enabled[name] = true
+
+		// NodeClients keeps the client while the URL and secret stay the same
+		client := w.clients.For(&nodes[i])
+		watcher, ok := w.running[name]
+		if ok && watcher.client == client {
+			continue
+		}
+		if ok {
+			logger.Info("Restarting occtl events of node %s", name)
+			watcher.cancel()
+		}
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		w.running[name] = nodeWatcher{client: client, cancel: cancel}
+		go w.start(watchCtx, name, client)
+	}
+
+	for name, watcher := range w.running {
+		if !enabled[name] {
+			logger.Info("Stopping occtl events of node %s", name)
+			watcher.cancel()
+			delete(w.running, name)
+		}
+	}
+}
+
+// watchEvents publishes the occtl events of the local ocserv and of every
+// enabled registered node on bus, following the registry every
+// nodeRefreshInterval until ctx is cancelled.
+func watchEvents(ctx context.Context, clients *occtlDocker.NodeClients, bus *eventbus.Bus) {
+	go occtl.WatchEvents(ctx, models.LocalNode, occtlDocker.NewOcctlClient(), bus)
+
+	watchers := &nodeWatchers{
+		clients: clients,
+		running: make(map[string]nodeWatcher),
+		start: func(ctx context.Context, node string, client *occtlDocker.OcservOcctlDocker) {
+			occtl.WatchEvents(ctx, node, client, bus)
+		},
+	}
+
+	ticker := time.NewTicker(nodeRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		nodes, err := clients.Enabled(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("Failed to load ocserv nodes: %v", err)
+			}
+		} else {
+			watchers.sync(ctx, nodes)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -20,6 +20,17 @@ var Migrations = []*gormigrate.Migration{
 	migrations.Migration009,
 	migrations.Migration010,
 	migrations.Migration011,
+	migrations.Migration012,
+	migrations.Migration013,
+	migrations.Migration014,
+	migrations.Migration015,
+	migrations.Migration016,
+	migrations.Migration017,
+	migrations.Migration018,
+	migrations.Migration019,
+	migrations.Migration020,
+	migrations.Migration021,
+	migrations.Migration022,
 }
 
 func Migrate() {
@@ -2,9 +2,12 @@ package bootstrap
 
 import (
 	"context"
+	"github.com/mmtaee/ocserv-dashboard/api/internal/repository"
 	"github.com/mmtaee/ocserv-dashboard/api/pkg/routing"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/config"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/eventbus"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
 	"os"
 	"os/signal"
@@ -30,6 +33,21 @@ func Serve(debug bool, host string, port int) {
 	database.Connect()
 	defer database.Close()
 
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go watchEvents(watchCtx, occtlDocker.NewNodeClients(database.GetConnection()), eventbus.Default())
+
+	// re-apply ip bans lost by an ocserv restart and expire outdated ones,
+	// on the local node and on every registered node
+	go repository.NewIPBanRepository().Run(watchCtx, time.Minute)
+
+	// backfill the certificate expiry of users created before it was stored
+	go func() {
+		if err := repository.NewtOcservUserRepository().SyncCertificateExpiry(watchCtx); err != nil {
+			logger.Warn("Failed to sync certificate expiry: %v", err)
+		}
+	}()
+
 	go routing.Serve(cfg)
 
 	quit := make(chan os.Signal, 1)
@@ -45,6 +63,7 @@ func Serve(debug bool, host string, port int) {
 
 	logger.Warn("Shutting down... Signal Reason: %s", sig.String())
 
+	stopWatch()
 	routing.Shutdown(ctx)
 	database.Close()
 
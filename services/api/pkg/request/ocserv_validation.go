@@ -0,0 +1,102 @@
+package request
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// registerOcservValidations adds the validation tags of the ocserv config
+// models: ocserv_route, ocserv_network, ocserv_no_overlap, ocserv_ports and
+// ocserv_cgroup, and checks explicit-ipv4 against ipv4-network.
+func registerOcservValidations(v *validator.Validate) {
+	// report fields by their json name, as the client sent them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	_ = v.RegisterValidation("ocserv_route", func(fl validator.FieldLevel) bool {
+		return utils.ValidRoute(fl.Field().String())
+	})
+	_ = v.RegisterValidation("ocserv_network", func(fl validator.FieldLevel) bool {
+		_, err := utils.ParseNetwork(fl.Field().String())
+		return err == nil
+	})
+	_ = v.RegisterValidation("ocserv_ports", func(fl validator.FieldLevel) bool {
+		return utils.ParsePortRestrictions(fl.Field().String()) == nil
+	})
+	_ = v.RegisterValidation("ocserv_cgroup", func(fl validator.FieldLevel) bool {
+		return utils.ValidCGroup(fl.Field().String())
+	})
+
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		var explicit, network *string
+		var route, noRoute *models.CSVStringList
+		switch config := sl.Current().Interface().(type) {
+		case models.OcservUserConfig:
+			explicit, network = config.ExplicitIPv4, config.IPv4Network
+			route, noRoute = config.Route, config.NoRoute
+		case models.OcservGroupConfig:
+			explicit, network = config.ExplicitIPv4, config.IPv4Network
+			route, noRoute = config.Route, config.NoRoute
+		}
+
+		for _, routes := range []struct {
+			name, field string
+			list        *models.CSVStringList
+		}{{"route", "Route", route}, {"no-route", "NoRoute", noRoute}} {
+			if routes.list == nil {
+				continue
+			}
+			if pair := utils.OverlappingRoutes(*routes.list); pair != nil {
+				sl.ReportError(*routes.list, routes.name, routes.field, "ocserv_no_overlap", pair[0]+" and "+pair[1])
+			}
+		}
+
+		if explicit == nil || network == nil || *explicit == "" || *network == "" {
+			return
+		}
+		if _, err := utils.ParseNetwork(*network); err != nil {
+			return // reported by ocserv_network
+		}
+		if !utils.IPInNetwork(*explicit, *network) {
+			sl.ReportError(*explicit, "explicit-ipv4", "ExplicitIPv4", "ocserv_in_pool", *network)
+		}
+	}, models.OcservUserConfig{}, models.OcservGroupConfig{})
+}
+
+// ocservErrorMessage describes the failure of an ocserv validation tag, or
+// returns "" for other tags.
+func ocservErrorMessage(field string, err validator.FieldError) string {
+	value, _ := err.Value().(string)
+
+	switch err.Tag() {
+	case "ip", "ipv4":
+		return field + " must be a valid IP address"
+	case "hostname_rfc1123":
+		return field + " must be a valid domain name"
+	case "ocserv_route":
+		return field + " must be a CIDR, an address with a netmask or default"
+	case "ocserv_network":
+		return field + " must be a network in CIDR or address/netmask form"
+	case "ocserv_no_overlap":
+		return field + " has overlapping routes " + err.Param()
+	case "ocserv_ports":
+		if parseErr := utils.ParsePortRestrictions(value); parseErr != nil {
+			return field + ": " + parseErr.Error()
+		}
+		return field + " is not a valid port restriction"
+	case "ocserv_cgroup":
+		return field + " must be in controller[,controller]:name form"
+	case "ocserv_in_pool":
+		return field + " must be an address of ipv4-network " + err.Param()
+	}
+	return ""
+}
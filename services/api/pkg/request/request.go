@@ -17,7 +17,10 @@ type CustomRequestInterface interface {
 }
 
 func NewCustomRequest() *Request {
+	v := validator.New()
+	registerOcservValidations(v)
+
 	return &Request{
-		validator: validator.New(),
+		validator: v,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"strings"
 	"unicode"
 )
 
@@ -32,7 +33,11 @@ func formatSnakeCase(s string) string {
 }
 
 func formatError(err validator.FieldError) string {
-	field := formatSnakeCase(err.Field())
+	field := formatSnakeCase(fieldPath(err))
+	if msg := ocservErrorMessage(field, err); msg != "" {
+		return msg
+	}
+
 	switch err.Tag() {
 	case "required":
 		return field + " is required"
@@ -45,6 +50,16 @@ func formatError(err validator.FieldError) string {
 	}
 }
 
+// fieldPath is the namespace of the failed field without the request
+// struct, e.g. config.route[1].
+func fieldPath(err validator.FieldError) string {
+	parts := strings.SplitN(err.Namespace(), ".", 2)
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return err.Field()
+}
+
 func errorWrapper(err error) interface{} {
 	var (
 		invalidValidationError *validator.InvalidValidationError
@@ -14,17 +14,39 @@ func AuthMiddleware() echo.MiddlewareFunc {
 				return UnauthorizedError(c, "missing or invalid Authorization header")
 			}
 
-			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+			return authenticate(c, next, strings.TrimPrefix(authHeader, "Bearer "))
+		}
+	}
+}
 
-			claims, ok := token.Check(tokenStr)
-			if !ok {
-				return UnauthorizedError(c, "invalid token")
+// StreamAuthMiddleware is AuthMiddleware for Server-Sent Events routes. A
+// browser EventSource cannot set headers, so the token may also be passed in
+// the token query parameter.
+func StreamAuthMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if strings.HasPrefix(authHeader, "Bearer ") {
+				return authenticate(c, next, strings.TrimPrefix(authHeader, "Bearer "))
 			}
 
-			c.Set("userUID", claims["sub"])
-			c.Set("isAdmin", claims["isAdmin"])
-			c.Set("username", claims["username"])
-			return next(c)
+			tokenStr := c.QueryParam("token")
+			if tokenStr == "" {
+				return UnauthorizedError(c, "missing or invalid Authorization header or token")
+			}
+			return authenticate(c, next, tokenStr)
 		}
 	}
 }
+
+func authenticate(c echo.Context, next echo.HandlerFunc, tokenStr string) error {
+	claims, ok := token.Check(tokenStr)
+	if !ok {
+		return UnauthorizedError(c, "invalid token")
+	}
+
+	c.Set("userUID", claims["sub"])
+	c.Set("isAdmin", claims["isAdmin"])
+	c.Set("username", claims["username"])
+	return next(c)
+}
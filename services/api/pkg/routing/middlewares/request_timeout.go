@@ -4,12 +4,19 @@ import (
 	"context"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"slices"
 	"time"
 )
 
-func TimeoutMiddleware(timeout time.Duration) echo.MiddlewareFunc {
+// TimeoutMiddleware aborts requests running longer than timeout. Long-lived
+// streaming routes listed in skipPaths are left alone.
+func TimeoutMiddleware(timeout time.Duration, skipPaths ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if slices.Contains(skipPaths, c.Path()) {
+				return next(c)
+			}
+
 			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
 			defer cancel()
 
@@ -33,6 +33,10 @@ var (
 		http.MethodHead,
 		http.MethodOptions,
 	}
+	// streamingPaths are long-lived responses excluded from request timeout and gzip
+	streamingPaths = []string{
+		"/api/occtl/events",
+	}
 )
 
 func Serve(cfg *config.Config) {
@@ -50,7 +54,7 @@ func Serve(cfg *config.Config) {
 			return nil
 		},
 	}))
-	e.Use(middlewares.TimeoutMiddleware(10 * time.Second))
+	e.Use(middlewares.TimeoutMiddleware(10*time.Second, streamingPaths...))
 
 	if cfg.Debug {
 		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
@@ -89,6 +93,8 @@ func Serve(cfg *config.Config) {
 			path := c.Path()
 
 			switch {
+			case slices.Contains(streamingPaths, path):
+				return true
 			case strings.HasPrefix(path, "/api/v1/ocserv/users/backup"):
 				return true
 			case strings.HasPrefix(path, "/api/v1/ocserv/groups/backup"):
@@ -7,6 +7,7 @@ require (
 	github.com/oklog/ulid/v2 v2.1.1
 	gorm.io/driver/postgres v1.6.0
 	gorm.io/gorm v1.30.1
+	software.sslmate.com/src/go-pkcs12 v0.5.0
 )
 
 require (
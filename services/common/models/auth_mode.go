@@ -0,0 +1,36 @@
+package models
+
+// Authentication modes of an ocserv user. A user without a mode inherits
+// the mode of its group, and a group without one uses AuthModeBoth, which
+// is what the dashboard always did.
+const (
+	// AuthModePassword writes an ocpasswd entry and no client certificate.
+	AuthModePassword = "password"
+	// AuthModeCertificate issues a client certificate and no ocpasswd entry.
+	// The stored password still protects the P12 bundle and the customer
+	// portal.
+	AuthModeCertificate = "certificate"
+	// AuthModeBoth writes an ocpasswd entry and issues a certificate.
+	AuthModeBoth = "both"
+)
+
+// ResolveAuthMode returns the first mode set, from the most specific, or
+// AuthModeBoth.
+func ResolveAuthMode(modes ...string) string {
+	for _, mode := range modes {
+		if mode != "" {
+			return mode
+		}
+	}
+	return AuthModeBoth
+}
+
+// AuthModeUsesPassword reports whether mode needs an ocpasswd entry.
+func AuthModeUsesPassword(mode string) bool {
+	return mode != AuthModeCertificate
+}
+
+// AuthModeUsesCertificate reports whether mode needs a client certificate.
+func AuthModeUsesCertificate(mode string) bool {
+	return mode != AuthModePassword
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type IPBan struct {
 	IP       string `json:"IP"`
 	Since    string `json:"Since"`
@@ -27,16 +29,25 @@ type OnlineUserSession struct {
 	VHost            string `json:"vhost" validate:"required"`
 	Device           string `json:"Device" validate:"required"`
 	SessionStartedAt string `json:"Session started at" validate:"required"`
+	// Node is the node of the session, set by the dashboard
+	Node string `json:"node"`
 }
 
 type ServerVersion struct {
 	OcservVersion string `json:"ocserv_version"`
 	OcctlVersion  string `json:"occtl_version"`
+	// Semantic is the parsed ocserv release (e.g. 1.2.4), empty when unknown
+	Semantic string `json:"semantic"`
 }
 
+// OcctlCapabilities maps capability names to whether the running ocserv
+// release supports them.
+type OcctlCapabilities map[string]bool
+
 type OcservInfo struct {
-	Version *ServerVersion `json:"version" validate:"required"`
-	Status  string         `json:"status" validate:"required"`
+	Version      *ServerVersion    `json:"version" validate:"required"`
+	Status       string            `json:"status" validate:"required"`
+	Capabilities OcctlCapabilities `json:"capabilities" validate:"required"`
 }
 
 type IPBanPoints struct {
@@ -54,3 +65,130 @@ type IRoute struct {
 	IP       string `json:"IP"`
 	IRoute   string `json:"iRoutes"`
 }
+
+const (
+	OcctlEventConnect    = "connect"
+	OcctlEventDisconnect = "disconnect"
+)
+
+type OcctlEvent struct {
+	Type      string    `json:"type" validate:"required" enums:"connect,disconnect"`
+	Node      string    `json:"node"` // node whose ocserv sent the event
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Group     string    `json:"group"`
+	VHost     string    `json:"vhost"`
+	Device    string    `json:"device"`
+	RemoteIP  string    `json:"remote_ip"`
+	IPv4      string    `json:"ipv4"`
+	UserAgent string    `json:"user_agent"`
+	RX        string    `json:"rx"`
+	TX        string    `json:"tx"`
+	Reason    string    `json:"reason,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// OcctlServerStatus is the output of `occtl -j show status`.
+type OcctlServerStatus struct {
+	Status              string `json:"Status"`
+	ServerPID           int    `json:"Server PID"`
+	SecModPID           int    `json:"Sec-mod PID"`
+	SecModInstanceCount int    `json:"Sec-mod instance count"`
+	SecModClientEntries int    `json:"Sec-mod client entries"`
+	UpSince             string `json:"Up since"`
+	UpSinceDuration     string `json:"_Up since"`
+	RawUpSince          int64  `json:"raw_up_since"`
+	Uptime              int64  `json:"uptime"`
+	ActiveSessions      int    `json:"Active sessions"`
+	TotalSessions       int    `json:"Total sessions"`
+	TotalAuthFailures   int    `json:"Total authentication failures"`
+	IPsInBanList        int    `json:"IPs in ban list"`
+	TLSDBEntries        int    `json:"TLS DB entries"`
+	MedianLatency       string `json:"Median latency"`
+	STDEVLatency        string `json:"STDEV latency"`
+	RawMedianLatency    int64  `json:"raw_median_latency"`
+	RawSTDEVLatency     int64  `json:"raw_stdev_latency"`
+
+	LastStatsReset           string `json:"Last stats reset"`
+	LastStatsResetDuration   string `json:"_Last stats reset"`
+	RawLastStatsReset        int64  `json:"raw_last_stats_reset"`
+	SessionsHandled          int    `json:"Sessions handled"`
+	TimedOutSessions         int    `json:"Timed out sessions"`
+	TimedOutIdleSessions     int    `json:"Timed out (idle) sessions"`
+	ClosedDueToErrorSessions int    `json:"Closed due to error sessions"`
+	AuthenticationFailures   int    `json:"Authentication failures"`
+	AverageAuthTime          string `json:"Average auth time"`
+	MaxAuthTime              string `json:"Max auth time"`
+	AverageSessionTime       string `json:"Average session time"`
+	MaxSessionTime           string `json:"Max session time"`
+	RX                       string `json:"RX"`
+	TX                       string `json:"TX"`
+	RawRX                    int64  `json:"raw_rx"`
+	RawTX                    int64  `json:"raw_tx"`
+	RawAvgAuthTime           int64  `json:"raw_avg_auth_time"`
+	RawMaxAuthTime           int64  `json:"raw_max_auth_time"`
+	RawAvgSessionTime        int64  `json:"raw_avg_session_time"`
+	RawMaxSessionTime        int64  `json:"raw_max_session_time"`
+}
+
+// OcctlSession is one entry of `occtl -j show sessions all|valid` and
+// `occtl -j show session <SID>`.
+type OcctlSession struct {
+	Session     string `json:"Session"`
+	FullSession string `json:"Full session"`
+	Created     string `json:"Created"`
+	RawCreated  int64  `json:"raw_created"`
+	State       string `json:"State"`
+	Username    string `json:"Username"`
+	Groupname   string `json:"Groupname"`
+	VHost       string `json:"vhost"`
+	UserAgent   string `json:"User-Agent"`
+	RemoteIP    string `json:"Remote IP"`
+	Location    string `json:"Location"`
+	InUse       int    `json:"In use"`
+}
+
+// OcctlUserDetail is the per-session detail printed by
+// `occtl -j show user <name>` and `occtl -j show id <id>`.
+type OcctlUserDetail struct {
+	ID                 int      `json:"ID"`
+	Username           string   `json:"Username"`
+	Groupname          string   `json:"Groupname"`
+	State              string   `json:"State"`
+	VHost              string   `json:"vhost"`
+	Device             string   `json:"Device"`
+	MTU                int      `json:"MTU"`
+	RemoteIP           string   `json:"Remote IP"`
+	Location           string   `json:"Location"`
+	LocalDeviceIP      string   `json:"Local Device IP"`
+	IPv4               string   `json:"IPv4"`
+	PtPIPv4            string   `json:"P-t-P IPv4"`
+	IPv6               string   `json:"IPv6"`
+	PtPIPv6            string   `json:"P-t-P IPv6"`
+	UserAgent          string   `json:"User-Agent"`
+	Hostname           string   `json:"Hostname"`
+	RX                 int64    `json:"RX"`
+	TX                 int64    `json:"TX"`
+	RXHuman            string   `json:"_RX"`
+	TXHuman            string   `json:"_TX"`
+	AverageRX          string   `json:"Average RX"`
+	AverageTX          string   `json:"Average TX"`
+	DPD                string   `json:"DPD"`
+	KeepAlive          string   `json:"KeepAlive"`
+	ConnectedAt        string   `json:"Connected at"`
+	ConnectedDuration  string   `json:"_Connected at"`
+	RawConnectedAt     int64    `json:"raw_connected_at"`
+	FullSession        string   `json:"Full session"`
+	Session            string   `json:"Session"`
+	TLSCiphersuite     string   `json:"TLS ciphersuite"`
+	DTLSCipher         string   `json:"DTLS cipher"`
+	CSTPCompression    string   `json:"CSTP compression"`
+	DTLSCompression    string   `json:"DTLS compression"`
+	DNS                []string `json:"DNS"`
+	NBNS               []string `json:"NBNS"`
+	SplitDNSDomains    []string `json:"Split-DNS-Domains"`
+	Routes             []string `json:"Routes"`
+	NoRoutes           []string `json:"No-routes"`
+	IRoutes            []string `json:"iRoutes"`
+	RestrictedToRoutes bool     `json:"Restricted to routes"`
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// occtlFields wraps one JSON object printed by `occtl -j`. Key names and
+// value types drift between ocserv releases (numbers printed as strings,
+// renamed keys, keys added or dropped), so lookups accept several aliases,
+// ignore case and convert between representations where possible.
+type occtlFields map[string]interface{}
+
+func newOcctlFields(data []byte) (occtlFields, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (f occtlFields) lookup(keys ...string) (interface{}, bool) {
+	for _, key := range keys {
+		if v, ok := f[key]; ok && v != nil {
+			return v, true
+		}
+	}
+	for _, key := range keys {
+		for k, v := range f {
+			if v != nil && strings.EqualFold(strings.TrimSpace(k), key) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (f occtlFields) str(keys ...string) string {
+	v, ok := f.lookup(keys...)
+	if !ok {
+		return ""
+	}
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return ""
+}
+
+func (f occtlFields) int64(keys ...string) int64 {
+	v, ok := f.lookup(keys...)
+	if !ok {
+		return 0
+	}
+	switch val := v.(type) {
+	case float64:
+		return int64(val)
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err == nil {
+			return int64(n)
+		}
+	}
+	return 0
+}
+
+func (f occtlFields) int(keys ...string) int {
+	return int(f.int64(keys...))
+}
+
+// isNumber reports whether the first matching key holds a plain number.
+func (f occtlFields) isNumber(keys ...string) bool {
+	v, ok := f.lookup(keys...)
+	if !ok {
+		return false
+	}
+	switch val := v.(type) {
+	case float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return err == nil
+	}
+	return false
+}
+
+func (f occtlFields) bool(keys ...string) bool {
+	v, ok := f.lookup(keys...)
+	if !ok {
+		return false
+	}
+	switch val := v.(type) {
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case string:
+		switch strings.ToLower(strings.TrimSpace(val)) {
+		case "true", "yes", "1":
+			return true
+		}
+	}
+	return false
+}
+
+// list accepts JSON arrays as well as comma separated strings.
+func (f occtlFields) list(keys ...string) []string {
+	v, ok := f.lookup(keys...)
+	if !ok {
+		return nil
+	}
+
+	var items []string
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				items = append(items, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, item := range strings.Split(val, ",") {
+			if s := strings.TrimSpace(item); s != "" {
+				items = append(items, s)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,148 @@
+package models
+
+// Version tolerant decoding of `occtl -j` output. Aliases cover keys that
+// were renamed between ocserv releases; fields missing from older releases
+// keep their zero value.
+
+func (s *OcctlServerStatus) UnmarshalJSON(data []byte) error {
+	f, err := newOcctlFields(data)
+	if err != nil {
+		return err
+	}
+	*s = f.serverStatus()
+	return nil
+}
+
+// NewOcctlServerStatus builds a status from an already decoded
+// `occtl -j show status` object.
+func NewOcctlServerStatus(raw map[string]interface{}) *OcctlServerStatus {
+	status := occtlFields(raw).serverStatus()
+	return &status
+}
+
+func (f occtlFields) serverStatus() OcctlServerStatus {
+	return OcctlServerStatus{
+		Status:              f.str("Status"),
+		ServerPID:           f.int("Server PID", "Main PID"),
+		SecModPID:           f.int("Sec-mod PID"),
+		SecModInstanceCount: f.int("Sec-mod instance count"),
+		SecModClientEntries: f.int("Sec-mod client entries"),
+		UpSince:             f.str("Up since"),
+		UpSinceDuration:     f.str("_Up since"),
+		RawUpSince:          f.int64("raw_up_since"),
+		Uptime:              f.int64("uptime"),
+		ActiveSessions:      f.int("Active sessions", "Active clients"),
+		TotalSessions:       f.int("Total sessions"),
+		TotalAuthFailures:   f.int("Total authentication failures"),
+		IPsInBanList:        f.int("IPs in ban list", "Banned IPs"),
+		TLSDBEntries:        f.int("TLS DB entries"),
+		MedianLatency:       f.str("Median latency"),
+		STDEVLatency:        f.str("STDEV latency"),
+		RawMedianLatency:    f.int64("raw_median_latency"),
+		RawSTDEVLatency:     f.int64("raw_stdev_latency"),
+
+		LastStatsReset:           f.str("Last stats reset"),
+		LastStatsResetDuration:   f.str("_Last stats reset"),
+		RawLastStatsReset:        f.int64("raw_last_stats_reset"),
+		SessionsHandled:          f.int("Sessions handled"),
+		TimedOutSessions:         f.int("Timed out sessions"),
+		TimedOutIdleSessions:     f.int("Timed out (idle) sessions"),
+		ClosedDueToErrorSessions: f.int("Closed due to error sessions"),
+		AuthenticationFailures:   f.int("Authentication failures"),
+		AverageAuthTime:          f.str("Average auth time"),
+		MaxAuthTime:              f.str("Max auth time"),
+		AverageSessionTime:       f.str("Average session time"),
+		MaxSessionTime:           f.str("Max session time"),
+		RX:                       f.str("RX"),
+		TX:                       f.str("TX"),
+		RawRX:                    f.int64("raw_rx"),
+		RawTX:                    f.int64("raw_tx"),
+		RawAvgAuthTime:           f.int64("raw_avg_auth_time"),
+		RawMaxAuthTime:           f.int64("raw_max_auth_time"),
+		RawAvgSessionTime:        f.int64("raw_avg_session_time"),
+		RawMaxSessionTime:        f.int64("raw_max_session_time"),
+	}
+}
+
+func (s *OcctlSession) UnmarshalJSON(data []byte) error {
+	f, err := newOcctlFields(data)
+	if err != nil {
+		return err
+	}
+	*s = OcctlSession{
+		Session:     f.str("Session"),
+		FullSession: f.str("Full session"),
+		Created:     f.str("Created"),
+		RawCreated:  f.int64("raw_created"),
+		State:       f.str("State"),
+		Username:    f.str("Username"),
+		Groupname:   f.str("Groupname", "Group"),
+		VHost:       f.str("vhost"),
+		UserAgent:   f.str("User-Agent", "UserAgent"),
+		RemoteIP:    f.str("Remote IP", "IP"),
+		Location:    f.str("Location"),
+		InUse:       f.int("In use"),
+	}
+	return nil
+}
+
+func (u *OcctlUserDetail) UnmarshalJSON(data []byte) error {
+	f, err := newOcctlFields(data)
+	if err != nil {
+		return err
+	}
+
+	*u = OcctlUserDetail{
+		ID:                 f.int("ID"),
+		Username:           f.str("Username"),
+		Groupname:          f.str("Groupname", "Group"),
+		State:              f.str("State"),
+		VHost:              f.str("vhost"),
+		Device:             f.str("Device"),
+		MTU:                f.int("MTU"),
+		RemoteIP:           f.str("Remote IP", "IP"),
+		Location:           f.str("Location"),
+		LocalDeviceIP:      f.str("Local Device IP"),
+		IPv4:               f.str("IPv4"),
+		PtPIPv4:            f.str("P-t-P IPv4"),
+		IPv6:               f.str("IPv6"),
+		PtPIPv6:            f.str("P-t-P IPv6"),
+		UserAgent:          f.str("User-Agent", "UserAgent"),
+		Hostname:           f.str("Hostname"),
+		RXHuman:            f.str("_RX"),
+		TXHuman:            f.str("_TX"),
+		AverageRX:          f.str("Average RX"),
+		AverageTX:          f.str("Average TX"),
+		DPD:                f.str("DPD"),
+		KeepAlive:          f.str("KeepAlive"),
+		ConnectedAt:        f.str("Connected at"),
+		ConnectedDuration:  f.str("_Connected at"),
+		RawConnectedAt:     f.int64("raw_connected_at"),
+		FullSession:        f.str("Full session"),
+		Session:            f.str("Session"),
+		TLSCiphersuite:     f.str("TLS ciphersuite"),
+		DTLSCipher:         f.str("DTLS cipher", "DTLS ciphersuite"),
+		CSTPCompression:    f.str("CSTP compression"),
+		DTLSCompression:    f.str("DTLS compression"),
+		DNS:                f.list("DNS"),
+		NBNS:               f.list("NBNS"),
+		SplitDNSDomains:    f.list("Split-DNS-Domains"),
+		Routes:             f.list("Routes"),
+		NoRoutes:           f.list("No-routes"),
+		IRoutes:            f.list("iRoutes"),
+		RestrictedToRoutes: f.bool("Restricted to routes"),
+	}
+
+	// Older releases print only the human readable counters under RX/TX.
+	if f.isNumber("RX") {
+		u.RX = f.int64("RX")
+	} else if u.RXHuman == "" {
+		u.RXHuman = f.str("RX")
+	}
+	if f.isNumber("TX") {
+		u.TX = f.int64("TX")
+	} else if u.TXHuman == "" {
+		u.TXHuman = f.str("TX")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	OcservActionDisconnect = "disconnect"
+	OcservActionLock       = "lock"
+	OcservActionUnlock     = "unlock"
+)
+
+// OcservAction is an ocserv side effect waiting to be applied in the ocserv
+// container. Rows are removed once delivered; FailedAt is set when the
+// action ran out of attempts.
+type OcservAction struct {
+	ID            uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Action        string     `json:"action" gorm:"type:varchar(16);not null" enums:"disconnect,lock,unlock"`
+	Username      string     `json:"username" gorm:"type:varchar(255);not null;index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error" gorm:"type:text;default:''"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"type:timestamptz;not null;index"`
+	ClaimedUntil  *time.Time `json:"-" gorm:"type:timestamptz"`
+	FailedAt      *time.Time `json:"failed_at" gorm:"type:timestamptz"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	AuthAlertKindUser = "user"
+	AuthAlertKindIP   = "ip"
+
+	AuthAlertActionLock = "lock"
+	AuthAlertActionBan  = "ban"
+)
+
+// OcservAuthAlert records a burst of failed ocserv logins for one username
+// or one source address, and the actions taken against it. Alerts with
+// Notify set are forwarded to the Telegram admin chat.
+type OcservAuthAlert struct {
+	ID            uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind          string         `json:"kind" gorm:"type:varchar(8);not null" enums:"user,ip" validate:"required"`
+	Target        string         `json:"target" gorm:"type:varchar(255);not null;index" validate:"required"`
+	Failures      int            `json:"failures" gorm:"not null" validate:"required"`
+	WindowSeconds int            `json:"window_seconds" gorm:"not null" validate:"required"`
+	Actions       *CSVStringList `json:"actions" gorm:"type:text" validate:"required"`
+	Notify        bool           `json:"-" gorm:"not null;default:false"`
+	NotifiedAt    *time.Time     `json:"notified_at" gorm:"type:timestamptz" validate:"omitempty"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime" validate:"required"`
+}
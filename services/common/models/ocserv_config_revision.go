@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	OcservConfigRevisionGroup    = "group"
+	OcservConfigRevisionDefaults = "defaults"
+	OcservConfigRevisionUser     = "user"
+
+	OcservConfigRevisionUpdate  = "update"
+	OcservConfigRevisionRestore = "restore"
+)
+
+// OcservConfigSnapshot is a group or user config as its JSON object.
+type OcservConfigSnapshot map[string]interface{}
+
+// OcservConfigRevision is a version of the config of a group, the defaults
+// group or a user. Changes is the diff against the previous revision of the
+// same target.
+type OcservConfigRevision struct {
+	ID           uint                 `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind         string               `json:"kind" gorm:"type:varchar(16);not null" enums:"group,defaults,user"`
+	Target       string               `json:"target" gorm:"type:varchar(255);not null"`
+	Config       OcservConfigSnapshot `json:"config" gorm:"type:json"`
+	Changes      OcservConfigChanges  `json:"changes" gorm:"type:json"`
+	Action       string               `json:"action" gorm:"type:varchar(16);not null" enums:"update,restore"`
+	RestoredFrom *uint                `json:"restored_from"`
+	Actor        string               `json:"actor" gorm:"type:varchar(32);not null"`
+	CreatedAt    time.Time            `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (c OcservConfigSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *OcservConfigSnapshot) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+
+	case []byte:
+		return json.Unmarshal(v, c)
+
+	case string:
+		return json.Unmarshal([]byte(v), c)
+
+	default:
+		return fmt.Errorf("unsupported type for OcservConfigSnapshot: %T", value)
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OcservConfigTemplate is a reusable group config. The templates of a group
+// are merged into its config file in order, a later template overrides an
+// earlier one and the group config overrides them all.
+type OcservConfigTemplate struct {
+	ID          uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string             `json:"name" gorm:"type:varchar(255);not null;uniqueIndex" validate:"required"`
+	Description string             `json:"description" gorm:"type:text"`
+	Owner       string             `json:"owner" gorm:"type:varchar(32);default:''" validate:"required"`
+	Config      *OcservGroupConfig `json:"config" gorm:"type:json"`
+	CreatedAt   time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// OcservGroupTemplates are the names of the templates of a group, lowest
+// precedence first.
+type OcservGroupTemplates []string
+
+func (t OcservGroupTemplates) Value() (driver.Value, error) {
+	if t == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *OcservGroupTemplates) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+
+	case []byte:
+		return json.Unmarshal(v, t)
+
+	case string:
+		return json.Unmarshal([]byte(v), t)
+
+	default:
+		return fmt.Errorf("unsupported type for OcservGroupTemplates: %T", value)
+	}
+}
@@ -8,13 +8,13 @@ import (
 
 type OcservGroupConfig struct {
 	// Comma-separated list of DNS servers to assign to the client. Example: '8.8.8.8,1.1.1.1'
-	DNS *CSVStringList `json:"dns" gorm:"type:text"`
+	DNS *CSVStringList `json:"dns" gorm:"type:text" validate:"omitempty,dive,ip"`
 
 	// NetBIOS Name Servers (WINS) for Windows clients. Example: '192.168.1.1'
-	NBNS *string `json:"nbns"`
+	NBNS *string `json:"nbns" validate:"omitempty,ip"`
 
 	// The pool of addresses that leases will be given from. Example: '192.168.1.0/24'
-	IPv4Network *string `json:"ipv4-network"`
+	IPv4Network *string `json:"ipv4-network" validate:"omitempty,ocserv_network"`
 
 	// Maximum receive bandwidth in bytes per second. Example: '100000' for 100 KB/s
 	RxDataPerSec *int `json:"rx-data-per-sec"`
@@ -23,19 +23,19 @@ type OcservGroupConfig struct {
 	TxDataPerSec *int `json:"tx-data-per-sec"`
 
 	// Static IPv4 address to assign to client. Example: '192.168.100.10'
-	ExplicitIPv4 *string `json:"explicit-ipv4"`
+	ExplicitIPv4 *string `json:"explicit-ipv4" validate:"omitempty,ipv4"`
 
 	// Linux control group to assign the VPN worker process to. Format: 'controller,subsystem:name'. Example: 'cpuset,cpu:test'
-	CGroup *string `json:"cgroup"`
+	CGroup *string `json:"cgroup" validate:"omitempty,ocserv_cgroup"`
 
 	// Internal route available only via VPN. Format: 'IP/prefix'. Example: '10.0.0.0/8'
-	IRoute *string `json:"iroute"`
+	IRoute *string `json:"iroute" validate:"omitempty,ocserv_route"`
 
 	// Routes pushed to the client for routing traffic. Example: ['0.0.0.0/0', '10.10.0.0/16']
-	Route *CSVStringList `json:"route" gorm:"type:text"`
+	Route *CSVStringList `json:"route" gorm:"type:text" validate:"omitempty,dive,ocserv_route"`
 
 	// List of networks to exclude from VPN routing. Each entry should be in 'IP/prefix' format. Example: ['192.168.0.0/16', '10.0.0.0/8']
-	NoRoute *CSVStringList `json:"no-route" gorm:"type:text"`
+	NoRoute *CSVStringList `json:"no-route" gorm:"type:text" validate:"omitempty,dive,ocserv_route"`
 
 	// Priority for routes; lower is higher priority. Example: 1
 	NetPriority *int `json:"net-priority"`
@@ -77,20 +77,23 @@ type OcservGroupConfig struct {
 	RestrictUserToRoutes *bool `json:"restrict-user-to-routes"`
 
 	// Comma-separated list of allowed (or blocked, if negated) protocols and ports. Supports 'tcp(port)', 'udp(port)', 'icmp()', 'icmpv6()', and negation with '!()'. Example: 'tcp(443), tcp(80), udp(53)', or '!(tcp(22), udp(1194))'
-	RestrictUserToPorts *string `json:"restrict-user-to-ports"`
+	RestrictUserToPorts *string `json:"restrict-user-to-ports" validate:"omitempty,ocserv_ports"`
 
 	// List of domains over which the provided DNS servers should be used. Example: ['example.com', 'internal.company.com']
-	SplitDNS *CSVStringList `json:"split-dns" gorm:"type:text"`
+	SplitDNS *CSVStringList `json:"split-dns" gorm:"type:text" validate:"omitempty,dive,hostname_rfc1123"`
 
 	// Max session time in seconds before forced disconnect. Example: 3600
 	SessionTimeout *int `json:"session-timeout"`
 }
 
 type OcservGroup struct {
-	ID     uint               `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name   string             `json:"name" gorm:"type:varchar(255);not null;uniqueIndex" validate:"required"`
-	Owner  string             `json:"owner" gorm:"type:varchar(32);default:''" validate:"required"`
-	Config *OcservGroupConfig `json:"config" gorm:"type:json"`
+	ID        uint                 `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string               `json:"name" gorm:"type:varchar(255);not null;uniqueIndex" validate:"required"`
+	Owner     string               `json:"owner" gorm:"type:varchar(32);default:''" validate:"required"`
+	AuthMode  string               `json:"auth_mode" gorm:"type:varchar(16);default:''" enums:"password,certificate,both" validate:"omitempty"` // empty means both
+	VHost     string               `json:"vhost" gorm:"column:vhost;type:varchar(64);not null;default:'default';index" validate:"required"`
+	Templates OcservGroupTemplates `json:"templates" gorm:"type:json"`
+	Config    *OcservGroupConfig   `json:"config" gorm:"type:json"`
 }
 
 func (c *OcservGroupConfig) Value() (driver.Value, error) {
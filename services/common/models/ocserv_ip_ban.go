@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	IPBanSourceManual = "manual"
+	IPBanSourceAuto   = "auto"
+)
+
+// OcservIPBan is a ban of an address or prefix on the firewall of Node. Rows
+// are kept after the unban as ban history; a ban is active while UnbannedAt
+// is nil and ExpiresAt has not passed.
+type OcservIPBan struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	IP          string     `json:"ip" gorm:"type:varchar(64);not null;index" validate:"required"`
+	Reason      string     `json:"reason" gorm:"type:text;default:''"`
+	Source      string     `json:"source" gorm:"type:varchar(16);not null;default:'manual'" enums:"manual,auto" validate:"required"`
+	Owner       string     `json:"owner" gorm:"type:varchar(255);default:''"`
+	Node        string     `json:"node" gorm:"type:varchar(64);not null;default:'local';index" validate:"required"`
+	ExpiresAt   *time.Time `json:"expires_at" gorm:"type:timestamptz" validate:"omitempty"`
+	UnbannedAt  *time.Time `json:"unbanned_at" gorm:"type:timestamptz;index" validate:"omitempty"`
+	UnbannedBy  string     `json:"unbanned_by" gorm:"type:varchar(255);default:''"`
+	UnbanReason string     `json:"unban_reason" gorm:"type:text;default:''"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime" validate:"required"`
+}
+
+// Active reports whether the ban is in force at now.
+func (b *OcservIPBan) Active(now time.Time) bool {
+	return b.UnbannedAt == nil && (b.ExpiresAt == nil || b.ExpiresAt.After(now))
+}
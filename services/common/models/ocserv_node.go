@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// LocalNode is the ocserv the dashboard services reach without the
+// registry: the local occtl, or the webhook at WEBHOOK_URL in remote mode.
+const LocalNode = "local"
+
+// OcservNode is a VPN server managed from this dashboard. Every node runs
+// the agent, the webhook service, at URL and signs with Secret. Users are
+// provisioned on one node.
+type OcservNode struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string     `json:"name" gorm:"type:varchar(64);not null;uniqueIndex" validate:"required"`
+	Location    string     `json:"location" gorm:"type:varchar(128);default:''"`
+	Description string     `json:"description" gorm:"type:text"`
+	URL         string     `json:"url" gorm:"type:varchar(255);not null" validate:"required"`
+	Secret      string     `json:"-" gorm:"type:varchar(255);not null"`
+	Enabled     bool       `json:"enabled" gorm:"not null;default:true"`
+	Version     string     `json:"version" gorm:"type:varchar(64);default:''"`
+	LastSeenAt  *time.Time `json:"last_seen_at" gorm:"type:timestamptz"`
+	LastError   string     `json:"last_error" gorm:"type:text;default:''"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// NodeName returns node, or LocalNode for users created before the
+// registry.
+func NodeName(node string) string {
+	if node == "" {
+		return LocalNode
+	}
+	return node
+}
+
+// AgentInfo describes the node an agent runs on.
+type AgentInfo struct {
+	Node         string             `json:"node"`
+	Hostname     string             `json:"hostname"`
+	Version      *ServerVersion     `json:"version"`
+	Capabilities OcctlCapabilities  `json:"capabilities"`
+	Status       *OcctlServerStatus `json:"status"`
+	Time         time.Time          `json:"time"`
+}
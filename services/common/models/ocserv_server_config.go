@@ -0,0 +1,242 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	OcservServerConfigReload          = "reload"
+	OcservServerConfigRestart         = "restart"
+	OcservServerConfigRestartRequired = "restart_required"
+)
+
+// OcservServerConfig is the typed part of the main ocserv.conf. Directives
+// it does not cover are kept as they are in the file.
+type OcservServerConfig struct {
+	// Authentication methods, the first one is the primary. Example: ['certificate']
+	Auth []string `json:"auth" validate:"omitempty,dive,required"`
+
+	// Additional authentication methods a client may use instead. Example: ['plain[passwd=/etc/ocserv/ocpasswd]']
+	EnableAuth []string `json:"enable-auth" validate:"omitempty,dive,required"`
+
+	// Address to listen on, all addresses when empty. Example: '0.0.0.0'
+	ListenHost *string `json:"listen-host"`
+
+	// TCP port to listen on. Example: 443
+	TCPPort *int `json:"tcp-port" validate:"omitempty,min=1,max=65535"`
+
+	// UDP port to listen on for DTLS. Example: 443
+	UDPPort *int `json:"udp-port" validate:"omitempty,min=1,max=65535"`
+
+	// Expect the PROXY protocol header from a load balancer. Example: false
+	ListenProxyProto *bool `json:"listen-proxy-proto"`
+
+	// Path of the server certificate. Example: '/etc/ocserv/certs/cert.pem'
+	ServerCert *string `json:"server-cert"`
+
+	// Path of the server certificate key. Example: '/etc/ocserv/certs/cert.key'
+	ServerKey *string `json:"server-key"`
+
+	// Path of the CA bundle client certificates are checked against. Example: '/etc/ocserv/ssl/ca-cert.pem'
+	CACert *string `json:"ca-cert"`
+
+	// Path of the certificate revocation list. Example: '/etc/ocserv/ssl/crl.pem'
+	CRL *string `json:"crl"`
+
+	// Certificate field holding the username. Example: '2.5.4.3'
+	CertUserOID *string `json:"cert-user-oid"`
+
+	// GnuTLS priority string. Example: 'NORMAL:%SERVER_PRECEDENCE:%COMPAT:-VERS-SSL3.0'
+	TLSPriorities *string `json:"tls-priorities"`
+
+	// Run each worker in a restricted sandbox. Example: true
+	IsolateWorkers *bool `json:"isolate-workers"`
+
+	// User the workers run as. Example: 'nobody'
+	RunAsUser *string `json:"run-as-user"`
+
+	// Group the workers run as. Example: 'daemon'
+	RunAsGroup *string `json:"run-as-group"`
+
+	// Path of the socket between the main process and the workers. Example: '/var/run/ocserv-socket'
+	SocketFile *string `json:"socket-file"`
+
+	// Allow occtl to control the server. Example: true
+	UseOcctl *bool `json:"use-occtl"`
+
+	// Path of the server pid file. Example: '/var/run/ocserv.pid'
+	PIDFile *string `json:"pid-file"`
+
+	// Name prefix of the tun devices. Example: 'vpns'
+	Device *string `json:"device"`
+
+	// Maximum number of connected clients, 0 for no limit. Example: 1024
+	MaxClients *int `json:"max-clients" validate:"omitempty,min=0"`
+
+	// Maximum simultaneous logins per user, 0 for no limit. Example: 2
+	MaxSameClients *int `json:"max-same-clients" validate:"omitempty,min=0"`
+
+	// Minimum time in milliseconds between two connections from the same IP. Example: 100
+	RateLimitMs *int `json:"rate-limit-ms" validate:"omitempty,min=0"`
+
+	// Interval in seconds to send keep-alive pings. Example: 32400
+	KeepAlive *int `json:"keepalive" validate:"omitempty,min=0"`
+
+	// Dead Peer Detection timeout in seconds. Example: 90
+	DPD *int `json:"dpd" validate:"omitempty,min=0"`
+
+	// DPD timeout for mobile clients. Example: 1800
+	MobileDPD *int `json:"mobile-dpd" validate:"omitempty,min=0"`
+
+	// Seconds without UDP traffic before switching to TCP. Example: 5
+	SwitchToTCPTimeout *int `json:"switch-to-tcp-timeout" validate:"omitempty,min=0"`
+
+	// Discover the path MTU of each client. Example: true
+	TryMTUDiscovery *bool `json:"try-mtu-discovery"`
+
+	// Seconds a client has to authenticate. Example: 40
+	AuthTimeout *int `json:"auth-timeout" validate:"omitempty,min=0"`
+
+	// Time in seconds before disconnecting idle clients. Example: 1200
+	IdleTimeout *int `json:"idle-timeout" validate:"omitempty,min=0"`
+
+	// Idle timeout for mobile clients. Example: 2400
+	MobileIdleTimeout *int `json:"mobile-idle-timeout" validate:"omitempty,min=0"`
+
+	// Seconds a client must wait before reauthenticating after a failure. Example: 300
+	MinReauthTime *int `json:"min-reauth-time" validate:"omitempty,min=0"`
+
+	// Ban score at which a client IP is banned, 0 disables banning. Example: 50
+	MaxBanScore *int `json:"max-ban-score" validate:"omitempty,min=0"`
+
+	// Seconds after which the ban score of an IP is reset. Example: 300
+	BanResetTime *int `json:"ban-reset-time" validate:"omitempty,min=0"`
+
+	// Lifetime in seconds of the session cookie. Example: 86400
+	CookieTimeout *int `json:"cookie-timeout" validate:"omitempty,min=0"`
+
+	// Disconnect client if its IP changes. Example: false
+	DenyRoaming *bool `json:"deny-roaming"`
+
+	// Seconds between TLS rekeys. Example: 172800
+	RekeyTime *int `json:"rekey-time" validate:"omitempty,min=0"`
+
+	// Rekey method. Example: 'ssl'
+	RekeyMethod *string `json:"rekey-method" validate:"omitempty,oneof=ssl new-tunnel"`
+
+	// The pool of addresses that leases will be given from. Example: '192.168.1.0/24'
+	IPv4Network *string `json:"ipv4-network"`
+
+	// The pool of IPv6 addresses. Example: 'fda9:4efe:7e3b:03ea::/48'
+	IPv6Network *string `json:"ipv6-network"`
+
+	// Prefix of the IPv6 subnet given to each client. Example: 64
+	IPv6SubnetPrefix *int `json:"ipv6-subnet-prefix" validate:"omitempty,min=1,max=128"`
+
+	// Give each user the same IP on every connection. Example: true
+	PredictableIPs *bool `json:"predictable-ips"`
+
+	// Ping an address before leasing it. Example: false
+	PingLeases *bool `json:"ping-leases"`
+
+	// Domain pushed to the clients. Example: 'example.com'
+	DefaultDomain *string `json:"default-domain"`
+
+	// DNS servers pushed to the clients. Example: ['8.8.8.8', '1.1.1.1']
+	DNS []string `json:"dns" validate:"omitempty,dive,required"`
+
+	// Force all DNS traffic through the VPN tunnel. Example: true
+	TunnelAllDNS *bool `json:"tunnel-all-dns"`
+
+	// Domains over which the provided DNS servers should be used. Example: ['internal.company.com']
+	SplitDNS []string `json:"split-dns" validate:"omitempty,dive,required"`
+
+	// Routes pushed to the clients, 'default' routes everything. Example: ['default']
+	Route []string `json:"route" validate:"omitempty,dive,required"`
+
+	// Networks excluded from the VPN routing. Example: ['192.168.0.0/16']
+	NoRoute []string `json:"no-route" validate:"omitempty,dive,required"`
+
+	// Tunnel interface MTU. Example: 1420
+	MTU *int `json:"mtu" validate:"omitempty,min=576,max=65535"`
+
+	// Compatibility with the Cisco AnyConnect clients. Example: true
+	CiscoClientCompat *bool `json:"cisco-client-compat"`
+
+	// Support the legacy DTLS negotiation of old clients. Example: true
+	DTLSLegacy *bool `json:"dtls-legacy"`
+
+	// Directory of the per group config files. Example: '/etc/ocserv/groups/'
+	ConfigPerGroup *string `json:"config-per-group"`
+
+	// Directory of the per user config files. Example: '/etc/ocserv/users/'
+	ConfigPerUser *string `json:"config-per-user"`
+
+	// Config file applied to users without a group file. Example: '/etc/ocserv/defaults/group.conf'
+	DefaultGroupConfig *string `json:"default-group-config"`
+
+	// Log verbosity, from 0 to 9. Example: 3
+	LogLevel *int `json:"log-level" validate:"omitempty,min=0,max=9"`
+
+	// Banner shown to the clients after login. Example: 'Welcome'
+	Banner *string `json:"banner"`
+
+	// Banner shown to the clients before login. Example: 'Authorized users only'
+	PreLoginBanner *string `json:"pre-login-banner"`
+
+	// Hide the server behind a fake web server unless the secret is in the URL. Example: true
+	Camouflage *bool `json:"camouflage"`
+
+	// Secret clients add to the URL when camouflage is on. Example: 'mysecretkey'
+	CamouflageSecret *string `json:"camouflage_secret"`
+
+	// Realm of the camouflage authentication prompt. Example: 'Restricted Content'
+	CamouflageRealm *string `json:"camouflage_realm"`
+}
+
+// OcservConfigChange is a directive changed between two versions of a
+// config file.
+type OcservConfigChange struct {
+	Key             string   `json:"key" validate:"required"`
+	Old             []string `json:"old" validate:"omitempty"`
+	New             []string `json:"new" validate:"omitempty"`
+	RestartRequired bool     `json:"restart_required" validate:"required"`
+}
+
+type OcservConfigChanges []OcservConfigChange
+
+// OcservServerConfigRevision is a version of ocserv.conf applied from the
+// dashboard. Action tells how it was applied.
+type OcservServerConfigRevision struct {
+	ID        uint                `json:"id" gorm:"primaryKey;autoIncrement"`
+	Content   string              `json:"content" gorm:"type:text;not null"`
+	Changes   OcservConfigChanges `json:"changes" gorm:"type:json"`
+	Action    string              `json:"action" gorm:"type:varchar(16);not null" enums:"reload,restart,restart_required"`
+	Actor     string              `json:"actor" gorm:"type:varchar(32);not null"`
+	CreatedAt time.Time           `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (c OcservConfigChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *OcservConfigChanges) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+
+	case []byte:
+		return json.Unmarshal(v, c)
+
+	case string:
+		return json.Unmarshal([]byte(v), c)
+
+	default:
+		return fmt.Errorf("unsupported type for OcservConfigChanges: %T", value)
+	}
+}
@@ -12,28 +12,28 @@ import (
 
 type OcservUserConfig struct {
 	// Static IPv4 address to assign to the user. Example: '192.168.100.10'
-	ExplicitIPv4 *string `json:"explicit-ipv4"`
+	ExplicitIPv4 *string `json:"explicit-ipv4" validate:"omitempty,ipv4"`
 
 	// The pool of addresses from which to assign to the user. Example: '192.168.1.0/24'
-	IPv4Network *string `json:"ipv4-network"`
+	IPv4Network *string `json:"ipv4-network" validate:"omitempty,ocserv_network"`
 
 	// Comma-separated list of DNS servers to assign to the user. Example: '8.8.8.8,1.1.1.1'
-	DNS *CSVStringList `json:"dns" gorm:"type:text"`
+	DNS *CSVStringList `json:"dns" gorm:"type:text" validate:"omitempty,dive,ip"`
 
 	// NetBIOS Name Servers (WINS) for Windows clients. Example: '192.168.1.1'
-	NBNS *string `json:"nbns"`
+	NBNS *string `json:"nbns" validate:"omitempty,ip"`
 
 	// Routes pushed to the user for routing traffic. Example: ['0.0.0.0/0', '10.10.0.0/16']
-	Route *CSVStringList `json:"route" gorm:"type:text"`
+	Route *CSVStringList `json:"route" gorm:"type:text" validate:"omitempty,dive,ocserv_route"`
 
 	// List of networks to exclude from VPN routing. Example: ['192.168.0.0/16', '10.0.0.0/8']
-	NoRoute *CSVStringList `json:"no-route" gorm:"type:text"`
+	NoRoute *CSVStringList `json:"no-route" gorm:"type:text" validate:"omitempty,dive,ocserv_route"`
 
 	// Internal route available only via VPN. Example: '10.0.0.0/8'
-	IRoute *string `json:"iroute"`
+	IRoute *string `json:"iroute" validate:"omitempty,ocserv_route"`
 
 	// List of domains over which the provided DNS servers should be used. Example: ['example.com', 'internal.company.com']
-	SplitDNS *CSVStringList `json:"split-dns" gorm:"type:text"`
+	SplitDNS *CSVStringList `json:"split-dns" gorm:"type:text" validate:"omitempty,dive,hostname_rfc1123"`
 
 	// Maximum session time in seconds before forced disconnect. Example: 3600
 	SessionTimeout *int `json:"session-timeout"`
@@ -51,7 +51,7 @@ type OcservUserConfig struct {
 	RestrictToRoutes *bool `json:"restrict-to-routes"`
 
 	// Comma-separated list of allowed or blocked ports/protocols. Supports 'tcp(port)', 'udp(port)', 'icmp()', 'icmpv6()', and negation with '!()'. Example: 'tcp(443), udp(53)' or '!(tcp(22), udp(1194))'
-	RestrictToPorts *string `json:"restrict-to-ports"`
+	RestrictToPorts *string `json:"restrict-to-ports" validate:"omitempty,ocserv_ports"`
 }
 
 type OcservUserCertificateBackup struct {
@@ -66,6 +66,9 @@ type OcservUser struct {
 	UID                  string                       `json:"uid" gorm:"gorm:type:char(26);not null;uniqueIndex" validate:"required"`
 	Owner                string                       `json:"owner" gorm:"type:varchar(16);default:''" validate:"required"`
 	Group                string                       `json:"group" gorm:"type:varchar(16);default:'defaults'" validate:"required"`
+	VHost                string                       `json:"vhost" gorm:"column:vhost;type:varchar(64);not null;default:'default';index" validate:"required"`
+	Node                 string                       `json:"node" gorm:"type:varchar(64);not null;default:'local';index" validate:"required"`
+	AuthMode             string                       `json:"auth_mode" gorm:"type:varchar(16);default:''" enums:"password,certificate,both" validate:"omitempty"` // empty inherits the group mode
 	Username             string                       `json:"username" gorm:"type:varchar(255);not null;uniqueIndex" validate:"required"`
 	Password             string                       `json:"password" gorm:"type:varchar(255);not null" validate:"required"`
 	IsLocked             bool                         `json:"is_locked" gorm:"default(false)" validate:"required"`
@@ -74,6 +77,7 @@ type OcservUser struct {
 	ExpireAt             *time.Time                   `json:"expire_at" gorm:"type:date" validate:"omitempty"`
 	DeactivatedAt        *time.Time                   `json:"deactivated_at" gorm:"type:date" validate:"omitempty"`
 	UsageResetAt         *time.Time                   `json:"-" gorm:"type:timestamptz" validate:"omitempty"`
+	AuthLockedUntil      *time.Time                   `json:"auth_locked_until" gorm:"type:timestamptz" validate:"omitempty"` // temporary lock after failed logins
 	TrafficType          string                       `json:"traffic_type" gorm:"type:varchar(32);not null;default:1" enums:"Free,MonthlyTransmit,MonthlyReceive,MonthlyRxTx,TotallyTransmit,TotallyReceive,TotallyRxTx" validate:"required"`
 	TrafficSize          int64                        `json:"traffic_size" gorm:"not null" validate:"required"` // in bytes
 	Rx                   int                          `json:"rx" gorm:"not null;default:0" validate:"required"` // Receive in bytes
@@ -84,6 +88,7 @@ type OcservUser struct {
 	Config               *OcservUserConfig            `json:"config" gorm:"type:text"`
 	CertificateEnabled   bool                         `json:"certificate_enabled" gorm:"-"`
 	CertificateAvailable bool                         `json:"certificate_available" gorm:"-"`
+	CertificateExpiresAt *time.Time                   `json:"certificate_expires_at" gorm:"type:timestamptz" validate:"omitempty"`
 	Certificate          *OcservUserCertificateBackup `json:"certificate,omitempty" gorm:"-"`
 }
 
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultVHost is the virtual host of the global section of ocserv.conf,
+// the one clients get when no other virtual host matches.
+const DefaultVHost = "default"
+
+// OcservVHostConfig is the typed part of a [vhost:name] section of
+// ocserv.conf. Anything not set is inherited from the global section.
+type OcservVHostConfig struct {
+	// Authentication methods, the first one is the primary. Password logins
+	// must use the ocpasswd file of the dashboard. Example: ['plain[passwd=/etc/ocserv/ocpasswd]', 'certificate']
+	Auth []string `json:"auth" validate:"omitempty,dive,required"`
+
+	// Additional authentication methods a client may use instead. Example: ['certificate']
+	EnableAuth []string `json:"enable-auth" validate:"omitempty,dive,required"`
+
+	// Path of the server certificate of the virtual host. Example: '/etc/ocserv/certs/corp.pem'
+	ServerCert *string `json:"server-cert"`
+
+	// Path of the server certificate key. Example: '/etc/ocserv/certs/corp.key'
+	ServerKey *string `json:"server-key"`
+
+	// Path of the CA bundle client certificates are checked against. Example: '/etc/ocserv/ssl/corp-ca.pem'
+	CACert *string `json:"ca-cert"`
+
+	// Certificate field holding the username. Example: '2.5.4.3'
+	CertUserOID *string `json:"cert-user-oid"`
+
+	// The pool of addresses leases are given from. Example: '10.1.0.0/24'
+	IPv4Network *string `json:"ipv4-network" validate:"omitempty,ocserv_network"`
+
+	// DNS servers pushed to the clients. Example: ['10.1.0.53']
+	DNS []string `json:"dns" validate:"omitempty,dive,ip"`
+
+	// Routes pushed to the clients. Example: ['10.1.0.0/16']
+	Route []string `json:"route" validate:"omitempty,dive,ocserv_route"`
+
+	// Networks excluded from the VPN routes. Example: ['192.168.0.0/16']
+	NoRoute []string `json:"no-route" validate:"omitempty,dive,ocserv_route"`
+
+	// Domains resolved with the pushed DNS servers. Example: ['corp.example.com']
+	SplitDNS []string `json:"split-dns" validate:"omitempty,dive,hostname_rfc1123"`
+
+	// Force all DNS traffic through the VPN tunnel. Example: true
+	TunnelAllDNS *bool `json:"tunnel-all-dns"`
+
+	// Domain suffix pushed to the clients. Example: 'corp.example.com'
+	DefaultDomain *string `json:"default-domain"`
+
+	// Message shown to the clients after login. Example: 'Welcome to corp'
+	Banner *string `json:"banner"`
+
+	// Maximum number of connected clients, 0 for no limit. Example: 256
+	MaxClients *int `json:"max-clients" validate:"omitempty,min=0"`
+
+	// Maximum simultaneous logins per user, 0 for no limit. Example: 2
+	MaxSameClients *int `json:"max-same-clients" validate:"omitempty,min=0"`
+
+	// Time in seconds before disconnecting idle clients. Example: 600
+	IdleTimeout *int `json:"idle-timeout" validate:"omitempty,min=0"`
+
+	// Idle timeout in seconds for mobile clients. Example: 900
+	MobileIdleTimeout *int `json:"mobile-idle-timeout" validate:"omitempty,min=0"`
+
+	// Max session time in seconds before forced disconnect. Example: 3600
+	SessionTimeout *int `json:"session-timeout" validate:"omitempty,min=0"`
+}
+
+// OcservVHost is a virtual host of ocserv, written to ocserv.conf as a
+// [vhost:name] section. Groups and users are assigned to one virtual host
+// for filtering and reports only: every virtual host checks passwords
+// against the same ocpasswd file and reads the same per-user and per-group
+// config files, so a user can connect to any of them.
+type OcservVHost struct {
+	ID          uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string             `json:"name" gorm:"type:varchar(64);not null;uniqueIndex" validate:"required"`
+	Description string             `json:"description" gorm:"type:text"`
+	Owner       string             `json:"owner" gorm:"type:varchar(32);default:''" validate:"required"`
+	Config      *OcservVHostConfig `json:"config" gorm:"type:json"`
+	CreatedAt   time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// VHostName returns vhost, or DefaultVHost for the empty name older occtl
+// releases report for the global section.
+func VHostName(vhost string) string {
+	if vhost == "" {
+		return DefaultVHost
+	}
+	return vhost
+}
+
+func (c *OcservVHostConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *OcservVHostConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+
+	case []byte:
+		return json.Unmarshal(v, c)
+
+	case string:
+		return json.Unmarshal([]byte(v), c)
+
+	default:
+		return fmt.Errorf("unsupported type for OcservVHostConfig: %T", value)
+	}
+}
@@ -54,6 +54,10 @@ type TelegramAccount struct {
 	OcservUserID           uint       `json:"ocserv_user_id" gorm:"index;not null;constraint:OnDelete:CASCADE"`
 	CreatedAt              time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	LastLowQuotaNotifiedAt *time.Time `json:"last_low_quota_notified_at"`
+	// CertificateExpiresAt is the certificate expiry the customer was last
+	// told about; a later one on the ocserv user means it was renewed.
+	CertificateExpiresAt      *time.Time `json:"certificate_expires_at"`
+	LastCertificateNotifiedAt *time.Time `json:"last_certificate_notified_at"`
 }
 
 // TelegramPackage describes a sellable plan that bot users can pick when
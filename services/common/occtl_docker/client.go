@@ -0,0 +1,64 @@
+package occtl_docker
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+// RemoteMode reports whether OCSERV_WEBHOOK_MODE asks for ocserv to be
+// managed through the webhook, for services not running in the ocserv
+// container.
+func RemoteMode() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("OCSERV_WEBHOOK_MODE"))
+	return enabled
+}
+
+// NewOcctlClient returns the webhook client in remote mode and the local
+// occtl client otherwise.
+func NewOcctlClient() occtl.OcservOcctlInterface {
+	if RemoteMode() {
+		return NewOcservOcctlDocker()
+	}
+	return occtl.NewOcservOcctlClient()
+}
+
+// NewOcservUserClient returns the webhook client in remote mode and the
+// local ocpasswd based implementation otherwise.
+func NewOcservUserClient() user.OcservUserInterface {
+	if RemoteMode() {
+		return NewOcservOcctlDocker()
+	}
+	return user.NewOcservUser()
+}
+
+// NewFirewallClient returns the webhook client in remote mode and the local
+// iptables firewall otherwise.
+func NewFirewallClient() firewall.Firewall {
+	if RemoteMode() {
+		return NewOcservOcctlDocker()
+	}
+	return firewall.NewIptables()
+}
+
+// localOutboxClient applies outbox actions to ocserv running on this host.
+type localOutboxClient struct {
+	occtl occtl.OcservOcctlInterface
+	user.OcservUserInterface
+}
+
+// NewLocalOutboxClient returns the outbox client of a natively installed
+// ocserv, using the local occtl and ocpasswd.
+func NewLocalOutboxClient() OutboxClient {
+	return &localOutboxClient{
+		occtl:               occtl.NewOcservOcctlClient(),
+		OcservUserInterface: user.NewOcservUser(),
+	}
+}
+
+func (c *localOutboxClient) DisconnectUser(username string) (string, error) {
+	return c.occtl.DisconnectUser(username)
+}
@@ -0,0 +1,111 @@
+package occtl_docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"gorm.io/gorm"
+)
+
+// ErrNodeNotFound is returned for a node missing from the registry.
+var ErrNodeNotFound = errors.New("node not found")
+
+// ErrNodeDisabled is returned for a node disabled in the registry.
+var ErrNodeDisabled = errors.New("node is disabled")
+
+// NodeClients resolves the agent client of the nodes in the ocserv_nodes
+// registry. Clients are reused while the URL and secret of their node stay
+// the same.
+type NodeClients struct {
+	db      *gorm.DB
+	mu      sync.Mutex
+	clients map[string]*nodeClient
+}
+
+type nodeClient struct {
+	url    string
+	secret string
+	client *OcservOcctlDocker
+}
+
+func NewNodeClients(db *gorm.DB) *NodeClients {
+	return &NodeClients{
+		db:      db,
+		clients: make(map[string]*nodeClient),
+	}
+}
+
+// Client returns the agent client of the enabled node name. The local node
+// is not in the registry; callers use their own clients for it.
+func (n *NodeClients) Client(ctx context.Context, name string) (*OcservOcctlDocker, error) {
+	var node models.OcservNode
+	err := n.db.WithContext(ctx).Where("name = ?", name).First(&node).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !node.Enabled {
+		return nil, fmt.Errorf("%w: %s", ErrNodeDisabled, name)
+	}
+	return n.For(&node), nil
+}
+
+// For returns the agent client of node without checking the registry.
+func (n *NodeClients) For(node *models.OcservNode) *OcservOcctlDocker {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	c, ok := n.clients[node.Name]
+	if !ok || c.url != node.URL || c.secret != node.Secret {
+		c = &nodeClient{
+			url:    node.URL,
+			secret: node.Secret,
+			client: NewNodeClient(node.URL, node.Secret),
+		}
+		n.clients[node.Name] = c
+	}
+	return c.client
+}
+
+// Enabled returns the enabled nodes of the registry.
+func (n *NodeClients) Enabled(ctx context.Context) ([]models.OcservNode, error) {
+	var nodes []models.OcservNode
+	if err := n.db.WithContext(ctx).Where("enabled = ?", true).Order("name").Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// SyncGroup writes the config file of group name to the agent of every node
+// and reloads their ocserv, or removes the file when config is nil. Users
+// of a group other than defaults can only be provisioned on a node holding
+// the file. Failing nodes are reported together.
+func (n *NodeClients) SyncGroup(nodes []models.OcservNode, name string, config *models.OcservGroupConfig) error {
+	var errs []error
+	for i := range nodes {
+		client := n.For(&nodes[i])
+
+		var err error
+		if config == nil {
+			err = client.DeleteGroup(name)
+		} else {
+			err = client.CreateGroup(name, config)
+		}
+		if err == nil {
+			_, err = client.ReloadConfigs()
+			if errors.Is(err, occtl.ErrOcservUnreachable) {
+				err = nil
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", nodes[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
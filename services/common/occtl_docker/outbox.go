@@ -0,0 +1,269 @@
+package occtl_docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// OutboxClient applies outbox actions and reads the ocpasswd lock state.
+// *OcservOcctlDocker implements it.
+type OutboxClient interface {
+	OcservOcctlUsersDocker
+	Ocpasswd(ctx context.Context) (*[]user.Ocpasswd, int, error)
+}
+
+const (
+	defaultOutboxMaxAttempts = 50
+	defaultOutboxBatch       = 20
+	// outboxClaimTTL must outlast a delivery; a worker that dies mid-batch
+	// releases its rows once it expires.
+	outboxClaimTTL = 2 * time.Minute
+
+	outboxBaseBackoff = 10 * time.Second
+	outboxMaxBackoff  = 15 * time.Minute
+)
+
+// Outbox persists ocserv actions in the ocserv_actions table so they
+// survive an unreachable or restarting ocserv container, and retries them
+// with exponential backoff until delivered. Actions of users provisioned
+// on a registered node go to the agent of that node, the others to client.
+// Enqueue only stores the action; Run delivers it.
+type Outbox struct {
+	db          *gorm.DB
+	client      OutboxClient
+	nodes       *NodeClients
+	maxAttempts int
+	// queued wakes Run up after Enqueue stored an action
+	queued chan struct{}
+}
+
+func NewOutbox(db *gorm.DB, client OutboxClient) *Outbox {
+	return &Outbox{
+		db:          db,
+		client:      client,
+		nodes:       NewNodeClients(db),
+		maxAttempts: defaultOutboxMaxAttempts,
+		queued:      make(chan struct{}, 1),
+	}
+}
+
+// oppositeAction returns the action superseded by action, if any.
+func oppositeAction(action string) string {
+	switch action {
+	case models.OcservActionLock:
+		return models.OcservActionUnlock
+	case models.OcservActionUnlock:
+		return models.OcservActionLock
+	}
+	return ""
+}
+
+// outboxBackoff returns the delay before retry number attempts.
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+// Enqueue stores action for username and wakes Run up to deliver it, so
+// callers are not held up by an unreachable ocserv or node. A lock replaces
+// a pending unlock of the same user and vice versa, so only the latest lock
+// state is applied.
+func (o *Outbox) Enqueue(ctx context.Context, action, username string) error {
+	item := models.OcservAction{
+		Action:        action,
+		Username:      username,
+		NextAttemptAt: time.Now(),
+	}
+
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if opposite := oppositeAction(action); opposite != "" {
+			if err := tx.Where("username = ? AND action = ? AND failed_at IS NULL", username, opposite).
+				Delete(&models.OcservAction{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&item).Error
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue %s %s: %w", action, username, err)
+	}
+
+	o.notify()
+	return nil
+}
+
+// notify wakes Run up. A wake-up already pending covers the new action.
+func (o *Outbox) notify() {
+	select {
+	case o.queued <- struct{}{}:
+	default:
+	}
+}
+
+// claim marks up to limit due actions as taken by this worker and returns
+// them in creation order. SKIP LOCKED lets several services flush the same
+// table without delivering an action twice.
+func (o *Outbox) claim(ctx context.Context, limit int) ([]models.OcservAction, error) {
+	now := time.Now()
+
+	var items []models.OcservAction
+	err := o.db.WithContext(ctx).Raw(`
+		UPDATE ocserv_actions SET claimed_until = ?
+		WHERE id IN (
+			SELECT id FROM ocserv_actions
+			WHERE failed_at IS NULL
+			  AND next_attempt_at <= ?
+			  AND (claimed_until IS NULL OR claimed_until < ?)
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(outboxClaimTTL), now, now, limit).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the sub-select order
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// clientFor returns the client of the node username is provisioned on.
+// Deleted users fall back to the local node.
+func (o *Outbox) clientFor(ctx context.Context, username string) (OutboxClient, error) {
+	var nodes []string
+	if err := o.db.WithContext(ctx).
+		Model(&models.OcservUser{}).
+		Where("username = ?", username).
+		Limit(1).
+		Pluck("node", &nodes).Error; err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 || models.NodeName(nodes[0]) == models.LocalNode {
+		return o.client, nil
+	}
+	return o.nodes.Client(ctx, nodes[0])
+}
+
+func (o *Outbox) execute(ctx context.Context, item *models.OcservAction) error {
+	client, err := o.clientFor(ctx, item.Username)
+	if err != nil {
+		return err
+	}
+
+	switch item.Action {
+	case models.OcservActionDisconnect:
+		_, err = client.DisconnectUser(item.Username)
+	case models.OcservActionLock:
+		_, err = client.Lock(item.Username)
+	case models.OcservActionUnlock:
+		_, err = client.UnLock(item.Username)
+	default:
+		err = fmt.Errorf("unknown ocserv action %q", item.Action)
+	}
+	return err
+}
+
+// deliver runs a claimed action, deleting it on success or scheduling the
+// next attempt on failure.
+func (o *Outbox) deliver(ctx context.Context, item *models.OcservAction) {
+	db := o.db.WithContext(ctx)
+
+	err := o.execute(ctx, item)
+	if err == nil {
+		if dErr := db.Delete(&models.OcservAction{}, item.ID).Error; dErr != nil {
+			logger.Error("Failed to remove delivered ocserv action %d: %v", item.ID, dErr)
+		}
+		return
+	}
+
+	attempts := item.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      err.Error(),
+		"next_attempt_at": time.Now().Add(outboxBackoff(attempts)),
+		"claimed_until":   nil,
+	}
+	if attempts >= o.maxAttempts {
+		updates["failed_at"] = time.Now()
+		logger.Error("Giving up ocserv action %s for %s after %d attempts: %v", item.Action, item.Username, attempts, err)
+	} else {
+		logger.Warn("Ocserv action %s for %s failed (attempt %d): %v", item.Action, item.Username, attempts, err)
+	}
+
+	if uErr := db.Model(&models.OcservAction{}).Where("id = ?", item.ID).Updates(updates).Error; uErr != nil {
+		logger.Error("Failed to reschedule ocserv action %d: %v", item.ID, uErr)
+	}
+}
+
+// Flush delivers every due action and returns how many were attempted.
+func (o *Outbox) Flush(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		items, err := o.claim(ctx, defaultOutboxBatch)
+		if err != nil {
+			return total, err
+		}
+		for i := range items {
+			o.deliver(ctx, &items[i])
+		}
+		total += len(items)
+
+		if len(items) < defaultOutboxBatch || ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
+
+// Run flushes the outbox as soon as Enqueue stores an action and every
+// flushEvery for the retries, and reconciles lock state every
+// reconcileEvery, until ctx is cancelled. A reconcileEvery of zero leaves
+// reconciliation to another service.
+func (o *Outbox) Run(ctx context.Context, flushEvery, reconcileEvery time.Duration) {
+	flushTicker := time.NewTicker(flushEvery)
+	defer flushTicker.Stop()
+
+	var reconcile <-chan time.Time
+	if reconcileEvery > 0 {
+		reconcileTicker := time.NewTicker(reconcileEvery)
+		defer reconcileTicker.Stop()
+		reconcile = reconcileTicker.C
+	}
+
+	flush := func() {
+		if _, err := o.Flush(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to flush ocserv actions: %v", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.queued:
+			flush()
+		case <-flushTicker.C:
+			flush()
+		case <-reconcile:
+			repaired, err := o.ReconcileLocks(ctx)
+			if err != nil {
+				logger.Error("Failed to reconcile ocpasswd lock state: %v", err)
+			} else if repaired > 0 {
+				logger.Warn("Queued %d ocpasswd lock repairs", repaired)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package occtl_docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{7, 640 * time.Second},
+		{8, 15 * time.Minute},
+		{40, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOppositeAction(t *testing.T) {
+	if oppositeAction(models.OcservActionLock) != models.OcservActionUnlock ||
+		oppositeAction(models.OcservActionUnlock) != models.OcservActionLock ||
+		oppositeAction(models.OcservActionDisconnect) != "" {
+		t.Error("unexpected opposite actions")
+	}
+}
+
+func TestOutboxNotify(t *testing.T) {
+	o := NewOutbox(nil, nil)
+
+	// Enqueue never blocks on a Run that is busy or not started
+	o.notify()
+	o.notify()
+
+	select {
+	case <-o.queued:
+	default:
+		t.Fatal("expected a pending wake-up")
+	}
+	select {
+	case <-o.queued:
+		t.Fatal("wake-ups were not coalesced")
+	default:
+	}
+}
+
+func TestLockDrift(t *testing.T) {
+	users := []models.OcservUser{
+		{Username: "alice", IsLocked: true},
+		{Username: "bob", IsLocked: false},
+		{Username: "carol", IsLocked: true},
+		{Username: "dave", IsLocked: true},
+		{Username: "erin", IsLocked: false},
+	}
+	entries := []user.Ocpasswd{
+		{Username: "alice", Locked: false},
+		{Username: "bob", Locked: true},
+		{Username: "carol", Locked: true},
+		{Username: "erin", Locked: true},
+	}
+	pending := map[string]bool{"erin": true}
+
+	drift := lockDrift(users, entries, pending)
+	if len(drift) != 2 {
+		t.Fatalf("expected 2 repairs, got %v", drift)
+	}
+	if drift["alice"] != models.OcservActionLock || drift["bob"] != models.OcservActionUnlock {
+		t.Errorf("unexpected repairs: %v", drift)
+	}
+}
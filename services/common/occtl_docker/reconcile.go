@@ -0,0 +1,108 @@
+package occtl_docker
+
+import (
+	"context"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+)
+
+// lockDrift returns the lock or unlock action bringing each ocpasswd entry
+// in line with is_locked in the database, keyed by username. Users with a
+// pending lock/unlock action and users missing from ocpasswd are skipped.
+func lockDrift(users []models.OcservUser, entries []user.Ocpasswd, pending map[string]bool) map[string]string {
+	locked := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		locked[e.Username] = e.Locked
+	}
+
+	drift := make(map[string]string)
+	for _, u := range users {
+		l, ok := locked[u.Username]
+		if !ok || pending[u.Username] || l == u.IsLocked {
+			continue
+		}
+		if u.IsLocked {
+			drift[u.Username] = models.OcservActionLock
+		} else {
+			drift[u.Username] = models.OcservActionUnlock
+		}
+	}
+	return drift
+}
+
+// ReconcileLocks compares the lock state in ocpasswd of the local node and
+// of every enabled registered node with the database, which is
+// authoritative, and queues the actions repairing any drift. It returns the
+// number of queued repairs.
+func (o *Outbox) ReconcileLocks(ctx context.Context) (int, error) {
+	db := o.db.WithContext(ctx)
+
+	var users []models.OcservUser
+	if err := db.Select("username", "is_locked", "node").Find(&users).Error; err != nil {
+		return 0, err
+	}
+	byNode := make(map[string][]models.OcservUser)
+	for _, u := range users {
+		node := models.NodeName(u.Node)
+		byNode[node] = append(byNode[node], u)
+	}
+
+	var pendingUsers []string
+	err := db.Model(&models.OcservAction{}).
+		Where("failed_at IS NULL AND action IN ?", []string{models.OcservActionLock, models.OcservActionUnlock}).
+		Distinct().
+		Pluck("username", &pendingUsers).Error
+	if err != nil {
+		return 0, err
+	}
+	pending := make(map[string]bool, len(pendingUsers))
+	for _, username := range pendingUsers {
+		pending[username] = true
+	}
+
+	repaired, err := o.reconcileNode(ctx, models.LocalNode, o.client, byNode[models.LocalNode], pending)
+	if err != nil {
+		return repaired, err
+	}
+
+	nodes, err := o.nodes.Enabled(ctx)
+	if err != nil {
+		return repaired, err
+	}
+	for i := range nodes {
+		n, nErr := o.reconcileNode(ctx, nodes[i].Name, o.nodes.For(&nodes[i]), byNode[nodes[i].Name], pending)
+		if nErr != nil {
+			logger.Error("Failed to reconcile ocpasswd lock state of node %s: %v", nodes[i].Name, nErr)
+		}
+		repaired += n
+	}
+	return repaired, nil
+}
+
+// reconcileNode queues the repairs of the users provisioned on node.
+func (o *Outbox) reconcileNode(ctx context.Context, node string, client OutboxClient, users []models.OcservUser, pending map[string]bool) (int, error) {
+	if len(users) == 0 {
+		return 0, nil
+	}
+
+	entries, _, err := client.Ocpasswd(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if entries == nil {
+		return 0, nil
+	}
+
+	repaired := 0
+	for username, action := range lockDrift(users, *entries, pending) {
+		logger.Warn("ocpasswd lock state of %s on node %s drifted from the database, queueing %s", username, node, action)
+		if err = o.Enqueue(ctx, action, username); err != nil {
+			logger.Error("Failed to queue %s for %s: %v", action, username, err)
+			continue
+		}
+		repaired++
+	}
+	return repaired, nil
+}
@@ -0,0 +1,118 @@
+package occtl_docker
+
+import (
+	"encoding/json"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+// RPC methods served by the webhook on POST /webhook/rpc/<method>. Each maps
+// to the method of occtl.OcservOcctlInterface, user.OcservUserInterface or
+// firewall.Firewall with the same name, MethodCreateGroup and
+// MethodDeleteGroup to group.OcservGroupInterface and MethodAgentInfo to the
+// agent itself.
+const (
+	MethodOnlineSessions    = "online_sessions"
+	MethodShowUser          = "show_user"
+	MethodShowUserByID      = "show_user_by_id"
+	MethodDisconnectUser    = "disconnect_user"
+	MethodDisconnectSession = "disconnect_session"
+	MethodTerminateUser     = "terminate_user"
+	MethodTerminateSession  = "terminate_session"
+	MethodShowSession       = "show_session"
+	MethodShowSessionAll    = "show_session_all"
+	MethodShowSessionsValid = "show_sessions_valid"
+	MethodShowIPBans        = "show_ip_bans"
+	MethodUnbanIP           = "unban_ip"
+	MethodShowStatus        = "show_status"
+	MethodShowStatusRaw     = "show_status_raw"
+	MethodReloadConfigs     = "reload_configs"
+	MethodShowIRoutes       = "show_iroutes"
+	MethodShowEvent         = "show_event"
+	MethodVersion           = "version"
+	MethodCapabilities      = "capabilities"
+	MethodAgentInfo         = "agent_info"
+
+	MethodCreateUser               = "create_user"
+	MethodApplyAuthMode            = "apply_auth_mode"
+	MethodLockUser                 = "lock_user"
+	MethodUnlockUser               = "unlock_user"
+	MethodDeleteUser               = "delete_user"
+	MethodSetGroup                 = "set_group"
+	MethodCreateGroup              = "create_group"
+	MethodDeleteGroup              = "delete_group"
+	MethodSaveUserState            = "save_user_state"
+	MethodRestoreUserState         = "restore_user_state"
+	MethodSyncConfig               = "sync_config"
+	MethodCreateConfig             = "create_config"
+	MethodDeleteConfig             = "delete_config"
+	MethodRestoreConfig            = "restore_config"
+	MethodConfigList               = "config_list"
+	MethodEffectiveConfig          = "effective_config"
+	MethodOcpasswd                 = "ocpasswd"
+	MethodCreateCertificate        = "create_certificate"
+	MethodRevokeCertificate        = "revoke_certificate"
+	MethodRenewCertificate         = "renew_certificate"
+	MethodSuspendCertificate       = "suspend_certificate"
+	MethodUnsuspendCertificate     = "unsuspend_certificate"
+	MethodCertificateStatus        = "certificate_status"
+	MethodCertificate              = "certificate"
+	MethodCertificateNotAfter      = "certificate_not_after"
+	MethodCertificateBackup        = "certificate_backup"
+	MethodRestoreCertificateBackup = "restore_certificate_backup"
+	MethodCAStatus                 = "ca_status"
+	MethodRotateCA                 = "rotate_ca"
+	MethodReissueCertificate       = "reissue_certificate"
+	MethodRetireCA                 = "retire_ca"
+	MethodCertificateInventory     = "certificate_inventory"
+	MethodCACertificate            = "ca_certificate"
+	MethodCRL                      = "crl"
+
+	MethodFirewallBan   = "firewall_ban"
+	MethodFirewallUnban = "firewall_unban"
+	MethodFirewallBans  = "firewall_bans"
+	MethodFirewallSync  = "firewall_sync"
+)
+
+// RPCParams carries the arguments of every RPC method; each method reads
+// only the fields it needs.
+type RPCParams struct {
+	Username    string                              `json:"username,omitempty"`
+	Group       string                              `json:"group,omitempty"`
+	GroupConfig *models.OcservGroupConfig           `json:"group_config,omitempty"`
+	Password    string                              `json:"password,omitempty"`
+	AuthMode    string                              `json:"auth_mode,omitempty"`
+	VHost       string                              `json:"vhost,omitempty"`
+	ID          string                              `json:"id,omitempty"`
+	IP          string                              `json:"ip,omitempty"`
+	CIDRs       []string                            `json:"cidrs,omitempty"`
+	Config      *models.OcservUserConfig            `json:"config,omitempty"`
+	Certificate *models.OcservUserCertificateBackup `json:"certificate,omitempty"`
+	State       *user.UserState                     `json:"state,omitempty"`
+	Force       bool                                `json:"force,omitempty"`
+}
+
+// RPCResponse is the body of every RPC reply. Error is set, and the status
+// code is 4xx/5xx, when the underlying call failed.
+type RPCResponse struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// OcpasswdResult is the result of MethodOcpasswd.
+type OcpasswdResult struct {
+	Users *[]user.Ocpasswd `json:"users"`
+	Total int              `json:"total"`
+}
+
+// ReissueCertificateResult is the result of MethodReissueCertificate.
+type ReissueCertificateResult struct {
+	Reissued bool `json:"reissued"`
+}
+
+// CertificateStatusResult is the result of MethodCertificateStatus.
+type CertificateStatusResult struct {
+	Available bool `json:"available"`
+	Enabled   bool `json:"enabled"`
+}
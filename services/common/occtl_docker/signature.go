@@ -0,0 +1,125 @@
+package occtl_docker
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers set by Sign and checked by Verifier.
+const (
+	TimestampHeader = "X-Webhook-Timestamp"
+	NonceHeader     = "X-Webhook-Nonce"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// DefaultSignatureWindow is how far a request timestamp may drift from the
+// webhook clock.
+const DefaultSignatureWindow = 30 * time.Second
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpiredSignature = errors.New("webhook signature expired")
+	ErrReplayedNonce    = errors.New("webhook nonce already used")
+)
+
+// signaturePayload binds the signature to the request line, the time, the
+// nonce and the body:
+//
+//	METHOD \n PATH \n TIMESTAMP \n NONCE \n hex(sha256(body))
+func signaturePayload(method, path, timestamp, nonce string, body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(sum[:]))
+}
+
+func computeSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign adds the timestamp, nonce and HMAC-SHA256 signature headers for body
+// to req.
+func Sign(req *http.Request, body []byte, secret string, now time.Time) error {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(raw)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(NonceHeader, nonce)
+	req.Header.Set(SignatureHeader, computeSignature(secret, signaturePayload(req.Method, req.URL.Path, timestamp, nonce, body)))
+	return nil
+}
+
+// Verifier checks signed webhook requests. Nonces are remembered for twice
+// the window, so a captured request can not be replayed while its timestamp
+// is still accepted.
+type Verifier struct {
+	secret string
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewVerifier(secret string, window time.Duration) *Verifier {
+	if window <= 0 {
+		window = DefaultSignatureWindow
+	}
+	return &Verifier{
+		secret: secret,
+		window: window,
+		now:    time.Now,
+		nonces: make(map[string]time.Time),
+	}
+}
+
+// Verify validates the signature headers of r against body.
+func (v *Verifier) Verify(r *http.Request, body []byte) error {
+	timestamp := r.Header.Get(TimestampHeader)
+	nonce := r.Header.Get(NonceHeader)
+	signature := r.Header.Get(SignatureHeader)
+	if timestamp == "" || nonce == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	expected := computeSignature(v.secret, signaturePayload(r.Method, r.URL.Path, timestamp, nonce, body))
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	now := v.now()
+	sent := time.Unix(unix, 0)
+	if sent.Before(now.Add(-v.window)) || sent.After(now.Add(v.window)) {
+		return ErrExpiredSignature
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for n, seen := range v.nonces {
+		if now.Sub(seen) > 2*v.window {
+			delete(v.nonces, n)
+		}
+	}
+	if _, ok := v.nonces[nonce]; ok {
+		return ErrReplayedNonce
+	}
+	v.nonces[nonce] = now
+	return nil
+}
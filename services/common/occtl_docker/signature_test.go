@@ -0,0 +1,86 @@
+package occtl_docker
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signedRequest(t *testing.T, secret string, body []byte, at time.Time) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/rpc/lock_user", bytes.NewReader(body))
+	if err := Sign(req, body, secret, at); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return req
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := NewVerifier("s3cret", 30*time.Second)
+	v.now = func() time.Time { return now }
+	body := []byte(`{"username":"alice"}`)
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		err  error
+	}{
+		{"valid", func() *http.Request { return signedRequest(t, "s3cret", body, now) }, nil},
+		{"wrong secret", func() *http.Request { return signedRequest(t, "other", body, now) }, ErrInvalidSignature},
+		{"expired", func() *http.Request { return signedRequest(t, "s3cret", body, now.Add(-time.Minute)) }, ErrExpiredSignature},
+		{"future", func() *http.Request { return signedRequest(t, "s3cret", body, now.Add(time.Minute)) }, ErrExpiredSignature},
+		{"unsigned", func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/webhook/rpc/lock_user", bytes.NewReader(body))
+		}, ErrMissingSignature},
+		{"other path", func() *http.Request {
+			req := signedRequest(t, "s3cret", body, now)
+			req.URL.Path = "/webhook/rpc/delete_user"
+			return req
+		}, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Verify(tt.req(), body); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	req := signedRequest(t, "s3cret", body, now)
+	if err := v.Verify(req, []byte(`{"username":"bob"}`)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected tampered body to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyReplay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := NewVerifier("s3cret", 30*time.Second)
+	v.now = func() time.Time { return now }
+	body := []byte(`{}`)
+
+	req := signedRequest(t, "s3cret", body, now)
+	if err := v.Verify(req, body); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := v.Verify(req, body); !errors.Is(err, ErrReplayedNonce) {
+		t.Fatalf("expected replay to be rejected, got %v", err)
+	}
+
+	// forgotten nonces are outside the timestamp window anyway
+	now = now.Add(2 * time.Minute)
+	if err := v.Verify(req, body); !errors.Is(err, ErrExpiredSignature) {
+		t.Errorf("expected expired signature, got %v", err)
+	}
+	if err := v.Verify(signedRequest(t, "s3cret", body, now), body); err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if len(v.nonces) != 1 {
+		t.Errorf("expected old nonces to be pruned, got %d", len(v.nonces))
+	}
+}
@@ -0,0 +1,51 @@
+package occtl_docker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfigFromEnv builds the mutual TLS configuration of the webhook from
+// WEBHOOK_TLS_CA, WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY. Each container
+// mounts its own certificate and key at those paths, both signed by the CA.
+// It returns nil when none of them is set, leaving the webhook on plain HTTP.
+func TLSConfigFromEnv(server bool) (*tls.Config, error) {
+	caPath := os.Getenv("WEBHOOK_TLS_CA")
+	certPath := os.Getenv("WEBHOOK_TLS_CERT")
+	keyPath := os.Getenv("WEBHOOK_TLS_KEY")
+	if caPath == "" && certPath == "" && keyPath == "" {
+		return nil, nil
+	}
+	if caPath == "" || certPath == "" || keyPath == "" {
+		return nil, errors.New("WEBHOOK_TLS_CA, WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+	}
+
+	caPEM, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("read webhook CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", caPath)
+	}
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load webhook certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if server {
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
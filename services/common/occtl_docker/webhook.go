@@ -1,77 +1,626 @@
 package occtl_docker
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
 	"net/http"
+	"os"
+	"strings"
 	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
 )
 
+// WebhookPayload is the body of the legacy /webhook/<action> endpoints.
 type WebhookPayload struct {
 	Username string `json:"username"`
 }
 
+const (
+	defaultWebhookURL    = "http://ocserv:8888"
+	defaultWebhookTLSURL = "https://ocserv:8888"
+)
+
+// rpcTimeout bounds a webhook RPC call.
+const rpcTimeout = 10 * time.Second
+
+// slowRPCTimeouts bound the RPC methods that generate keys or rebuild the
+// CRL, which take longer than rpcTimeout with RSA keys.
+var slowRPCTimeouts = map[string]time.Duration{
+	MethodApplyAuthMode:            time.Minute,
+	MethodCreateCertificate:        time.Minute,
+	MethodRenewCertificate:         time.Minute,
+	MethodRevokeCertificate:        time.Minute,
+	MethodReissueCertificate:       time.Minute,
+	MethodRestoreCertificateBackup: time.Minute,
+	MethodRestoreUserState:         time.Minute,
+	MethodRotateCA:                 3 * time.Minute,
+	MethodRetireCA:                 3 * time.Minute,
+}
+
+// OcservOcctlDocker runs occtl and ocpasswd operations inside the ocserv
+// container through the webhook service. It implements both
+// occtl.OcservOcctlInterface and user.OcservUserInterface, so services
+// running in their own containers can use it in place of the local
+// implementations.
 type OcservOcctlDocker struct {
 	apiURL string
+	secret string
+	// client has no overall timeout, RPC calls are bounded by their
+	// context and streams last until theirs is cancelled
+	client *http.Client
 }
 
 type OcservOcctlUsersDocker interface {
 	DisconnectUser(username string) (string, error)
 	Lock(username string) (string, error)
-	Unlock(username string) (string, error)
+	UnLock(username string) (string, error)
 }
 
+var (
+	_ occtl.OcservOcctlInterface = (*OcservOcctlDocker)(nil)
+	_ user.OcservUserInterface   = (*OcservOcctlDocker)(nil)
+	_ firewall.Firewall          = (*OcservOcctlDocker)(nil)
+)
+
+// NewOcservOcctlDocker returns a client for the webhook at WEBHOOK_URL
+// signing every request with WEBHOOK_SECRET. When WEBHOOK_TLS_* is set the
+// client authenticates with its certificate over https://ocserv:8888.
 func NewOcservOcctlDocker() *OcservOcctlDocker {
-	return &OcservOcctlDocker{apiURL: "http://ocserv:8888"}
+	transport, secure := webhookTransport()
+	defaultURL := defaultWebhookURL
+	if secure {
+		defaultURL = defaultWebhookTLSURL
+	}
+
+	apiURL := os.Getenv("WEBHOOK_URL")
+	if apiURL == "" {
+		apiURL = defaultURL
+	}
+	return newOcservOcctlDocker(apiURL, os.Getenv("WEBHOOK_SECRET"), transport)
 }
 
-// call webhook endpoint api
-func (d *OcservOcctlDocker) call(name string, username string) error {
-	endpoint := fmt.Sprintf("%s/webhook/%s", d.apiURL, name)
+// NewNodeClient returns a client for the agent of a registered node at
+// apiURL, signing every request with secret. The WEBHOOK_TLS_* certificate
+// is presented to https agents.
+func NewNodeClient(apiURL, secret string) *OcservOcctlDocker {
+	transport, _ := webhookTransport()
+	return newOcservOcctlDocker(strings.TrimRight(apiURL, "/"), secret, transport)
+}
 
-	logger.Info("Docker webhook call for %s %s", name, username)
+func newOcservOcctlDocker(apiURL, secret string, transport http.RoundTripper) *OcservOcctlDocker {
+	return &OcservOcctlDocker{
+		apiURL: apiURL,
+		secret: secret,
+		client: &http.Client{Transport: transport},
+	}
+}
 
-	payload := WebhookPayload{Username: username}
+// webhookTransport returns the transport of webhook clients, configured
+// for mutual TLS when WEBHOOK_TLS_* is set.
+func webhookTransport() (*http.Transport, bool) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 
-	body, err := json.Marshal(payload)
+	tlsConfig, err := TLSConfigFromEnv(false)
 	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
+		logger.Error("Failed to load webhook TLS configuration: %v", err)
+	}
+	if tlsConfig == nil {
+		return transport, false
 	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, true
+}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(body))
+func (d *OcservOcctlDocker) newRequest(ctx context.Context, path string, params RPCParams) (*http.Request, error) {
+	body, err := json.Marshal(params)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
+	if err = Sign(req, body, d.secret, time.Now()); err != nil {
+		return nil, fmt.Errorf("sign request: %w", err)
+	}
+	return req, nil
+}
+
+// call invokes an RPC method on the webhook and decodes its result into
+// result, which may be nil. Errors returned by the remote call keep their
+// original message.
+func (d *OcservOcctlDocker) call(method string, params RPCParams, result interface{}) error {
+	return d.callContext(context.Background(), method, params, result)
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+// callContext is call bounded by ctx as well as the method timeout.
+func (d *OcservOcctlDocker) callContext(ctx context.Context, method string, params RPCParams, result interface{}) error {
+	timeout, ok := slowRPCTimeouts[method]
+	if !ok {
+		timeout = rpcTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := d.newRequest(ctx, "/webhook/rpc/"+method, params)
+	if err != nil {
+		return err
+	}
 
-	resp, err := client.Do(req)
+	resp, err := d.client.Do(req)
 	if err != nil {
 		logger.Error("Failed to call webhook endpoint: %v", err)
-		return fmt.Errorf("call webhook %s: %w", name, err)
+		return fmt.Errorf("call webhook %s: %w", method, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+	var rpcResp RPCResponse
+	if err = json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
 		logger.Error("Failed to call webhook endpoint with status: %d", resp.StatusCode)
-		return fmt.Errorf("webhook %s failed: status %d", name, resp.StatusCode)
+		return fmt.Errorf("webhook %s failed: status %d", method, resp.StatusCode)
+	}
+	if rpcResp.Error != "" {
+		// keep the one error callers tell apart across the webhook
+		if msg, ok := strings.CutPrefix(rpcResp.Error, occtl.ErrOcservUnreachable.Error()); ok {
+			return fmt.Errorf("%w%s", occtl.ErrOcservUnreachable, msg)
+		}
+		return errors.New(rpcResp.Error)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s failed: status %d", method, resp.StatusCode)
 	}
 
+	if result == nil || len(rpcResp.Result) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("decode webhook %s result: %w", method, err)
+	}
 	return nil
 }
 
+// callString is call for methods returning the occtl/ocpasswd output.
+func (d *OcservOcctlDocker) callString(method string, params RPCParams) (string, error) {
+	var out string
+	err := d.call(method, params, &out)
+	return out, err
+}
+
+func (d *OcservOcctlDocker) OnlineSessions() ([]models.OnlineUserSession, error) {
+	var sessions []models.OnlineUserSession
+	if err := d.call(MethodOnlineSessions, RPCParams{}, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (d *OcservOcctlDocker) ShowUser(username string) (*[]models.OcctlUserDetail, error) {
+	var users []models.OcctlUserDetail
+	if err := d.call(MethodShowUser, RPCParams{Username: username}, &users); err != nil {
+		return nil, err
+	}
+	return &users, nil
+}
+
+func (d *OcservOcctlDocker) ShowUserByID(id string) (*models.OcctlUserDetail, error) {
+	var detail models.OcctlUserDetail
+	if err := d.call(MethodShowUserByID, RPCParams{ID: id}, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
 func (d *OcservOcctlDocker) DisconnectUser(username string) (string, error) {
-	return "", d.call("disconnect", username)
+	return d.callString(MethodDisconnectUser, RPCParams{Username: username})
+}
+
+func (d *OcservOcctlDocker) DisconnectSession(id string) (string, error) {
+	return d.callString(MethodDisconnectSession, RPCParams{ID: id})
+}
+
+func (d *OcservOcctlDocker) TerminateUser(username string) (string, error) {
+	return d.callString(MethodTerminateUser, RPCParams{Username: username})
+}
+
+func (d *OcservOcctlDocker) TerminateSession(id string) (string, error) {
+	return d.callString(MethodTerminateSession, RPCParams{ID: id})
+}
+
+func (d *OcservOcctlDocker) ShowSession(sid string) (*models.OcctlSession, error) {
+	var session models.OcctlSession
+	if err := d.call(MethodShowSession, RPCParams{ID: sid}, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (d *OcservOcctlDocker) ShowSessionAll() (*[]models.OcctlSession, error) {
+	var sessions []models.OcctlSession
+	if err := d.call(MethodShowSessionAll, RPCParams{}, &sessions); err != nil {
+		return nil, err
+	}
+	return &sessions, nil
+}
+
+func (d *OcservOcctlDocker) ShowSessionsValid() (*[]models.OcctlSession, error) {
+	var sessions []models.OcctlSession
+	if err := d.call(MethodShowSessionsValid, RPCParams{}, &sessions); err != nil {
+		return nil, err
+	}
+	return &sessions, nil
+}
+
+func (d *OcservOcctlDocker) ShowIPBans() (*[]models.IPBanPoints, error) {
+	var bans []models.IPBanPoints
+	if err := d.call(MethodShowIPBans, RPCParams{}, &bans); err != nil {
+		return nil, err
+	}
+	return &bans, nil
+}
+
+func (d *OcservOcctlDocker) UnbanIP(ip string) (string, error) {
+	return d.callString(MethodUnbanIP, RPCParams{IP: ip})
+}
+
+func (d *OcservOcctlDocker) ShowStatus() (*models.OcctlServerStatus, error) {
+	var status models.OcctlServerStatus
+	if err := d.call(MethodShowStatus, RPCParams{}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (d *OcservOcctlDocker) ShowStatusRaw() (string, error) {
+	return d.callString(MethodShowStatusRaw, RPCParams{})
+}
+
+func (d *OcservOcctlDocker) ReloadConfigs() (string, error) {
+	return d.callString(MethodReloadConfigs, RPCParams{})
+}
+
+func (d *OcservOcctlDocker) ShowIRoutes() (*[]models.IRoute, error) {
+	var routes []models.IRoute
+	if err := d.call(MethodShowIRoutes, RPCParams{}, &routes); err != nil {
+		return nil, err
+	}
+	return &routes, nil
+}
+
+func (d *OcservOcctlDocker) ShowEvent() string {
+	out, err := d.callString(MethodShowEvent, RPCParams{})
+	if err != nil {
+		logger.Error("Failed to read occtl events: %v", err)
+	}
+	return out
+}
+
+func (d *OcservOcctlDocker) Version() *models.ServerVersion {
+	var version models.ServerVersion
+	if err := d.call(MethodVersion, RPCParams{}, &version); err != nil {
+		logger.Error("Failed to read ocserv version: %v", err)
+	}
+	return &version
+}
+
+// Capabilities reports every capability as available when the webhook is
+// unreachable, matching occtl.CapabilitiesFor for an unknown release.
+func (d *OcservOcctlDocker) Capabilities() models.OcctlCapabilities {
+	var caps models.OcctlCapabilities
+	if err := d.call(MethodCapabilities, RPCParams{}, &caps); err != nil {
+		logger.Error("Failed to read occtl capabilities: %v", err)
+		return occtl.CapabilitiesFor(occtl.Version{}, false)
+	}
+	return caps
+}
+
+// SubscribeEvents streams occtl events from POST /webhook/events, which
+// writes one JSON encoded models.OcctlEvent per line. The channel is closed
+// when ctx is cancelled or the stream ends.
+func (d *OcservOcctlDocker) SubscribeEvents(ctx context.Context) (<-chan models.OcctlEvent, error) {
+	req, err := d.newRequest(ctx, "/webhook/events", RPCParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call webhook events: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("webhook events failed: status %d", resp.StatusCode)
+	}
+
+	events := make(chan models.OcctlEvent)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var event models.OcctlEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// SubscribeLogs streams the ocserv log of the agent host from POST
+// /webhook/logs, one line at a time. The channel is closed when ctx is
+// cancelled or the stream ends.
+func (d *OcservOcctlDocker) SubscribeLogs(ctx context.Context) (<-chan string, error) {
+	req, err := d.newRequest(ctx, "/webhook/logs", RPCParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call webhook logs: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("webhook logs failed: status %d", resp.StatusCode)
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return lines, nil
+}
+
+// AgentInfo returns the node name, version and status reported by the
+// agent.
+func (d *OcservOcctlDocker) AgentInfo() (*models.AgentInfo, error) {
+	var info models.AgentInfo
+	if err := d.call(MethodAgentInfo, RPCParams{}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (d *OcservOcctlDocker) Create(group, username, password string, config *models.OcservUserConfig) error {
+	return d.call(MethodCreateUser, RPCParams{Group: group, Username: username, Password: password, Config: config}, nil)
+}
+
+func (d *OcservOcctlDocker) ApplyAuthMode(group, username, password, mode string, config *models.OcservUserConfig) error {
+	return d.call(MethodApplyAuthMode, RPCParams{
+		Group:    group,
+		Username: username,
+		Password: password,
+		AuthMode: mode,
+		Config:   config,
+	}, nil)
 }
 
 func (d *OcservOcctlDocker) Lock(username string) (string, error) {
-	return "", d.call("lock", username)
+	return d.callString(MethodLockUser, RPCParams{Username: username})
+}
+
+func (d *OcservOcctlDocker) UnLock(username string) (string, error) {
+	return d.callString(MethodUnlockUser, RPCParams{Username: username})
+}
+
+func (d *OcservOcctlDocker) Delete(username string) (string, error) {
+	return d.callString(MethodDeleteUser, RPCParams{Username: username})
+}
+
+func (d *OcservOcctlDocker) SetGroup(username, group string) error {
+	return d.call(MethodSetGroup, RPCParams{Username: username, Group: group}, nil)
+}
+
+func (d *OcservOcctlDocker) SyncConfig(username, group string, config *models.OcservUserConfig) error {
+	return d.call(MethodSyncConfig, RPCParams{Username: username, Group: group, Config: config}, nil)
+}
+
+func (d *OcservOcctlDocker) CreateConfig(username string, config *models.OcservUserConfig) error {
+	return d.call(MethodCreateConfig, RPCParams{Username: username, Config: config}, nil)
+}
+
+func (d *OcservOcctlDocker) DeleteConfig(username string) error {
+	return d.call(MethodDeleteConfig, RPCParams{Username: username}, nil)
+}
+
+func (d *OcservOcctlDocker) RestoreConfig(username string) error {
+	return d.call(MethodRestoreConfig, RPCParams{Username: username}, nil)
+}
+
+// ConfigList lists the user config directory of the ocserv container.
+func (d *OcservOcctlDocker) ConfigList(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := d.callContext(ctx, MethodConfigList, RPCParams{}, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// EffectiveConfig merges the config files the ocserv container reads for
+// username.
+func (d *OcservOcctlDocker) EffectiveConfig(username, group, vhost string) (*user.EffectiveConfig, error) {
+	var effective user.EffectiveConfig
+	if err := d.call(MethodEffectiveConfig, RPCParams{Username: username, Group: group, VHost: vhost}, &effective); err != nil {
+		return nil, err
+	}
+	return &effective, nil
+}
+
+// Ocpasswd lists the ocpasswd file of the ocserv container.
+func (d *OcservOcctlDocker) Ocpasswd(ctx context.Context) (*[]user.Ocpasswd, int, error) {
+	var result OcpasswdResult
+	if err := d.callContext(ctx, MethodOcpasswd, RPCParams{}, &result); err != nil {
+		return nil, 0, err
+	}
+	return result.Users, result.Total, nil
+}
+
+func (d *OcservOcctlDocker) CreateCertificate(username, password string) error {
+	return d.call(MethodCreateCertificate, RPCParams{Username: username, Password: password}, nil)
+}
+
+func (d *OcservOcctlDocker) RevokeCertificate(username string) error {
+	return d.call(MethodRevokeCertificate, RPCParams{Username: username}, nil)
+}
+
+func (d *OcservOcctlDocker) RenewCertificate(username, password string) error {
+	return d.call(MethodRenewCertificate, RPCParams{Username: username, Password: password}, nil)
+}
+
+func (d *OcservOcctlDocker) SuspendCertificate(username string) error {
+	return d.call(MethodSuspendCertificate, RPCParams{Username: username}, nil)
+}
+
+func (d *OcservOcctlDocker) UnsuspendCertificate(username string) error {
+	return d.call(MethodUnsuspendCertificate, RPCParams{Username: username}, nil)
+}
+
+func (d *OcservOcctlDocker) CertificateStatus(username string) user.CertificateStatus {
+	var result CertificateStatusResult
+	if err := d.call(MethodCertificateStatus, RPCParams{Username: username}, &result); err != nil {
+		logger.Error("Failed to read certificate status of %s: %v", username, err)
+	}
+	return user.CertificateStatus{Available: result.Available, Enabled: result.Enabled}
+}
+
+// Certificate returns the PKCS#12 bundle of username read by the agent,
+// its path only exists on the agent's host.
+func (d *OcservOcctlDocker) Certificate(username string) ([]byte, error) {
+	var content []byte
+	err := d.call(MethodCertificate, RPCParams{Username: username}, &content)
+	return content, err
+}
+
+func (d *OcservOcctlDocker) CertificateNotAfter(username string) (*time.Time, error) {
+	var notAfter *time.Time
+	if err := d.call(MethodCertificateNotAfter, RPCParams{Username: username}, &notAfter); err != nil {
+		return nil, err
+	}
+	return notAfter, nil
+}
+
+func (d *OcservOcctlDocker) CertificateBackup(username string) (*models.OcservUserCertificateBackup, error) {
+	var backup models.OcservUserCertificateBackup
+	if err := d.call(MethodCertificateBackup, RPCParams{Username: username}, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (d *OcservOcctlDocker) RestoreCertificateBackup(username string, cert *models.OcservUserCertificateBackup) error {
+	return d.call(MethodRestoreCertificateBackup, RPCParams{Username: username, Certificate: cert}, nil)
+}
+
+// CreateGroup writes the config file of group name on the agent's host.
+func (d *OcservOcctlDocker) CreateGroup(name string, config *models.OcservGroupConfig) error {
+	return d.call(MethodCreateGroup, RPCParams{Group: name, GroupConfig: config}, nil)
+}
+
+// DeleteGroup removes the config file of group name on the agent's host.
+func (d *OcservOcctlDocker) DeleteGroup(name string) error {
+	return d.call(MethodDeleteGroup, RPCParams{Group: name}, nil)
+}
+
+func (d *OcservOcctlDocker) SaveState(username string) (*user.UserState, error) {
+	var state user.UserState
+	if err := d.call(MethodSaveUserState, RPCParams{Username: username}, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (d *OcservOcctlDocker) RestoreState(username string, state *user.UserState) error {
+	return d.call(MethodRestoreUserState, RPCParams{Username: username, State: state}, nil)
+}
+
+func (d *OcservOcctlDocker) CAStatus() (*user.CAStatus, error) {
+	var status user.CAStatus
+	if err := d.call(MethodCAStatus, RPCParams{}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (d *OcservOcctlDocker) RotateCA() error {
+	return d.call(MethodRotateCA, RPCParams{}, nil)
+}
+
+func (d *OcservOcctlDocker) ReissueCertificate(username, password string) (bool, error) {
+	var result ReissueCertificateResult
+	if err := d.call(MethodReissueCertificate, RPCParams{Username: username, Password: password}, &result); err != nil {
+		return false, err
+	}
+	return result.Reissued, nil
+}
+
+func (d *OcservOcctlDocker) RetireCA(force bool) error {
+	return d.call(MethodRetireCA, RPCParams{Force: force}, nil)
+}
+
+func (d *OcservOcctlDocker) CertificateInventory() ([]user.CertificateRecord, error) {
+	var records []user.CertificateRecord
+	if err := d.call(MethodCertificateInventory, RPCParams{}, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (d *OcservOcctlDocker) CACertificate() ([]byte, error) {
+	var content []byte
+	err := d.call(MethodCACertificate, RPCParams{}, &content)
+	return content, err
+}
+
+func (d *OcservOcctlDocker) CRL() ([]byte, error) {
+	var content []byte
+	err := d.call(MethodCRL, RPCParams{}, &content)
+	return content, err
+}
+
+func (d *OcservOcctlDocker) Ban(cidr string) error {
+	return d.call(MethodFirewallBan, RPCParams{IP: cidr}, nil)
+}
+
+func (d *OcservOcctlDocker) Unban(cidr string) error {
+	return d.call(MethodFirewallUnban, RPCParams{IP: cidr}, nil)
+}
+
+func (d *OcservOcctlDocker) Bans() ([]string, error) {
+	var cidrs []string
+	if err := d.call(MethodFirewallBans, RPCParams{}, &cidrs); err != nil {
+		return nil, err
+	}
+	return cidrs, nil
 }
 
-func (d *OcservOcctlDocker) Unlock(username string) (string, error) {
-	return "", d.call("unlock", username)
+func (d *OcservOcctlDocker) Sync(cidrs []string) error {
+	return d.call(MethodFirewallSync, RPCParams{CIDRs: cidrs}, nil)
 }
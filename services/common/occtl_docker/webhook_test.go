@@ -0,0 +1,216 @@
+package occtl_docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+)
+
+func newTestDocker(t *testing.T, handler http.HandlerFunc) *OcservOcctlDocker {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("WEBHOOK_URL", srv.URL)
+	t.Setenv("WEBHOOK_SECRET", "s3cret")
+	return NewOcservOcctlDocker()
+}
+
+func writeResult(w http.ResponseWriter, status int, result interface{}, errMsg string) {
+	resp := RPCResponse{Error: errMsg}
+	if result != nil {
+		resp.Result, _ = json.Marshal(result)
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func TestDockerCall(t *testing.T) {
+	var gotPath string
+	var gotParams RPCParams
+	var verifyErr error
+	verifier := NewVerifier("s3cret", 0)
+	d := newTestDocker(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, verifyErr = r.URL.Path, verifier.Verify(r, body)
+		_ = json.Unmarshal(body, &gotParams)
+		writeResult(w, http.StatusOK, []models.OcctlUserDetail{{ID: 4, Username: "alice"}}, "")
+	})
+
+	users, err := d.ShowUser("alice")
+	if err != nil {
+		t.Fatalf("ShowUser: %v", err)
+	}
+	if len(*users) != 1 || (*users)[0].ID != 4 {
+		t.Errorf("unexpected users: %+v", *users)
+	}
+	if gotPath != "/webhook/rpc/"+MethodShowUser || gotParams.Username != "alice" {
+		t.Errorf("unexpected request: %s %+v", gotPath, gotParams)
+	}
+	if verifyErr != nil {
+		t.Errorf("request signature rejected: %v", verifyErr)
+	}
+}
+
+func TestDockerCallError(t *testing.T) {
+	d := newTestDocker(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, http.StatusBadRequest, nil, "could not disconnect user 'bob'")
+	})
+
+	_, err := d.DisconnectUser("bob")
+	if err == nil || err.Error() != "could not disconnect user 'bob'" {
+		t.Fatalf("expected remote error message, got %v", err)
+	}
+
+	if status := d.CertificateStatus("bob"); status.Available {
+		t.Errorf("expected empty status on error, got %+v", status)
+	}
+}
+
+func TestDockerCallUnreachable(t *testing.T) {
+	d := newTestDocker(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, http.StatusBadRequest, nil, occtl.ErrOcservUnreachable.Error()+": exit status 1")
+	})
+
+	_, err := d.ReloadConfigs()
+	if !errors.Is(err, occtl.ErrOcservUnreachable) {
+		t.Fatalf("expected ErrOcservUnreachable, got %v", err)
+	}
+	if err.Error() != "ocserv is unreachable: exit status 1" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestDockerCallUnexpectedStatus(t *testing.T) {
+	d := newTestDocker(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+
+	if _, err := d.Lock("alice"); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
+
+func TestDockerSubscribeEvents(t *testing.T) {
+	d := newTestDocker(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/webhook/events" {
+			http.NotFound(w, r)
+			return
+		}
+		encoder := json.NewEncoder(w)
+		_ = encoder.Encode(models.OcctlEvent{Type: models.OcctlEventConnect, Username: "alice"})
+		_, _ = w.Write([]byte("garbage\n"))
+		_ = encoder.Encode(models.OcctlEvent{Type: models.OcctlEventDisconnect, Username: "alice"})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := d.SubscribeEvents(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeEvents: %v", err)
+	}
+
+	var got []models.OcctlEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	if len(got) != 2 || got[0].Type != models.OcctlEventConnect || got[1].Type != models.OcctlEventDisconnect {
+		t.Errorf("unexpected events: %+v", got)
+	}
+}
+
+func TestNodeClientSubscribeLogs(t *testing.T) {
+	verifier := NewVerifier("node-secret", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/webhook/logs" || verifier.Verify(r, body) != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("ocserv[12]: main: started\nocserv[13]: worker[alice]: 10.0.0.2 sent periodic stats\n"))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lines, err := NewNodeClient(srv.URL+"/", "node-secret").SubscribeLogs(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeLogs: %v", err)
+	}
+
+	var got []string
+	for line := range lines {
+		got = append(got, line)
+	}
+	if len(got) != 2 || !strings.Contains(got[1], "worker[alice]") {
+		t.Errorf("unexpected lines: %q", got)
+	}
+}
+
+func TestNodeClientsSyncGroup(t *testing.T) {
+	var calls []string
+	var gotParams RPCParams
+	verifier := NewVerifier("node-secret", 0)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if verifier.Verify(r, body) != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		method := strings.TrimPrefix(r.URL.Path, "/webhook/rpc/")
+		calls = append(calls, method)
+		if method != MethodReloadConfigs {
+			_ = json.Unmarshal(body, &gotParams)
+		}
+		writeResult(w, http.StatusOK, nil, "")
+	}))
+	t.Cleanup(agent.Close)
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, http.StatusBadRequest, nil, "permission denied")
+	}))
+	t.Cleanup(broken.Close)
+
+	clients := NewNodeClients(nil)
+	nodes := []models.OcservNode{
+		{Name: "edge-1", URL: agent.URL, Secret: "node-secret", Enabled: true},
+		{Name: "edge-2", URL: broken.URL, Secret: "node-secret", Enabled: true},
+	}
+
+	maxSameClients := 2
+	config := &models.OcservGroupConfig{MaxSameClients: &maxSameClients}
+	err := clients.SyncGroup(nodes, "staff", config)
+	if err == nil || !strings.Contains(err.Error(), "node edge-2: permission denied") {
+		t.Fatalf("expected the failing node to be reported, got %v", err)
+	}
+	if strings.Contains(err.Error(), "edge-1") {
+		t.Errorf("edge-1 reported as failing: %v", err)
+	}
+	if strings.Join(calls, ",") != MethodCreateGroup+","+MethodReloadConfigs {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if gotParams.Group != "staff" || gotParams.GroupConfig == nil ||
+		gotParams.GroupConfig.MaxSameClients == nil || *gotParams.GroupConfig.MaxSameClients != 2 {
+		t.Errorf("unexpected params: %+v", gotParams)
+	}
+
+	calls, gotParams = nil, RPCParams{}
+	if err = clients.SyncGroup(nodes[:1], "staff", nil); err != nil {
+		t.Fatalf("SyncGroup delete: %v", err)
+	}
+	if strings.Join(calls, ",") != MethodDeleteGroup+","+MethodReloadConfigs || gotParams.Group != "staff" {
+		t.Errorf("unexpected delete: %v %+v", calls, gotParams)
+	}
+}
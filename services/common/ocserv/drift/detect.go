@@ -0,0 +1,119 @@
+package drift
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+const defaultGroup = "defaults"
+
+func normalizeGroup(group string) string {
+	group = strings.TrimSpace(group)
+	if group == "" || group == "*" {
+		return defaultGroup
+	}
+	return group
+}
+
+// Detect compares both states and returns every drift, sorted by kind in
+// Kinds order and then by name.
+func Detect(db DatabaseState, oc OcservState) Report {
+	var items []Item
+
+	entries := make(map[string]user.Ocpasswd, len(oc.Ocpasswd))
+	for _, e := range oc.Ocpasswd {
+		entries[e.Username] = e
+	}
+	configs := toSet(oc.UserConfigs)
+
+	dbUsers := make(map[string]bool, len(db.Users))
+	for _, u := range db.Users {
+		dbUsers[u.Username] = true
+
+		e, ok := entries[u.Username]
+		if !ok {
+			// Certificate-only users have no ocpasswd entry by design.
+			if models.AuthModeUsesPassword(models.ResolveAuthMode(u.AuthMode)) {
+				items = append(items, Item{Kind: KindMissingInOcpasswd, Name: u.Username})
+			}
+		} else {
+			if e.Locked != u.IsLocked {
+				items = append(items, Item{
+					Kind:     KindLockMismatch,
+					Name:     u.Username,
+					Database: strconv.FormatBool(u.IsLocked),
+					Ocserv:   strconv.FormatBool(e.Locked),
+				})
+			}
+			if dbGroup, ocGroup := normalizeGroup(u.Group), normalizeGroup(e.Group); dbGroup != ocGroup {
+				items = append(items, Item{Kind: KindGroupMismatch, Name: u.Username, Database: dbGroup, Ocserv: ocGroup})
+			}
+		}
+
+		if user.HasConfigValues(u.Config) && !configs[u.Username] {
+			items = append(items, Item{Kind: KindMissingConfig, Name: u.Username})
+		}
+	}
+
+	for _, e := range oc.Ocpasswd {
+		if !dbUsers[e.Username] {
+			items = append(items, Item{Kind: KindMissingInDatabase, Name: e.Username, Ocserv: normalizeGroup(e.Group)})
+		}
+	}
+
+	for _, name := range oc.UserConfigs {
+		if !dbUsers[name] {
+			items = append(items, Item{Kind: KindStaleConfig, Name: name})
+		}
+	}
+
+	dbGroups := toSet(db.Groups)
+	ocGroups := toSet(oc.Groups)
+	for _, name := range oc.Groups {
+		if !dbGroups[name] {
+			items = append(items, Item{Kind: KindOrphanGroup, Name: name})
+		}
+	}
+	for _, name := range db.Groups {
+		if name != defaultGroup && !ocGroups[name] {
+			items = append(items, Item{Kind: KindMissingGroupFile, Name: name})
+		}
+	}
+
+	order := make(map[string]int, len(Kinds))
+	for i, k := range Kinds {
+		order[k] = i
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return order[items[i].Kind] < order[items[j].Kind]
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	summary := make(map[string]int, len(Kinds))
+	for _, k := range Kinds {
+		summary[k] = 0
+	}
+	for _, item := range items {
+		summary[item.Kind]++
+	}
+
+	if items == nil {
+		items = []Item{}
+	}
+	return Report{CheckedAt: time.Now(), Summary: summary, Items: items}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
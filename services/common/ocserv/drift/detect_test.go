@@ -0,0 +1,92 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+func TestDetect(t *testing.T) {
+	dns := models.CSVStringList{"1.1.1.1"}
+	db := DatabaseState{
+		Users: []models.OcservUser{
+			{Username: "alice", Group: "defaults"},
+			{Username: "bob", Group: "staff", IsLocked: true},
+			{Username: "carol", Group: "staff", Config: &models.OcservUserConfig{DNS: &dns}},
+			{Username: "dave", Group: ""},
+			{Username: "erin", Group: "staff", AuthMode: models.AuthModeCertificate},
+		},
+		Groups: []string{"defaults", "staff", "ops"},
+	}
+	oc := OcservState{
+		Ocpasswd: []user.Ocpasswd{
+			{Username: "alice", Group: "defaults"},
+			{Username: "bob", Group: "ops", Locked: false},
+			{Username: "carol", Group: "staff"},
+			{Username: "mallory", Group: "*"},
+		},
+		UserConfigs: []string{"bob", "ghost"},
+		Groups:      []string{"staff", "legacy"},
+	}
+
+	report := Detect(db, oc)
+
+	want := []Item{
+		{Kind: KindMissingInOcpasswd, Name: "dave"},
+		{Kind: KindMissingInDatabase, Name: "mallory", Ocserv: "defaults"},
+		{Kind: KindLockMismatch, Name: "bob", Database: "true", Ocserv: "false"},
+		{Kind: KindGroupMismatch, Name: "bob", Database: "staff", Ocserv: "ops"},
+		{Kind: KindStaleConfig, Name: "ghost"},
+		{Kind: KindMissingConfig, Name: "carol"},
+		{Kind: KindOrphanGroup, Name: "legacy"},
+		{Kind: KindMissingGroupFile, Name: "ops"},
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("expected %d items, got %d: %+v", len(want), len(report.Items), report.Items)
+	}
+	for i := range want {
+		if report.Items[i] != want[i] {
+			t.Errorf("item %d: expected %+v, got %+v", i, want[i], report.Items[i])
+		}
+	}
+
+	for _, k := range Kinds {
+		if report.Summary[k] != 1 {
+			t.Errorf("summary[%s] = %d, want 1", k, report.Summary[k])
+		}
+	}
+}
+
+func TestDetectInSync(t *testing.T) {
+	report := Detect(
+		DatabaseState{Users: []models.OcservUser{{Username: "alice"}}, Groups: []string{"defaults"}},
+		OcservState{Ocpasswd: []user.Ocpasswd{{Username: "alice", Group: "*"}}},
+	)
+	if len(report.Items) != 0 {
+		t.Errorf("expected no drift, got %+v", report.Items)
+	}
+	if report.Items == nil {
+		t.Error("expected empty, non-nil items")
+	}
+}
+
+func TestDestructive(t *testing.T) {
+	for _, source := range []string{SourceDatabase, SourceOcserv} {
+		destructive := 0
+		for _, kind := range Kinds {
+			if Destructive(source, kind) {
+				destructive++
+			}
+		}
+		if destructive == 0 || destructive == len(Kinds) {
+			t.Errorf("%s: %d destructive kinds of %d", source, destructive, len(Kinds))
+		}
+	}
+	if !Destructive(SourceOcserv, KindMissingInOcpasswd) || !Destructive(SourceOcserv, KindMissingGroupFile) {
+		t.Error("deleting database users and groups must be destructive")
+	}
+	if Destructive(SourceOcserv, KindLockMismatch) {
+		t.Error("lock mismatch must not be destructive")
+	}
+}
@@ -0,0 +1,93 @@
+package drift
+
+import (
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+// Drift classes. The values are part of the API contract.
+const (
+	// KindMissingInOcpasswd is a database user without an ocpasswd entry.
+	KindMissingInOcpasswd = "missing_in_ocpasswd"
+	// KindMissingInDatabase is an ocpasswd entry without a database user.
+	KindMissingInDatabase = "missing_in_database"
+	// KindLockMismatch is a user locked on one side only.
+	KindLockMismatch = "lock_mismatch"
+	// KindGroupMismatch is a user assigned to different groups.
+	KindGroupMismatch = "group_mismatch"
+	// KindStaleConfig is a per-user config file of an unknown user.
+	KindStaleConfig = "stale_config"
+	// KindMissingConfig is a database user with config options but no
+	// config file.
+	KindMissingConfig = "missing_config"
+	// KindOrphanGroup is a group config file without a database group.
+	KindOrphanGroup = "orphan_group"
+	// KindMissingGroupFile is a database group without a config file.
+	KindMissingGroupFile = "missing_group_file"
+)
+
+// Kinds lists every drift class in report order.
+var Kinds = []string{
+	KindMissingInOcpasswd,
+	KindMissingInDatabase,
+	KindLockMismatch,
+	KindGroupMismatch,
+	KindStaleConfig,
+	KindMissingConfig,
+	KindOrphanGroup,
+	KindMissingGroupFile,
+}
+
+// Sources of truth a drift can be fixed from.
+const (
+	SourceDatabase = "database"
+	SourceOcserv   = "ocserv"
+)
+
+// Destructive reports whether fixing kind from source deletes users, groups
+// or config files. Fixes list these kinds explicitly, they are never part
+// of a fix of all kinds.
+func Destructive(source, kind string) bool {
+	switch source {
+	case SourceDatabase:
+		return kind == KindMissingInDatabase || kind == KindStaleConfig || kind == KindOrphanGroup
+	case SourceOcserv:
+		return kind == KindMissingInOcpasswd || kind == KindMissingGroupFile
+	}
+	return false
+}
+
+// Item is a single difference between the database and the ocserv files.
+// Database and Ocserv hold the value on each side for mismatches.
+type Item struct {
+	Kind     string `json:"kind" enums:"missing_in_ocpasswd,missing_in_database,lock_mismatch,group_mismatch,stale_config,missing_config,orphan_group,missing_group_file" validate:"required"`
+	Name     string `json:"name" validate:"required"`
+	Database string `json:"database,omitempty" validate:"omitempty"`
+	Ocserv   string `json:"ocserv,omitempty" validate:"omitempty"`
+}
+
+// Report is the result of a drift check.
+type Report struct {
+	CheckedAt time.Time      `json:"checked_at" validate:"required"`
+	Summary   map[string]int `json:"summary" validate:"required"`
+	Items     []Item         `json:"items" validate:"required"`
+}
+
+// DatabaseState is what Postgres knows about users and groups. Users need
+// Username, Group, IsLocked and Config loaded, and AuthMode resolved against
+// their group.
+type DatabaseState struct {
+	Users  []models.OcservUser
+	Groups []string
+}
+
+// OcservState is what the ocserv files contain.
+type OcservState struct {
+	Ocpasswd []user.Ocpasswd
+	// UserConfigs are the usernames with a file in the user config directory.
+	UserConfigs []string
+	// Groups are the file names in the group config directory.
+	Groups []string
+}
@@ -0,0 +1,197 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os/exec"
+	"strings"
+)
+
+// Chain is the iptables chain holding the ban rules. It is jumped to from
+// the top of INPUT, so banned addresses cannot reach ocserv at all.
+const Chain = "OCSERV-BANS"
+
+const (
+	iptablesExec  = "iptables"
+	ip6tablesExec = "ip6tables"
+)
+
+var ErrPrefixTooBroad = errors.New("refusing to ban a /0 prefix")
+
+// Firewall applies IP bans on the ocserv host.
+type Firewall interface {
+	Ban(cidr string) error
+	Unban(cidr string) error
+	Bans() ([]string, error)
+	// Sync makes the banned set exactly cidrs.
+	Sync(cidrs []string) error
+}
+
+type runner func(name string, args ...string) ([]byte, error)
+
+// Iptables implements Firewall with iptables and ip6tables DROP rules in
+// Chain.
+type Iptables struct {
+	run runner
+}
+
+func NewIptables() *Iptables {
+	return &Iptables{run: func(name string, args ...string) ([]byte, error) {
+		return exec.Command(name, args...).CombinedOutput()
+	}}
+}
+
+// NormalizeCIDR accepts an address or a prefix and returns the masked
+// prefix, e.g. "10.0.0.7" becomes "10.0.0.7/32" and "10.0.0.7/24"
+// becomes "10.0.0.0/24".
+func NormalizeCIDR(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	var prefix netip.Prefix
+	if strings.Contains(value, "/") {
+		p, err := netip.ParsePrefix(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid ip or cidr %q", value)
+		}
+		prefix = p.Masked()
+	} else {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid ip or cidr %q", value)
+		}
+		addr = addr.Unmap()
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+
+	if prefix.Bits() == 0 {
+		return "", ErrPrefixTooBroad
+	}
+	return prefix.String(), nil
+}
+
+func binary(cidr string) string {
+	if strings.Contains(cidr, ":") {
+		return ip6tablesExec
+	}
+	return iptablesExec
+}
+
+func (f *Iptables) exec(name string, args ...string) error {
+	out, err := f.run(name, args...)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %s: %w", name, strings.Join(args, " "), strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// ensureChain creates Chain and its INPUT jump when missing.
+func (f *Iptables) ensureChain(bin string) error {
+	if _, err := f.run(bin, "-n", "-L", Chain); err != nil {
+		if err = f.exec(bin, "-N", Chain); err != nil {
+			return err
+		}
+	}
+	if _, err := f.run(bin, "-C", "INPUT", "-j", Chain); err != nil {
+		return f.exec(bin, "-I", "INPUT", "1", "-j", Chain)
+	}
+	return nil
+}
+
+func ruleArgs(op, cidr string) []string {
+	return []string{op, Chain, "-s", cidr, "-j", "DROP"}
+}
+
+func (f *Iptables) Ban(cidr string) error {
+	cidr, err := NormalizeCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	bin := binary(cidr)
+	if err = f.ensureChain(bin); err != nil {
+		return err
+	}
+	if _, err = f.run(bin, ruleArgs("-C", cidr)...); err == nil {
+		return nil
+	}
+	return f.exec(bin, ruleArgs("-A", cidr)...)
+}
+
+func (f *Iptables) Unban(cidr string) error {
+	cidr, err := NormalizeCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	bin := binary(cidr)
+	for {
+		if _, err = f.run(bin, ruleArgs("-C", cidr)...); err != nil {
+			return nil
+		}
+		if err = f.exec(bin, ruleArgs("-D", cidr)...); err != nil {
+			return err
+		}
+	}
+}
+
+// Bans lists the prefixes currently dropped by Chain.
+func (f *Iptables) Bans() ([]string, error) {
+	var bans []string
+	for _, bin := range []string{iptablesExec, ip6tablesExec} {
+		out, err := f.run(bin, "-S", Chain)
+		if err != nil {
+			// The chain is created on the first ban.
+			continue
+		}
+		bans = append(bans, parseRules(string(out))...)
+	}
+	return bans, nil
+}
+
+// parseRules extracts the source prefixes of "-A Chain -s X -j DROP" lines
+// of iptables -S output.
+func parseRules(out string) []string {
+	var cidrs []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 6 || fields[0] != "-A" || fields[1] != Chain || fields[2] != "-s" || fields[5] != "DROP" {
+			continue
+		}
+		if cidr, err := NormalizeCIDR(fields[3]); err == nil {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+func (f *Iptables) Sync(cidrs []string) error {
+	want := make(map[string]bool, len(cidrs))
+	for _, c := range cidrs {
+		cidr, err := NormalizeCIDR(c)
+		if err != nil {
+			return err
+		}
+		want[cidr] = true
+	}
+
+	current, err := f.Bans()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	have := make(map[string]bool, len(current))
+	for _, cidr := range current {
+		have[cidr] = true
+		if !want[cidr] {
+			errs = append(errs, f.Unban(cidr))
+		}
+	}
+	for cidr := range want {
+		if !have[cidr] {
+			errs = append(errs, f.Ban(cidr))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,160 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeIptables keeps the rules of Chain per binary in memory.
+type fakeIptables struct {
+	chains map[string][]string
+	jumps  map[string]bool
+}
+
+func newFake() (*fakeIptables, *Iptables) {
+	f := &fakeIptables{chains: map[string][]string{}, jumps: map[string]bool{}}
+	return f, &Iptables{run: f.run}
+}
+
+func (f *fakeIptables) run(name string, args ...string) ([]byte, error) {
+	fail := errors.New("exit status 1")
+	rules, exists := f.chains[name]
+
+	switch {
+	case args[0] == "-n" && args[1] == "-L":
+		if !exists {
+			return nil, fail
+		}
+	case args[0] == "-N":
+		f.chains[name] = []string{}
+	case args[0] == "-C" && args[1] == "INPUT":
+		if !f.jumps[name] {
+			return nil, fail
+		}
+	case args[0] == "-I" && args[1] == "INPUT":
+		f.jumps[name] = true
+	case args[0] == "-S":
+		if !exists {
+			return nil, fail
+		}
+		out := fmt.Sprintf("-N %s\n", Chain)
+		for _, r := range rules {
+			out += fmt.Sprintf("-A %s -s %s -j DROP\n", Chain, r)
+		}
+		return []byte(out), nil
+	case args[0] == "-C":
+		for _, r := range rules {
+			if r == args[3] {
+				return nil, nil
+			}
+		}
+		return nil, fail
+	case args[0] == "-A":
+		f.chains[name] = append(rules, args[3])
+	case args[0] == "-D":
+		for i, r := range rules {
+			if r == args[3] {
+				f.chains[name] = append(rules[:i], rules[i+1:]...)
+				return nil, nil
+			}
+		}
+		return nil, fail
+	default:
+		return nil, fmt.Errorf("unexpected %s %s", name, strings.Join(args, " "))
+	}
+	return nil, nil
+}
+
+func TestNormalizeCIDR(t *testing.T) {
+	cases := map[string]string{
+		"10.0.0.7":           "10.0.0.7/32",
+		" 10.0.0.7/24 ":      "10.0.0.0/24",
+		"::ffff:192.0.2.1":   "192.0.2.1/32",
+		"2001:db8::1":        "2001:db8::1/128",
+		"2001:db8:1:2::/48":  "2001:db8:1::/48",
+		"not-an-ip":          "",
+		"10.0.0.0/0":         "",
+		"10.0.0.300":         "",
+		"2001:db8::/129":     "",
+		"192.0.2.1/32 extra": "",
+	}
+	for in, want := range cases {
+		got, err := NormalizeCIDR(in)
+		if want == "" {
+			if err == nil {
+				t.Errorf("NormalizeCIDR(%q) = %q, want error", in, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("NormalizeCIDR(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestBanUnban(t *testing.T) {
+	fake, fw := newFake()
+
+	if err := fw.Ban("192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	// Banning twice must not duplicate the rule.
+	if err := fw.Ban("192.0.2.1/32"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Ban("2001:db8::/32"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !fake.jumps[iptablesExec] || !fake.jumps[ip6tablesExec] {
+		t.Fatalf("expected INPUT jumps for both families, got %v", fake.jumps)
+	}
+	if got := fake.chains[iptablesExec]; len(got) != 1 || got[0] != "192.0.2.1/32" {
+		t.Fatalf("unexpected v4 rules %v", got)
+	}
+
+	bans, err := fw.Bans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bans) != 2 {
+		t.Fatalf("expected 2 bans, got %v", bans)
+	}
+
+	if err = fw.Unban("192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = fw.Unban("192.0.2.1"); err != nil {
+		t.Fatalf("unban of a missing rule should be a no-op, got %v", err)
+	}
+	if got := fake.chains[iptablesExec]; len(got) != 0 {
+		t.Fatalf("expected no v4 rules, got %v", got)
+	}
+}
+
+func TestSync(t *testing.T) {
+	fake, fw := newFake()
+	for _, c := range []string{"192.0.2.1", "198.51.100.0/24"} {
+		if err := fw.Ban(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := fw.Sync([]string{"198.51.100.7/24", "203.0.113.9"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := append([]string(nil), fake.chains[iptablesExec]...)
+	sort.Strings(got)
+	want := []string{"198.51.100.0/24", "203.0.113.9/32"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := fw.Sync([]string{"bogus"}); err == nil {
+		t.Fatal("expected invalid prefix error")
+	}
+}
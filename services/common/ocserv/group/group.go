@@ -19,6 +19,8 @@ type OcservGroupInterface interface {
 	Delete(name string) error
 	DefaultsGroup() (*models.OcservGroupConfig, error)
 	UpdateDefaultsGroup(config *models.OcservGroupConfig) error
+	RestoreConfig(name string) error
+	RestoreDefaultsGroup() error
 	GroupList(ctx context.Context) ([]UnsyncedGroup, error)
 }
 
@@ -26,24 +28,13 @@ func NewOcservGroup() *OcservGroup {
 	return &OcservGroup{}
 }
 
-// Create creates a new group configuration file for the given group name.
+// Create creates or updates the group configuration file for the given group name.
 // The file is written to ocserv.ConfigGroupBaseDir/<name> with permission 0640.
-// It serializes the provided OcservGroupConfig into the file using pkg.ConfigWriter.
+// The provided OcservGroupConfig is applied to the existing file as a
+// utils.ConfigDocument, so comments, order and unknown directives are kept.
 func (g *OcservGroup) Create(name string, config *models.OcservGroupConfig) error {
 	filename := filepath.Join(utils.ConfigGroupBaseDir, name)
-
-	// Open file with create, truncate, write-only flags and permission 0640
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	err = utils.ConfigWriter(file, utils.ToMap(config))
-	if err != nil {
-		return err
-	}
-	return nil
+	return writeGroupConfig(filename, config)
 }
 
 // Delete removes the configuration file for the given group name
@@ -92,25 +83,27 @@ func (g *OcservGroup) DefaultsGroup() (*models.OcservGroupConfig, error) {
 	return &config, nil
 }
 
-// UpdateDefaultsGroup overwrites the ocserv.DefaultGroupFile with
-// the provided OcservGroupConfig. The file is opened with create,
-// truncate, and write-only flags and written with permission 0640.
+// UpdateDefaultsGroup applies the provided OcservGroupConfig to
+// ocserv.DefaultGroupFile, keeping its comments, order and unknown
+// directives. The file is written with permission 0640.
 func (g *OcservGroup) UpdateDefaultsGroup(config *models.OcservGroupConfig) error {
-	file, err := os.OpenFile(utils.DefaultGroupFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	return writeGroupConfig(utils.DefaultGroupFile, config)
+}
 
-	err = utils.ConfigWriter(file, utils.ToMap(config))
-	if err != nil {
-		return err
-	}
-	return nil
+// RestoreConfig puts back the group configuration file as it was before
+// the last Create, e.g. when ocserv refused to reload it.
+func (g *OcservGroup) RestoreConfig(name string) error {
+	return utils.RestoreConfigFile(filepath.Join(utils.ConfigGroupBaseDir, name))
+}
+
+// RestoreDefaultsGroup puts back ocserv.DefaultGroupFile as it was before
+// the last UpdateDefaultsGroup.
+func (g *OcservGroup) RestoreDefaultsGroup() error {
+	return utils.RestoreConfigFile(utils.DefaultGroupFile)
 }
 
 // GroupList scans the ConfigGroupBaseDir for directories and returns their configurations.
-// It respects context cancellation.
+// Temp files of interrupted writes are skipped. It respects context cancellation.
 func (g *OcservGroup) GroupList(ctx context.Context) ([]UnsyncedGroup, error) {
 	var groups []UnsyncedGroup
 
@@ -124,7 +117,7 @@ func (g *OcservGroup) GroupList(ctx context.Context) ([]UnsyncedGroup, error) {
 			return ctx.Err()
 		}
 
-		if d.IsDir() {
+		if d.IsDir() || utils.IsAtomicTempFile(d.Name()) {
 			return nil
 		}
 
@@ -154,3 +147,18 @@ func (g *OcservGroup) GroupList(ctx context.Context) ([]UnsyncedGroup, error) {
 
 	return groups, nil
 }
+
+// writeGroupConfig applies config to the group config file at filename.
+// The result is validated before it replaces the file atomically, and the
+// previous version is kept for RestoreConfig.
+func writeGroupConfig(filename string, config *models.OcservGroupConfig) error {
+	doc, err := utils.ReadConfigDocument(filename)
+	if err != nil {
+		return err
+	}
+	doc.Apply(utils.ToMap(config))
+	if err = doc.Validate(models.OcservGroupConfig{}); err != nil {
+		return err
+	}
+	return doc.WriteFile(filename, 0640)
+}
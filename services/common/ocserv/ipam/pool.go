@@ -0,0 +1,87 @@
+// Package ipam keeps track of the IPv4 address pools of ocserv and the
+// static (explicit-ipv4) addresses assigned from them.
+package ipam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// ErrPoolExhausted is returned when a pool has no free address left.
+var ErrPoolExhausted = errors.New("no free address left in the pool")
+
+// Pool is an ipv4-network. The network and broadcast addresses are not
+// usable, and ocserv takes the first host address for its side of the
+// tunnels.
+type Pool struct {
+	network *net.IPNet
+	first   uint32
+	last    uint32
+}
+
+// NewPool parses an ipv4-network, a CIDR or an address with a dotted
+// netmask.
+func NewPool(network string) (*Pool, error) {
+	ipNet, err := utils.ParseNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+	ip := ipNet.IP.To4()
+	if ip == nil {
+		return nil, fmt.Errorf("%s is not an IPv4 network", network)
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones < 2 {
+		return nil, fmt.Errorf("%s is too small for a pool", network)
+	}
+
+	start := binary.BigEndian.Uint32(ip)
+	end := start | ^binary.BigEndian.Uint32(ipNet.Mask)
+	return &Pool{network: ipNet, first: start + 2, last: end - 1}, nil
+}
+
+// String returns the pool in CIDR notation.
+func (p *Pool) String() string {
+	return p.network.String()
+}
+
+// Size returns the number of addresses clients can be given.
+func (p *Pool) Size() int {
+	if p.last < p.first {
+		return 0
+	}
+	return int(p.last - p.first + 1)
+}
+
+// Contains reports whether ip is in the pool network.
+func (p *Pool) Contains(ip string) bool {
+	addr := net.ParseIP(ip)
+	return addr != nil && p.network.Contains(addr)
+}
+
+// Usable reports whether ip is an address of the pool clients can be given.
+func (p *Pool) Usable(ip string) bool {
+	addr := net.ParseIP(ip).To4()
+	if addr == nil {
+		return false
+	}
+	n := binary.BigEndian.Uint32(addr)
+	return n >= p.first && n <= p.last
+}
+
+// Next returns the lowest usable address of the pool not in used.
+func (p *Pool) Next(used map[string]bool) (string, error) {
+	addr := make(net.IP, 4)
+	for n := p.first; n <= p.last && n >= p.first; n++ {
+		binary.BigEndian.PutUint32(addr, n)
+		if !used[addr.String()] {
+			return addr.String(), nil
+		}
+	}
+	return "", ErrPoolExhausted
+}
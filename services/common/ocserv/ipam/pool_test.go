@@ -0,0 +1,43 @@
+package ipam
+
+import "testing"
+
+func TestPool(t *testing.T) {
+	pool, err := NewPool("192.168.1.0/255.255.255.248")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.String() != "192.168.1.0/29" || pool.Size() != 5 {
+		t.Errorf("pool = %s with %d addresses, want 192.168.1.0/29 with 5", pool, pool.Size())
+	}
+
+	for ip, usable := range map[string]bool{
+		"192.168.1.0": false, // network
+		"192.168.1.1": false, // ocserv
+		"192.168.1.2": true,
+		"192.168.1.6": true,
+		"192.168.1.7": false, // broadcast
+		"192.168.2.2": false,
+	} {
+		if pool.Usable(ip) != usable {
+			t.Errorf("Usable(%s) = %v, want %v", ip, !usable, usable)
+		}
+	}
+
+	next, err := pool.Next(map[string]bool{"192.168.1.2": true, "192.168.1.3": true})
+	if err != nil || next != "192.168.1.4" {
+		t.Errorf("Next() = %q, %v, want 192.168.1.4", next, err)
+	}
+
+	used := map[string]bool{}
+	for _, ip := range []string{"192.168.1.2", "192.168.1.3", "192.168.1.4", "192.168.1.5", "192.168.1.6"} {
+		used[ip] = true
+	}
+	if _, err = pool.Next(used); err != ErrPoolExhausted {
+		t.Errorf("Next() on a full pool = %v, want ErrPoolExhausted", err)
+	}
+
+	if _, err = NewPool("10.0.0.0/31"); err == nil {
+		t.Error("NewPool(/31) = nil error, want too small")
+	}
+}
@@ -0,0 +1,173 @@
+package ipban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// UnbannedBySystem is recorded as UnbannedBy when a ban expires.
+const UnbannedBySystem = "system"
+
+var (
+	ErrAlreadyBanned = errors.New("ip is already banned")
+	ErrNotBanned     = errors.New("ban not found or no longer active")
+)
+
+// BanRequest describes a new ban. A nil ExpiresAt bans until unbanned.
+type BanRequest struct {
+	IP        string
+	Reason    string
+	Source    string
+	Owner     string
+	ExpiresAt *time.Time
+}
+
+// Manager keeps the firewall of a node in line with the bans of that node
+// in the ocserv_ip_bans table, which is the source of truth: bans are
+// recorded first and re-applied by Reapply whenever the firewall lost them,
+// e.g. after an ocserv restart.
+type Manager struct {
+	db    *gorm.DB
+	node  string
+	fw    firewall.Firewall
+	occtl occtl.OcservOcctlIPBans
+}
+
+// NewManager returns the Manager of the bans of node, applied through fw.
+// oc, when not nil, is used to also clear the ocserv ban score of unbanned
+// addresses.
+func NewManager(db *gorm.DB, node string, fw firewall.Firewall, oc occtl.OcservOcctlIPBans) *Manager {
+	return &Manager{db: db, node: models.NodeName(node), fw: fw, occtl: oc}
+}
+
+// Ban records and applies a ban.
+func (m *Manager) Ban(ctx context.Context, req BanRequest) (*models.OcservIPBan, error) {
+	cidr, err := firewall.NormalizeCIDR(req.IP)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+	if req.Source == "" {
+		req.Source = models.IPBanSourceManual
+	}
+
+	if _, err = m.expire(ctx); err != nil {
+		return nil, err
+	}
+
+	ban := models.OcservIPBan{
+		IP:        cidr,
+		Reason:    req.Reason,
+		Source:    req.Source,
+		Owner:     req.Owner,
+		Node:      m.node,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var active int64
+		if err := tx.Model(&models.OcservIPBan{}).
+			Where("ip = ? AND node = ? AND unbanned_at IS NULL", cidr, m.node).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrAlreadyBanned
+		}
+		return tx.Create(&ban).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.fw.Ban(cidr); err != nil {
+		logger.Warn("Failed to apply ban of %s, it will be retried on the next re-apply: %v", cidr, err)
+	}
+	return &ban, nil
+}
+
+// Unban ends the active ban id of the node, keeping it as history.
+func (m *Manager) Unban(ctx context.Context, id uint, actor, reason string) (*models.OcservIPBan, error) {
+	var bans []models.OcservIPBan
+
+	result := m.db.WithContext(ctx).Raw(`
+		UPDATE ocserv_ip_bans
+		SET unbanned_at = ?, unbanned_by = ?, unban_reason = ?
+		WHERE id = ? AND node = ? AND unbanned_at IS NULL
+		RETURNING *
+	`, time.Now(), actor, reason, id, m.node).Scan(&bans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(bans) == 0 {
+		return nil, ErrNotBanned
+	}
+	ban := bans[0]
+
+	if err := m.fw.Unban(ban.IP); err != nil {
+		logger.Warn("Failed to remove ban of %s, it will be retried on the next re-apply: %v", ban.IP, err)
+	}
+
+	// ocserv keeps its own score based bans; clear them too so the address
+	// is not rejected right away.
+	if prefix, err := netip.ParsePrefix(ban.IP); err == nil && prefix.IsSingleIP() && m.occtl != nil {
+		if _, err = m.occtl.UnbanIP(prefix.Addr().String()); err != nil {
+			logger.Warn("Failed to clear ocserv ban score of %s: %v", ban.IP, err)
+		}
+	}
+	return &ban, nil
+}
+
+// Active returns the bans of the node in force.
+func (m *Manager) Active(ctx context.Context) ([]models.OcservIPBan, error) {
+	var bans []models.OcservIPBan
+	err := m.db.WithContext(ctx).
+		Where("node = ? AND unbanned_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", m.node, time.Now()).
+		Order("id ASC").
+		Find(&bans).Error
+	return bans, err
+}
+
+// expire closes the bans of the node whose expiry passed.
+func (m *Manager) expire(ctx context.Context) (int64, error) {
+	result := m.db.WithContext(ctx).Exec(`
+		UPDATE ocserv_ip_bans
+		SET unbanned_at = expires_at, unbanned_by = ?, unban_reason = 'expired'
+		WHERE node = ? AND unbanned_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?
+	`, UnbannedBySystem, m.node, time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// Reapply expires outdated bans and makes the firewall hold exactly the
+// active ones.
+func (m *Manager) Reapply(ctx context.Context) error {
+	expired, err := m.expire(ctx)
+	if err != nil {
+		return fmt.Errorf("expire bans: %w", err)
+	}
+	if expired > 0 {
+		logger.Info("Expired %d ip bans of node %s", expired, m.node)
+	}
+
+	bans, err := m.Active(ctx)
+	if err != nil {
+		return fmt.Errorf("list active bans: %w", err)
+	}
+
+	cidrs := make([]string, 0, len(bans))
+	for _, b := range bans {
+		cidrs = append(cidrs, b.IP)
+	}
+	return m.fw.Sync(cidrs)
+}
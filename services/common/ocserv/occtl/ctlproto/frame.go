@@ -0,0 +1,90 @@
+package ctlproto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Command identifies a control request or reply. Values are those of the
+// CTL_CMD_* enum in src/ctl.h of ocserv 1.3.0, where requests and replies
+// share one sequence. CTL_CMD_STOP (3) and CTL_CMD_TOP are left out on
+// purpose: the dashboard never stops ocserv through the socket.
+type Command uint8
+
+const (
+	CmdStatus            Command = 1
+	CmdReload            Command = 2
+	CmdList              Command = 4
+	CmdListRep           Command = 5
+	CmdUserInfo          Command = 7
+	CmdDisconnectName    Command = 9
+	CmdDisconnectID      Command = 10
+	CmdStatusRep         Command = 11
+	CmdReloadRep         Command = 12
+	CmdDisconnectNameRep Command = 14
+	CmdDisconnectIDRep   Command = 15
+	CmdIDInfo            Command = 16
+	CmdListBanned        Command = 18
+	CmdListBannedRep     Command = 19
+	CmdUnbanIP           Command = 20
+	CmdUnbanIPRep        Command = 21
+)
+
+// replies maps each request to the reply ocserv answers it with. ocserv
+// answers the user and session info requests with a user list, like
+// CTL_CMD_LIST.
+var replies = map[Command]Command{
+	CmdStatus:         CmdStatusRep,
+	CmdReload:         CmdReloadRep,
+	CmdList:           CmdListRep,
+	CmdUserInfo:       CmdListRep,
+	CmdIDInfo:         CmdListRep,
+	CmdDisconnectName: CmdDisconnectNameRep,
+	CmdDisconnectID:   CmdDisconnectIDRep,
+	CmdListBanned:     CmdListBannedRep,
+	CmdUnbanIP:        CmdUnbanIPRep,
+}
+
+// Reply returns the reply command ocserv answers a request with, and false
+// for a command that is not a request the client may send.
+func (c Command) Reply() (Command, bool) {
+	rep, ok := replies[c]
+	return rep, ok
+}
+
+// maxFrameSize guards against allocating huge buffers when the peer is
+// not ocserv or the stream is out of sync.
+const maxFrameSize = 16 << 20
+
+// WriteFrame writes a single frame: one command byte, the payload length as
+// a 32-bit integer in host byte order (little-endian on every platform
+// ocserv is built for) and the payload itself.
+func WriteFrame(w io.Writer, cmd Command, payload []byte) error {
+	header := make([]byte, 5, 5+len(payload))
+	header[0] = byte(cmd)
+	binary.LittleEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// ReadFrame reads a single frame written by WriteFrame.
+func ReadFrame(r io.Reader) (Command, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("ctlproto: frame too large (%d bytes)", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return Command(header[0]), payload, nil
+}
@@ -0,0 +1,436 @@
+package ctlproto
+
+// Field numbers below follow the message definitions in ocserv's
+// src/ctl.proto. Fields added by newer ocserv releases are simply skipped
+// by Decode, and fields missing in older releases keep their zero value.
+
+// BoolMsg is the generic success/failure reply (bool_msg).
+type BoolMsg struct {
+	Status bool
+}
+
+func (m *BoolMsg) Marshal() []byte {
+	var e Encoder
+	e.Bool(1, m.Status)
+	return e.Bytes()
+}
+
+func (m *BoolMsg) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		if f.Number == 1 {
+			m.Status = f.Bool()
+		}
+		return nil
+	})
+}
+
+// UsernameReq selects a user by name (username_req).
+type UsernameReq struct {
+	Username string
+}
+
+func (m *UsernameReq) Marshal() []byte {
+	var e Encoder
+	e.String(1, m.Username)
+	return e.Bytes()
+}
+
+func (m *UsernameReq) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		if f.Number == 1 {
+			m.Username = f.String()
+		}
+		return nil
+	})
+}
+
+// IDReq selects a session by its numeric ID (id_req).
+type IDReq struct {
+	ID uint32
+}
+
+func (m *IDReq) Marshal() []byte {
+	var e Encoder
+	e.Uint(1, uint64(m.ID))
+	return e.Bytes()
+}
+
+func (m *IDReq) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		if f.Number == 1 {
+			m.ID = uint32(f.Varint)
+		}
+		return nil
+	})
+}
+
+// UnbanReq removes an IP address from the ban list (unban_req).
+type UnbanReq struct {
+	IP string
+}
+
+func (m *UnbanReq) Marshal() []byte {
+	var e Encoder
+	e.String(1, m.IP)
+	return e.Bytes()
+}
+
+func (m *UnbanReq) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		if f.Number == 1 {
+			m.IP = f.String()
+		}
+		return nil
+	})
+}
+
+// StatusRep is the reply to CmdStatus (status_rep).
+type StatusRep struct {
+	Status              bool
+	PID                 uint32
+	SecModPID           uint32
+	ActiveClients       uint32
+	StartTime           uint32
+	StoredTLSSessions   uint32
+	BannedIPs           uint32
+	SecModClientEntries uint32
+	SessionTimeouts     uint32
+	SessionIdleTimeouts uint32
+	SessionErrors       uint32
+	SessionsHandled     uint32
+	KBytesIn            uint64
+	KBytesOut           uint64
+	LastReset           uint32
+	MinMTU              uint32
+	MaxMTU              uint32
+	AvgAuthTime         uint32
+	AvgSessionMins      uint32
+	MaxAuthTime         uint32
+	MaxSessionMins      uint32
+	AuthFailures        uint32
+	TotalAuthFailures   uint64
+	TotalSessionsClosed uint64
+	LatencyMedianTotal  uint64
+	LatencyRMSTotal     uint64
+	LatencySampleCount  uint64
+}
+
+func (m *StatusRep) Marshal() []byte {
+	var e Encoder
+	e.Bool(1, m.Status)
+	e.Uint(2, uint64(m.PID))
+	e.Uint(3, uint64(m.SecModPID))
+	e.Uint(4, uint64(m.ActiveClients))
+	e.Uint(5, uint64(m.StartTime))
+	e.Uint(6, uint64(m.StoredTLSSessions))
+	e.Uint(7, uint64(m.BannedIPs))
+	e.Uint(8, uint64(m.SecModClientEntries))
+	e.Uint(9, uint64(m.SessionTimeouts))
+	e.Uint(10, uint64(m.SessionIdleTimeouts))
+	e.Uint(11, uint64(m.SessionErrors))
+	e.Uint(12, uint64(m.SessionsHandled))
+	e.Uint(13, m.KBytesIn)
+	e.Uint(14, m.KBytesOut)
+	e.Uint(15, uint64(m.LastReset))
+	e.Uint(16, uint64(m.MinMTU))
+	e.Uint(17, uint64(m.MaxMTU))
+	e.Uint(18, uint64(m.AvgAuthTime))
+	e.Uint(19, uint64(m.AvgSessionMins))
+	e.Uint(20, uint64(m.MaxAuthTime))
+	e.Uint(21, uint64(m.MaxSessionMins))
+	e.Uint(22, uint64(m.AuthFailures))
+	e.Uint(23, m.TotalAuthFailures)
+	e.Uint(24, m.TotalSessionsClosed)
+	e.Uint(25, m.LatencyMedianTotal)
+	e.Uint(26, m.LatencyRMSTotal)
+	e.Uint(27, m.LatencySampleCount)
+	return e.Bytes()
+}
+
+func (m *StatusRep) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		switch f.Number {
+		case 1:
+			m.Status = f.Bool()
+		case 2:
+			m.PID = uint32(f.Varint)
+		case 3:
+			m.SecModPID = uint32(f.Varint)
+		case 4:
+			m.ActiveClients = uint32(f.Varint)
+		case 5:
+			m.StartTime = uint32(f.Varint)
+		case 6:
+			m.StoredTLSSessions = uint32(f.Varint)
+		case 7:
+			m.BannedIPs = uint32(f.Varint)
+		case 8:
+			m.SecModClientEntries = uint32(f.Varint)
+		case 9:
+			m.SessionTimeouts = uint32(f.Varint)
+		case 10:
+			m.SessionIdleTimeouts = uint32(f.Varint)
+		case 11:
+			m.SessionErrors = uint32(f.Varint)
+		case 12:
+			m.SessionsHandled = uint32(f.Varint)
+		case 13:
+			m.KBytesIn = f.Varint
+		case 14:
+			m.KBytesOut = f.Varint
+		case 15:
+			m.LastReset = uint32(f.Varint)
+		case 16:
+			m.MinMTU = uint32(f.Varint)
+		case 17:
+			m.MaxMTU = uint32(f.Varint)
+		case 18:
+			m.AvgAuthTime = uint32(f.Varint)
+		case 19:
+			m.AvgSessionMins = uint32(f.Varint)
+		case 20:
+			m.MaxAuthTime = uint32(f.Varint)
+		case 21:
+			m.MaxSessionMins = uint32(f.Varint)
+		case 22:
+			m.AuthFailures = uint32(f.Varint)
+		case 23:
+			m.TotalAuthFailures = f.Varint
+		case 24:
+			m.TotalSessionsClosed = f.Varint
+		case 25:
+			m.LatencyMedianTotal = f.Varint
+		case 26:
+			m.LatencyRMSTotal = f.Varint
+		case 27:
+			m.LatencySampleCount = f.Varint
+		}
+		return nil
+	})
+}
+
+// UserInfo describes one connected session (user_info_rep).
+type UserInfo struct {
+	ID               uint32
+	Username         string
+	Groupname        string
+	RemoteIP         string
+	Device           string
+	LocalIP          string
+	LocalIP6         string
+	LocalDeviceIP    string
+	ConnTime         uint32
+	Hostname         string
+	UserAgent        string
+	Status           string
+	TLSCiphersuite   string
+	DTLSCiphersuite  string
+	CSTPCompression  string
+	DTLSCompression  string
+	DNS              []string
+	NBNS             []string
+	Routes           []string
+	NoRoutes         []string
+	IRoutes          []string
+	MTU              uint32
+	RestrictToRoutes bool
+	RxPerSec         uint64
+	TxPerSec         uint64
+	VHost            string
+	BytesIn          uint64
+	BytesOut         uint64
+	SessionID        string
+}
+
+func (m *UserInfo) Marshal() []byte {
+	var e Encoder
+	e.Uint(1, uint64(m.ID))
+	e.String(2, m.Username)
+	e.String(3, m.Groupname)
+	e.String(4, m.RemoteIP)
+	e.String(5, m.Device)
+	e.String(6, m.LocalIP)
+	e.String(7, m.LocalIP6)
+	e.String(8, m.LocalDeviceIP)
+	e.Uint(9, uint64(m.ConnTime))
+	e.String(10, m.Hostname)
+	e.String(11, m.UserAgent)
+	e.String(12, m.Status)
+	e.String(13, m.TLSCiphersuite)
+	e.String(14, m.DTLSCiphersuite)
+	e.String(15, m.CSTPCompression)
+	e.String(16, m.DTLSCompression)
+	for _, v := range m.DNS {
+		e.String(17, v)
+	}
+	for _, v := range m.NBNS {
+		e.String(18, v)
+	}
+	for _, v := range m.Routes {
+		e.String(19, v)
+	}
+	for _, v := range m.NoRoutes {
+		e.String(20, v)
+	}
+	for _, v := range m.IRoutes {
+		e.String(21, v)
+	}
+	e.Uint(22, uint64(m.MTU))
+	e.Bool(23, m.RestrictToRoutes)
+	e.Uint(24, m.RxPerSec)
+	e.Uint(25, m.TxPerSec)
+	e.String(26, m.VHost)
+	e.Uint(27, m.BytesIn)
+	e.Uint(28, m.BytesOut)
+	e.String(29, m.SessionID)
+	return e.Bytes()
+}
+
+func (m *UserInfo) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		switch f.Number {
+		case 1:
+			m.ID = uint32(f.Varint)
+		case 2:
+			m.Username = f.String()
+		case 3:
+			m.Groupname = f.String()
+		case 4:
+			m.RemoteIP = f.String()
+		case 5:
+			m.Device = f.String()
+		case 6:
+			m.LocalIP = f.String()
+		case 7:
+			m.LocalIP6 = f.String()
+		case 8:
+			m.LocalDeviceIP = f.String()
+		case 9:
+			m.ConnTime = uint32(f.Varint)
+		case 10:
+			m.Hostname = f.String()
+		case 11:
+			m.UserAgent = f.String()
+		case 12:
+			m.Status = f.String()
+		case 13:
+			m.TLSCiphersuite = f.String()
+		case 14:
+			m.DTLSCiphersuite = f.String()
+		case 15:
+			m.CSTPCompression = f.String()
+		case 16:
+			m.DTLSCompression = f.String()
+		case 17:
+			m.DNS = append(m.DNS, f.String())
+		case 18:
+			m.NBNS = append(m.NBNS, f.String())
+		case 19:
+			m.Routes = append(m.Routes, f.String())
+		case 20:
+			m.NoRoutes = append(m.NoRoutes, f.String())
+		case 21:
+			m.IRoutes = append(m.IRoutes, f.String())
+		case 22:
+			m.MTU = uint32(f.Varint)
+		case 23:
+			m.RestrictToRoutes = f.Bool()
+		case 24:
+			m.RxPerSec = f.Varint
+		case 25:
+			m.TxPerSec = f.Varint
+		case 26:
+			m.VHost = f.String()
+		case 27:
+			m.BytesIn = f.Varint
+		case 28:
+			m.BytesOut = f.Varint
+		case 29:
+			m.SessionID = f.String()
+		}
+		return nil
+	})
+}
+
+// UserListRep is the reply to CmdList, CmdUserInfo and CmdIDInfo (user_list_rep).
+type UserListRep struct {
+	Users []UserInfo
+}
+
+func (m *UserListRep) Marshal() []byte {
+	var e Encoder
+	for i := range m.Users {
+		e.BytesField(1, m.Users[i].Marshal())
+	}
+	return e.Bytes()
+}
+
+func (m *UserListRep) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		if f.Number != 1 {
+			return nil
+		}
+		var u UserInfo
+		if err := u.Unmarshal(f.Data); err != nil {
+			return err
+		}
+		m.Users = append(m.Users, u)
+		return nil
+	})
+}
+
+// BanInfo is a single entry of the ban list (ban_info_rep).
+type BanInfo struct {
+	IP      string
+	Score   uint32
+	Expires uint32
+}
+
+func (m *BanInfo) Marshal() []byte {
+	var e Encoder
+	e.String(1, m.IP)
+	e.Uint(2, uint64(m.Score))
+	e.Uint(3, uint64(m.Expires))
+	return e.Bytes()
+}
+
+func (m *BanInfo) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		switch f.Number {
+		case 1:
+			m.IP = f.String()
+		case 2:
+			m.Score = uint32(f.Varint)
+		case 3:
+			m.Expires = uint32(f.Varint)
+		}
+		return nil
+	})
+}
+
+// BanListRep is the reply to CmdListBanned (ban_list_rep).
+type BanListRep struct {
+	Bans []BanInfo
+}
+
+func (m *BanListRep) Marshal() []byte {
+	var e Encoder
+	for i := range m.Bans {
+		e.BytesField(1, m.Bans[i].Marshal())
+	}
+	return e.Bytes()
+}
+
+func (m *BanListRep) Unmarshal(data []byte) error {
+	return Decode(data, func(f Field) error {
+		if f.Number != 1 {
+			return nil
+		}
+		var b BanInfo
+		if err := b.Unmarshal(f.Data); err != nil {
+			return err
+		}
+		m.Bans = append(m.Bans, b)
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+// Package ctlproto implements the control protocol spoken by ocserv's main
+// process on its occtl unix socket (occtl-socket-file). Every request and
+// reply is a small frame carrying a command byte followed by a protobuf
+// (proto2) encoded message as defined in ocserv's src/ctl.proto.
+//
+// Only the subset of wire types used by ctl.proto is supported, which keeps
+// the package free of generated code and external protobuf dependencies.
+package ctlproto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("ctlproto: truncated message")
+
+// Encoder appends protobuf fields to an internal buffer.
+type Encoder struct {
+	buf []byte
+}
+
+// Bytes returns the encoded message.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+func (e *Encoder) key(field int, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+// Uint writes an unsigned varint field (uint32, uint64).
+func (e *Encoder) Uint(field int, v uint64) {
+	e.key(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+// Sint writes a zigzag encoded varint field (sint32, sint64).
+func (e *Encoder) Sint(field int, v int64) {
+	e.key(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, uint64(v<<1)^uint64(v>>63))
+}
+
+// Bool writes a bool field.
+func (e *Encoder) Bool(field int, v bool) {
+	var n uint64
+	if v {
+		n = 1
+	}
+	e.Uint(field, n)
+}
+
+// BytesField writes a length-delimited field (string, bytes, nested message).
+func (e *Encoder) BytesField(field int, v []byte) {
+	e.key(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// String writes a string field.
+func (e *Encoder) String(field int, v string) {
+	e.BytesField(field, []byte(v))
+}
+
+// Field is a single decoded protobuf field. Varint holds the value of
+// varint and fixed fields, Data holds the payload of length-delimited ones.
+type Field struct {
+	Number   int
+	WireType int
+	Varint   uint64
+	Data     []byte
+}
+
+// String returns the payload of a length-delimited field as a string.
+func (f Field) String() string {
+	return string(f.Data)
+}
+
+// Bool returns the value of a varint field as a bool.
+func (f Field) Bool() bool {
+	return f.Varint != 0
+}
+
+// Sint returns the zigzag decoded value of a varint field.
+func (f Field) Sint() int64 {
+	return int64(f.Varint>>1) ^ -int64(f.Varint&1)
+}
+
+// Decode walks every field of a protobuf message and calls fn for each one.
+// Unknown fields are passed through as well so callers can simply ignore
+// what they do not understand, which keeps older clients working against
+// newer ocserv releases.
+func Decode(data []byte, fn func(Field) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+
+		f := Field{Number: int(key >> 3), WireType: int(key & 7)}
+
+		switch f.WireType {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+			f.Varint = v
+			data = data[n:]
+
+		case wireFixed64:
+			if len(data) < 8 {
+				return errTruncated
+			}
+			f.Varint = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+
+		case wireFixed32:
+			if len(data) < 4 {
+				return errTruncated
+			}
+			f.Varint = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errTruncated
+			}
+			f.Data = data[n : n+int(size)]
+			data = data[n+int(size):]
+
+		default:
+			return fmt.Errorf("ctlproto: unsupported wire type %d for field %d", f.WireType, f.Number)
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package occtl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/eventbus"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+)
+
+const (
+	// eventRestartDelay is how long WatchEvents waits before resubscribing
+	// after the event stream ended. The delay doubles up to
+	// eventMaxRestartDelay while occtl keeps failing, e.g. when ocserv is
+	// down, and is reset once a subscription stayed up for eventStableAfter
+	// or delivered an event.
+	eventRestartDelay    = 5 * time.Second
+	eventMaxRestartDelay = 5 * time.Minute
+	eventStableAfter     = time.Minute
+)
+
+// SubscribeEvents streams connect and disconnect events until ctx is done
+// or occtl exits, at which point the returned channel is closed.
+// Executes: occtl -j show events
+func (o *OcservOcctl) SubscribeEvents(ctx context.Context) (<-chan models.OcctlEvent, error) {
+	if err := o.require(CapShowEvents); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, occtlExec, "-j", "show", "events")
+
+	// occtl quits on 'q' or EOF on stdin, so keep the pipe open for the
+	// lifetime of the subscription.
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	events := make(chan models.OcctlEvent, 32)
+	go func() {
+		defer close(events)
+		defer func() {
+			_ = stdin.Close()
+			_ = cmd.Wait()
+		}()
+
+		err := ParseEvents(stdout, func(event models.OcctlEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("occtl event stream error: %v", err)
+		}
+	}()
+
+	return events, nil
+}
+
+// SubscribeEvents uses the occtl binary, see OcservOcctl.SubscribeEvents.
+func (o *OcservOcctlSocket) SubscribeEvents(ctx context.Context) (<-chan models.OcctlEvent, error) {
+	return o.fallback.SubscribeEvents(ctx)
+}
+
+// WatchEvents keeps an event subscription of the ocserv of node open until
+// ctx is done and publishes every event, tagged with node, on bus under
+// eventbus.TopicOcctlEvent. The
+// subscription is re-established whenever occtl exits, with a growing delay
+// while it keeps failing. Only the first failure of a streak is logged.
+func WatchEvents(ctx context.Context, node string, client OcservOcctlEvents, bus *eventbus.Bus) {
+	delay := time.Duration(0)
+	failing := false
+	for {
+		started := time.Now()
+		received := false
+
+		events, err := client.SubscribeEvents(ctx)
+		if err == nil {
+			for event := range events {
+				received = true
+				event.Node = node
+				bus.Publish(eventbus.TopicOcctlEvent, event)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		healthy := received || time.Since(started) >= eventStableAfter
+		switch {
+		case healthy && failing:
+			logger.Info("occtl event subscription of node %s restored", node)
+			failing = false
+		case !healthy && !failing:
+			if err != nil {
+				logger.Warn("occtl event subscription of node %s failed, retrying with backoff: %v", node, err)
+			} else {
+				logger.Warn("occtl event stream of node %s ended, retrying with backoff", node)
+			}
+			failing = true
+		}
+		delay = nextEventRestartDelay(delay, healthy)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// nextEventRestartDelay returns the delay before the next subscription
+// attempt, see eventRestartDelay.
+func nextEventRestartDelay(delay time.Duration, healthy bool) time.Duration {
+	if healthy || delay < eventRestartDelay {
+		return eventRestartDelay
+	}
+	return min(delay*2, eventMaxRestartDelay)
+}
+
+// ParseEvents reads the output of `occtl -j show events` and calls fn for
+// every complete JSON object. Banner lines and other text between objects
+// are ignored.
+func ParseEvents(r io.Reader, fn func(models.OcctlEvent)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		buf      strings.Builder
+		depth    int
+		inString bool
+		escaped  bool
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		for i := 0; i < len(line); i++ {
+			ch := line[i]
+			if depth == 0 && ch != '{' {
+				continue
+			}
+			buf.WriteByte(ch)
+
+			switch {
+			case escaped:
+				escaped = false
+			case inString && ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = !inString
+			case inString:
+			case ch == '{':
+				depth++
+			case ch == '}':
+				depth--
+				if depth == 0 {
+					var raw map[string]interface{}
+					if err := json.Unmarshal([]byte(buf.String()), &raw); err == nil {
+						fn(eventFromMap(raw, time.Now()))
+					}
+					buf.Reset()
+				}
+			}
+		}
+		if depth > 0 {
+			buf.WriteByte('\n')
+		}
+	}
+	return scanner.Err()
+}
+
+func eventFromMap(raw map[string]interface{}, now time.Time) models.OcctlEvent {
+	str := func(key string) string {
+		switch v := raw[key].(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+
+	event := models.OcctlEvent{
+		Type:      models.OcctlEventConnect,
+		Username:  str("Username"),
+		Group:     str("Groupname"),
+		VHost:     str("vhost"),
+		Device:    str("Device"),
+		RemoteIP:  str("Remote IP"),
+		IPv4:      str("IPv4"),
+		UserAgent: str("User-Agent"),
+		RX:        str("RX"),
+		TX:        str("TX"),
+		Time:      now,
+	}
+	if id, ok := raw["ID"].(float64); ok {
+		event.ID = int(id)
+	}
+
+	// The reason key differs between occtl releases ("Disconnect reason",
+	// "Discon reason"), so match on the prefix.
+	for key, value := range raw {
+		if strings.HasPrefix(strings.ToLower(key), "discon") {
+			event.Type = models.OcctlEventDisconnect
+			if reason, ok := value.(string); ok {
+				event.Reason = reason
+			}
+		}
+	}
+	return event
+}
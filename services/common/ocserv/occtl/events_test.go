@@ -0,0 +1,110 @@
+package occtl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/eventbus"
+)
+
+const eventStream = `Press 'q' or CTRL+C to quit
+{
+  "ID" : 1205,
+  "Username" : "alice",
+  "Groupname" : "staff",
+  "vhost" : "default",
+  "Device" : "vpns0",
+  "Remote IP" : "198.51.100.7",
+  "IPv4" : "172.16.24.10",
+  "User-Agent" : "AnyConnect {Linux}",
+  "RX" : "0 bytes",
+  "TX" : "0 bytes"
+}
+{ "ID" : 1205, "Username" : "alice", "Disconnect reason" : "user disconnected", "RX" : "1.2 MB" }
+`
+
+func TestParseEvents(t *testing.T) {
+	var events []models.OcctlEvent
+	err := ParseEvents(strings.NewReader(eventStream), func(e models.OcctlEvent) {
+		events = append(events, e)
+	})
+	if err != nil {
+		t.Fatalf("ParseEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	connect := events[0]
+	if connect.Type != models.OcctlEventConnect || connect.ID != 1205 || connect.Username != "alice" ||
+		connect.Group != "staff" || connect.RemoteIP != "198.51.100.7" || connect.IPv4 != "172.16.24.10" {
+		t.Errorf("unexpected connect event: %+v", connect)
+	}
+	if connect.UserAgent != "AnyConnect {Linux}" {
+		t.Errorf("braces inside strings must not split objects, got %q", connect.UserAgent)
+	}
+
+	disconnect := events[1]
+	if disconnect.Type != models.OcctlEventDisconnect || disconnect.Reason != "user disconnected" || disconnect.RX != "1.2 MB" {
+		t.Errorf("unexpected disconnect event: %+v", disconnect)
+	}
+}
+
+func TestNextEventRestartDelay(t *testing.T) {
+	delay := time.Duration(0)
+	var delays []time.Duration
+	for i := 0; i < 9; i++ {
+		delay = nextEventRestartDelay(delay, false)
+		delays = append(delays, delay)
+	}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second,
+		160 * time.Second, 5 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("unexpected delays while failing: %v", delays)
+		}
+	}
+
+	if got := nextEventRestartDelay(delay, true); got != eventRestartDelay {
+		t.Errorf("expected the delay to reset after a healthy subscription, got %v", got)
+	}
+}
+
+// eventsClient delivers events once, then keeps the stream open until ctx
+// is done.
+type eventsClient []models.OcctlEvent
+
+func (c eventsClient) SubscribeEvents(ctx context.Context) (<-chan models.OcctlEvent, error) {
+	events := make(chan models.OcctlEvent, len(c))
+	for _, event := range c {
+		events <- event
+	}
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events, nil
+}
+
+func TestWatchEventsTagsNode(t *testing.T) {
+	bus := eventbus.New()
+	sub := bus.Subscribe(4, eventbus.TopicOcctlEvent)
+	defer sub.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchEvents(ctx, "edge-1", eventsClient{{Type: models.OcctlEventConnect, Username: "alice"}}, bus)
+
+	select {
+	case e := <-sub.C:
+		event := e.Payload.(models.OcctlEvent)
+		if event.Node != "edge-1" || event.Username != "alice" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event published")
+	}
+}
@@ -0,0 +1,180 @@
+package occtl
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl/ctlproto"
+)
+
+// occtlTimeLayout matches the timestamps printed by `occtl -j`.
+const occtlTimeLayout = "2006-01-02 15:04"
+
+// formatDuration renders an interval the way occtl does ("5m:10s", "3h:12m", "2days 4h").
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	secs := int64(d / time.Second)
+
+	switch {
+	case secs < 60:
+		return fmt.Sprintf("%ds", secs)
+	case secs < 3600:
+		return fmt.Sprintf("%dm:%02ds", secs/60, secs%60)
+	case secs < 86400:
+		return fmt.Sprintf("%dh:%02dm", secs/3600, (secs%3600)/60)
+	default:
+		return fmt.Sprintf("%ddays %dh", secs/86400, (secs%86400)/3600)
+	}
+}
+
+// formatBytes renders a byte count the way occtl does ("512 bytes", "1.2 KB").
+func formatBytes(n uint64) string {
+	units := []string{"KB", "MB", "GB", "TB"}
+	if n < 1000 {
+		return fmt.Sprintf("%d bytes", n)
+	}
+
+	v := float64(n) / 1000
+	unit := units[0]
+	for _, u := range units[1:] {
+		if v < 1000 {
+			break
+		}
+		v /= 1000
+		unit = u
+	}
+	return fmt.Sprintf("%.1f %s", v, unit)
+}
+
+func toOnlineUserSession(u *ctlproto.UserInfo) models.OnlineUserSession {
+	session := models.OnlineUserSession{
+		ID:        int(u.ID),
+		Username:  u.Username,
+		Group:     u.Groupname,
+		AverageRX: formatBytes(u.RxPerSec) + "/sec",
+		AverageTX: formatBytes(u.TxPerSec) + "/sec",
+		IPv4:      u.LocalIP,
+		VHost:     u.VHost,
+		Device:    u.Device,
+	}
+	if u.VHost == "" {
+		session.VHost = "default"
+	}
+	if u.ConnTime > 0 {
+		connected := time.Unix(int64(u.ConnTime), 0)
+		session.SessionStartedAt = connected.Format(occtlTimeLayout)
+		session.LastConnectedAt = formatDuration(time.Since(connected))
+	}
+	return session
+}
+
+func toUserDetail(u *ctlproto.UserInfo) models.OcctlUserDetail {
+	detail := models.OcctlUserDetail{
+		ID:                 int(u.ID),
+		Username:           u.Username,
+		Groupname:          u.Groupname,
+		State:              u.Status,
+		VHost:              u.VHost,
+		Device:             u.Device,
+		MTU:                int(u.MTU),
+		RemoteIP:           u.RemoteIP,
+		LocalDeviceIP:      u.LocalDeviceIP,
+		IPv4:               u.LocalIP,
+		IPv6:               u.LocalIP6,
+		UserAgent:          u.UserAgent,
+		Hostname:           u.Hostname,
+		RX:                 int64(u.BytesIn),
+		TX:                 int64(u.BytesOut),
+		RXHuman:            formatBytes(u.BytesIn),
+		TXHuman:            formatBytes(u.BytesOut),
+		AverageRX:          formatBytes(u.RxPerSec) + "/sec",
+		AverageTX:          formatBytes(u.TxPerSec) + "/sec",
+		Session:            u.SessionID,
+		TLSCiphersuite:     u.TLSCiphersuite,
+		DTLSCipher:         u.DTLSCiphersuite,
+		CSTPCompression:    u.CSTPCompression,
+		DTLSCompression:    u.DTLSCompression,
+		DNS:                u.DNS,
+		NBNS:               u.NBNS,
+		Routes:             u.Routes,
+		NoRoutes:           u.NoRoutes,
+		IRoutes:            u.IRoutes,
+		RestrictedToRoutes: u.RestrictToRoutes,
+	}
+	if u.VHost == "" {
+		detail.VHost = "default"
+	}
+	if u.ConnTime > 0 {
+		connected := time.Unix(int64(u.ConnTime), 0)
+		detail.ConnectedAt = connected.Format(occtlTimeLayout)
+		detail.ConnectedDuration = formatDuration(time.Since(connected))
+		detail.RawConnectedAt = int64(u.ConnTime)
+	}
+	return detail
+}
+
+// statusToMap converts a status reply into the flat map produced by
+// `occtl -j show status`, so consumers parsing that output keep working.
+func statusToMap(s *ctlproto.StatusRep, now time.Time) map[string]interface{} {
+	state := "offline"
+	if s.Status {
+		state = "online"
+	}
+
+	out := map[string]interface{}{
+		"Status":                        state,
+		"Server PID":                    float64(s.PID),
+		"Sec-mod PID":                   float64(s.SecModPID),
+		"Sec-mod client entries":        float64(s.SecModClientEntries),
+		"Active sessions":               float64(s.ActiveClients),
+		"Total sessions":                float64(s.TotalSessionsClosed),
+		"Total authentication failures": float64(s.TotalAuthFailures),
+		"IPs in ban list":               float64(s.BannedIPs),
+		"TLS DB entries":                float64(s.StoredTLSSessions),
+		"Sessions handled":              float64(s.SessionsHandled),
+		"Timed out sessions":            float64(s.SessionTimeouts),
+		"Timed out (idle) sessions":     float64(s.SessionIdleTimeouts),
+		"Closed due to error sessions":  float64(s.SessionErrors),
+		"Authentication failures":       float64(s.AuthFailures),
+		"Average auth time":             fmt.Sprintf("%ds", s.AvgAuthTime),
+		"Max auth time":                 fmt.Sprintf("%ds", s.MaxAuthTime),
+		"Average session time":          formatDuration(time.Duration(s.AvgSessionMins) * time.Minute),
+		"Max session time":              formatDuration(time.Duration(s.MaxSessionMins) * time.Minute),
+		"RX":                            formatBytes(s.KBytesIn * 1000),
+		"TX":                            formatBytes(s.KBytesOut * 1000),
+		"raw_rx":                        float64(s.KBytesIn * 1000),
+		"raw_tx":                        float64(s.KBytesOut * 1000),
+		"raw_avg_auth_time":             float64(s.AvgAuthTime),
+		"raw_max_auth_time":             float64(s.MaxAuthTime),
+		"raw_avg_session_time":          float64(s.AvgSessionMins * 60),
+		"raw_max_session_time":          float64(s.MaxSessionMins * 60),
+	}
+
+	if s.StartTime > 0 {
+		started := time.Unix(int64(s.StartTime), 0)
+		out["Up since"] = started.Format(occtlTimeLayout)
+		out["_Up since"] = formatDuration(now.Sub(started))
+		out["raw_up_since"] = float64(s.StartTime)
+		out["uptime"] = float64(now.Unix() - int64(s.StartTime))
+	}
+	if s.LastReset > 0 {
+		reset := time.Unix(int64(s.LastReset), 0)
+		out["Last stats reset"] = reset.Format(occtlTimeLayout)
+		out["_Last stats reset"] = formatDuration(now.Sub(reset))
+		out["raw_last_stats_reset"] = float64(s.LastReset)
+	}
+	if s.LatencySampleCount > 0 {
+		// Latency totals are accumulated in microseconds.
+		median := s.LatencyMedianTotal / s.LatencySampleCount
+		stdev := s.LatencyRMSTotal / s.LatencySampleCount
+		out["Median latency"] = strconv.FormatFloat(float64(median)/1000, 'f', 2, 64) + "ms"
+		out["STDEV latency"] = strconv.FormatFloat(float64(stdev)/1000, 'f', 2, 64) + "ms"
+		out["raw_median_latency"] = float64(median)
+		out["raw_stdev_latency"] = float64(stdev)
+	}
+	return out
+}
@@ -1,8 +1,11 @@
 package occtl
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"os/exec"
 	"strings"
@@ -15,8 +18,8 @@ type OcservOcctl struct{}
 
 type OcservOcctlUsers interface {
 	OnlineSessions() ([]models.OnlineUserSession, error)
-	ShowUser(username string) (models.OnlineUserSession, error)
-	ShowUserByID(id string) (models.OnlineUserSession, error)
+	ShowUser(username string) (*[]models.OcctlUserDetail, error)
+	ShowUserByID(id string) (*models.OcctlUserDetail, error)
 	DisconnectUser(username string) (string, error)
 	DisconnectSession(sid string) (string, error)
 	TerminateUser(username string) (string, error)
@@ -24,9 +27,9 @@ type OcservOcctlUsers interface {
 }
 
 type OcservOcctlSessions interface {
-	ShowSession(sid string) (map[string]interface{}, error)
-	ShowSessionAll() (*[]interface{}, error)
-	ShowSessionsValid() (*[]interface{}, error)
+	ShowSession(sid string) (*models.OcctlSession, error)
+	ShowSessionAll() (*[]models.OcctlSession, error)
+	ShowSessionsValid() (*[]models.OcctlSession, error)
 }
 
 type OcservOcctlIPBans interface {
@@ -35,11 +38,17 @@ type OcservOcctlIPBans interface {
 }
 
 type OcservOcctlServer interface {
-	ShowStatus(raw bool) (interface{}, error)
+	ShowStatus() (*models.OcctlServerStatus, error)
+	ShowStatusRaw() (string, error)
 	ReloadConfigs() (string, error)
 	ShowIRoutes() (*[]models.IRoute, error)
 	ShowEvent() string
 	Version() *models.ServerVersion
+	Capabilities() models.OcctlCapabilities
+}
+
+type OcservOcctlEvents interface {
+	SubscribeEvents(ctx context.Context) (<-chan models.OcctlEvent, error)
 }
 
 type OcservOcctlInterface interface {
@@ -47,10 +56,16 @@ type OcservOcctlInterface interface {
 	OcservOcctlSessions
 	OcservOcctlIPBans
 	OcservOcctlServer
+	OcservOcctlEvents
 }
 
 const occtlExec = "/usr/bin/occtl"
 
+// ErrOcservUnreachable is returned by ReloadConfigs when occtl cannot reach
+// ocserv, e.g. while it is stopped. The new config is then read at the next
+// start, so callers keep it instead of treating it as rejected.
+var ErrOcservUnreachable = errors.New("ocserv is unreachable")
+
 func NewOcservOcctl() *OcservOcctl {
 	return &OcservOcctl{}
 }
@@ -58,8 +73,7 @@ func NewOcservOcctl() *OcservOcctl {
 // OnlineSessions returns a list of currently connected user info.
 // Executes: occtl -j show users
 func (o *OcservOcctl) OnlineSessions() ([]models.OnlineUserSession, error) {
-	command := "-j show users"
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("%s %s", occtlExec, command))
+	cmd := exec.Command(occtlExec, "-j", "show", "users")
 	result, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -86,6 +100,9 @@ func (o *OcservOcctl) DisconnectUser(username string) (string, error) {
 // DisconnectSession Disconnect the specified ID.
 // Executes: occtl disconnect id <sid>
 func (o *OcservOcctl) DisconnectSession(id string) (string, error) {
+	if err := o.require(CapDisconnectID); err != nil {
+		return "", err
+	}
 	cmd := exec.Command(occtlExec, "disconnect", "id", id)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -97,6 +114,9 @@ func (o *OcservOcctl) DisconnectSession(id string) (string, error) {
 // TerminateUser disconnects a user and invalidates session cookies.
 // Executes: occtl terminate user <username>
 func (o *OcservOcctl) TerminateUser(username string) (string, error) {
+	if err := o.require(CapTerminate); err != nil {
+		return "", err
+	}
 	cmd := exec.Command(occtlExec, "terminate", "user", username)
 	out, err := cmd.CombinedOutput()
 	return string(out), err
@@ -105,6 +125,9 @@ func (o *OcservOcctl) TerminateUser(username string) (string, error) {
 // TerminateSession Disconnect ID and invalidate session cookies.
 // Executes: occtl terminate id <sid>
 func (o *OcservOcctl) TerminateSession(id string) (string, error) {
+	if err := o.require(CapTerminate); err != nil {
+		return "", err
+	}
 	cmd := exec.Command(occtlExec, "terminate", "id", id)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -119,15 +142,41 @@ func (o *OcservOcctl) ReloadConfigs() (string, error) {
 	cmd := exec.Command(occtlExec, "reload")
 	out, err := cmd.CombinedOutput()
 	if err != nil {
+		if reloadUnreachable(err, out) {
+			return "", fmt.Errorf("%w: %v", ErrOcservUnreachable, err)
+		}
 		return "", err
 	}
 	return string(out), nil
 }
 
+// occtlConnectFailure starts the line occtl prints when it cannot connect
+// to the socket of ocserv, followed by the socket path and the error.
+const occtlConnectFailure = "error connecting to sock "
+
+// reloadUnreachable reports whether a failed occtl reload never reached
+// ocserv: occtl is not installed or could not connect to the socket.
+func reloadUnreachable(err error, out []byte) bool {
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), occtlConnectFailure) {
+			return true
+		}
+	}
+	return false
+}
+
 // ShowIPBans returns the current list of IP bans with scores.
 // Executes: occtl -j show ip bans points
 func (o *OcservOcctl) ShowIPBans() (*[]models.IPBanPoints, error) {
-	cmd := exec.Command(occtlExec, "-j", "show", "ip", "bans", "points")
+	args := []string{"-j", "show", "ip", "bans", "points"}
+	if v, known, _ := detectVersion(); known && !v.Supports(CapIPBanPoints) {
+		args = args[:len(args)-1]
+	}
+
+	cmd := exec.Command(occtlExec, args...)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, err
@@ -162,27 +211,29 @@ func (o *OcservOcctl) UnbanIP(ip string) (string, error) {
 
 // ShowStatus returns the current status of ocserv.
 // Executes: occtl -j show status
-// Executes: occtl show status
-func (o *OcservOcctl) ShowStatus(raw bool) (interface{}, error) {
+func (o *OcservOcctl) ShowStatus() (*models.OcctlServerStatus, error) {
 	cmd := exec.Command(occtlExec, "-j", "show", "status")
-	if raw {
-		cmd = exec.Command(occtlExec, "show", "status")
-	}
-
 	out, err := cmd.Output()
 	if err != nil {
-		return "", err
-	}
-
-	if raw {
-		return string(out), nil
+		return nil, err
 	}
 
-	var status map[string]interface{}
+	var status models.OcctlServerStatus
 	if err = json.Unmarshal(out, &status); err != nil {
 		return nil, err
 	}
-	return status, nil
+	return &status, nil
+}
+
+// ShowStatusRaw returns the current status of ocserv as printed by occtl.
+// Executes: occtl show status
+func (o *OcservOcctl) ShowStatusRaw() (string, error) {
+	cmd := exec.Command(occtlExec, "show", "status")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
 }
 
 // ShowIRoutes returns the current iRoutes information.
@@ -190,8 +241,7 @@ func (o *OcservOcctl) ShowStatus(raw bool) (interface{}, error) {
 func (o *OcservOcctl) ShowIRoutes() (*[]models.IRoute, error) {
 	var routes []models.IRoute
 
-	version := utils.GetOcservVersion()
-	if version == "1.2.4" { // has bug on IRoute Command
+	if v, known, _ := detectVersion(); known && !v.Supports(CapShowIRoutes) {
 		return &routes, nil
 	}
 
@@ -212,92 +262,153 @@ func (o *OcservOcctl) ShowIRoutes() (*[]models.IRoute, error) {
 	return &routes, nil
 }
 
-// ShowUser returns detailed information about a specific user by username.
+// ShowUser returns detailed information about every session of a user.
 // Executes: occtl -j show user <username>
-func (o *OcservOcctl) ShowUser(username string) (models.OnlineUserSession, error) {
-	var session models.OnlineUserSession
-
+func (o *OcservOcctl) ShowUser(username string) (*[]models.OcctlUserDetail, error) {
 	cmd := exec.Command(occtlExec, "-j", "show", "user", username)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return session, err
+		return nil, err
 	}
-	if err = json.Unmarshal(out, &session); err != nil {
-		return session, err
+
+	users, err := unmarshalUserDetails(out)
+	if err != nil {
+		return nil, err
 	}
-	return session, nil
+	return &users, nil
 }
 
 // Version returns detailed information about ocserv version.
 // Executes: ocserv -v
 func (o *OcservOcctl) Version() *models.ServerVersion {
-	version := utils.GetOcservVersion()
+	v, known, version := detectVersion()
 	occtlVersion := utils.GetOCCTLVersion()
-	return &models.ServerVersion{
+
+	info := &models.ServerVersion{
 		OcservVersion: version,
 		OcctlVersion:  occtlVersion,
 	}
+	if known {
+		info.Semantic = v.String()
+	}
+	return info
+}
+
+// Capabilities returns which occtl commands the installed ocserv supports.
+func (o *OcservOcctl) Capabilities() models.OcctlCapabilities {
+	v, known, _ := detectVersion()
+	return CapabilitiesFor(v, known)
+}
+
+// require fails with a descriptive error when the installed ocserv is known
+// not to support cap.
+func (o *OcservOcctl) require(cap string) error {
+	if v, known, _ := detectVersion(); known && !v.Supports(cap) {
+		return unsupportedError(cap, v)
+	}
+	return nil
 }
 
 // ShowUserByID returns detailed information about a specific user by ID.
 // Executes: occtl -j show id <id>
-func (o *OcservOcctl) ShowUserByID(id string) (models.OnlineUserSession, error) {
-	var session models.OnlineUserSession
+func (o *OcservOcctl) ShowUserByID(id string) (*models.OcctlUserDetail, error) {
 	cmd := exec.Command(occtlExec, "-j", "show", "id", id)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return session, err
+		return nil, err
+	}
+
+	users, err := unmarshalUserDetails(out)
+	if err != nil {
+		return nil, err
 	}
-	if err = json.Unmarshal(out, &session); err != nil {
-		return session, err
+	if len(users) == 0 {
+		return nil, fmt.Errorf("session id %s not found", id)
 	}
-	return session, nil
+	return &users[0], nil
+}
+
+// unmarshalUserDetails accepts both the array printed by current occtl
+// releases and the single object printed by older ones.
+func unmarshalUserDetails(out []byte) ([]models.OcctlUserDetail, error) {
+	var users []models.OcctlUserDetail
+
+	trimmed := strings.TrimSpace(string(out))
+	switch {
+	case trimmed == "":
+		return users, nil
+	case strings.HasPrefix(trimmed, "{"):
+		var user models.OcctlUserDetail
+		if err := json.Unmarshal([]byte(trimmed), &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	default:
+		if err := json.Unmarshal([]byte(trimmed), &users); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
 }
 
 // ShowSession returns detailed information about a specific session by SID.
 // Executes: occtl -j show session <SID>
-func (o *OcservOcctl) ShowSession(sid string) (map[string]interface{}, error) {
+func (o *OcservOcctl) ShowSession(sid string) (*models.OcctlSession, error) {
+	if err := o.require(CapShowSessions); err != nil {
+		return nil, err
+	}
 	cmd := exec.Command(occtlExec, "-j", "show", "session", sid)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, err
 	}
 
-	var session map[string]interface{}
-	if err = json.Unmarshal(out, &session); err != nil {
+	trimmed := strings.TrimSpace(string(out))
+	if strings.HasPrefix(trimmed, "[") {
+		var sessions []models.OcctlSession
+		if err = json.Unmarshal([]byte(trimmed), &sessions); err != nil {
+			return nil, err
+		}
+		if len(sessions) == 0 {
+			return nil, fmt.Errorf("session %s not found", sid)
+		}
+		return &sessions[0], nil
+	}
+
+	var session models.OcctlSession
+	if err = json.Unmarshal([]byte(trimmed), &session); err != nil {
 		return nil, err
 	}
-	return session, nil
+	return &session, nil
 }
 
 // ShowSessionAll returns detailed information about all sessions.
 // Executes: occtl -j show sessions all
-func (o *OcservOcctl) ShowSessionAll() (*[]interface{}, error) {
-	var sessions []interface{}
-
-	cmd := exec.Command(occtlExec, "-j", "show", "sessions", "all")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, err
-	}
-
-	if err = json.Unmarshal(out, &sessions); err != nil {
-		return nil, err
-	}
-	return &sessions, nil
+func (o *OcservOcctl) ShowSessionAll() (*[]models.OcctlSession, error) {
+	return o.showSessions("all")
 }
 
 // ShowSessionsValid returns detailed information  about all valid sessions.
 // Executes: occtl -j show sessions valid
-func (o *OcservOcctl) ShowSessionsValid() (*[]interface{}, error) {
-	var sessions []interface{}
+func (o *OcservOcctl) ShowSessionsValid() (*[]models.OcctlSession, error) {
+	return o.showSessions("valid")
+}
 
-	cmd := exec.Command(occtlExec, "-j", "show", "sessions", "valid")
+func (o *OcservOcctl) showSessions(filter string) (*[]models.OcctlSession, error) {
+	if err := o.require(CapShowSessions); err != nil {
+		return nil, err
+	}
+	sessions := []models.OcctlSession{}
+
+	cmd := exec.Command(occtlExec, "-j", "show", "sessions", filter)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, err
 	}
 
+	if strings.TrimSpace(string(out)) == "" {
+		return &sessions, nil
+	}
 	if err = json.Unmarshal(out, &sessions); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,248 @@
+// Package occtltest provides a fake ocserv control socket for tests, in the
+// spirit of net/http/httptest.
+package occtltest
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl/ctlproto"
+)
+
+// Server answers occtl control requests from in-memory state.
+type Server struct {
+	// Path is the unix socket the server listens on.
+	Path string
+
+	listener net.Listener
+	dir      string
+	wg       sync.WaitGroup
+
+	mu           sync.Mutex
+	status       ctlproto.StatusRep
+	users        []ctlproto.UserInfo
+	bans         []ctlproto.BanInfo
+	reloads      int
+	disconnected []string
+	unbanned     []string
+}
+
+// NewServer starts a server on a socket in a fresh temporary directory.
+// Callers must call Close when finished.
+func NewServer() (*Server, error) {
+	dir, err := os.MkdirTemp("", "occtltest")
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, "occtl.socket")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	s := &Server{
+		Path:     path,
+		listener: listener,
+		dir:      dir,
+		status:   ctlproto.StatusRep{Status: true},
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the server and removes its socket.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+	_ = os.RemoveAll(s.dir)
+}
+
+// SetStatus replaces the reply returned for status requests.
+func (s *Server) SetStatus(status ctlproto.StatusRep) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// AddUser registers a connected session.
+func (s *Server) AddUser(user ctlproto.UserInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = append(s.users, user)
+}
+
+// AddBan registers a banned IP address.
+func (s *Server) AddBan(ban ctlproto.BanInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bans = append(s.bans, ban)
+}
+
+// Reloads returns how many reload requests were received.
+func (s *Server) Reloads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reloads
+}
+
+// Disconnected returns the usernames of sessions disconnected so far.
+func (s *Server) Disconnected() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.disconnected...)
+}
+
+// Unbanned returns the IP addresses unbanned so far.
+func (s *Server) Unbanned() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.unbanned...)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		cmd, payload, err := ctlproto.ReadFrame(conn)
+		if err != nil {
+			return
+		}
+
+		reply, ok := s.dispatch(cmd, payload)
+		if !ok {
+			return
+		}
+		rep, _ := cmd.Reply()
+		if err = ctlproto.WriteFrame(conn, rep, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(cmd ctlproto.Command, payload []byte) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch cmd {
+	case ctlproto.CmdStatus:
+		status := s.status
+		status.ActiveClients = uint32(len(s.users))
+		status.BannedIPs = uint32(len(s.bans))
+		return status.Marshal(), true
+
+	case ctlproto.CmdReload:
+		s.reloads++
+		return (&ctlproto.BoolMsg{Status: true}).Marshal(), true
+
+	case ctlproto.CmdList:
+		return (&ctlproto.UserListRep{Users: s.users}).Marshal(), true
+
+	case ctlproto.CmdUserInfo:
+		var req ctlproto.UsernameReq
+		if req.Unmarshal(payload) != nil {
+			return nil, false
+		}
+		return (&ctlproto.UserListRep{Users: s.filter(func(u *ctlproto.UserInfo) bool {
+			return u.Username == req.Username
+		})}).Marshal(), true
+
+	case ctlproto.CmdIDInfo:
+		var req ctlproto.IDReq
+		if req.Unmarshal(payload) != nil {
+			return nil, false
+		}
+		return (&ctlproto.UserListRep{Users: s.filter(func(u *ctlproto.UserInfo) bool {
+			return u.ID == req.ID
+		})}).Marshal(), true
+
+	case ctlproto.CmdDisconnectName:
+		var req ctlproto.UsernameReq
+		if req.Unmarshal(payload) != nil {
+			return nil, false
+		}
+		found := s.remove(func(u *ctlproto.UserInfo) bool {
+			return u.Username == req.Username
+		})
+		return (&ctlproto.BoolMsg{Status: found}).Marshal(), true
+
+	case ctlproto.CmdDisconnectID:
+		var req ctlproto.IDReq
+		if req.Unmarshal(payload) != nil {
+			return nil, false
+		}
+		found := s.remove(func(u *ctlproto.UserInfo) bool {
+			return u.ID == req.ID
+		})
+		return (&ctlproto.BoolMsg{Status: found}).Marshal(), true
+
+	case ctlproto.CmdListBanned:
+		return (&ctlproto.BanListRep{Bans: s.bans}).Marshal(), true
+
+	case ctlproto.CmdUnbanIP:
+		var req ctlproto.UnbanReq
+		if req.Unmarshal(payload) != nil {
+			return nil, false
+		}
+		found := false
+		bans := s.bans[:0]
+		for _, b := range s.bans {
+			if b.IP == req.IP {
+				found = true
+				continue
+			}
+			bans = append(bans, b)
+		}
+		s.bans = bans
+		if found {
+			s.unbanned = append(s.unbanned, req.IP)
+		}
+		return (&ctlproto.BoolMsg{Status: found}).Marshal(), true
+	}
+
+	return nil, false
+}
+
+func (s *Server) filter(match func(*ctlproto.UserInfo) bool) []ctlproto.UserInfo {
+	var users []ctlproto.UserInfo
+	for i := range s.users {
+		if match(&s.users[i]) {
+			users = append(users, s.users[i])
+		}
+	}
+	return users
+}
+
+func (s *Server) remove(match func(*ctlproto.UserInfo) bool) bool {
+	found := false
+	users := s.users[:0]
+	for i := range s.users {
+		if match(&s.users[i]) {
+			found = true
+			s.disconnected = append(s.disconnected, s.users[i].Username)
+			continue
+		}
+		users = append(users, s.users[i])
+	}
+	s.users = users
+	return found
+}
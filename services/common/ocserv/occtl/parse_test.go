@@ -0,0 +1,116 @@
+package occtl
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+// go test ./common/ocserv/occtl -run TestGolden -update
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// Each testdata/<shape> directory holds hand-written `occtl -j` style
+// output together with the expected typed result: a single user object
+// instead of an array, counters printed as strings, and counters, lists and
+// flags printed with their JSON types. They cover the shapes the models
+// accept, not the output of any ocserv release.
+var goldenShapes = []string{"single_object", "string_values", "typed_values"}
+
+func TestGoldenShowStatus(t *testing.T) {
+	for _, shape := range goldenShapes {
+		t.Run(shape, func(t *testing.T) {
+			var status models.OcctlServerStatus
+			if err := json.Unmarshal(readTestdata(t, shape, "show_status.json"), &status); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if status.Status != "online" || status.ActiveSessions == 0 || status.SessionsHandled == 0 {
+				t.Errorf("core fields missing: %+v", status)
+			}
+			checkGolden(t, shape, "show_status", status)
+		})
+	}
+}
+
+func TestGoldenShowUser(t *testing.T) {
+	for _, shape := range goldenShapes {
+		t.Run(shape, func(t *testing.T) {
+			users, err := unmarshalUserDetails(readTestdata(t, shape, "show_user.json"))
+			if err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(users) != 1 {
+				t.Fatalf("expected 1 user, got %d", len(users))
+			}
+
+			u := users[0]
+			if u.ID == 0 || u.Username == "" || u.Groupname == "" || u.RemoteIP == "" || u.UserAgent == "" {
+				t.Errorf("identity fields missing: %+v", u)
+			}
+			if u.MTU == 0 || u.DTLSCipher == "" || len(u.DNS) == 0 {
+				t.Errorf("connection fields missing: %+v", u)
+			}
+			if u.RX == 0 && u.RXHuman == "" {
+				t.Errorf("byte counters missing: %+v", u)
+			}
+			checkGolden(t, shape, "show_user", users)
+		})
+	}
+}
+
+func TestGoldenShowSessions(t *testing.T) {
+	for _, shape := range goldenShapes {
+		t.Run(shape, func(t *testing.T) {
+			var sessions []models.OcctlSession
+			if err := json.Unmarshal(readTestdata(t, shape, "show_sessions.json"), &sessions); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			for _, s := range sessions {
+				if s.Session == "" || s.Username == "" || s.RemoteIP == "" || s.UserAgent == "" {
+					t.Errorf("session fields missing: %+v", s)
+				}
+			}
+			checkGolden(t, shape, "show_sessions", sessions)
+		})
+	}
+}
+
+func readTestdata(t *testing.T, shape, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", shape, name))
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	return data
+}
+
+func checkGolden(t *testing.T, shape, name string, got interface{}) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", shape, name+".golden")
+	if *update {
+		if err = os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("write golden: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("%s does not match, got:\n%s", path, actual)
+	}
+}
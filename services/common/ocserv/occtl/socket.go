@@ -0,0 +1,347 @@
+package occtl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl/ctlproto"
+)
+
+// OcservOcctlSocket talks to the ocserv main process over its occtl control
+// socket instead of spawning /usr/bin/occtl for every call. Commands the
+// control protocol does not cover, and every command while the socket is
+// unreachable, are served by the exec based OcservOcctl.
+type OcservOcctlSocket struct {
+	path     string
+	timeout  time.Duration
+	fallback *OcservOcctl
+}
+
+const defaultOcctlTimeout = 5 * time.Second
+
+var errSocketUnavailable = errors.New("occtl socket unavailable")
+
+func NewOcservOcctlSocket(path string) *OcservOcctlSocket {
+	return &OcservOcctlSocket{
+		path:     path,
+		timeout:  defaultOcctlTimeout,
+		fallback: NewOcservOcctl(),
+	}
+}
+
+// NewOcservOcctlClient returns the native socket client when OCCTL_SOCKET
+// names the control socket, falling back to the occtl binary when it
+// cannot be reached, and the occtl binary otherwise. The socket client is
+// opt-in until its command numbering is checked against a capture of a
+// running ocserv.
+func NewOcservOcctlClient() OcservOcctlInterface {
+	path := os.Getenv("OCCTL_SOCKET")
+	if path == "" {
+		return NewOcservOcctl()
+	}
+	return NewOcservOcctlSocket(path)
+}
+
+// roundTrip sends a single request and returns the payload of its reply.
+// Dial failures are reported as errSocketUnavailable so callers can fall
+// back to the occtl binary.
+func (o *OcservOcctlSocket) roundTrip(cmd ctlproto.Command, payload []byte) ([]byte, error) {
+	want, ok := cmd.Reply()
+	if !ok {
+		return nil, fmt.Errorf("occtl command %d is not a request", cmd)
+	}
+
+	conn, err := net.DialTimeout("unix", o.path, o.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errSocketUnavailable, err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(o.timeout))
+
+	if err = ctlproto.WriteFrame(conn, cmd, payload); err != nil {
+		return nil, err
+	}
+
+	rep, data, err := ctlproto.ReadFrame(conn)
+	if err != nil {
+		return nil, err
+	}
+	if rep != want {
+		return nil, fmt.Errorf("unexpected occtl reply %d to command %d", rep, cmd)
+	}
+	return data, nil
+}
+
+func (o *OcservOcctlSocket) userList(cmd ctlproto.Command, payload []byte) ([]ctlproto.UserInfo, error) {
+	data, err := o.roundTrip(cmd, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var rep ctlproto.UserListRep
+	if err = rep.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return rep.Users, nil
+}
+
+func (o *OcservOcctlSocket) boolCall(cmd ctlproto.Command, payload []byte) (bool, error) {
+	data, err := o.roundTrip(cmd, payload)
+	if err != nil {
+		return false, err
+	}
+
+	var rep ctlproto.BoolMsg
+	if err = rep.Unmarshal(data); err != nil {
+		return false, err
+	}
+	return rep.Status, nil
+}
+
+// OnlineSessions returns a list of currently connected user info.
+// Sends: CTL_CMD_LIST
+func (o *OcservOcctlSocket) OnlineSessions() ([]models.OnlineUserSession, error) {
+	users, err := o.userList(ctlproto.CmdList, nil)
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.OnlineSessions()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.OnlineUserSession, 0, len(users))
+	for i := range users {
+		sessions = append(sessions, toOnlineUserSession(&users[i]))
+	}
+	return sessions, nil
+}
+
+// ShowUser returns detailed information about a specific user by username.
+// Sends: CTL_CMD_LIST_USER_INFO
+func (o *OcservOcctlSocket) ShowUser(username string) (*[]models.OcctlUserDetail, error) {
+	req := ctlproto.UsernameReq{Username: username}
+	users, err := o.userList(ctlproto.CmdUserInfo, req.Marshal())
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.ShowUser(username)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("user '%s' not found", username)
+	}
+
+	details := make([]models.OcctlUserDetail, 0, len(users))
+	for i := range users {
+		details = append(details, toUserDetail(&users[i]))
+	}
+	return &details, nil
+}
+
+// ShowUserByID returns detailed information about a specific user by ID.
+// Sends: CTL_CMD_LIST_ID_INFO
+func (o *OcservOcctlSocket) ShowUserByID(id string) (*models.OcctlUserDetail, error) {
+	sid, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session id: %s", id)
+	}
+
+	req := ctlproto.IDReq{ID: uint32(sid)}
+	users, err := o.userList(ctlproto.CmdIDInfo, req.Marshal())
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.ShowUserByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("session id %s not found", id)
+	}
+
+	detail := toUserDetail(&users[0])
+	return &detail, nil
+}
+
+// DisconnectUser Disconnect the specified user.
+// Sends: CTL_CMD_DISCONNECT_NAME
+func (o *OcservOcctlSocket) DisconnectUser(username string) (string, error) {
+	req := ctlproto.UsernameReq{Username: username}
+	ok, err := o.boolCall(ctlproto.CmdDisconnectName, req.Marshal())
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.DisconnectUser(username)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		// Same wording as occtl, callers match on it.
+		return "", fmt.Errorf("could not disconnect user '%s'", username)
+	}
+	return fmt.Sprintf("user '%s' was disconnected", username), nil
+}
+
+// DisconnectSession Disconnect the specified ID.
+// Sends: CTL_CMD_DISCONNECT_ID
+func (o *OcservOcctlSocket) DisconnectSession(id string) (string, error) {
+	if err := o.fallback.require(CapDisconnectID); err != nil {
+		return "", err
+	}
+
+	sid, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid session id: %s", id)
+	}
+
+	req := ctlproto.IDReq{ID: uint32(sid)}
+	ok, err := o.boolCall(ctlproto.CmdDisconnectID, req.Marshal())
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.DisconnectSession(id)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("could not disconnect ID %s", id)
+	}
+	return fmt.Sprintf("connection ID %s was disconnected", id), nil
+}
+
+// TerminateUser has no control socket counterpart, see OcservOcctl.TerminateUser.
+func (o *OcservOcctlSocket) TerminateUser(username string) (string, error) {
+	return o.fallback.TerminateUser(username)
+}
+
+// TerminateSession has no control socket counterpart, see OcservOcctl.TerminateSession.
+func (o *OcservOcctlSocket) TerminateSession(id string) (string, error) {
+	return o.fallback.TerminateSession(id)
+}
+
+// ShowSession is served by the occtl binary, see OcservOcctl.ShowSession.
+func (o *OcservOcctlSocket) ShowSession(sid string) (*models.OcctlSession, error) {
+	return o.fallback.ShowSession(sid)
+}
+
+// ShowSessionAll is served by the occtl binary, see OcservOcctl.ShowSessionAll.
+func (o *OcservOcctlSocket) ShowSessionAll() (*[]models.OcctlSession, error) {
+	return o.fallback.ShowSessionAll()
+}
+
+// ShowSessionsValid is served by the occtl binary, see OcservOcctl.ShowSessionsValid.
+func (o *OcservOcctlSocket) ShowSessionsValid() (*[]models.OcctlSession, error) {
+	return o.fallback.ShowSessionsValid()
+}
+
+// ShowIPBans returns the current list of IP bans with scores.
+// Sends: CTL_CMD_LIST_BANNED
+func (o *OcservOcctlSocket) ShowIPBans() (*[]models.IPBanPoints, error) {
+	data, err := o.roundTrip(ctlproto.CmdListBanned, nil)
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.ShowIPBans()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rep ctlproto.BanListRep
+	if err = rep.Unmarshal(data); err != nil {
+		return nil, err
+	}
+
+	ipBans := make([]models.IPBanPoints, 0, len(rep.Bans))
+	for _, ban := range rep.Bans {
+		item := models.IPBanPoints{IP: ban.IP, Score: int(ban.Score)}
+		if ban.Expires > 0 {
+			expires := time.Unix(int64(ban.Expires), 0)
+			item.Since = expires.Format(occtlTimeLayout)
+			item.Until = formatDuration(time.Until(expires))
+		}
+		ipBans = append(ipBans, item)
+	}
+	return &ipBans, nil
+}
+
+// UnbanIP removes an IP ban from the given IP address.
+// Sends: CTL_CMD_UNBAN_IP
+func (o *OcservOcctlSocket) UnbanIP(ip string) (string, error) {
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("invalid IP: %s", ip)
+	}
+
+	req := ctlproto.UnbanReq{IP: ip}
+	ok, err := o.boolCall(ctlproto.CmdUnbanIP, req.Marshal())
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.UnbanIP(ip)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("could not unban IP '%s'", ip)
+	}
+	return fmt.Sprintf("IP '%s' was unbanned", ip), nil
+}
+
+// ShowStatus returns the current status of ocserv.
+// Sends: CTL_CMD_STATUS
+func (o *OcservOcctlSocket) ShowStatus() (*models.OcctlServerStatus, error) {
+	data, err := o.roundTrip(ctlproto.CmdStatus, nil)
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.ShowStatus()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rep ctlproto.StatusRep
+	if err = rep.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return models.NewOcctlServerStatus(statusToMap(&rep, time.Now())), nil
+}
+
+// ShowStatusRaw is served by the occtl binary, see OcservOcctl.ShowStatusRaw.
+func (o *OcservOcctlSocket) ShowStatusRaw() (string, error) {
+	return o.fallback.ShowStatusRaw()
+}
+
+// ReloadConfigs reloads the ocserv configuration.
+// Sends: CTL_CMD_RELOAD
+func (o *OcservOcctlSocket) ReloadConfigs() (string, error) {
+	ok, err := o.boolCall(ctlproto.CmdReload, nil)
+	if errors.Is(err, errSocketUnavailable) {
+		return o.fallback.ReloadConfigs()
+	}
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errors.New("could not reload ocserv configuration")
+	}
+	return "Server scheduled to reload", nil
+}
+
+// ShowIRoutes is served by the occtl binary, see OcservOcctl.ShowIRoutes.
+func (o *OcservOcctlSocket) ShowIRoutes() (*[]models.IRoute, error) {
+	return o.fallback.ShowIRoutes()
+}
+
+// ShowEvent is served by the occtl binary, see OcservOcctl.ShowEvent.
+func (o *OcservOcctlSocket) ShowEvent() string {
+	return o.fallback.ShowEvent()
+}
+
+// Version returns detailed information about ocserv version.
+func (o *OcservOcctlSocket) Version() *models.ServerVersion {
+	return o.fallback.Version()
+}
+
+// Capabilities returns which occtl commands the installed ocserv supports.
+func (o *OcservOcctlSocket) Capabilities() models.OcctlCapabilities {
+	return o.fallback.Capabilities()
+}
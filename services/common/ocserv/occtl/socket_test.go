@@ -0,0 +1,275 @@
+package occtl
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl/ctlproto"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl/occtltest"
+)
+
+func newTestClient(t *testing.T) (*OcservOcctlSocket, *occtltest.Server) {
+	t.Helper()
+
+	srv, err := occtltest.NewServer()
+	if err != nil {
+		t.Fatalf("start fake occtl server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	return NewOcservOcctlSocket(srv.Path), srv
+}
+
+func TestSocketOnlineSessions(t *testing.T) {
+	client, srv := newTestClient(t)
+	connTime := uint32(time.Now().Add(-90 * time.Second).Unix())
+	srv.AddUser(ctlproto.UserInfo{ID: 7, Username: "alice", Groupname: "staff", LocalIP: "10.0.0.2", Device: "vpns0", ConnTime: connTime, RxPerSec: 2048})
+	srv.AddUser(ctlproto.UserInfo{ID: 8, Username: "bob", LocalIP: "10.0.0.3", Device: "vpns1", VHost: "corp"})
+
+	sessions, err := client.OnlineSessions()
+	if err != nil {
+		t.Fatalf("OnlineSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	alice := sessions[0]
+	if alice.ID != 7 || alice.Username != "alice" || alice.Group != "staff" || alice.IPv4 != "10.0.0.2" || alice.Device != "vpns0" {
+		t.Errorf("unexpected session: %+v", alice)
+	}
+	if alice.VHost != "default" {
+		t.Errorf("expected default vhost, got %q", alice.VHost)
+	}
+	if alice.AverageRX != "2.0 KB/sec" {
+		t.Errorf("unexpected average RX %q", alice.AverageRX)
+	}
+	if alice.SessionStartedAt == "" || !strings.HasPrefix(alice.LastConnectedAt, "1m:") {
+		t.Errorf("unexpected connection times: %q %q", alice.SessionStartedAt, alice.LastConnectedAt)
+	}
+	if sessions[1].VHost != "corp" {
+		t.Errorf("expected corp vhost, got %q", sessions[1].VHost)
+	}
+}
+
+func TestSocketShowUser(t *testing.T) {
+	client, srv := newTestClient(t)
+	srv.AddUser(ctlproto.UserInfo{ID: 3, Username: "alice", MTU: 1400, DTLSCiphersuite: "(DTLS1.2)-(ECDHE-RSA)-(AES-256-GCM)", BytesIn: 2048, UserAgent: "AnyConnect"})
+
+	users, err := client.ShowUser("alice")
+	if err != nil {
+		t.Fatalf("ShowUser: %v", err)
+	}
+	if len(*users) != 1 || (*users)[0].ID != 3 {
+		t.Fatalf("unexpected users: %+v", *users)
+	}
+	user := (*users)[0]
+	if user.MTU != 1400 || user.RX != 2048 || user.RXHuman != "2.0 KB" || user.UserAgent != "AnyConnect" || user.DTLSCipher == "" {
+		t.Errorf("unexpected user detail: %+v", user)
+	}
+
+	detail, err := client.ShowUserByID("3")
+	if err != nil || detail.Username != "alice" {
+		t.Errorf("ShowUserByID: %+v, %v", detail, err)
+	}
+
+	if _, err = client.ShowUser("nobody"); err == nil {
+		t.Error("expected error for unknown user")
+	}
+	if _, err = client.ShowUserByID("abc"); err == nil {
+		t.Error("expected error for invalid ID")
+	}
+}
+
+func TestSocketDisconnect(t *testing.T) {
+	client, srv := newTestClient(t)
+	srv.AddUser(ctlproto.UserInfo{ID: 1, Username: "alice"})
+	srv.AddUser(ctlproto.UserInfo{ID: 2, Username: "bob"})
+
+	if _, err := client.DisconnectUser("alice"); err != nil {
+		t.Fatalf("DisconnectUser: %v", err)
+	}
+	if _, err := client.DisconnectSession("2"); err != nil {
+		t.Fatalf("DisconnectSession: %v", err)
+	}
+
+	got := srv.Disconnected()
+	if len(got) != 2 || got[0] != "alice" || got[1] != "bob" {
+		t.Errorf("unexpected disconnects: %v", got)
+	}
+
+	_, err := client.DisconnectUser("alice")
+	if err == nil || !strings.Contains(err.Error(), "could not disconnect user") {
+		t.Errorf("expected occtl style error, got %v", err)
+	}
+}
+
+func TestSocketIPBans(t *testing.T) {
+	client, srv := newTestClient(t)
+	srv.AddBan(ctlproto.BanInfo{IP: "192.0.2.10", Score: 80, Expires: uint32(time.Now().Add(time.Hour).Unix())})
+
+	bans, err := client.ShowIPBans()
+	if err != nil {
+		t.Fatalf("ShowIPBans: %v", err)
+	}
+	if len(*bans) != 1 || (*bans)[0].IP != "192.0.2.10" || (*bans)[0].Score != 80 {
+		t.Fatalf("unexpected bans: %+v", *bans)
+	}
+
+	if _, err = client.UnbanIP("not-an-ip"); err == nil {
+		t.Error("expected error for invalid IP")
+	}
+	if _, err = client.UnbanIP("192.0.2.10"); err != nil {
+		t.Fatalf("UnbanIP: %v", err)
+	}
+	if _, err = client.UnbanIP("192.0.2.10"); err == nil {
+		t.Error("expected error when IP is not banned")
+	}
+	if got := srv.Unbanned(); len(got) != 1 {
+		t.Errorf("unexpected unbans: %v", got)
+	}
+}
+
+func TestSocketStatusAndReload(t *testing.T) {
+	client, srv := newTestClient(t)
+	srv.SetStatus(ctlproto.StatusRep{
+		Status:             true,
+		PID:                42,
+		StartTime:          uint32(time.Now().Add(-2 * time.Hour).Unix()),
+		KBytesIn:           1500,
+		LatencyMedianTotal: 3000,
+		LatencySampleCount: 2,
+	})
+	srv.AddUser(ctlproto.UserInfo{ID: 1, Username: "alice"})
+
+	status, err := client.ShowStatus()
+	if err != nil {
+		t.Fatalf("ShowStatus: %v", err)
+	}
+
+	if status.Status != "online" || status.ServerPID != 42 || status.ActiveSessions != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if status.RawRX != 1500000 || status.RX != "1.5 MB" {
+		t.Errorf("unexpected RX: %v %v", status.RawRX, status.RX)
+	}
+	if status.RawMedianLatency != 1500 || status.MedianLatency != "1.50ms" {
+		t.Errorf("unexpected latency: %v %v", status.RawMedianLatency, status.MedianLatency)
+	}
+	if status.Uptime < 7199 {
+		t.Errorf("unexpected uptime %v", status.Uptime)
+	}
+
+	if _, err = client.ReloadConfigs(); err != nil {
+		t.Fatalf("ReloadConfigs: %v", err)
+	}
+	if srv.Reloads() != 1 {
+		t.Errorf("expected 1 reload, got %d", srv.Reloads())
+	}
+}
+
+func TestSocketUnavailable(t *testing.T) {
+	client := NewOcservOcctlSocket(filepath.Join(t.TempDir(), "missing.socket"))
+
+	_, err := client.roundTrip(ctlproto.CmdStatus, nil)
+	if !errors.Is(err, errSocketUnavailable) {
+		t.Fatalf("expected errSocketUnavailable, got %v", err)
+	}
+}
+
+func TestCtlprotoCommands(t *testing.T) {
+	// CTL_CMD_* values of src/ctl.h, a request must never carry CTL_CMD_STOP
+	const ctlCmdStop = 3
+	requests := map[ctlproto.Command]ctlproto.Command{
+		1:  11, // STATUS -> STATUS_REP
+		2:  12, // RELOAD -> RELOAD_REP
+		4:  5,  // LIST -> LIST_REP
+		7:  5,  // USER_INFO -> LIST_REP
+		16: 5,  // ID_INFO -> LIST_REP
+		9:  14, // DISCONNECT_NAME -> DISCONNECT_NAME_REP
+		10: 15, // DISCONNECT_ID -> DISCONNECT_ID_REP
+		18: 19, // LIST_BANNED -> LIST_BANNED_REP
+		20: 21, // UNBAN_IP -> UNBAN_IP_REP
+	}
+	for cmd, want := range requests {
+		if got, ok := cmd.Reply(); !ok || got != want {
+			t.Errorf("reply to %d = %d, %v, want %d", cmd, got, ok, want)
+		}
+	}
+	for cmd := ctlproto.Command(0); cmd < 255; cmd++ {
+		if _, ok := cmd.Reply(); ok && requests[cmd] == 0 {
+			t.Errorf("unexpected request %d", cmd)
+		}
+	}
+	if _, ok := ctlproto.Command(ctlCmdStop).Reply(); ok {
+		t.Fatal("CTL_CMD_STOP is accepted as a request")
+	}
+
+	client := NewOcservOcctlSocket(filepath.Join(t.TempDir(), "missing.socket"))
+	if _, err := client.roundTrip(ctlCmdStop, nil); err == nil || errors.Is(err, errSocketUnavailable) {
+		t.Errorf("CTL_CMD_STOP reached the socket: %v", err)
+	}
+}
+
+func TestCtlprotoListFrame(t *testing.T) {
+	// CTL_CMD_LIST has no payload: the command byte and a zero length
+	var buf bytes.Buffer
+	if err := ctlproto.WriteFrame(&buf, ctlproto.CmdList, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{4, 0, 0, 0, 0}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("LIST frame = %v, want %v", buf.Bytes(), want)
+	}
+}
+
+func TestCtlprotoRoundTrip(t *testing.T) {
+	in := ctlproto.UserInfo{
+		ID:       9,
+		Username: "alice",
+		DNS:      []string{"1.1.1.1", "8.8.8.8"},
+		Routes:   []string{"10.0.0.0/8"},
+		BytesIn:  1 << 40,
+	}
+
+	var out ctlproto.UserInfo
+	if err := out.Unmarshal(in.Marshal()); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if out.ID != in.ID || out.Username != in.Username || len(out.DNS) != 2 || out.Routes[0] != "10.0.0.0/8" || out.BytesIn != in.BytesIn {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+
+	if err := out.Unmarshal([]byte{0x0a, 0x05, 'a'}); err == nil {
+		t.Error("expected error for truncated message")
+	}
+}
+
+func TestReloadUnreachable(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+
+	tests := []struct {
+		name string
+		err  error
+		out  string
+		want bool
+	}{
+		{"not installed", exec.ErrNotFound, "", true},
+		{"missing binary", &fs.PathError{Op: "fork/exec", Path: "/usr/bin/occtl", Err: fs.ErrNotExist}, "", true},
+		{"socket down", exitErr, "error connecting to sock '/var/run/occtl.socket': No such file or directory\n", true},
+		{"socket refused", exitErr, "error connecting to sock '/var/run/occtl.socket': Connection refused\n", true},
+		{"refused", exitErr, "could not reload the configuration\n", false},
+		{"connect in a message", exitErr, "ocserv: cannot reload, users still connected\n", false},
+		{"no output", exitErr, "", false},
+	}
+	for _, tt := range tests {
+		if got := reloadUnreachable(tt.err, []byte(tt.out)); got != tt.want {
+			t.Errorf("%s: reloadUnreachable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package occtl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// Version is a parsed ocserv release number.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Capabilities reported to clients. The keys are part of the API contract.
+const (
+	CapShowEvents    = "show_events"
+	CapShowIRoutes   = "show_iroutes"
+	CapShowSessions  = "show_sessions"
+	CapIPBanPoints   = "ip_ban_points"
+	CapTerminate     = "terminate"
+	CapDisconnectID  = "disconnect_id"
+	CapRawJSONFields = "raw_json_fields"
+)
+
+// capabilityRule marks a capability as available from Since onwards,
+// except in the releases listed in Broken.
+type capabilityRule struct {
+	Since  Version
+	Broken []Version
+}
+
+var capabilityMatrix = map[string]capabilityRule{
+	CapShowEvents:   {Since: Version{0, 11, 0}},
+	CapShowIRoutes:  {Since: Version{0, 11, 0}, Broken: []Version{{1, 2, 4}}},
+	CapShowSessions: {Since: Version{1, 1, 0}},
+	CapIPBanPoints:  {Since: Version{0, 11, 7}},
+	CapTerminate:    {Since: Version{1, 2, 0}},
+	CapDisconnectID: {Since: Version{0, 10, 0}},
+	// raw_* counters next to the human readable values in -j output
+	CapRawJSONFields: {Since: Version{1, 2, 0}},
+}
+
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extracts the ocserv release from strings such as
+// "OpenConnect VPN Server 1.2.4, GnuTLS version: 3.7.9" or "1.3.0".
+func ParseVersion(s string) (Version, bool) {
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, false
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, true
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when v is older than, equal to or newer than o.
+func (v Version) Compare(o Version) int {
+	for _, d := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		switch {
+		case d[0] < d[1]:
+			return -1
+		case d[0] > d[1]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Supports reports whether capability cap is available in release v.
+// Unknown capabilities are reported as unsupported.
+func (v Version) Supports(cap string) bool {
+	rule, ok := capabilityMatrix[cap]
+	if !ok || v.Compare(rule.Since) < 0 {
+		return false
+	}
+	for _, broken := range rule.Broken {
+		if v.Compare(broken) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CapabilitiesFor evaluates the whole matrix for release v. When the
+// release could not be determined every capability is assumed available,
+// leaving the decision to ocserv itself.
+func CapabilitiesFor(v Version, known bool) models.OcctlCapabilities {
+	caps := make(models.OcctlCapabilities, len(capabilityMatrix))
+	for name := range capabilityMatrix {
+		caps[name] = !known || v.Supports(name)
+	}
+	return caps
+}
+
+var (
+	detectOnce     sync.Once
+	detectedVer    Version
+	detectedKnown  bool
+	detectedString string
+)
+
+// detectVersion runs `ocserv --version` once per process.
+func detectVersion() (Version, bool, string) {
+	detectOnce.Do(func() {
+		detectedString = utils.GetOcservVersion()
+		detectedVer, detectedKnown = ParseVersion(detectedString)
+	})
+	return detectedVer, detectedKnown, detectedString
+}
+
+// unsupportedError is returned for commands the running ocserv lacks.
+func unsupportedError(cap string, v Version) error {
+	return fmt.Errorf("%s is not supported by ocserv %s", cap, v)
+}
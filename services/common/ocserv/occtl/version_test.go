@@ -0,0 +1,51 @@
+package occtl
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]Version{
+		"OpenConnect VPN Server 1.2.4, GnuTLS version: 3.7.9": {1, 2, 4},
+		"1.3.0":  {1, 3, 0},
+		"v1.1":   {1, 1, 0},
+		"0.12.6": {0, 12, 6},
+	}
+	for in, want := range cases {
+		got, ok := ParseVersion(in)
+		if !ok || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+
+	if _, ok := ParseVersion("OpenConnect VPN Server"); ok {
+		t.Error("expected no version")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	v124 := Version{1, 2, 4}
+	if v124.Supports(CapShowIRoutes) {
+		t.Error("iroutes must be disabled on 1.2.4")
+	}
+	if !(Version{1, 2, 5}).Supports(CapShowIRoutes) {
+		t.Error("iroutes must be enabled on 1.2.5")
+	}
+	if (Version{1, 0, 0}).Supports(CapShowSessions) || !(Version{1, 1, 0}).Supports(CapShowSessions) {
+		t.Error("sessions must start with 1.1.0")
+	}
+	if (Version{0, 9, 2}).Supports(CapDisconnectID) || !v124.Supports(CapDisconnectID) {
+		t.Error("disconnect by id must start with 0.10.0")
+	}
+	if v124.Supports("unknown") {
+		t.Error("unknown capabilities must be unsupported")
+	}
+
+	caps := CapabilitiesFor(v124, true)
+	if caps[CapShowIRoutes] || !caps[CapTerminate] {
+		t.Errorf("unexpected capabilities: %v", caps)
+	}
+	for name, ok := range CapabilitiesFor(Version{}, false) {
+		if !ok {
+			t.Errorf("%s must be assumed available for unknown versions", name)
+		}
+	}
+}
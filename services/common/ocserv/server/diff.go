@@ -0,0 +1,82 @@
+package server
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// restartKeys are read by ocserv at startup only, occtl reload does not
+// apply them.
+var restartKeys = map[string]bool{
+	"auth":                  true,
+	"enable-auth":           true,
+	"listen-host":           true,
+	"listen-host-is-dyndns": true,
+	"udp-listen-host":       true,
+	"tcp-port":              true,
+	"udp-port":              true,
+	"listen-proxy-proto":    true,
+	"listen-clear-file":     true,
+	"socket-file":           true,
+	"occtl-socket-file":     true,
+	"use-occtl":             true,
+	"run-as-user":           true,
+	"run-as-group":          true,
+	"chroot-dir":            true,
+	"pid-file":              true,
+	"isolate-workers":       true,
+	"device":                true,
+}
+
+// Diff compares two versions of a config file directive by directive,
+// ignoring comments and the order of the directives.
+func Diff(oldContent, newContent []byte) models.OcservConfigChanges {
+	oldDirectives := directives(oldContent)
+	newDirectives := directives(newContent)
+
+	keys := make(map[string]struct{}, len(oldDirectives)+len(newDirectives))
+	for key := range oldDirectives {
+		keys[key] = struct{}{}
+	}
+	for key := range newDirectives {
+		keys[key] = struct{}{}
+	}
+
+	changes := models.OcservConfigChanges{}
+	for key := range keys {
+		if slices.Equal(oldDirectives[key], newDirectives[key]) {
+			continue
+		}
+		changes = append(changes, models.OcservConfigChange{
+			Key:             key,
+			Old:             oldDirectives[key],
+			New:             newDirectives[key],
+			RestartRequired: restartKeys[key],
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// RestartRequired reports whether changes need an ocserv restart rather
+// than a reload.
+func RestartRequired(changes models.OcservConfigChanges) bool {
+	for _, change := range changes {
+		if change.RestartRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// directives returns the values of each directive of content, in file
+// order.
+func directives(content []byte) map[string][]string {
+	return utils.ParseConfigDocument(content).Directives()
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+const ConfigPath = "/etc/ocserv/ocserv.conf"
+
+// vhostPrefix starts the name of the ocserv.conf section of a virtual host.
+const vhostPrefix = "vhost:"
+
+// unquotedValue matches the values written without quotes.
+var unquotedValue = regexp.MustCompile(`^[A-Za-z0-9._/:@,-]*$`)
+
+type OcservServer struct {
+	path string
+}
+
+type OcservServerInterface interface {
+	Config() (*models.OcservServerConfig, error)
+	Content() ([]byte, error)
+	Render(config *models.OcservServerConfig) ([]byte, error)
+	VHosts() ([]string, error)
+	RenderVHost(name string, config *models.OcservVHostConfig) ([]byte, error)
+	Write(content []byte) error
+	Restore() error
+}
+
+func NewOcservServer() *OcservServer {
+	return &OcservServer{path: ConfigPath}
+}
+
+// Config loads the typed directives of ocserv.conf.
+func (s *OcservServer) Config() (*models.OcservServerConfig, error) {
+	raw, err := utils.ParseOcservConfigFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return configFromMap(raw)
+}
+
+// Content returns ocserv.conf as it is on disk.
+func (s *OcservServer) Content() ([]byte, error) {
+	return os.ReadFile(s.path)
+}
+
+// Render builds the ocserv.conf for config without writing it. The config
+// is applied to the current file as a utils.ConfigDocument, so comments,
+// including the header marking the file as managed by the dashboard, and the
+// directives the typed config does not cover are kept; a typed directive
+// left empty is removed.
+func (s *OcservServer) Render(config *models.OcservServerConfig) ([]byte, error) {
+	doc, err := utils.ReadConfigDocument(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	directives := mapFromConfig(config)
+	for _, key := range configKeys(models.OcservServerConfig{}) {
+		if _, ok := directives[key]; !ok {
+			directives[key] = nil
+		}
+	}
+	doc.Apply(directives)
+	return doc.Bytes(), nil
+}
+
+// VHosts returns the names of the virtual hosts with a section in
+// ocserv.conf, in file order.
+func (s *OcservServer) VHosts() ([]string, error) {
+	doc, err := utils.ReadConfigDocument(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, section := range doc.Sections() {
+		if name, ok := strings.CutPrefix(section, vhostPrefix); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// RenderVHost builds the ocserv.conf with the [vhost:name] section set to
+// config without writing it, like Render does for the global section. A
+// nil config removes the section.
+func (s *OcservServer) RenderVHost(name string, config *models.OcservVHostConfig) ([]byte, error) {
+	doc, err := utils.ReadConfigDocument(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	section := vhostPrefix + name
+	if config == nil {
+		doc.DeleteSection(section)
+		return doc.Bytes(), nil
+	}
+
+	directives := mapFromConfig(config)
+	for _, key := range configKeys(models.OcservVHostConfig{}) {
+		if _, ok := directives[key]; !ok {
+			directives[key] = nil
+		}
+	}
+	doc.ApplySection(section, directives)
+	return doc.Bytes(), nil
+}
+
+// Write replaces ocserv.conf with content atomically, keeping the file mode
+// and the previous version for Restore. content is validated first, and
+// checked with "ocserv --test-config" when OCSERV_CONFIG_CHECK is set.
+func (s *OcservServer) Write(content []byte) error {
+	if err := utils.ParseConfigDocument(content).Validate(models.OcservServerConfig{}); err != nil {
+		return err
+	}
+	if err := s.check(content); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(s.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return utils.WriteConfigFile(s.path, content, mode)
+}
+
+// Restore puts back ocserv.conf as it was before the last Write.
+func (s *OcservServer) Restore() error {
+	return utils.RestoreConfigFile(s.path)
+}
+
+// check runs ocserv's own config check on content. It is skipped unless
+// OCSERV_CONFIG_CHECK is set and the ocserv binary is installed.
+func (s *OcservServer) check(content []byte) error {
+	if enabled, _ := strconv.ParseBool(os.Getenv("OCSERV_CONFIG_CHECK")); !enabled {
+		return nil
+	}
+	bin, err := exec.LookPath("ocserv")
+	if err != nil {
+		return nil
+	}
+
+	// next to ocserv.conf, for relative paths in the config
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".ocserv.conf.check-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	output, err := exec.Command(bin, "--test-config", "--config", tmp.Name()).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ocserv rejected the configuration: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// configFromMap converts the directives parsed by ParseOcservConfigFile
+// into the typed config. Repeated directives fill list fields; for the
+// others ocserv keeps the last value, and so does this.
+func configFromMap(raw map[string]interface{}) (*models.OcservServerConfig, error) {
+	config := &models.OcservServerConfig{}
+	v := reflect.ValueOf(config).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		key := configKey(t.Field(i))
+		value, ok := raw[key]
+		if !ok {
+			continue
+		}
+		values := rawValues(value)
+		if len(values) == 0 {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Slice {
+			field.Set(reflect.ValueOf(values))
+			continue
+		}
+
+		last := values[len(values)-1]
+		switch field.Type().Elem().Kind() {
+		case reflect.String:
+			field.Set(reflect.ValueOf(&last))
+		case reflect.Int:
+			n, err := strconv.Atoi(last)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid number %q", key, last)
+			}
+			field.Set(reflect.ValueOf(&n))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(last)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid boolean %q", key, last)
+			}
+			field.Set(reflect.ValueOf(&b))
+		}
+	}
+	return config, nil
+}
+
+// mapFromConfig converts a typed config, a pointer to OcservServerConfig or
+// OcservVHostConfig, into ConfigDocument values. False booleans are written
+// out, ocserv defaults some of them to true.
+func mapFromConfig(config interface{}) map[string]interface{} {
+	directives := make(map[string]interface{})
+	v := reflect.ValueOf(config).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.IsNil() {
+			continue
+		}
+		key := configKey(t.Field(i))
+
+		if field.Kind() == reflect.Slice {
+			values := make([]interface{}, 0, field.Len())
+			for j := 0; j < field.Len(); j++ {
+				values = append(values, quoteValue(field.Index(j).String()))
+			}
+			if len(values) > 0 {
+				directives[key] = values
+			}
+			continue
+		}
+
+		switch value := field.Elem().Interface().(type) {
+		case string:
+			directives[key] = quoteValue(value)
+		case bool:
+			directives[key] = strconv.FormatBool(value)
+		default:
+			directives[key] = value
+		}
+	}
+	return directives
+}
+
+// rawValues returns the values of a parsed directive as unquoted strings.
+func rawValues(value interface{}) []string {
+	var items []interface{}
+	switch list := value.(type) {
+	case []interface{}:
+		items = list
+	case []string:
+		for _, item := range list {
+			items = append(items, item)
+		}
+	default:
+		items = []interface{}{value}
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, utils.UnquoteConfigValue(fmt.Sprint(item)))
+	}
+	return values
+}
+
+func quoteValue(value string) string {
+	if unquotedValue.MatchString(value) {
+		return value
+	}
+	return `"` + value + `"`
+}
+
+func configKey(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+func configKeys(schema interface{}) []string {
+	t := reflect.TypeOf(schema)
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, configKey(t.Field(i)))
+	}
+	return keys
+}
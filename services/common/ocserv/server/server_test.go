@@ -0,0 +1,202 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+const testConfig = `# ===============================================
+# Managed by ocserv-dashboard install.sh
+# DO NOT edit or remove this file header
+# ===============================================
+auth = "certificate"
+enable-auth = "plain[passwd=/etc/ocserv/ocpasswd]"
+ca-cert = /etc/ocserv/ssl/ca-cert.pem
+cert-user-oid = 2.5.4.3
+tcp-port=443
+isolate-workers=true
+deny-roaming=false
+dns=8.8.8.8
+dns=1.1.1.1
+compression=false
+tls-priorities="NORMAL:%SERVER_PRECEDENCE:%COMPAT:-RSA"
+`
+
+func TestRenderRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ocserv.conf")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := &OcservServer{path: path}
+
+	config, err := s.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Auth) != 1 || config.Auth[0] != "certificate" {
+		t.Errorf("Auth = %v, want [certificate]", config.Auth)
+	}
+	if config.CertUserOID == nil || *config.CertUserOID != "2.5.4.3" {
+		t.Errorf("CertUserOID = %v, want 2.5.4.3", config.CertUserOID)
+	}
+	if config.DenyRoaming == nil || *config.DenyRoaming {
+		t.Errorf("DenyRoaming = %v, want false", config.DenyRoaming)
+	}
+
+	rendered, err := s.Render(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(rendered), "# ===============================================\n# Managed by ocserv-dashboard") {
+		t.Errorf("managed header not kept:\n%s", rendered)
+	}
+	if changes := Diff([]byte(testConfig), rendered); len(changes) != 0 {
+		t.Errorf("unchanged config rendered with changes %+v:\n%s", changes, rendered)
+	}
+
+	port := 8443
+	config.TCPPort = &port
+	config.DNS = []string{"9.9.9.9"}
+	rendered, err = s.Render(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := Diff([]byte(testConfig), rendered)
+	if len(changes) != 2 || changes[0].Key != "dns" || changes[1].Key != "tcp-port" {
+		t.Fatalf("Diff() = %+v, want dns and tcp-port", changes)
+	}
+	if changes[0].RestartRequired || !changes[1].RestartRequired {
+		t.Errorf("Diff() = %+v, want a restart for tcp-port only", changes)
+	}
+	if !RestartRequired(changes) {
+		t.Error("RestartRequired() = false, want true")
+	}
+}
+
+func TestRenderVHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ocserv.conf")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := &OcservServer{path: path}
+
+	network := "10.1.0.0/24"
+	rendered, err := s.RenderVHost("corp", &models.OcservVHostConfig{
+		Auth:        []string{"plain[passwd=/etc/ocserv/corp.passwd]"},
+		IPv4Network: &network,
+		DNS:         []string{"10.1.0.53"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(rendered), "\n[vhost:corp]\nauth=\"plain[passwd=/etc/ocserv/corp.passwd]\"\ndns=10.1.0.53\nipv4-network=10.1.0.0/24\n") {
+		t.Errorf("RenderVHost() =\n%s", rendered)
+	}
+	if changes := Diff([]byte(testConfig), rendered); len(changes) != 0 {
+		t.Errorf("the global section changed: %+v", changes)
+	}
+	if err = os.WriteFile(path, rendered, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := s.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.DNS) != 2 || config.IPv4Network != nil {
+		t.Errorf("Config() read the vhost section: dns %v, ipv4-network %v", config.DNS, config.IPv4Network)
+	}
+	if names, _ := s.VHosts(); len(names) != 1 || names[0] != "corp" {
+		t.Errorf("VHosts() = %v, want [corp]", names)
+	}
+
+	rendered, err = s.RenderVHost("corp", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rendered) != testConfig {
+		t.Errorf("RenderVHost(nil) =\n%s\nwant the section removed", rendered)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	caCert := "/etc/ocserv/ssl/ca-cert.pem"
+	network := "10.0.0.0/24"
+
+	config, err := configFromMap(map[string]interface{}{"auth": `"certificate"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Validate(config); err == nil || !strings.Contains(err.Error(), "ca-cert") {
+		t.Errorf("Validate() = %v, want a missing ca-cert error", err)
+	}
+
+	config.CACert = &caCert
+	config.IPv4Network = &network
+	config.Route = []string{"default", "192.168.0.0/255.255.0.0"}
+	if err = Validate(config); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	config.EnableAuth = []string{"ldap"}
+	config.DNS = []string{"dns.example.com"}
+	err = Validate(config)
+	if err == nil || !strings.Contains(err.Error(), "ldap") || !strings.Contains(err.Error(), "dns.example.com") {
+		t.Errorf("Validate() = %v, want auth and dns errors", err)
+	}
+}
+
+func TestCheckAuthMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       []string
+		enableAuth []string
+		mode       string
+		wantErr    bool
+	}{
+		{"password under plain", []string{"plain[passwd=/etc/ocserv/ocpasswd]"}, nil, models.AuthModePassword, false},
+		{"certificate under plain", []string{"plain[passwd=/etc/ocserv/ocpasswd]"}, nil, models.AuthModeCertificate, true},
+		{"certificate combined with plain", []string{"plain", "certificate"}, nil, models.AuthModeCertificate, true},
+		{"certificate only", []string{"certificate"}, nil, models.AuthModeCertificate, false},
+		{"certificate alternative", []string{"plain"}, []string{"certificate"}, models.AuthModeCertificate, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.OcservServerConfig{Auth: tt.auth, EnableAuth: tt.enableAuth}
+			if err := CheckAuthMode(config, tt.mode); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAuthMode() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckVHostAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       []string
+		enableAuth []string
+		wantErr    bool
+	}{
+		{"shared passwd", []string{"plain[passwd=/etc/ocserv/ocpasswd]"}, nil, false},
+		{"legacy syntax", []string{"plain[/etc/ocserv/ocpasswd]"}, nil, false},
+		{"with otp", []string{"plain[passwd=/etc/ocserv/ocpasswd,otp=/etc/ocserv/otp]"}, nil, false},
+		{"certificate only", []string{"certificate"}, nil, false},
+		{"inherited auth", nil, nil, false},
+		{"own passwd", []string{"plain[passwd=/etc/ocserv/corp.passwd]"}, nil, true},
+		{"own passwd as alternative", []string{"certificate"}, []string{"plain[/etc/ocserv/corp.passwd]"}, true},
+		{"no passwd", []string{"plain"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.OcservVHostConfig{Auth: tt.auth, EnableAuth: tt.enableAuth}
+			if err := CheckVHostAuth(config); (err != nil) != tt.wantErr {
+				t.Errorf("CheckVHostAuth() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// authMethods are the authentication methods ocserv supports.
+var authMethods = map[string]bool{
+	"plain":       true,
+	"certificate": true,
+	"pam":         true,
+	"radius":      true,
+	"gssapi":      true,
+	"oidc":        true,
+}
+
+// Validate checks the values ocserv would refuse to start with, beyond the
+// field ranges checked by the request validation.
+func Validate(config *models.OcservServerConfig) error {
+	var errs []error
+
+	if len(config.Auth) == 0 {
+		errs = append(errs, errors.New("auth: at least one authentication method is required"))
+	}
+	usesCertificate := false
+	for _, method := range append(append([]string{}, config.Auth...), config.EnableAuth...) {
+		name := authMethod(method)
+		if !authMethods[name] {
+			errs = append(errs, fmt.Errorf("auth: unknown authentication method %q", method))
+		}
+		if name == "certificate" {
+			usesCertificate = true
+		}
+	}
+	if usesCertificate && (config.CACert == nil || *config.CACert == "") {
+		errs = append(errs, errors.New("ca-cert: required by certificate authentication"))
+	}
+
+	for key, path := range map[string]*string{
+		"server-cert":          config.ServerCert,
+		"server-key":           config.ServerKey,
+		"ca-cert":              config.CACert,
+		"crl":                  config.CRL,
+		"socket-file":          config.SocketFile,
+		"pid-file":             config.PIDFile,
+		"config-per-group":     config.ConfigPerGroup,
+		"config-per-user":      config.ConfigPerUser,
+		"default-group-config": config.DefaultGroupConfig,
+	} {
+		if path != nil && *path != "" && !filepath.IsAbs(*path) {
+			errs = append(errs, fmt.Errorf("%s: %q is not an absolute path", key, *path))
+		}
+	}
+
+	if config.IPv4Network != nil && *config.IPv4Network != "" {
+		if _, _, err := net.ParseCIDR(*config.IPv4Network); err != nil && net.ParseIP(*config.IPv4Network) == nil {
+			errs = append(errs, fmt.Errorf("ipv4-network: invalid network %q", *config.IPv4Network))
+		}
+	}
+	if config.IPv6Network != nil && *config.IPv6Network != "" {
+		if _, _, err := net.ParseCIDR(*config.IPv6Network); err != nil {
+			errs = append(errs, fmt.Errorf("ipv6-network: invalid network %q", *config.IPv6Network))
+		}
+	}
+
+	for _, dns := range config.DNS {
+		if net.ParseIP(dns) == nil {
+			errs = append(errs, fmt.Errorf("dns: invalid address %q", dns))
+		}
+	}
+	for key, routes := range map[string][]string{"route": config.Route, "no-route": config.NoRoute} {
+		for _, route := range routes {
+			if !utils.ValidRoute(route) {
+				errs = append(errs, fmt.Errorf("%s: invalid route %q", key, route))
+			}
+		}
+	}
+
+	if config.Camouflage != nil && *config.Camouflage && (config.CamouflageSecret == nil || *config.CamouflageSecret == "") {
+		errs = append(errs, errors.New("camouflage_secret: required when camouflage is enabled"))
+	}
+
+	// ocserv has no escaping, a quote or a line break would end the value
+	v := reflect.ValueOf(config).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		for _, value := range stringValues(v.Field(i)) {
+			if strings.ContainsAny(value, "\"\r\n") {
+				errs = append(errs, fmt.Errorf("%s: quotes and line breaks are not allowed", configKey(t.Field(i))))
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ErrCertificateAuthDisabled is returned for the certificate auth mode when
+// ocserv.conf does not let a user log in with a certificate alone.
+var ErrCertificateAuthDisabled = errors.New(
+	"ocserv does not accept certificate-only logins: set auth to certificate or add it to enable-auth first",
+)
+
+// CheckAuthMode checks that users in the dashboard auth mode can log in
+// with config. Certificate-only users have no ocpasswd entry, so they need
+// certificate to be the only auth method, auth methods combine, or one of
+// the enable-auth alternatives.
+func CheckAuthMode(config *models.OcservServerConfig, mode string) error {
+	if models.AuthModeUsesPassword(mode) {
+		return nil
+	}
+
+	certificateOnly := len(config.Auth) > 0
+	for _, method := range config.Auth {
+		if authMethod(method) != "certificate" {
+			certificateOnly = false
+		}
+	}
+	for _, method := range config.EnableAuth {
+		if authMethod(method) == "certificate" {
+			certificateOnly = true
+		}
+	}
+	if !certificateOnly {
+		return ErrCertificateAuthDisabled
+	}
+	return nil
+}
+
+// CheckVHostAuth checks that the password logins of a virtual host use the
+// ocpasswd file the dashboard writes every user to, whatever their virtual
+// host.
+func CheckVHostAuth(config *models.OcservVHostConfig) error {
+	for _, method := range append(append([]string{}, config.Auth...), config.EnableAuth...) {
+		if authMethod(method) != "plain" {
+			continue
+		}
+		if path := plainPasswd(method); path == "" || filepath.Clean(path) != utils.OcpasswdPath {
+			return fmt.Errorf("auth %q: virtual hosts must check passwords against %s, the file users are written to", method, utils.OcpasswdPath)
+		}
+	}
+	return nil
+}
+
+// plainPasswd returns the passwd file of a plain auth method, given as
+// plain[passwd=<file>] or, in older releases, plain[<file>].
+func plainPasswd(method string) string {
+	_, options, ok := strings.Cut(strings.TrimSuffix(method, "]"), "[")
+	if !ok {
+		return ""
+	}
+	for i, option := range strings.Split(options, ",") {
+		option = strings.TrimSpace(option)
+		if value, found := strings.CutPrefix(option, "passwd="); found {
+			return value
+		}
+		if i == 0 && !strings.Contains(option, "=") {
+			return option
+		}
+	}
+	return ""
+}
+
+// authMethod returns the name of an auth method without its options.
+func authMethod(method string) string {
+	return strings.SplitN(method, "[", 2)[0]
+}
+
+func stringValues(field reflect.Value) []string {
+	switch {
+	case field.Kind() == reflect.Slice:
+		return field.Interface().([]string)
+	case !field.IsNil() && field.Elem().Kind() == reflect.String:
+		return []string{field.Elem().String()}
+	default:
+		return nil
+	}
+}
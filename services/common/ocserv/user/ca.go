@@ -0,0 +1,433 @@
+package user
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// A CA rollover trusts the active CA and the retiring ones side by side:
+// ca-cert.pem bundles them, active first, and crl.pem holds one CRL per
+// CA. New certificates are always issued by the active CA, whose key stays
+// in ca-key.pem; the retiring CAs keep their key to sign their own CRL.
+const (
+	certCARetiringDir = certSSLDir + "/ca-retiring"
+	certCARetiredDir  = certSSLDir + "/ca-retired"
+
+	CAStateActive   = "active"
+	CAStateRetiring = "retiring"
+)
+
+var (
+	ErrCARotationInProgress = errors.New("a CA rotation is already in progress")
+	ErrNoCARotation         = errors.New("no CA rotation in progress")
+)
+
+// CAInfo describes a CA trusted by ocserv.
+type CAInfo struct {
+	Serial    string    `json:"serial" validate:"required"`
+	Subject   string    `json:"subject" validate:"required"`
+	State     string    `json:"state" enums:"active,retiring" validate:"required"`
+	NotBefore time.Time `json:"not_before" validate:"required"`
+	NotAfter  time.Time `json:"not_after" validate:"required"`
+	// Certificates counts the active and suspended user certificates the
+	// CA issued.
+	Certificates int `json:"certificates" validate:"required"`
+}
+
+// CAStatus is the state of the CA rollover. Pending lists the users whose
+// certificate is not issued by the active CA yet.
+type CAStatus struct {
+	InProgress bool     `json:"in_progress" validate:"required"`
+	CAs        []CAInfo `json:"cas" validate:"required"`
+	Pending    []string `json:"pending" validate:"required"`
+}
+
+type trustedCA struct {
+	cert     *x509.Certificate
+	key      crypto.Signer
+	state    string
+	certPath string
+	keyPath  string
+}
+
+type userCertificate struct {
+	username string
+	cert     *x509.Certificate
+}
+
+// CAStatus reports the trusted CAs and the users still to reissue.
+func (u *OcservUser) CAStatus() (*CAStatus, error) {
+	if err := ensureCertificatePKI(); err != nil {
+		return nil, err
+	}
+
+	cas, err := loadTrustedCAs()
+	if err != nil {
+		return nil, err
+	}
+	certs, err := listUserCertificates()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int, len(cas))
+	pending := make(map[string]struct{})
+	for _, uc := range certs {
+		i := issuerIndex(cas, uc.cert)
+		if i >= 0 {
+			counts[i]++
+		}
+		if i != 0 {
+			pending[uc.username] = struct{}{}
+		}
+	}
+
+	status := &CAStatus{
+		InProgress: len(cas) > 1,
+		CAs:        make([]CAInfo, 0, len(cas)),
+		Pending:    make([]string, 0, len(pending)),
+	}
+	for i, ca := range cas {
+		status.CAs = append(status.CAs, CAInfo{
+			Serial:       caSerial(ca.cert),
+			Subject:      ca.cert.Subject.String(),
+			State:        ca.state,
+			NotBefore:    ca.cert.NotBefore,
+			NotAfter:     ca.cert.NotAfter,
+			Certificates: counts[i],
+		})
+	}
+	for username := range pending {
+		status.Pending = append(status.Pending, username)
+	}
+	sort.Strings(status.Pending)
+	return status, nil
+}
+
+// RotateCA creates a new active CA. The current CA is kept as retiring, so
+// the certificates it issued keep working until they are reissued and the
+// CA is retired with RetireCA.
+func (u *OcservUser) RotateCA() error {
+	if err := ensureCertificatePKI(); err != nil {
+		return err
+	}
+
+	cas, err := loadTrustedCAs()
+	if err != nil {
+		return err
+	}
+	if len(cas) > 1 {
+		return ErrCARotationInProgress
+	}
+	current := cas[0]
+
+	if err = os.MkdirAll(certCARetiringDir, 0700); err != nil {
+		return err
+	}
+
+	// the current key is copied as is, certtool may have written it
+	currentKey, err := os.ReadFile(certCAKeyPath)
+	if err != nil {
+		return err
+	}
+	currentBundle, err := os.ReadFile(certCACertPath)
+	if err != nil {
+		return err
+	}
+
+	cert, key, err := newCA(certificateProfile(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return err
+	}
+
+	base := filepath.Join(certCARetiringDir, caSerial(current.cert))
+	rollback := func(err error) error {
+		_ = utils.WriteFileAtomic(certCACertPath, currentBundle, 0644)
+		_ = utils.WriteFileAtomic(certCAKeyPath, currentKey, 0600)
+		_ = os.Remove(base + "-cert.pem")
+		_ = os.Remove(base + "-key.pem")
+		return err
+	}
+
+	if err = os.WriteFile(base+"-cert.pem", encodeCertificatePEM(current.cert), 0644); err != nil {
+		return rollback(err)
+	}
+	if err = os.WriteFile(base+"-key.pem", currentKey, 0600); err != nil {
+		return rollback(err)
+	}
+
+	// the bundle goes first and a failed key write restores the old pair;
+	// meanwhile loadCA refuses the mismatched pair, so nothing is issued
+	if err = writeCABundle(cert, current.cert); err != nil {
+		return rollback(err)
+	}
+	if err = utils.WriteFileAtomic(certCAKeyPath, keyPEM, 0600); err != nil {
+		return rollback(err)
+	}
+
+	// the CRL must be signed by the new key, or ocserv rejects every client
+	if err = rebuildCertificateCRL(); err != nil {
+		return rollback(err)
+	}
+	return nil
+}
+
+// ReissueCertificate issues a new certificate under the active CA when the
+// certificate of username comes from another CA, and reports whether it
+// did. Suspended certificates are reissued suspended, so locked users stay
+// locked.
+func (u *OcservUser) ReissueCertificate(username, password string) (bool, error) {
+	if !ValidCertificateUsername(username) {
+		return false, fmt.Errorf("invalid username: %s", username)
+	}
+
+	if err := ensureCertificatePKI(); err != nil {
+		return false, err
+	}
+
+	cas, err := loadTrustedCAs()
+	if err != nil {
+		return false, err
+	}
+
+	activeCert := userCertificateFile(username, "cer")
+	if fileExists(activeCert) {
+		cert, err := readCertificateFile(activeCert)
+		if err != nil {
+			return false, err
+		}
+		if issuerIndex(cas, cert) == 0 {
+			return false, nil
+		}
+		return true, u.RenewCertificate(username, password)
+	}
+
+	suspendedDir := latestSuspendedCertificateDir(username)
+	if suspendedDir == "" {
+		return false, nil
+	}
+	cert, err := readCertificateFile(filepath.Join(suspendedDir, username+".cer"))
+	if err != nil {
+		return false, err
+	}
+	if issuerIndex(cas, cert) == 0 {
+		return false, nil
+	}
+
+	newDir := filepath.Join(
+		certDisabledDir,
+		fmt.Sprintf("%s-susp-%s", username, time.Now().Format("20060102-150405")),
+	)
+	if fileExists(newDir) {
+		return false, fmt.Errorf("certificate of %s changed in the last second, retry", username)
+	}
+	if err = writeCertificateFiles(newDir, username, password); err != nil {
+		return false, err
+	}
+
+	for _, dir := range suspendedCertificateDirs(username) {
+		if dir == newDir {
+			continue
+		}
+		if err = appendCertificateToFile(filepath.Join(dir, username+".cer"), certRevokedPath); err != nil {
+			return true, err
+		}
+		if err = os.RemoveAll(dir); err != nil {
+			return true, err
+		}
+	}
+
+	return true, rebuildCertificateCRL()
+}
+
+// RetireCA ends the rollover: the retiring CAs are archived and no longer
+// trusted. Unless force is set, it refuses while users still have a
+// certificate from another CA than the active one.
+func (u *OcservUser) RetireCA(force bool) error {
+	cas, err := loadTrustedCAs()
+	if err != nil {
+		return err
+	}
+	if len(cas) == 1 {
+		return ErrNoCARotation
+	}
+
+	if !force {
+		status, err := u.CAStatus()
+		if err != nil {
+			return err
+		}
+		if len(status.Pending) > 0 {
+			return fmt.Errorf("%d users still have a certificate from a retiring CA", len(status.Pending))
+		}
+	}
+
+	if err = os.MkdirAll(certCARetiredDir, 0700); err != nil {
+		return err
+	}
+
+	for _, ca := range cas[1:] {
+		for _, path := range []string{ca.certPath, ca.keyPath} {
+			if err = os.Rename(path, filepath.Join(certCARetiredDir, filepath.Base(path))); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = writeCABundle(cas[0].cert); err != nil {
+		return err
+	}
+
+	if err = pruneRevoked(cas[:1]); err != nil {
+		return err
+	}
+	return rebuildCertificateCRL()
+}
+
+// loadTrustedCAs returns the active CA followed by the retiring ones.
+func loadTrustedCAs() ([]trustedCA, error) {
+	cert, key, err := loadCA(certCACertPath, certCAKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	cas := []trustedCA{{cert: cert, key: key, state: CAStateActive, certPath: certCACertPath, keyPath: certCAKeyPath}}
+
+	paths, err := filepath.Glob(filepath.Join(certCARetiringDir, "*-cert.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	for _, certPath := range paths {
+		keyPath := strings.TrimSuffix(certPath, "-cert.pem") + "-key.pem"
+		cert, key, err := loadCA(certPath, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, trustedCA{cert: cert, key: key, state: CAStateRetiring, certPath: certPath, keyPath: keyPath})
+	}
+	return cas, nil
+}
+
+// writeCABundle writes the certificates ocserv trusts, active CA first.
+func writeCABundle(certs ...*x509.Certificate) error {
+	var bundle []byte
+	for _, cert := range certs {
+		bundle = append(bundle, encodeCertificatePEM(cert)...)
+	}
+	return utils.WriteFileAtomic(certCACertPath, bundle, 0644)
+}
+
+// issuerIndex returns the index of the CA that signed cert, or -1.
+func issuerIndex(cas []trustedCA, cert *x509.Certificate) int {
+	for i, ca := range cas {
+		if cert.CheckSignatureFrom(ca.cert) == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// pruneRevoked drops the revoked certificates no CA in cas issued.
+func pruneRevoked(cas []trustedCA) error {
+	content, err := os.ReadFile(certRevokedPath)
+	if err != nil {
+		return err
+	}
+	certs, err := parseCertificatesPEM(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", certRevokedPath, err)
+	}
+
+	var kept []byte
+	for _, cert := range certs {
+		if issuerIndex(cas, cert) >= 0 {
+			kept = append(kept, encodeCertificatePEM(cert)...)
+		}
+	}
+	return utils.WriteFileAtomic(certRevokedPath, kept, 0600)
+}
+
+// listUserCertificates returns the active and suspended user certificates.
+func listUserCertificates() ([]userCertificate, error) {
+	active, err := listActiveCertificates()
+	if err != nil {
+		return nil, err
+	}
+	suspended, err := listSuspendedCertificates()
+	if err != nil {
+		return nil, err
+	}
+	return append(active, suspended...), nil
+}
+
+func listActiveCertificates() ([]userCertificate, error) {
+	dirs, err := filepath.Glob(filepath.Join(certUsersDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []userCertificate
+	for _, dir := range dirs {
+		username := filepath.Base(dir)
+		path := filepath.Join(dir, username+".cer")
+		if !fileExists(path) {
+			continue
+		}
+		cert, err := readCertificateFile(path)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, userCertificate{username: username, cert: cert})
+	}
+	return certs, nil
+}
+
+func listSuspendedCertificates() ([]userCertificate, error) {
+	var certs []userCertificate
+	for _, dir := range suspendedCertificateDirs("*") {
+		username := suspendedUsernameFromDir(dir)
+		path := filepath.Join(dir, username+".cer")
+		if username == "" || !fileExists(path) {
+			continue
+		}
+		cert, err := readCertificateFile(path)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, userCertificate{username: username, cert: cert})
+	}
+	return certs, nil
+}
+
+func readCertificateFile(path string) (*x509.Certificate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := parseCertificatesPEM(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s: no certificate found", path)
+	}
+	return certs[0], nil
+}
+
+func caSerial(cert *x509.Certificate) string {
+	return cert.SerialNumber.Text(16)
+}
@@ -0,0 +1,33 @@
+package user
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssuerIndex(t *testing.T) {
+	now := time.Now().UTC()
+
+	var cas []trustedCA
+	for i := 0; i < 2; i++ {
+		cert, key, err := newCA(testProfile, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cas = append(cas, trustedCA{cert: cert, key: key})
+	}
+	if cas[0].cert.Subject.String() == cas[1].cert.Subject.String() {
+		t.Fatal("rotated CAs must have distinct subjects")
+	}
+
+	old, _, err := issueClientCertificate(cas[1].cert, cas[1].key, "bob", testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issuerIndex(cas, old); got != 1 {
+		t.Errorf("issuerIndex() = %d, want 1", got)
+	}
+	if got := issuerIndex(cas[:1], old); got != -1 {
+		t.Errorf("issuerIndex() = %d, want -1 once the issuer is retired", got)
+	}
+}
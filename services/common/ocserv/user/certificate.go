@@ -1,9 +1,12 @@
 package user
 
 import (
+	"crypto/x509"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -20,11 +23,12 @@ const (
 	certCACertPath   = certSSLDir + "/ca-cert.pem"
 	certCAKeyPath    = certSSLDir + "/ca-key.pem"
 	certCRLPath      = certSSLDir + "/crl.pem"
-	certCRLTmplPath  = certSSLDir + "/crl.tmpl"
 	certRevokedPath  = certSSLDir + "/revoked.pem"
 	certSuspendedPEM = certSSLDir + "/suspended.pem"
-	certtoolExec     = "/usr/bin/certtool"
-	opensslExec      = "/usr/bin/openssl"
+
+	// CertificateValidityDays is the default lifetime of the client
+	// certificates CreateCertificate issues, see CERT_VALIDITY_DAYS.
+	CertificateValidityDays = 825
 )
 
 var certificateUsernameRe = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
@@ -44,11 +48,21 @@ func (u *OcservUser) CertificateStatus(username string) CertificateStatus {
 	}
 }
 
-func (u *OcservUser) CertificatePath(username string) (string, error) {
-	if !validCertificateUsername(username) {
-		return "", fmt.Errorf("invalid username: %s", username)
+// Certificate returns the PKCS#12 bundle of the active certificate of
+// username, or of the latest suspended one.
+func (u *OcservUser) Certificate(username string) ([]byte, error) {
+	if !ValidCertificateUsername(username) {
+		return nil, fmt.Errorf("invalid username: %s", username)
 	}
 
+	path, err := certificatePath(username)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+func certificatePath(username string) (string, error) {
 	activePath := userCertificateFile(username, "p12")
 	if fileExists(activePath) {
 		return activePath, nil
@@ -65,8 +79,50 @@ func (u *OcservUser) CertificatePath(username string) (string, error) {
 	return "", fmt.Errorf("certificate not found for user %s", username)
 }
 
+// CertificateNotAfter returns the expiry of the active certificate of
+// username, or of the latest suspended one. It returns nil when the user
+// has no certificate.
+func (u *OcservUser) CertificateNotAfter(username string) (*time.Time, error) {
+	if !ValidCertificateUsername(username) {
+		return nil, fmt.Errorf("invalid username: %s", username)
+	}
+
+	certPath := userCertificateFile(username, "cer")
+	if !fileExists(certPath) {
+		suspendedDir := latestSuspendedCertificateDir(username)
+		if suspendedDir == "" {
+			return nil, nil
+		}
+		certPath = filepath.Join(suspendedDir, username+".cer")
+	}
+
+	content, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	notAfter, err := ParseCertificateNotAfter(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certPath, err)
+	}
+	return &notAfter, nil
+}
+
+// ParseCertificateNotAfter returns the expiry of the first certificate in a
+// PEM bundle.
+func ParseCertificateNotAfter(content []byte) (time.Time, error) {
+	certs, err := parseCertificatesPEM(content)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(certs) == 0 {
+		return time.Time{}, errors.New("no certificate found")
+	}
+	return certs[0].NotAfter.UTC(), nil
+}
+
 func (u *OcservUser) CreateCertificate(username, password string) error {
-	if !validCertificateUsername(username) {
+	if !ValidCertificateUsername(username) {
 		return fmt.Errorf("invalid username: %s", username)
 	}
 
@@ -79,76 +135,57 @@ func (u *OcservUser) CreateCertificate(username, password string) error {
 		return err
 	}
 
-	userDir := filepath.Join(certUsersDir, username)
-	if err := os.MkdirAll(userDir, 0700); err != nil {
+	return writeCertificateFiles(filepath.Join(certUsersDir, username), username, password)
+}
+
+// writeCertificateFiles issues a certificate for username under the active
+// CA and writes its key, certificate and P12 bundle to dir. dir is removed
+// again when issuing fails.
+func writeCertificateFiles(dir, username, password string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
 	}
 
 	cleanup := true
 	defer func() {
 		if cleanup {
-			_ = os.RemoveAll(userDir)
+			_ = os.RemoveAll(dir)
 		}
 	}()
 
-	keyPath := filepath.Join(userDir, username+"-key.pem")
-	tmplPath := filepath.Join(userDir, username+".tmpl")
-	certPath := filepath.Join(userDir, username+".cer")
-	p12Path := filepath.Join(userDir, username+".p12")
-
-	if err := runCommand(certtoolExec, "--generate-privkey", "--outfile", keyPath); err != nil {
+	ca, caKey, err := loadCA(certCACertPath, certCAKeyPath)
+	if err != nil {
 		return err
 	}
 
-	activationDate := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02 15:04:05 UTC")
-
-	tmpl := fmt.Sprintf(`cn = "%s"
-tls_www_client
-encryption_key
-signing_key
-activation_date = "%s"
-expiration_days = 825
-`, username, activationDate)
-
-	if err := os.WriteFile(tmplPath, []byte(tmpl), 0600); err != nil {
+	p := certificateProfile()
+	cert, key, err := issueClientCertificate(ca, caKey, username, p, time.Now().UTC())
+	if err != nil {
 		return err
 	}
 
-	if err := runCommand(
-		certtoolExec,
-		"--generate-certificate",
-		"--load-privkey", keyPath,
-		"--load-ca-certificate", certCACertPath,
-		"--load-ca-privkey", certCAKeyPath,
-		"--template", tmplPath,
-		"--outfile", certPath,
-	); err != nil {
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
 		return err
 	}
 
-	if err := runCommand(
-		opensslExec,
-		"pkcs12",
-		"-export",
-		"-legacy",
-		"-keypbe", "PBE-SHA1-3DES",
-		"-certpbe", "PBE-SHA1-3DES",
-		"-macalg", "sha1",
-		"-inkey", keyPath,
-		"-in", certPath,
-		"-certfile", certCACertPath,
-		"-name", "AnyConnect VPN - "+username,
-		"-out", p12Path,
-		"-passout", "pass:"+password,
-	); err != nil {
+	p12, err := encodeP12(key, cert, ca, "AnyConnect VPN - "+username, password, p)
+	if err != nil {
 		return err
 	}
 
-	if err := os.Chmod(keyPath, 0600); err != nil {
-		return err
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{username + "-key.pem", keyPEM},
+		{username + ".cer", encodeCertificatePEM(cert)},
+		{username + ".p12", p12},
 	}
-	if err := os.Chmod(p12Path, 0600); err != nil {
-		return err
+	for _, f := range files {
+		if err = os.WriteFile(filepath.Join(dir, f.name), f.content, 0600); err != nil {
+			return err
+		}
 	}
 
 	cleanup = false
@@ -156,7 +193,7 @@ expiration_days = 825
 }
 
 func (u *OcservUser) SuspendCertificate(username string) error {
-	if !validCertificateUsername(username) {
+	if !ValidCertificateUsername(username) {
 		return fmt.Errorf("invalid username: %s", username)
 	}
 
@@ -186,7 +223,7 @@ func (u *OcservUser) SuspendCertificate(username string) error {
 }
 
 func (u *OcservUser) UnsuspendCertificate(username string) error {
-	if !validCertificateUsername(username) {
+	if !ValidCertificateUsername(username) {
 		return fmt.Errorf("invalid username: %s", username)
 	}
 
@@ -212,7 +249,7 @@ func (u *OcservUser) UnsuspendCertificate(username string) error {
 }
 
 func (u *OcservUser) RevokeCertificate(username string) error {
-	if !validCertificateUsername(username) {
+	if !ValidCertificateUsername(username) {
 		return fmt.Errorf("invalid username: %s", username)
 	}
 
@@ -254,8 +291,79 @@ func (u *OcservUser) RevokeCertificate(username string) error {
 	return nil
 }
 
+// RenewCertificate issues a new certificate for username and revokes the
+// active one. The old certificate is kept until the new one is issued and
+// listed in the CRL, so a failed renewal leaves the user with a working
+// certificate.
+func (u *OcservUser) RenewCertificate(username, password string) error {
+	if !ValidCertificateUsername(username) {
+		return fmt.Errorf("invalid username: %s", username)
+	}
+
+	activeDir := filepath.Join(certUsersDir, username)
+	oldCert := filepath.Join(activeDir, username+".cer")
+	if !fileExists(oldCert) {
+		return fmt.Errorf("no active certificate for user %s", username)
+	}
+
+	if err := ensureCertificatePKI(); err != nil {
+		return err
+	}
+
+	revokedBefore, err := os.ReadFile(certRevokedPath)
+	if err != nil {
+		return err
+	}
+
+	oldDir := filepath.Join(
+		certDisabledDir,
+		fmt.Sprintf("%s-renew-%s", username, time.Now().Format("20060102-150405")),
+	)
+	if err = os.Rename(activeDir, oldDir); err != nil {
+		return err
+	}
+
+	// restore puts the old certificate back in place of whatever was
+	// written since and takes it off the revoked list again
+	restore := func(err error) error {
+		var errs []error
+		if rmErr := os.RemoveAll(activeDir); rmErr != nil {
+			errs = append(errs, rmErr)
+		} else if renameErr := os.Rename(oldDir, activeDir); renameErr != nil {
+			errs = append(errs, renameErr)
+		}
+		if writeErr := utils.WriteFileAtomic(certRevokedPath, revokedBefore, 0600); writeErr != nil {
+			errs = append(errs, writeErr)
+		}
+		if restoreErr := errors.Join(errs...); restoreErr != nil {
+			return fmt.Errorf("%w (restoring the old certificate failed: %v)", err, restoreErr)
+		}
+		return err
+	}
+
+	// CreateCertificate would skip issuing when a suspended certificate of
+	// the user is left over, the new one is written unconditionally
+	if err = writeCertificateFiles(activeDir, username, password); err != nil {
+		return restore(err)
+	}
+	if !fileExists(filepath.Join(activeDir, username+".cer")) {
+		return restore(fmt.Errorf("no certificate was issued for user %s", username))
+	}
+
+	if err = appendCertificateToFile(filepath.Join(oldDir, username+".cer"), certRevokedPath); err != nil {
+		return restore(err)
+	}
+	if err = rebuildCertificateCRL(); err != nil {
+		return restore(err)
+	}
+
+	// the old certificate is revoked from here on, putting it back would
+	// hand out a certificate ocserv refuses
+	return os.RemoveAll(oldDir)
+}
+
 func (u *OcservUser) CertificateBackup(username string) (*models.OcservUserCertificateBackup, error) {
-	if !validCertificateUsername(username) {
+	if !ValidCertificateUsername(username) {
 		return nil, fmt.Errorf("invalid username: %s", username)
 	}
 
@@ -298,7 +406,7 @@ func (u *OcservUser) RestoreCertificateBackup(username string, cert *models.Ocse
 		return nil
 	}
 
-	if !validCertificateUsername(username) {
+	if !ValidCertificateUsername(username) {
 		return fmt.Errorf("invalid username: %s", username)
 	}
 
@@ -362,7 +470,7 @@ func ensureCertificatePKI() error {
 	}
 
 	if !fileExists(certCRLPath) {
-		if err := generateCertificateCRL(""); err != nil {
+		if err := generateCertificateCRL(nil); err != nil {
 			return err
 		}
 	}
@@ -381,12 +489,6 @@ func ensureCertificateDirsAndFiles() error {
 		return err
 	}
 
-	if !fileExists(certCRLTmplPath) {
-		if err := os.WriteFile(certCRLTmplPath, []byte("crl_next_update = 365\ncrl_number = 1\n"), 0600); err != nil {
-			return err
-		}
-	}
-
 	for _, path := range []string{certRevokedPath, certSuspendedPEM} {
 		if !fileExists(path) {
 			if err := os.WriteFile(path, []byte{}, 0600); err != nil {
@@ -399,33 +501,20 @@ func ensureCertificateDirsAndFiles() error {
 }
 
 func generateCertificateCA() error {
-	tmplPath := filepath.Join(certSSLDir, "ca.tmpl")
-
-	tmpl := `cn = "Ocserv Dashboard CA"
-organization = "Ocserv Dashboard"
-serial = 1
-expiration_days = 3650
-ca
-signing_key
-cert_signing_key
-crl_signing_key
-`
-
-	if err := os.WriteFile(tmplPath, []byte(tmpl), 0600); err != nil {
+	cert, key, err := newCA(certificateProfile(), time.Now().UTC())
+	if err != nil {
 		return err
 	}
 
-	if err := runCommand(certtoolExec, "--generate-privkey", "--outfile", certCAKeyPath); err != nil {
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
 		return err
 	}
 
-	return runCommand(
-		certtoolExec,
-		"--generate-self-signed",
-		"--load-privkey", certCAKeyPath,
-		"--template", tmplPath,
-		"--outfile", certCACertPath,
-	)
+	if err = os.WriteFile(certCAKeyPath, keyPEM, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(certCACertPath, encodeCertificatePEM(cert), 0644)
 }
 
 func rebuildCertificateCRL() error {
@@ -437,39 +526,20 @@ func rebuildCertificateCRL() error {
 		return err
 	}
 
-	tmpPath := filepath.Join(certSSLDir, ".crl-input.tmp")
-	if err := os.WriteFile(tmpPath, []byte{}, 0600); err != nil {
-		return err
-	}
-	defer os.Remove(tmpPath)
-
+	var revoked []*x509.Certificate
 	for _, path := range []string{certRevokedPath, certSuspendedPEM} {
 		content, err := os.ReadFile(path)
 		if err != nil {
 			return err
 		}
-		if len(strings.TrimSpace(string(content))) > 0 {
-			if err := appendBytesToFile(tmpPath, content); err != nil {
-				return err
-			}
-		}
-	}
-
-	if fileExists(tmpPath) {
-		content, err := os.ReadFile(tmpPath)
+		certs, err := parseCertificatesPEM(content)
 		if err != nil {
-			return err
-		}
-		if len(strings.TrimSpace(string(content))) > 0 {
-			if err := generateCertificateCRL(tmpPath); err != nil {
-				return err
-			}
-			signalOcservReloadCRL()
-			return nil
+			return fmt.Errorf("%s: %w", path, err)
 		}
+		revoked = append(revoked, certs...)
 	}
 
-	if err := generateCertificateCRL(""); err != nil {
+	if err := generateCertificateCRL(revoked); err != nil {
 		return err
 	}
 
@@ -499,21 +569,40 @@ func rebuildSuspendedPEM() error {
 	return nil
 }
 
-func generateCertificateCRL(inputPath string) error {
-	args := []string{
-		"--generate-crl",
-		"--load-ca-privkey", certCAKeyPath,
-		"--load-ca-certificate", certCACertPath,
-		"--template", certCRLTmplPath,
+// generateCertificateCRL writes one CRL per trusted CA, each listing the
+// revoked certificates that CA issued. Certificates of retired CAs are
+// dropped, ocserv no longer trusts them.
+func generateCertificateCRL(revoked []*x509.Certificate) error {
+	cas, err := loadTrustedCAs()
+	if err != nil {
+		return err
 	}
 
-	if inputPath != "" {
-		args = append(args, "--load-certificate", inputPath)
+	byCA := make([][]*x509.Certificate, len(cas))
+	for _, cert := range revoked {
+		if i := issuerIndex(cas, cert); i >= 0 {
+			byCA[i] = append(byCA[i], cert)
+		}
+	}
+
+	// certificates already listed keep their revocation time
+	previous, err := os.ReadFile(certCRLPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
+	revokedAt := crlRevocationTimes(previous)
 
-	args = append(args, "--outfile", certCRLPath)
+	now := time.Now().UTC()
 
-	return runCommand(certtoolExec, args...)
+	var crls []byte
+	for i, ca := range cas {
+		crl, err := generateCRL(ca.cert, ca.key, byCA[i], revokedAt, now)
+		if err != nil {
+			return err
+		}
+		crls = append(crls, crl...)
+	}
+	return utils.WriteFileAtomic(certCRLPath, crls, 0644)
 }
 
 func signalOcservReloadCRL() {
@@ -530,7 +619,9 @@ func signalOcservReloadCRL() {
 	_ = exec.Command("/bin/kill", "-HUP", pid).Run()
 }
 
-func validCertificateUsername(username string) bool {
+// ValidCertificateUsername reports whether username can be used as a
+// certificate common name and directory name.
+func ValidCertificateUsername(username string) bool {
 	return certificateUsernameRe.MatchString(username) && username != "." && username != ".."
 }
 
@@ -599,15 +690,6 @@ func appendBytesToFile(dstPath string, content []byte) error {
 	return nil
 }
 
-func runCommand(name string, args ...string) error {
-	cmd := exec.Command(name, args...)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%s %s failed: %s: %w", name, strings.Join(args, " "), strings.TrimSpace(string(out)), err)
-	}
-	return nil
-}
-
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -0,0 +1,53 @@
+package user
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestParseCertificateNotAfter(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notAfter := time.Date(2027, 3, 4, 5, 6, 7, 0, time.UTC)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bob"},
+		NotBefore:    notAfter.AddDate(0, 0, -CertificateValidityDays),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A key block ahead of the certificate is skipped.
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+
+	got, err := ParseCertificateNotAfter(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(notAfter) {
+		t.Errorf("ParseCertificateNotAfter() = %v, want %v", got, notAfter)
+	}
+
+	if _, err = ParseCertificateNotAfter([]byte("not a certificate")); err == nil {
+		t.Error("expected an error without a certificate")
+	}
+}
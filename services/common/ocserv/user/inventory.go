@@ -0,0 +1,124 @@
+package user
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Certificate states of the inventory.
+const (
+	CertificateStateActive    = "active"
+	CertificateStateSuspended = "suspended"
+	CertificateStateRevoked   = "revoked"
+)
+
+// CertificateRecord is an issued user certificate.
+type CertificateRecord struct {
+	Serial   string `json:"serial" validate:"required"`
+	Subject  string `json:"subject" validate:"required"`
+	Username string `json:"username" validate:"required"`
+	State    string `json:"state" enums:"active,suspended,revoked" validate:"required"`
+	// IssuerSerial is the serial of the trusted CA that issued the
+	// certificate, empty when that CA was retired.
+	IssuerSerial string    `json:"issuer_serial" validate:"omitempty"`
+	IssuedAt     time.Time `json:"issued_at" validate:"required"`
+	ExpiresAt    time.Time `json:"expires_at" validate:"required"`
+	Expired      bool      `json:"expired" validate:"required"`
+}
+
+// CertificateInventory lists the active, suspended and revoked user
+// certificates, ordered by username and issue date.
+func (u *OcservUser) CertificateInventory() ([]CertificateRecord, error) {
+	if err := ensureCertificatePKI(); err != nil {
+		return nil, err
+	}
+
+	cas, err := loadTrustedCAs()
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := listActiveCertificates()
+	if err != nil {
+		return nil, err
+	}
+	suspended, err := listSuspendedCertificates()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(certRevokedPath)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := parseCertificatesPEM(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", certRevokedPath, err)
+	}
+
+	now := time.Now()
+	records := make([]CertificateRecord, 0, len(active)+len(suspended)+len(revoked))
+	seen := make(map[string]struct{})
+
+	add := func(cert *x509.Certificate, state string) {
+		serial := cert.SerialNumber.Text(16)
+		if _, ok := seen[serial]; ok {
+			return
+		}
+		seen[serial] = struct{}{}
+
+		record := CertificateRecord{
+			Serial:    serial,
+			Subject:   cert.Subject.String(),
+			Username:  cert.Subject.CommonName,
+			State:     state,
+			IssuedAt:  cert.NotBefore,
+			ExpiresAt: cert.NotAfter,
+			Expired:   now.After(cert.NotAfter),
+		}
+		if i := issuerIndex(cas, cert); i >= 0 {
+			record.IssuerSerial = caSerial(cas[i].cert)
+		}
+		records = append(records, record)
+	}
+
+	// a renewed or reissued certificate may still sit in a directory while
+	// it is already revoked; revoked wins
+	for _, cert := range revoked {
+		add(cert, CertificateStateRevoked)
+	}
+	for _, uc := range active {
+		add(uc.cert, CertificateStateActive)
+	}
+	for _, uc := range suspended {
+		add(uc.cert, CertificateStateSuspended)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Username != records[j].Username {
+			return records[i].Username < records[j].Username
+		}
+		return records[i].IssuedAt.Before(records[j].IssuedAt)
+	})
+	return records, nil
+}
+
+// CACertificate returns the CA bundle ocserv trusts.
+func (u *OcservUser) CACertificate() ([]byte, error) {
+	if err := ensureCertificatePKI(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(certCACertPath)
+}
+
+// CRL returns the current certificate revocation lists, one per trusted
+// CA.
+func (u *OcservUser) CRL() ([]byte, error) {
+	if err := ensureCertificatePKI(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(certCRLPath)
+}
@@ -0,0 +1,242 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+// go-pkcs12 only tags the key bag with a localKeyId, so the friendly name
+// AnyConnect, iOS and Android show for the imported identity (openssl -name)
+// is added here: the key bag lives in the unencrypted SafeContents, which can
+// be rewritten without the password-derived encryption key, after which the
+// MAC is recomputed over the new authenticated safe.
+
+var (
+	oidDataContentType      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidShroudedKeyBag       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidFriendlyName         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidMacSHA1              = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidMacSHA256            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	errFriendlyNameNoKeyBag = errors.New("p12 bundle has no unencrypted key bag")
+)
+
+type p12PFX struct {
+	Version  int
+	AuthSafe p12ContentInfo
+	MacData  p12MacData `asn1:"optional"`
+}
+
+type p12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type p12MacData struct {
+	Mac        p12DigestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type p12DigestInfo struct {
+	Algorithm asn1.RawValue
+	Digest    []byte
+}
+
+type p12AlgorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type p12SafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue  `asn1:"tag:0,explicit"`
+	Attributes []p12Attribute `asn1:"set,optional"`
+}
+
+type p12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+// setP12FriendlyName adds a friendlyName attribute to the key bag of a
+// bundle produced by go-pkcs12 and re-signs it with password.
+func setP12FriendlyName(pfxData []byte, name, password string) ([]byte, error) {
+	var pfx p12PFX
+	if rest, err := asn1.Unmarshal(pfxData, &pfx); err != nil {
+		return nil, fmt.Errorf("parse p12: %w", err)
+	} else if len(rest) != 0 {
+		return nil, errors.New("parse p12: trailing data")
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return nil, errors.New("parse p12: authenticated safe is not data")
+	}
+
+	var authSafeData []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafeData); err != nil {
+		return nil, fmt.Errorf("parse p12 authenticated safe: %w", err)
+	}
+	var authSafe []p12ContentInfo
+	if _, err := asn1.Unmarshal(authSafeData, &authSafe); err != nil {
+		return nil, fmt.Errorf("parse p12 authenticated safe: %w", err)
+	}
+
+	attribute, err := friendlyNameAttribute(name)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i, ci := range authSafe {
+		if !ci.ContentType.Equal(oidDataContentType) {
+			continue
+		}
+		var safeData []byte
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &safeData); err != nil {
+			return nil, fmt.Errorf("parse p12 safe contents: %w", err)
+		}
+		var bags []p12SafeBag
+		if _, err := asn1.Unmarshal(safeData, &bags); err != nil {
+			return nil, fmt.Errorf("parse p12 safe contents: %w", err)
+		}
+
+		changed := false
+		for j := range bags {
+			if bags[j].ID.Equal(oidShroudedKeyBag) {
+				bags[j].Attributes = append(bags[j].Attributes, attribute)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		if safeData, err = asn1.Marshal(bags); err != nil {
+			return nil, err
+		}
+		if authSafe[i].Content, err = explicitOctetString(safeData); err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if !found {
+		return nil, errFriendlyNameNoKeyBag
+	}
+
+	if authSafeData, err = asn1.Marshal(authSafe); err != nil {
+		return nil, err
+	}
+	if pfx.AuthSafe.Content, err = explicitOctetString(authSafeData); err != nil {
+		return nil, err
+	}
+
+	var algorithm p12AlgorithmIdentifier
+	if _, err := asn1.Unmarshal(pfx.MacData.Mac.Algorithm.FullBytes, &algorithm); err != nil {
+		return nil, fmt.Errorf("parse p12 mac algorithm: %w", err)
+	}
+	var newHash func() hash.Hash
+	switch {
+	case algorithm.Algorithm.Equal(oidMacSHA1):
+		newHash = sha1.New
+	case algorithm.Algorithm.Equal(oidMacSHA256):
+		newHash = sha256.New
+	default:
+		return nil, fmt.Errorf("unsupported p12 mac algorithm %s", algorithm.Algorithm)
+	}
+
+	key := pbkdf(newHash, 64, bmpPassword(password), pfx.MacData.MacSalt, 3, pfx.MacData.Iterations, newHash().Size())
+	mac := hmac.New(newHash, key)
+	mac.Write(authSafeData)
+	pfx.MacData.Mac.Digest = mac.Sum(nil)
+
+	return asn1.Marshal(pfx)
+}
+
+func friendlyNameAttribute(name string) (p12Attribute, error) {
+	var bmp []byte
+	for _, r := range utf16.Encode([]rune(name)) {
+		bmp = append(bmp, byte(r>>8), byte(r))
+	}
+	value, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: 30, Bytes: bmp})
+	if err != nil {
+		return p12Attribute{}, err
+	}
+	return p12Attribute{
+		ID:    oidFriendlyName,
+		Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+	}, nil
+}
+
+func explicitOctetString(data []byte) (asn1.RawValue, error) {
+	octets, err := asn1.Marshal(data)
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: octets}, nil
+}
+
+// bmpPassword encodes password as the zero-terminated BMPString PKCS#12
+// feeds to its key derivation.
+func bmpPassword(password string) []byte {
+	var out []byte
+	for _, r := range utf16.Encode([]rune(password)) {
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return append(out, 0, 0)
+}
+
+// pbkdf is the PKCS#12 key derivation function (RFC 7292, appendix B.2)
+// with hash block size v and id 3 for MAC keys.
+func pbkdf(newHash func() hash.Hash, v int, password, salt []byte, id byte, iterations, size int) []byte {
+	fill := func(in []byte) []byte {
+		if len(in) == 0 {
+			return nil
+		}
+		n := v * ((len(in) + v - 1) / v)
+		out := make([]byte, n)
+		for i := range out {
+			out[i] = in[i%len(in)]
+		}
+		return out
+	}
+
+	d := make([]byte, v)
+	for i := range d {
+		d[i] = id
+	}
+	i := append(fill(salt), fill(password)...)
+
+	var out []byte
+	for len(out) < size {
+		h := newHash()
+		h.Write(d)
+		h.Write(i)
+		a := h.Sum(nil)
+		for r := 1; r < iterations; r++ {
+			h = newHash()
+			h.Write(a)
+			a = h.Sum(nil)
+		}
+		out = append(out, a...)
+		if len(out) >= size {
+			break
+		}
+
+		b := fill(a)[:v]
+		for j := 0; j < len(i); j += v {
+			// I_j = (I_j + B + 1) mod 2^(8v)
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				carry += int(i[j+k]) + int(b[k])
+				i[j+k] = byte(carry)
+				carry >>= 8
+			}
+		}
+	}
+	return out[:size]
+}
@@ -0,0 +1,360 @@
+package user
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	KeyTypeRSA   = "rsa"
+	KeyTypeECDSA = "ecdsa"
+
+	// P12ProfileLegacy encrypts bundles with PBE-SHA1-3DES and a SHA-1 MAC,
+	// the only profile older AnyConnect, iOS and Android clients import.
+	P12ProfileLegacy = "legacy"
+	// P12ProfileModern encrypts bundles with PBES2 AES-256 and a SHA-256 MAC.
+	P12ProfileModern = "modern"
+
+	defaultRSABits     = 3072
+	caValidityDays     = 3650
+	crlNextUpdateDays  = 365
+	serialNumberBits   = 128
+	certificateBackday = 24 * time.Hour
+)
+
+// CertificateProfile controls the keys, lifetime and PKCS#12 encryption of
+// the client certificates. The CA is created with the same key type.
+type CertificateProfile struct {
+	KeyType      string
+	RSABits      int
+	ValidityDays int
+	P12Profile   string
+}
+
+var (
+	profileOnce sync.Once
+	profile     CertificateProfile
+)
+
+// CertificateProfileFromEnv reads the profile from CERT_KEY_TYPE,
+// CERT_RSA_BITS, CERT_VALIDITY_DAYS and CERT_P12_PROFILE, falling back to
+// RSA 3072, CertificateValidityDays and the legacy P12 profile.
+func CertificateProfileFromEnv() CertificateProfile {
+	p := CertificateProfile{
+		KeyType:      strings.ToLower(strings.TrimSpace(os.Getenv("CERT_KEY_TYPE"))),
+		RSABits:      defaultRSABits,
+		ValidityDays: CertificateValidityDays,
+		P12Profile:   strings.ToLower(strings.TrimSpace(os.Getenv("CERT_P12_PROFILE"))),
+	}
+
+	if p.KeyType != KeyTypeECDSA {
+		p.KeyType = KeyTypeRSA
+	}
+	if p.P12Profile != P12ProfileModern {
+		p.P12Profile = P12ProfileLegacy
+	}
+	if bits, err := strconv.Atoi(os.Getenv("CERT_RSA_BITS")); err == nil && bits >= 2048 {
+		p.RSABits = bits
+	}
+	if days, err := strconv.Atoi(os.Getenv("CERT_VALIDITY_DAYS")); err == nil && days > 0 {
+		p.ValidityDays = days
+	}
+	return p
+}
+
+func certificateProfile() CertificateProfile {
+	profileOnce.Do(func() {
+		profile = CertificateProfileFromEnv()
+	})
+	return profile
+}
+
+func generatePrivateKey(p CertificateProfile) (crypto.Signer, error) {
+	if p.KeyType == KeyTypeECDSA {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	bits := p.RSABits
+	if bits == 0 {
+		bits = defaultRSABits
+	}
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+func randomSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+}
+
+// newCA creates a self-signed CA able to sign client certificates and CRLs.
+func newCA(p CertificateProfile, now time.Time) (*x509.Certificate, crypto.Signer, error) {
+	key, err := generatePrivateKey(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		// the serial keeps the subject unique across rotated CAs, so ocserv
+		// matches every CRL with its own CA
+		Subject: pkix.Name{
+			CommonName:   "Ocserv Dashboard CA",
+			Organization: []string{"Ocserv Dashboard"},
+			SerialNumber: serial.Text(16),
+		},
+		NotBefore:             now.Add(-certificateBackday),
+		NotAfter:              now.AddDate(0, 0, caValidityDays),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// issueClientCertificate signs a TLS client certificate for cn with a new
+// key. Like the certtool template it replaces, it is backdated by a day to
+// tolerate clients with a slow clock.
+func issueClientCertificate(
+	ca *x509.Certificate,
+	caKey crypto.Signer,
+	cn string,
+	p CertificateProfile,
+	now time.Time,
+) (*x509.Certificate, crypto.Signer, error) {
+	key, err := generatePrivateKey(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validity := p.ValidityDays
+	if validity <= 0 {
+		validity = CertificateValidityDays
+	}
+
+	keyUsage := x509.KeyUsageDigitalSignature
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             now.Add(-certificateBackday),
+		NotAfter:              now.AddDate(0, 0, validity),
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// generateCRL lists revoked under ca. Certificates keep the revocation
+// time revokedAt has for their serial number, the others are revoked at
+// now. The CRL number follows the clock so every rebuild supersedes the
+// previous list.
+func generateCRL(
+	ca *x509.Certificate, caKey crypto.Signer, revoked []*x509.Certificate, revokedAt map[string]time.Time, now time.Time,
+) ([]byte, error) {
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	seen := make(map[string]struct{}, len(revoked))
+	for _, cert := range revoked {
+		serial := cert.SerialNumber.String()
+		if _, ok := seen[serial]; ok {
+			continue
+		}
+		seen[serial] = struct{}{}
+
+		revocationTime, ok := revokedAt[serial]
+		if !ok {
+			revocationTime = now
+		}
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: revocationTime,
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(now.UnixNano()),
+		ThisUpdate:                now,
+		NextUpdate:                now.AddDate(0, 0, crlNextUpdateDays),
+		RevokedCertificateEntries: entries,
+	}, ca, caKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
+
+// crlRevocationTimes returns the revocation time of the entries of the PEM
+// encoded CRLs in content by serial number. Blocks that do not parse are
+// skipped.
+func crlRevocationTimes(content []byte) map[string]time.Time {
+	times := make(map[string]time.Time)
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return times
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			continue
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			times[entry.SerialNumber.String()] = entry.RevocationTime.UTC()
+		}
+	}
+}
+
+// encodeP12 bundles the client key and certificate with the CA, encrypted
+// for password according to the P12 profile.
+func encodeP12(key crypto.Signer, cert, ca *x509.Certificate, name, password string, p CertificateProfile) ([]byte, error) {
+	encoder := pkcs12.LegacyDES
+	if p.P12Profile == P12ProfileModern {
+		encoder = pkcs12.Modern2023
+	}
+	pfxData, err := encoder.Encode(key, cert, []*x509.Certificate{ca}, password)
+	if err != nil {
+		return nil, err
+	}
+	return setP12FriendlyName(pfxData, name, password)
+}
+
+func encodeCertificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func encodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// parsePrivateKeyPEM accepts the PKCS#8 keys written here as well as the
+// PKCS#1 and SEC 1 keys certtool wrote before.
+func parsePrivateKeyPEM(content []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return nil, errors.New("no private key found")
+		}
+
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			signer, ok := key.(crypto.Signer)
+			if !ok {
+				return nil, fmt.Errorf("unsupported private key type %T", key)
+			}
+			return signer, nil
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		}
+	}
+}
+
+// parseCertificatesPEM returns every certificate of a PEM bundle.
+func parseCertificatesPEM(content []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// loadCA reads the CA certificate and key from disk.
+func loadCA(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	certs, err := parseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("%s: no certificate found", certPath)
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", keyPath, err)
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(certs[0].PublicKey) {
+		return nil, nil, fmt.Errorf("%s does not match the certificate in %s", keyPath, certPath)
+	}
+	return certs[0], key, nil
+}
@@ -0,0 +1,263 @@
+package user
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+var testProfile = CertificateProfile{KeyType: KeyTypeECDSA, ValidityDays: 30, P12Profile: P12ProfileLegacy}
+
+func TestIssueClientCertificate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ca, caKey, err := newCA(testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, _, err := issueClientCertificate(ca, caKey, "bob", testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert.Subject.CommonName != "bob" {
+		t.Errorf("CommonName = %q, want bob", cert.Subject.CommonName)
+	}
+	if want := now.AddDate(0, 0, 30); !cert.NotAfter.Equal(want) {
+		t.Errorf("NotAfter = %v, want %v", cert.NotAfter, want)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		t.Errorf("client certificate does not verify against the CA: %v", err)
+	}
+}
+
+func TestIssueClientCertificateRSA(t *testing.T) {
+	p := CertificateProfile{KeyType: KeyTypeRSA, RSABits: 2048}
+	now := time.Now().UTC()
+
+	ca, caKey, err := newCA(p, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, key, err := issueClientCertificate(ca, caKey, "alice", p, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := key.(*rsa.PrivateKey); !ok {
+		t.Fatalf("key type = %T, want RSA", key)
+	}
+	if cert.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+		t.Error("RSA client certificates must allow key encipherment")
+	}
+	if want := now.AddDate(0, 0, CertificateValidityDays); !cert.NotAfter.Equal(want.Truncate(time.Second)) {
+		t.Errorf("NotAfter = %v, want the default validity %v", cert.NotAfter, want)
+	}
+}
+
+func TestGenerateCRL(t *testing.T) {
+	now := time.Now().UTC()
+	ca, caKey, err := newCA(testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, _, err := issueClientCertificate(ca, caKey, "bob", testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crlPEM, err := generateCRL(ca, caKey, []*x509.Certificate{revoked, revoked}, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := pem.Decode(crlPEM)
+	if block == nil || block.Type != "X509 CRL" {
+		t.Fatalf("unexpected CRL PEM %q", crlPEM)
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = crl.CheckSignatureFrom(ca); err != nil {
+		t.Errorf("CRL signature: %v", err)
+	}
+	if len(crl.RevokedCertificateEntries) != 1 || crl.RevokedCertificateEntries[0].SerialNumber.Cmp(revoked.SerialNumber) != 0 {
+		t.Errorf("expected the revoked serial once, got %+v", crl.RevokedCertificateEntries)
+	}
+}
+
+func TestGenerateCRLKeepsRevocationTime(t *testing.T) {
+	revokedAt := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	ca, caKey, err := newCA(testProfile, revokedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _, err := issueClientCertificate(ca, caKey, "bob", testProfile, revokedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crlPEM, err := generateCRL(ca, caKey, []*x509.Certificate{first}, nil, revokedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a later rebuild adds a certificate and keeps the time of the first
+	now := revokedAt.Add(48 * time.Hour)
+	second, _, err := issueClientCertificate(ca, caKey, "carol", testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crlPEM, err = generateCRL(ca, caKey, []*x509.Certificate{first, second}, crlRevocationTimes(crlPEM), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	times := crlRevocationTimes(crlPEM)
+	if got := times[first.SerialNumber.String()]; !got.Equal(revokedAt) {
+		t.Errorf("first certificate revoked at %v, want %v", got, revokedAt)
+	}
+	if got := times[second.SerialNumber.String()]; !got.Equal(now) {
+		t.Errorf("second certificate revoked at %v, want %v", got, now)
+	}
+}
+
+func TestEncodeP12(t *testing.T) {
+	now := time.Now().UTC()
+	ca, caKey, err := newCA(testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, key, err := issueClientCertificate(ca, caKey, "bob", testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range []string{P12ProfileLegacy, P12ProfileModern} {
+		p := testProfile
+		p.P12Profile = profile
+
+		p12, err := encodeP12(key, cert, ca, "AnyConnect VPN - bob", "s3cret", p)
+		if err != nil {
+			t.Fatalf("%s: %v", profile, err)
+		}
+
+		_, gotCert, gotCA, err := pkcs12.DecodeChain(p12, "s3cret")
+		if err != nil {
+			t.Fatalf("%s: decode: %v", profile, err)
+		}
+		if !gotCert.Equal(cert) || len(gotCA) != 1 || !gotCA[0].Equal(ca) {
+			t.Errorf("%s: bundle does not hold the client certificate and the CA", profile)
+		}
+
+		blocks, err := pkcs12.ToPEM(p12, "s3cret")
+		if err != nil {
+			t.Fatalf("%s: to pem: %v", profile, err)
+		}
+		named := false
+		for _, block := range blocks {
+			if block.Type == "PRIVATE KEY" && block.Headers["friendlyName"] == "AnyConnect VPN - bob" {
+				named = true
+			}
+		}
+		if !named {
+			t.Errorf("%s: key bag has no friendly name", profile)
+		}
+	}
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	key, err := generatePrivateKey(testProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parsePrivateKeyPEM(pkcs8); err != nil {
+		t.Errorf("PKCS#8: %v", err)
+	}
+
+	rsaKey, err := generatePrivateKey(CertificateProfile{KeyType: KeyTypeRSA, RSABits: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey.(*rsa.PrivateKey))})
+	if _, err = parsePrivateKeyPEM(pkcs1); err != nil {
+		t.Errorf("PKCS#1: %v", err)
+	}
+
+	if _, err = parsePrivateKeyPEM([]byte("garbage")); err == nil {
+		t.Error("expected an error without a key")
+	}
+}
+
+func TestCertificateProfileFromEnv(t *testing.T) {
+	t.Setenv("CERT_KEY_TYPE", "ECDSA")
+	t.Setenv("CERT_RSA_BITS", "1024")
+	t.Setenv("CERT_VALIDITY_DAYS", "365")
+	t.Setenv("CERT_P12_PROFILE", "bogus")
+
+	got := CertificateProfileFromEnv()
+	want := CertificateProfile{KeyType: KeyTypeECDSA, RSABits: defaultRSABits, ValidityDays: 365, P12Profile: P12ProfileLegacy}
+	if got != want {
+		t.Errorf("CertificateProfileFromEnv() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadCARejectsMismatchedKey(t *testing.T) {
+	now := time.Now().UTC()
+	ca, caKey, err := newCA(testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := newCA(testProfile, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "ca-cert.pem")
+	keyPath := filepath.Join(dir, "ca-key.pem")
+	if err = os.WriteFile(certPath, encodeCertificatePEM(ca), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		key     crypto.Signer
+		wantErr bool
+	}{
+		{caKey, false},
+		{otherKey, true},
+	} {
+		keyPEM, err := encodePrivateKeyPEM(tc.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err = loadCA(certPath, keyPath); (err != nil) != tc.wantErr {
+			t.Errorf("loadCA() error = %v, want error %v", err, tc.wantErr)
+		}
+	}
+}
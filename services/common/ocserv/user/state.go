@@ -0,0 +1,191 @@
+package user
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+)
+
+// UserState is what ApplyAuthMode may change for a user: the ocpasswd
+// entry, the per-user config file and the certificate. RestoreState puts
+// it back when ocserv rejects the change.
+type UserState struct {
+	// Ocpasswd is the line of the user in the ocpasswd file, empty when the
+	// user has none.
+	Ocpasswd string `json:"ocpasswd,omitempty"`
+	// Config is the content of the per-user config file and ConfigLink the
+	// group config it links to, both unset when the user has no file.
+	Config     *string `json:"config,omitempty"`
+	ConfigLink string  `json:"config_link,omitempty"`
+	// Certificate is the active or latest suspended certificate.
+	Certificate *models.OcservUserCertificateBackup `json:"certificate,omitempty"`
+}
+
+// SaveState reads the current state of username, see UserState.
+func (u *OcservUser) SaveState(username string) (*UserState, error) {
+	if err := stateUsername(username); err != nil {
+		return nil, err
+	}
+
+	state := &UserState{}
+
+	content, err := os.ReadFile(utils.OcpasswdPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if isOcpasswdLine(line, username) {
+			state.Ocpasswd = line
+			break
+		}
+	}
+
+	filename := utils.UserConfigFilePathCreator(username)
+	info, err := os.Lstat(filename)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	case info.Mode()&os.ModeSymlink != 0:
+		if state.ConfigLink, err = os.Readlink(filename); err != nil {
+			return nil, err
+		}
+	default:
+		config, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		text := string(config)
+		state.Config = &text
+	}
+
+	if ValidCertificateUsername(username) {
+		if state.Certificate, err = u.CertificateBackup(username); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// RestoreState puts back the state of username saved by SaveState. A
+// certificate issued since is revoked, one revoked since is restored and
+// dropped from the revoked list.
+func (u *OcservUser) RestoreState(username string, state *UserState) error {
+	if err := stateUsername(username); err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("no saved state for user %s", username)
+	}
+
+	if err := restoreOcpasswdLine(username, state.Ocpasswd); err != nil {
+		return err
+	}
+
+	filename := utils.UserConfigFilePathCreator(username)
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	switch {
+	case state.ConfigLink != "":
+		if err := os.Symlink(state.ConfigLink, filename); err != nil {
+			return err
+		}
+	case state.Config != nil:
+		if err := utils.WriteFileAtomic(filename, []byte(*state.Config), 0640); err != nil {
+			return err
+		}
+	}
+
+	if !ValidCertificateUsername(username) {
+		return nil
+	}
+	if state.Certificate == nil {
+		return u.RevokeCertificate(username)
+	}
+	if err := ensureCertificatePKI(); err != nil {
+		return err
+	}
+	if err := unrevokeCertificate([]byte(state.Certificate.CertPEM)); err != nil {
+		return err
+	}
+	return u.RestoreCertificateBackup(username, state.Certificate)
+}
+
+func stateUsername(username string) error {
+	if username == "" || filepath.Base(username) != username || username == "." || username == ".." {
+		return fmt.Errorf("invalid username: %s", username)
+	}
+	return nil
+}
+
+func isOcpasswdLine(line, username string) bool {
+	return !strings.HasPrefix(line, "#") && strings.HasPrefix(strings.TrimSpace(line), username+":")
+}
+
+// restoreOcpasswdLine replaces the ocpasswd entry of username with line, or
+// removes it when line is empty.
+func restoreOcpasswdLine(username, line string) error {
+	perm := os.FileMode(0600)
+	content, err := os.ReadFile(utils.OcpasswdPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if info, err := os.Stat(utils.OcpasswdPath); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	return utils.WriteFileAtomic(utils.OcpasswdPath, replaceOcpasswdLine(content, username, line), perm)
+}
+
+func replaceOcpasswdLine(content []byte, username, line string) []byte {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		if l == "" || isOcpasswdLine(l, username) {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// unrevokeCertificate drops the certificates in certPEM from the revoked
+// list. The CRL is rebuilt by the caller.
+func unrevokeCertificate(certPEM []byte) error {
+	certs, err := parseCertificatesPEM(certPEM)
+	if err != nil || len(certs) == 0 {
+		return err
+	}
+
+	content, err := os.ReadFile(certRevokedPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	revoked, err := parseCertificatesPEM(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", certRevokedPath, err)
+	}
+
+	var kept []byte
+	for _, cert := range revoked {
+		if cert.Equal(certs[0]) {
+			continue
+		}
+		kept = append(kept, encodeCertificatePEM(cert)...)
+	}
+	return utils.WriteFileAtomic(certRevokedPath, kept, 0600)
+}
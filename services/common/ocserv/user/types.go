@@ -1,6 +1,16 @@
 package user
 
+import "github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
+
 type Ocpasswd struct {
 	Username string `json:"username"`
 	Group    string `json:"group"`
+	Locked   bool   `json:"locked"`
+}
+
+// EffectiveConfig is the config ocserv applies to a user. Sources are the
+// files and sections read, lowest precedence first.
+type EffectiveConfig struct {
+	Sources []string                     `json:"sources"`
+	Values  []utils.EffectiveConfigValue `json:"values"`
 }
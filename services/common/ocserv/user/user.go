@@ -4,26 +4,36 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/server"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/utils"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 type OcservUser struct{}
 
 type OcservUserManagement interface {
 	Create(username, group, password string, config *models.OcservUserConfig) error
+	ApplyAuthMode(group, username, password, mode string, config *models.OcservUserConfig) error
 	Lock(username string) (string, error)
 	UnLock(username string) (string, error)
 	Delete(username string) (string, error)
+	SetGroup(username, group string) error
+	SaveState(username string) (*UserState, error)
+	RestoreState(username string, state *UserState) error
 }
 
 type OcservUserConfigManagement interface {
 	SyncConfig(username, group string, config *models.OcservUserConfig) error
 	CreateConfig(username string, config *models.OcservUserConfig) error
 	DeleteConfig(username string) error
+	RestoreConfig(username string) error
+	ConfigList(ctx context.Context) ([]string, error)
+	EffectiveConfig(username, group, vhost string) (*EffectiveConfig, error)
 }
 type OcservUserPasswords interface {
 	Ocpasswd(ctx context.Context) (*[]Ocpasswd, int, error)
@@ -32,19 +42,32 @@ type OcservUserPasswords interface {
 type OcservUserCertificateManagement interface {
 	CreateCertificate(username, password string) error
 	RevokeCertificate(username string) error
+	RenewCertificate(username, password string) error
 	SuspendCertificate(username string) error
 	UnsuspendCertificate(username string) error
 	CertificateStatus(username string) CertificateStatus
-	CertificatePath(username string) (string, error)
+	Certificate(username string) ([]byte, error)
+	CertificateNotAfter(username string) (*time.Time, error)
 	CertificateBackup(username string) (*models.OcservUserCertificateBackup, error)
 	RestoreCertificateBackup(username string, cert *models.OcservUserCertificateBackup) error
 }
 
+type OcservUserCAManagement interface {
+	CAStatus() (*CAStatus, error)
+	RotateCA() error
+	ReissueCertificate(username, password string) (bool, error)
+	RetireCA(force bool) error
+	CertificateInventory() ([]CertificateRecord, error)
+	CACertificate() ([]byte, error)
+	CRL() ([]byte, error)
+}
+
 type OcservUserInterface interface {
 	OcservUserManagement
 	OcservUserConfigManagement
 	OcservUserPasswords
 	OcservUserCertificateManagement
+	OcservUserCAManagement
 }
 
 func NewOcservUser() *OcservUser {
@@ -74,10 +97,44 @@ func (u *OcservUser) Create(group, username, password string, config *models.Ocs
 	return nil
 }
 
-// Lock disables a user account by running ocpasswd with the -l flag.
-// Returns the command output or an error.
+// ApplyAuthMode writes the user for the given auth mode: an ocpasswd
+// entry for password logins and a client certificate for certificate
+// logins. Credentials the mode does not use are removed, so switching
+// modes converges. The config file is written in every mode. The
+// certificate mode is refused unless ocserv.conf accepts certificate-only
+// logins, removing the ocpasswd entry would lock the user out.
+func (u *OcservUser) ApplyAuthMode(group, username, password, mode string, config *models.OcservUserConfig) error {
+	if models.AuthModeUsesPassword(mode) {
+		if err := u.Create(group, username, password, config); err != nil {
+			return err
+		}
+	} else {
+		serverConfig, err := server.NewOcservServer().Config()
+		if err != nil {
+			return err
+		}
+		if err = server.CheckAuthMode(serverConfig, mode); err != nil {
+			return err
+		}
+		if _, err = runOcpasswdIfPresent("-d", username); err != nil {
+			return err
+		}
+		if err := u.SyncConfig(username, group, config); err != nil {
+			return err
+		}
+	}
+
+	if models.AuthModeUsesCertificate(mode) {
+		return u.CreateCertificate(username, password)
+	}
+	return u.RevokeCertificate(username)
+}
+
+// Lock disables a user account by running ocpasswd with the -l flag and
+// suspending its certificate. Certificate-only users have no ocpasswd
+// entry to lock. Returns the command output or an error.
 func (u *OcservUser) Lock(username string) (string, error) {
-	output, err := utils.RunOcpasswd("-l", "-c", utils.OcpasswdPath, username)
+	output, err := runOcpasswdIfPresent("-l", username)
 	if err != nil {
 		return "", err
 	}
@@ -90,9 +147,10 @@ func (u *OcservUser) Lock(username string) (string, error) {
 }
 
 // UnLock re-enables a previously locked user account by running ocpasswd
-// with the -u flag. Returns the command output or an error.
+// with the -u flag and restoring its certificate. Returns the command
+// output or an error.
 func (u *OcservUser) UnLock(username string) (string, error) {
-	output, err := utils.RunOcpasswd("-u", "-c", utils.OcpasswdPath, username)
+	output, err := runOcpasswdIfPresent("-u", username)
 	if err != nil {
 		return "", err
 	}
@@ -110,12 +168,13 @@ func (u *OcservUser) Delete(username string) (string, error) {
 	if err := u.RevokeCertificate(username); err != nil {
 		return "", err
 	}
-	output, err := utils.RunOcpasswd("-d", "-c", utils.OcpasswdPath, username)
+
+	output, err := runOcpasswdIfPresent("-d", username)
 	if err != nil {
 		return "", err
 	}
 
-	if err := u.DeleteConfig(username); err != nil {
+	if err = u.DeleteConfig(username); err != nil {
 		return "", err
 	}
 
@@ -130,12 +189,17 @@ func (u *OcservUser) SyncConfig(username, group string, config *models.OcservUse
 		return err
 	}
 
-	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
-		return err
+	// a config file of the user's own is updated in place, to keep its
+	// comments and unknown directives
+	if HasConfigValues(config) {
+		return u.CreateConfig(username, config)
 	}
 
-	if hasConfigValues(config) {
-		return u.CreateConfig(username, config)
+	if err := utils.KeepPreviousConfigFile(filename); err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
 	}
 
 	group = strings.TrimSpace(group)
@@ -151,7 +215,9 @@ func (u *OcservUser) SyncConfig(username, group string, config *models.OcservUse
 	return os.Symlink(groupConfig, filename)
 }
 
-func hasConfigValues(config *models.OcservUserConfig) bool {
+// HasConfigValues reports whether config sets at least one option, i.e.
+// whether the user needs a config file of its own.
+func HasConfigValues(config *models.OcservUserConfig) bool {
 	if config == nil {
 		return false
 	}
@@ -181,10 +247,13 @@ func hasConfigValues(config *models.OcservUserConfig) bool {
 }
 
 // CreateConfig writes a per-user configuration file for the given username.
-// The configuration is serialized from OcservUserConfig using pkg.ConfigWriter.
-// The file is created with permission 0640 and stored in the user config directory.
+// The OcservUserConfig is applied to the existing file as a utils.ConfigDocument,
+// keeping its comments, order and unknown directives; a symlink to a group file
+// is replaced rather than followed. The result is validated, then atomically
+// replaces the file with permission 0640 in the user config directory, and the
+// previous version is kept for RestoreConfig.
 func (u *OcservUser) CreateConfig(username string, config *models.OcservUserConfig) error {
-	if !hasConfigValues(config) {
+	if !HasConfigValues(config) {
 		return nil
 	}
 
@@ -194,13 +263,27 @@ func (u *OcservUser) CreateConfig(username string, config *models.OcservUserConf
 		return err
 	}
 
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
-	if err != nil {
+	doc := utils.ParseConfigDocument(nil)
+	if info, err := os.Lstat(filename); err == nil && info.Mode().IsRegular() {
+		if doc, err = utils.ReadConfigDocument(filename); err != nil {
+			return err
+		}
+	}
+	doc.Apply(utils.ToMap(config))
+	if err := doc.Validate(models.OcservUserConfig{}); err != nil {
+		return err
+	}
+
+	if err := utils.KeepPreviousConfigFile(filename); err != nil {
 		return err
 	}
-	defer file.Close()
+	return utils.WriteFileAtomic(filename, doc.Bytes(), 0640)
+}
 
-	return utils.ConfigWriter(file, utils.ToMap(config))
+// RestoreConfig puts back the per-user configuration file, or its symlink to
+// the group file, as it was before the last CreateConfig or SyncConfig.
+func (u *OcservUser) RestoreConfig(username string) error {
+	return utils.RestoreConfigFile(utils.UserConfigFilePathCreator(username))
 }
 
 // DeleteConfig removes the per-user configuration file for the given username.
@@ -213,6 +296,127 @@ func (u *OcservUser) DeleteConfig(username string) error {
 	return nil
 }
 
+// ConfigList returns the usernames having a file, or a symlink to their
+// group config, in the user config directory. Temp files of interrupted
+// writes are skipped.
+func (u *OcservUser) ConfigList(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(utils.ConfigUserBaseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if e.IsDir() || utils.IsAtomicTempFile(e.Name()) {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+// EffectiveConfig merges the config files ocserv reads for username: the
+// per-user directives of ocserv.conf and of the section of vhost,
+// overridden by the file of group (or the defaults group file when group
+// has none) and then by the user file.
+func (u *OcservUser) EffectiveConfig(username, group, vhost string) (*EffectiveConfig, error) {
+	groupFile := utils.DefaultGroupFile
+	if group != "" && group != "defaults" {
+		path := utils.GroupConfigFilePathCreator(group)
+		if _, err := os.Stat(path); err == nil {
+			groupFile = path
+		}
+	}
+	files := []string{server.ConfigPath, groupFile, utils.UserConfigFilePathCreator(username)}
+
+	// ocserv.conf also holds server-wide directives, only the ones a group
+	// or user file can set apply per user
+	perUser := utils.ToMap(models.OcservGroupConfig{})
+	for key := range utils.ToMap(models.OcservUserConfig{}) {
+		perUser[key] = nil
+	}
+
+	layer := func(source string, directives map[string][]string, serverWide bool) utils.ConfigLayer {
+		config := make(map[string]interface{})
+		for key, values := range directives {
+			if _, ok := perUser[key]; serverWide && !ok {
+				continue
+			}
+			config[key] = values
+		}
+		return utils.ConfigLayer{Source: source, Config: config}
+	}
+
+	vhost = models.VHostName(vhost)
+	layers := make([]utils.ConfigLayer, 0, len(files)+1)
+	for i, path := range files {
+		doc, err := utils.ReadConfigDocument(path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer(path, doc.Directives(), i == 0))
+
+		if i == 0 && vhost != models.DefaultVHost {
+			section := "vhost:" + vhost
+			layers = append(layers, layer(path+" ["+section+"]", doc.SectionDirectives(section), true))
+		}
+	}
+
+	sources := make([]string, 0, len(layers))
+	for _, l := range layers {
+		sources = append(sources, l.Source)
+	}
+
+	_, values := utils.MergeConfigs(layers...)
+	return &EffectiveConfig{Sources: sources, Values: values}, nil
+}
+
+// SetGroup moves username to group in the ocpasswd file, keeping the
+// password hash and lock state. ocpasswd -g would prompt for a new
+// password, so the entry is rewritten in place. The defaults group is
+// stored as "*".
+func (u *OcservUser) SetGroup(username, group string) error {
+	group = strings.TrimSpace(group)
+	if group == "" || group == "defaults" {
+		group = "*"
+	}
+
+	info, err := os.Stat(utils.OcpasswdPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(utils.OcpasswdPath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 3 || parts[0] != username || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines[i] = parts[0] + ":" + group + ":" + parts[2]
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("user %s not found in ocpasswd", username)
+	}
+
+	tmp := utils.OcpasswdPath + ".tmp"
+	if err = os.WriteFile(tmp, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, utils.OcpasswdPath)
+}
+
 // Ocpasswd reads the ocpasswd file and returns a list of all user entries.
 // Each line of the ocpasswd file describes one user, including their username,
 // password hash information, and optional attributes such as assigned groups.
@@ -264,6 +468,8 @@ func (u *OcservUser) Ocpasswd(ctx context.Context) (*[]Ocpasswd, int, error) {
 		users = append(users, Ocpasswd{
 			Username: username,
 			Group:    group,
+			// ocpasswd -l prefixes the password hash with '!'
+			Locked: strings.HasPrefix(parts[2], "!"),
 		})
 
 	}
@@ -279,3 +485,33 @@ func (u *OcservUser) Ocpasswd(ctx context.Context) (*[]Ocpasswd, int, error) {
 
 	return &users, total, nil
 }
+
+// runOcpasswdIfPresent runs ocpasswd with flag on username when it has an
+// ocpasswd entry. Certificate-only users have none.
+func runOcpasswdIfPresent(flag, username string) (string, error) {
+	if !hasOcpasswdEntry(username) {
+		return "", nil
+	}
+	return utils.RunOcpasswd(flag, "-c", utils.OcpasswdPath, username)
+}
+
+// hasOcpasswdEntry reports whether username has a line in the ocpasswd
+// file.
+func hasOcpasswdEntry(username string) bool {
+	content, err := os.ReadFile(utils.OcpasswdPath)
+	if err != nil {
+		// let ocpasswd report the problem
+		return true
+	}
+	return ocpasswdContains(content, username)
+}
+
+func ocpasswdContains(content []byte, username string) bool {
+	prefix := username + ":"
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), prefix) {
+			return true
+		}
+	}
+	return false
+}
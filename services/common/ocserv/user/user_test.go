@@ -0,0 +1,44 @@
+package user
+
+import "testing"
+
+func TestOcpasswdContains(t *testing.T) {
+	content := []byte("alice:defaults:$5$abc\n  bob:staff:!$5$def\nalice2:*:$5$ghi\n")
+
+	tests := []struct {
+		username string
+		want     bool
+	}{
+		{"alice", true},
+		{"bob", true},
+		{"alice2", true},
+		{"ali", false},
+		{"carol", false},
+	}
+	for _, tt := range tests {
+		if got := ocpasswdContains(content, tt.username); got != tt.want {
+			t.Errorf("ocpasswdContains(%q) = %v, want %v", tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceOcpasswdLine(t *testing.T) {
+	content := []byte("alice:defaults:$5$abc\nbob:staff:!$5$def\n")
+
+	tests := []struct {
+		name     string
+		username string
+		line     string
+		want     string
+	}{
+		{"replace", "bob", "bob:staff:$5$def", "alice:defaults:$5$abc\nbob:staff:$5$def\n"},
+		{"remove", "alice", "", "bob:staff:!$5$def\n"},
+		{"add", "carol", "carol:*:$5$ghi", "alice:defaults:$5$abc\nbob:staff:!$5$def\ncarol:*:$5$ghi\n"},
+		{"prefix", "ali", "", string(content)},
+	}
+	for _, tt := range tests {
+		if got := string(replaceOcpasswdLine(content, tt.username, tt.line)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
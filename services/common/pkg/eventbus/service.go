@@ -0,0 +1,80 @@
+package eventbus
+
+import "time"
+
+func New() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Default returns the process wide bus.
+func Default() *Bus {
+	return defaultBus
+}
+
+// Subscribe registers a subscriber for the given topics, or for every topic
+// when none are given. buffer sets how many events may be queued before new
+// ones are dropped for this subscriber.
+func (b *Bus) Subscribe(buffer int, topics ...string) *Subscription {
+	ch := make(chan Event, buffer)
+	s := &Subscription{
+		C:      ch,
+		ch:     ch,
+		topics: make(map[string]struct{}, len(topics)),
+		bus:    b,
+	}
+	for _, t := range topics {
+		s.topics[t] = struct{}{}
+	}
+
+	b.mu.Lock()
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+	return s
+}
+
+// Publish delivers payload to every subscriber of topic.
+func (b *Bus) Publish(topic string, payload interface{}) {
+	event := Event{Topic: topic, Payload: payload, Time: time.Now()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subs {
+		if len(s.topics) > 0 {
+			if _, ok := s.topics[topic]; !ok {
+				continue
+			}
+		}
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close unsubscribes and closes C. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// Publish publishes on the default bus.
+func Publish(topic string, payload interface{}) {
+	defaultBus.Publish(topic, payload)
+}
+
+// Subscribe subscribes to the default bus.
+func Subscribe(buffer int, topics ...string) *Subscription {
+	return defaultBus.Subscribe(buffer, topics...)
+}
@@ -0,0 +1,38 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a single message published on the bus.
+type Event struct {
+	Topic   string
+	Payload interface{}
+	Time    time.Time
+}
+
+// Bus fans published events out to every subscriber of the event topic.
+// Publishing never blocks: events are dropped for subscribers that do not
+// keep up.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription receives events for its topics on C until Close is called.
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	topics map[string]struct{}
+	bus    *Bus
+	once   sync.Once
+}
+
+// Topics published by the dashboard services
+const (
+	TopicOcctlEvent = "occtl.event"
+)
+
+var defaultBus = New()
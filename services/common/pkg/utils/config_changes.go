@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+// ConfigChanges compares two configs, as produced by ToMap from a config
+// model, directive by directive. Values are compared as they would be
+// written to the config file, so nil, empty and false are the same.
+func ConfigChanges(oldConfig, newConfig map[string]interface{}) models.OcservConfigChanges {
+	keys := make(map[string]struct{}, len(oldConfig)+len(newConfig))
+	for key := range oldConfig {
+		keys[key] = struct{}{}
+	}
+	for key := range newConfig {
+		keys[key] = struct{}{}
+	}
+
+	changes := models.OcservConfigChanges{}
+	for key := range keys {
+		oldValues := configValues(oldConfig[key])
+		newValues := configValues(newConfig[key])
+		if slices.Equal(oldValues, newValues) {
+			continue
+		}
+		changes = append(changes, models.OcservConfigChange{
+			Key: key,
+			Old: oldValues,
+			New: newValues,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
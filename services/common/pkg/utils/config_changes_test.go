@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestConfigChanges(t *testing.T) {
+	oldConfig := map[string]interface{}{
+		"dns":          []interface{}{"10.0.0.53"},
+		"mtu":          json.Number("1400"),
+		"deny-roaming": false,
+		"banner":       "Welcome",
+	}
+	// as scanned back from a json column
+	newConfig := map[string]interface{}{
+		"dns":    []interface{}{"10.0.0.53", "1.1.1.1"},
+		"mtu":    float64(1400),
+		"banner": nil,
+	}
+
+	changes := ConfigChanges(oldConfig, newConfig)
+	if len(changes) != 2 {
+		t.Fatalf("ConfigChanges() = %+v, want changes of banner and dns", changes)
+	}
+	if changes[0].Key != "banner" || !slices.Equal(changes[0].Old, []string{"Welcome"}) || len(changes[0].New) != 0 {
+		t.Errorf("changes[0] = %+v, want banner removed", changes[0])
+	}
+	if changes[1].Key != "dns" || !slices.Equal(changes[1].New, []string{"10.0.0.53", "1.1.1.1"}) {
+		t.Errorf("changes[1] = %+v, want dns with 1.1.1.1 added", changes[1])
+	}
+}
@@ -0,0 +1,355 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigDocument is an ocserv config file kept line by line, so comments,
+// the order of the directives and directives the dashboard does not know
+// survive a rewrite.
+//
+// ocserv.conf may end with sections such as [vhost:corp]. The directives
+// before the first section header are the global section, the one Values,
+// Directives, Set and Apply work on; the Section methods work on the others.
+type ConfigDocument struct {
+	lines []configLine
+}
+
+// configLine is a line of a ConfigDocument. key is empty for comments,
+// blank lines, section headers and anything else that is not a directive.
+// section is the name of the section the line belongs to, empty for the
+// global one.
+type configLine struct {
+	text    string
+	key     string
+	value   string
+	section string
+	header  bool
+}
+
+// ParseConfigDocument parses the content of an ocserv config file.
+func ParseConfigDocument(content []byte) *ConfigDocument {
+	doc := &ConfigDocument{}
+	if len(content) == 0 {
+		return doc
+	}
+
+	section := ""
+	for _, text := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+		trimmed := strings.TrimSpace(text)
+		if name, ok := sectionHeader(trimmed); ok {
+			section = name
+			doc.lines = append(doc.lines, configLine{text: text, section: section, header: true})
+			continue
+		}
+
+		line := configLine{text: text, section: section}
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			if parts := strings.SplitN(trimmed, "=", 2); len(parts) == 2 {
+				line.key = strings.TrimSpace(parts[0])
+				line.value = UnquoteConfigValue(strings.TrimSpace(parts[1]))
+			}
+		}
+		doc.lines = append(doc.lines, line)
+	}
+	return doc
+}
+
+// ReadConfigDocument reads the ocserv config file at path. A missing file
+// is an empty document.
+func ReadConfigDocument(path string) (*ConfigDocument, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return ParseConfigDocument(content), nil
+}
+
+// Values returns the unquoted values of key, in file order.
+func (d *ConfigDocument) Values(key string) []string {
+	var values []string
+	for _, line := range d.lines {
+		if line.section == "" && line.key == key {
+			values = append(values, line.value)
+		}
+	}
+	return values
+}
+
+// Directives returns the unquoted values of every directive, in file order.
+func (d *ConfigDocument) Directives() map[string][]string {
+	return d.SectionDirectives("")
+}
+
+// Set replaces the values of key. The existing lines of key are reused in
+// place, and left untouched when their value does not change; extra values
+// go after the last of them, or at the end of the global section for a new
+// key. No values removes key.
+func (d *ConfigDocument) Set(key string, values ...string) {
+	d.set("", key, values)
+}
+
+// Delete removes every line of key.
+func (d *ConfigDocument) Delete(key string) {
+	d.Set(key)
+}
+
+// Apply sets the directives of config, as produced by ToMap from a config
+// model. Like ConfigWriter, nil, empty and false values are not written,
+// so they remove the directive. Keys not in config are left as they are.
+func (d *ConfigDocument) Apply(config map[string]interface{}) {
+	d.apply("", config)
+}
+
+// Sections returns the names of the sections of the document, such as
+// "vhost:corp", in file order.
+func (d *ConfigDocument) Sections() []string {
+	var sections []string
+	for _, line := range d.lines {
+		if line.header {
+			sections = append(sections, line.section)
+		}
+	}
+	return sections
+}
+
+// SectionDirectives returns the unquoted values of every directive of
+// section, in file order. An empty section is the global one.
+func (d *ConfigDocument) SectionDirectives(section string) map[string][]string {
+	directives := make(map[string][]string)
+	for _, line := range d.lines {
+		if line.section == section && line.key != "" {
+			directives[line.key] = append(directives[line.key], line.value)
+		}
+	}
+	return directives
+}
+
+// ApplySection is Apply for the directives of section. A missing section
+// is added at the end of the document.
+func (d *ConfigDocument) ApplySection(section string, config map[string]interface{}) {
+	if section != "" && !slices.Contains(d.Sections(), section) {
+		if n := len(d.lines); n > 0 && strings.TrimSpace(d.lines[n-1].text) != "" {
+			d.lines = append(d.lines, configLine{section: d.lines[n-1].section})
+		}
+		d.lines = append(d.lines, configLine{text: "[" + section + "]", section: section, header: true})
+	}
+	d.apply(section, config)
+}
+
+// DeleteSection removes section with its header and lines. The global
+// section cannot be removed.
+func (d *ConfigDocument) DeleteSection(section string) {
+	if section == "" {
+		return
+	}
+
+	lines := make([]configLine, 0, len(d.lines))
+	for _, line := range d.lines {
+		if line.section != section {
+			lines = append(lines, line)
+		}
+	}
+	// drop the blank line left before the removed header
+	if n := len(lines); n > 0 && strings.TrimSpace(lines[n-1].text) == "" && len(lines) < len(d.lines) {
+		lines = lines[:n-1]
+	}
+	d.lines = lines
+}
+
+func (d *ConfigDocument) apply(section string, config map[string]interface{}) {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		d.set(section, key, configValues(config[key]))
+	}
+}
+
+// set replaces the values of key in section, see Set. New lines go at the
+// end of section. When another section follows, they go after the last
+// directive or blank line instead, so they do not land under the comments
+// of the next header.
+func (d *ConfigDocument) set(section, key string, values []string) {
+	last, end, anchor := -1, -1, -1
+	for i, line := range d.lines {
+		if line.section != section {
+			continue
+		}
+		end = i
+		switch {
+		case line.key != "" || line.header:
+			anchor = i
+		case strings.TrimSpace(line.text) == "":
+			anchor = max(anchor, i-1)
+		}
+		if line.key == key {
+			last = i
+		}
+	}
+	if end < len(d.lines)-1 {
+		end = anchor
+	}
+	if last >= 0 {
+		end = last
+	}
+
+	lines := make([]configLine, 0, len(d.lines)+len(values))
+	next := 0
+	add := func() {
+		for ; next < len(values); next++ {
+			lines = append(lines, newConfigLine(section, key, values[next]))
+		}
+	}
+	if end < 0 {
+		add()
+	}
+	for i, line := range d.lines {
+		if line.section == section && line.key == key {
+			if next < len(values) {
+				lines = append(lines, setLine(line, key, values[next]))
+				next++
+			}
+		} else {
+			lines = append(lines, line)
+		}
+		if i == end {
+			add()
+		}
+	}
+	add()
+	d.lines = lines
+}
+
+// Validate checks the document against schema, a config model such as
+// models.OcservGroupConfig: every line must be a comment or a directive,
+// and the directives of the model must hold a value of the field type.
+// Directives the model does not have are not checked.
+func (d *ConfigDocument) Validate(schema interface{}) error {
+	kinds := make(map[string]reflect.Kind)
+	t := reflect.TypeOf(schema)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		kind := field.Type.Kind()
+		if kind == reflect.Ptr {
+			kind = field.Type.Elem().Kind()
+		}
+		kinds[key] = kind
+	}
+
+	var errs []error
+	for i, line := range d.lines {
+		trimmed := strings.TrimSpace(line.text)
+		if line.key == "" {
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !line.header {
+				errs = append(errs, fmt.Errorf("line %d: not a directive: %q", i+1, trimmed))
+			}
+			continue
+		}
+
+		switch kinds[line.key] {
+		case reflect.Int:
+			if _, err := strconv.Atoi(line.value); err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %s must be a number, got %q", i+1, line.key, line.value))
+			}
+		case reflect.Bool:
+			if _, err := strconv.ParseBool(line.value); err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %s must be true or false, got %q", i+1, line.key, line.value))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Bytes returns the document as file content.
+func (d *ConfigDocument) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, line := range d.lines {
+		buf.WriteString(line.text)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// WriteFile writes the document to path with permission perm through
+// WriteConfigFile, keeping the previous version of path.
+func (d *ConfigDocument) WriteFile(path string, perm os.FileMode) error {
+	return WriteConfigFile(path, d.Bytes(), perm)
+}
+
+// UnquoteConfigValue strips the double quotes around a config value.
+func UnquoteConfigValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// sectionHeader returns the name of the section a trimmed line such as
+// [vhost:corp] starts.
+func sectionHeader(trimmed string) (string, bool) {
+	if len(trimmed) < 3 || !strings.HasPrefix(trimmed, "[") || !strings.HasSuffix(trimmed, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(trimmed[1 : len(trimmed)-1]), true
+}
+
+func setLine(line configLine, key, value string) configLine {
+	if line.value == UnquoteConfigValue(value) {
+		return line
+	}
+	return newConfigLine(line.section, key, value)
+}
+
+func newConfigLine(section, key, value string) configLine {
+	return configLine{
+		text:    key + "=" + value,
+		key:     key,
+		value:   UnquoteConfigValue(value),
+		section: section,
+	}
+}
+
+// configValues formats a config map value into the values of its lines.
+func configValues(v interface{}) []string {
+	var items []interface{}
+	switch list := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		items = list
+	case []string:
+		for _, item := range list {
+			items = append(items, item)
+		}
+	default:
+		items = []interface{}{v}
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if b, ok := item.(bool); ok && !b {
+			continue
+		}
+		if s, ok := item.(string); ok && s == "" {
+			continue
+		}
+		values = append(values, formatConfigValue(item))
+	}
+	return values
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDocument = `# office group
+dns=10.0.0.53
+# pushed routes
+route=10.0.0.0/8
+route = 172.16.0.0/12
+banner = "Welcome"
+custom-directive=kept
+`
+
+func TestConfigDocumentApply(t *testing.T) {
+	doc := ParseConfigDocument([]byte(testDocument))
+
+	doc.Apply(map[string]interface{}{
+		"dns":          []interface{}{"10.0.0.53", "1.1.1.1"},
+		"route":        []interface{}{"10.0.0.0/8"},
+		"banner":       "Welcome",
+		"deny-roaming": false,
+		"mtu":          1400,
+	})
+
+	want := `# office group
+dns=10.0.0.53
+dns=1.1.1.1
+# pushed routes
+route=10.0.0.0/8
+banner = "Welcome"
+custom-directive=kept
+mtu=1400
+`
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("Apply() =\n%s\nwant\n%s", got, want)
+	}
+
+	directives := doc.Directives()
+	if dns := directives["dns"]; len(dns) != 2 || dns[1] != "1.1.1.1" {
+		t.Errorf("Directives()[dns] = %v, want [10.0.0.53 1.1.1.1]", dns)
+	}
+	if banner := directives["banner"]; len(banner) != 1 || banner[0] != "Welcome" {
+		t.Errorf("Directives()[banner] = %v, want unquoted [Welcome]", banner)
+	}
+
+	doc.Delete("dns")
+	if values := doc.Values("dns"); len(values) != 0 {
+		t.Errorf("Values(dns) = %v after Delete, want none", values)
+	}
+	if values := doc.Values("banner"); len(values) != 1 || values[0] != "Welcome" {
+		t.Errorf("Values(banner) = %v, want [Welcome]", values)
+	}
+}
+
+func TestConfigDocumentValidate(t *testing.T) {
+	type schema struct {
+		MTU         *int    `json:"mtu"`
+		DenyRoaming *bool   `json:"deny-roaming"`
+		Banner      *string `json:"banner"`
+	}
+
+	valid := ParseConfigDocument([]byte("# comment\nmtu=1400\ndeny-roaming=true\nbanner=hi\nunknown=x\n"))
+	if err := valid.Validate(schema{}); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	invalid := ParseConfigDocument([]byte("mtu=large\ndeny-roaming=1x\nnot a directive\n"))
+	err := invalid.Validate(&schema{})
+	if err == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	for _, want := range []string{"line 1: mtu", "line 2: deny-roaming", "line 3: not a directive"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want %q", err, want)
+		}
+	}
+}
+
+const testSectionDocument = `# global
+dns=10.0.0.53
+
+# corporate users
+[vhost:corp]
+dns=10.1.0.53
+ipv4-network=10.1.0.0/24
+`
+
+func TestConfigDocumentSections(t *testing.T) {
+	doc := ParseConfigDocument([]byte(testSectionDocument))
+
+	if sections := doc.Sections(); len(sections) != 1 || sections[0] != "vhost:corp" {
+		t.Fatalf("Sections() = %v, want [vhost:corp]", sections)
+	}
+	if dns := doc.Values("dns"); len(dns) != 1 || dns[0] != "10.0.0.53" {
+		t.Errorf("Values(dns) = %v, want the global [10.0.0.53]", dns)
+	}
+
+	doc.Apply(map[string]interface{}{"mtu": 1400, "dns": nil})
+	doc.ApplySection("vhost:corp", map[string]interface{}{"dns": "10.1.0.54", "max-clients": 10})
+	doc.ApplySection("vhost:lab", map[string]interface{}{"ipv4-network": "10.2.0.0/24"})
+
+	want := `# global
+mtu=1400
+
+# corporate users
+[vhost:corp]
+dns=10.1.0.54
+ipv4-network=10.1.0.0/24
+max-clients=10
+
+[vhost:lab]
+ipv4-network=10.2.0.0/24
+`
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("Apply() =\n%s\nwant\n%s", got, want)
+	}
+	if err := doc.Validate(struct{}{}); err != nil {
+		t.Errorf("Validate() = %v, want section headers accepted", err)
+	}
+
+	doc.DeleteSection("vhost:lab")
+	if sections := doc.Sections(); len(sections) != 1 {
+		t.Errorf("Sections() = %v after DeleteSection, want [vhost:corp]", sections)
+	}
+	if directives := doc.SectionDirectives("vhost:corp"); len(directives["dns"]) != 1 || directives["dns"][0] != "10.1.0.54" {
+		t.Errorf("SectionDirectives(vhost:corp) = %v", directives)
+	}
+	if !strings.HasSuffix(string(doc.Bytes()), "max-clients=10\n") {
+		t.Errorf("DeleteSection() left\n%s", doc.Bytes())
+	}
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigPreviousDir keeps the version of each config file before its last
+// write, under the file's absolute path, for RestoreConfigFile.
+var ConfigPreviousDir = "/etc/ocserv/.previous"
+
+// atomicTempInfix separates the name of the file WriteFileAtomic replaces
+// from the random suffix of its dot-prefixed temp file.
+const atomicTempInfix = ".tmp-"
+
+// IsAtomicTempFile reports whether name is a temp file of WriteFileAtomic,
+// left behind when the process died before renaming it. Directory scans
+// skip them.
+func IsAtomicTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, atomicTempInfix)
+}
+
+// WriteFileAtomic replaces path with content: content goes to a temp file
+// in the same directory, is synced and renamed over path, so a reader sees
+// either the old or the new file, never a partial one.
+func WriteFileAtomic(path string, content []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+atomicTempInfix+"*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	// sync the directory so the rename survives a crash
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// WriteConfigFile keeps the current version of path, see
+// KeepPreviousConfigFile, and replaces it atomically with content.
+func WriteConfigFile(path string, content []byte, perm os.FileMode) error {
+	if err := KeepPreviousConfigFile(path); err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, content, perm)
+}
+
+// KeepPreviousConfigFile saves the current version of path before it is
+// changed or removed. A symlink is kept as a symlink. When path does not
+// exist, a stale previous version is dropped, so restoring removes path.
+func KeepPreviousConfigFile(path string) error {
+	previous := previousConfigPath(path)
+	if err := os.Remove(previous); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(previous), 0750); err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, previous)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(previous, content, info.Mode().Perm())
+}
+
+// RestoreConfigFile puts back the version of path saved by the last
+// WriteConfigFile or KeepPreviousConfigFile, and removes path when it did
+// not exist then.
+func RestoreConfigFile(path string) error {
+	previous := previousConfigPath(path)
+
+	info, err := os.Lstat(previous)
+	if os.IsNotExist(err) {
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(previous)
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err = os.Symlink(target, path); err != nil {
+			return err
+		}
+		return os.Remove(previous)
+	}
+
+	content, err := os.ReadFile(previous)
+	if err != nil {
+		return err
+	}
+	// the rename replaces a symlink in place of the file, it does not write
+	// through it
+	if err = WriteFileAtomic(path, content, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Remove(previous)
+}
+
+func previousConfigPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+	return filepath.Join(ConfigPreviousDir, abs)
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	ConfigPreviousDir = filepath.Join(dir, "previous")
+
+	path := filepath.Join(dir, "office")
+	if err := WriteConfigFile(path, []byte("dns=10.0.0.53\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteConfigFile(path, []byte("dns=1.1.1.1\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "dns=10.0.0.53\n" {
+		t.Errorf("restored content = %q, want the previous version", content)
+	}
+
+	// the first write had nothing to keep, restoring it removes the file
+	if err := RestoreConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Stat() = %v, want the file removed", err)
+	}
+
+	link := filepath.Join(dir, "alice")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteConfigFile(link, []byte("mtu=1400\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink != 0 {
+		t.Fatal("WriteConfigFile() wrote through the symlink")
+	}
+	if err := RestoreConfigFile(link); err != nil {
+		t.Fatal(err)
+	}
+	if target, err := os.Readlink(link); err != nil || target != path {
+		t.Errorf("Readlink() = %q, %v, want the symlink to %q restored", target, err, path)
+	}
+}
+
+func TestIsAtomicTempFile(t *testing.T) {
+	dir := t.TempDir()
+	tmp, err := os.CreateTemp(dir, ".alice"+atomicTempInfix+"*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp.Close()
+
+	if name := filepath.Base(tmp.Name()); !IsAtomicTempFile(name) {
+		t.Errorf("IsAtomicTempFile(%q) = false", name)
+	}
+	for _, name := range []string{"alice", "alice.tmp-1", "bob.conf"} {
+		if IsAtomicTempFile(name) {
+			t.Errorf("IsAtomicTempFile(%q) = true", name)
+		}
+	}
+}
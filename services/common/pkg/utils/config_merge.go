@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ConfigLayer is one source of a merged config, such as a template, a
+// group or a user. Config is a config model or a map as produced by ToMap.
+type ConfigLayer struct {
+	Source string
+	Config interface{}
+}
+
+// EffectiveConfigValue is a directive of a merged config with the layer it
+// comes from and the layers it overrides, lowest first.
+type EffectiveConfigValue struct {
+	Key       string      `json:"key" validate:"required"`
+	Value     interface{} `json:"value" validate:"required"`
+	Source    string      `json:"source" validate:"required"`
+	Overrides []string    `json:"overrides" validate:"omitempty"`
+}
+
+// MergeConfigs merges layers in order, a directive set by a later layer
+// replaces the value of an earlier one. Unset (nil) directives do not
+// override, while an explicit false or empty value does. It returns the
+// merged config and its values with their sources, sorted by key.
+func MergeConfigs(layers ...ConfigLayer) (map[string]interface{}, []EffectiveConfigValue) {
+	merged := make(map[string]interface{})
+	values := make(map[string]*EffectiveConfigValue)
+
+	for _, layer := range layers {
+		config, ok := layer.Config.(map[string]interface{})
+		if !ok {
+			config = ToMap(layer.Config)
+		}
+
+		for key, value := range config {
+			if value == nil {
+				continue
+			}
+			merged[key] = value
+
+			if current, ok := values[key]; ok {
+				current.Overrides = append(current.Overrides, current.Source)
+				current.Value = value
+				current.Source = layer.Source
+				continue
+			}
+			values[key] = &EffectiveConfigValue{Key: key, Value: value, Source: layer.Source}
+		}
+	}
+
+	result := make([]EffectiveConfigValue, 0, len(values))
+	for _, value := range values {
+		result = append(result, *value)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return merged, result
+}
+
+// DecodeConfig converts a config map back to its config model, config.
+func DecodeConfig(from map[string]interface{}, config interface{}) error {
+	by, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(by, config)
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeConfigs(t *testing.T) {
+	merged, values := MergeConfigs(
+		ConfigLayer{Source: "template:base", Config: map[string]interface{}{
+			"dns":          []interface{}{"10.0.0.53"},
+			"mtu":          1400,
+			"deny-roaming": true,
+		}},
+		ConfigLayer{Source: "template:office", Config: map[string]interface{}{
+			"mtu":       1300,
+			"keepalive": nil,
+		}},
+		ConfigLayer{Source: "group:office", Config: map[string]interface{}{
+			"deny-roaming": false,
+			"mtu":          nil,
+		}},
+	)
+
+	if merged["mtu"] != 1300 || merged["deny-roaming"] != false {
+		t.Errorf("MergeConfigs() = %v, want mtu 1300 and deny-roaming false", merged)
+	}
+	if _, ok := merged["keepalive"]; ok {
+		t.Errorf("MergeConfigs() = %v, want unset keepalive left out", merged)
+	}
+
+	if len(values) != 3 {
+		t.Fatalf("values = %+v, want deny-roaming, dns and mtu", values)
+	}
+	roaming, mtu := values[0], values[2]
+	if roaming.Source != "group:office" || !slices.Equal(roaming.Overrides, []string{"template:base"}) {
+		t.Errorf("deny-roaming = %+v, want group:office overriding template:base", roaming)
+	}
+	if mtu.Source != "template:office" || !slices.Equal(mtu.Overrides, []string{"template:base"}) {
+		t.Errorf("mtu = %+v, want template:office overriding template:base", mtu)
+	}
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	portRule   = regexp.MustCompile(`^(tcp|udp|sctp)\((\d+)\)$`)
+	icmpRule   = regexp.MustCompile(`^icmp(v6)?\(\)$`)
+	cgroupRule = regexp.MustCompile(`^[a-z_]+(,[a-z_]+)*:[A-Za-z0-9_.\-/]+$`)
+)
+
+// ParseRoute parses an ocserv route: "default", a CIDR or an address with
+// a dotted netmask such as 10.0.0.0/255.0.0.0.
+func ParseRoute(route string) (*net.IPNet, error) {
+	if route == "default" {
+		_, network, _ := net.ParseCIDR("0.0.0.0/0")
+		return network, nil
+	}
+	if _, network, err := net.ParseCIDR(route); err == nil {
+		return network, nil
+	}
+
+	parts := strings.SplitN(route, "/", 2)
+	if len(parts) == 2 {
+		ip := net.ParseIP(parts[0]).To4()
+		mask := net.ParseIP(parts[1]).To4()
+		if ip != nil && mask != nil {
+			ipMask := net.IPMask(mask)
+			if ones, bits := ipMask.Size(); bits != 0 || ones != 0 {
+				return &net.IPNet{IP: ip.Mask(ipMask), Mask: ipMask}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("invalid route %q", route)
+}
+
+// ValidRoute reports whether route is a valid ocserv route.
+func ValidRoute(route string) bool {
+	_, err := ParseRoute(route)
+	return err == nil
+}
+
+// ParseNetwork parses an address pool such as ipv4-network: a CIDR or an
+// address with a dotted netmask.
+func ParseNetwork(network string) (*net.IPNet, error) {
+	if network == "default" {
+		return nil, fmt.Errorf("invalid network %q", network)
+	}
+	return ParseRoute(network)
+}
+
+// OverlappingRoutes returns the first pair of routes of which one contains
+// the other, duplicates included, or nil. Invalid routes are ignored.
+func OverlappingRoutes(routes []string) []string {
+	networks := make([]*net.IPNet, len(routes))
+	for i, route := range routes {
+		networks[i], _ = ParseRoute(route)
+	}
+
+	for i := range networks {
+		for j := i + 1; j < len(networks); j++ {
+			if networks[i] == nil || networks[j] == nil {
+				continue
+			}
+			if networks[i].Contains(networks[j].IP) || networks[j].Contains(networks[i].IP) {
+				return []string{routes[i], routes[j]}
+			}
+		}
+	}
+	return nil
+}
+
+// ParsePortRestrictions checks the restrict-user-to-ports syntax: a comma
+// separated list of tcp(port), udp(port), sctp(port), icmp() and icmpv6(),
+// optionally negated as a whole with !(...).
+func ParsePortRestrictions(value string) error {
+	rules := strings.TrimSpace(value)
+	if strings.HasPrefix(rules, "!") {
+		rules = strings.TrimSpace(strings.TrimPrefix(rules, "!"))
+		if !strings.HasPrefix(rules, "(") || !strings.HasSuffix(rules, ")") {
+			return errors.New("a negated list must be written as !(...)")
+		}
+		rules = rules[1 : len(rules)-1]
+	}
+	if strings.TrimSpace(rules) == "" {
+		return errors.New("no ports given")
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.ReplaceAll(strings.TrimSpace(rule), " ", "")
+		if icmpRule.MatchString(rule) {
+			continue
+		}
+		match := portRule.FindStringSubmatch(rule)
+		if match == nil {
+			return fmt.Errorf("invalid rule %q, expected tcp(port), udp(port), sctp(port), icmp() or icmpv6()", rule)
+		}
+		if port, err := strconv.Atoi(match[2]); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port in %q", rule)
+		}
+	}
+	return nil
+}
+
+// ValidCGroup reports whether value is a cgroup in the
+// controller[,controller]:name form.
+func ValidCGroup(value string) bool {
+	return cgroupRule.MatchString(value)
+}
+
+// IPInNetwork reports whether ip is an address of network, as parsed by
+// ParseNetwork.
+func IPInNetwork(ip, network string) bool {
+	addr := net.ParseIP(ip)
+	pool, err := ParseNetwork(network)
+	return addr != nil && err == nil && pool.Contains(addr)
+}
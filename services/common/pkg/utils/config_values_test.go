@@ -0,0 +1,53 @@
+package utils
+
+import "testing"
+
+func TestParseRoute(t *testing.T) {
+	for route, want := range map[string]string{
+		"default":            "0.0.0.0/0",
+		"10.1.2.0/24":        "10.1.2.0/24",
+		"10.0.0.0/255.0.0.0": "10.0.0.0/8",
+		"fd00::/64":          "fd00::/64",
+	} {
+		network, err := ParseRoute(route)
+		if err != nil || network.String() != want {
+			t.Errorf("ParseRoute(%q) = %v, %v, want %s", route, network, err, want)
+		}
+	}
+	for _, route := range []string{"", "10.0.0.0", "10.0.0.0/33", "10.0.0.0/255.0.255.0", "example.com/8"} {
+		if ValidRoute(route) {
+			t.Errorf("ValidRoute(%q) = true, want false", route)
+		}
+	}
+}
+
+func TestOverlappingRoutes(t *testing.T) {
+	if pair := OverlappingRoutes([]string{"10.0.0.0/16", "192.168.0.0/24", "10.0.1.0/24"}); len(pair) != 2 || pair[1] != "10.0.1.0/24" {
+		t.Errorf("OverlappingRoutes() = %v, want 10.0.0.0/16 and 10.0.1.0/24", pair)
+	}
+	if pair := OverlappingRoutes([]string{"10.0.0.0/16", "10.1.0.0/16", "bad"}); pair != nil {
+		t.Errorf("OverlappingRoutes() = %v, want none", pair)
+	}
+}
+
+func TestParsePortRestrictions(t *testing.T) {
+	for _, value := range []string{"tcp(443), udp(53)", "!(tcp(22), udp(1194))", "icmp(), icmpv6(), sctp(99)", "! ( tcp(22) )"} {
+		if err := ParsePortRestrictions(value); err != nil {
+			t.Errorf("ParsePortRestrictions(%q) = %v, want nil", value, err)
+		}
+	}
+	for _, value := range []string{"", "tcp(0)", "tcp(65536)", "http(80)", "tcp(443), !(udp(53))", "!tcp(22)", "tcp(22-25)"} {
+		if err := ParsePortRestrictions(value); err == nil {
+			t.Errorf("ParsePortRestrictions(%q) = nil, want an error", value)
+		}
+	}
+}
+
+func TestIPInNetwork(t *testing.T) {
+	if !IPInNetwork("192.168.1.10", "192.168.1.0/24") || !IPInNetwork("10.2.0.1", "10.0.0.0/255.0.0.0") {
+		t.Error("IPInNetwork() = false for an address of the pool")
+	}
+	if IPInNetwork("192.168.2.10", "192.168.1.0/24") || IPInNetwork("bad", "192.168.1.0/24") {
+		t.Error("IPInNetwork() = true for an address outside the pool")
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"github.com/mmtaee/ocserv-dashboard/common/models"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"io"
 	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -51,12 +53,20 @@ func ToMap(data interface{}) map[string]interface{} {
 	return result
 }
 
-// ConfigWriter writes key-value pairs from a configuration map to the given file.
-// It skips nil values and boolean false values. For keys like dns, route, no-route,
-// and split-dns, it writes multiple lines for each entry. Other keys are written
-// as "key=value". Returns an error if writing fails.
-func ConfigWriter(file *os.File, config map[string]interface{}) error {
-	for k, v := range config {
+// ConfigWriter writes key-value pairs from a configuration map to the given writer,
+// in key order. It skips nil values and boolean false values. For keys like dns,
+// route, no-route, and split-dns, and any other key holding a list, it writes
+// one line per entry. Other keys are written as "key=value". Returns an error
+// if writing fails.
+func ConfigWriter(file io.Writer, config map[string]interface{}) error {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := config[k]
 		if b, ok := v.(bool); ok && !b {
 			continue
 		}
@@ -68,36 +78,20 @@ func ConfigWriter(file *os.File, config map[string]interface{}) error {
 			continue
 		}
 
-		if k == "dns" {
-			for _, dns := range v.([]interface{}) {
-				if _, err := file.WriteString(fmt.Sprintf("dns=%s\n", dns)); err != nil {
-					return fmt.Errorf("failed to write to file: %w", err)
-				}
-			}
-			continue
-		} else if k == "route" {
-			for _, route := range v.([]interface{}) {
-				if _, err := file.WriteString(fmt.Sprintf("route=%s\n", route)); err != nil {
-					return fmt.Errorf("failed to write to file: %w", err)
-				}
+		var values []interface{}
+		switch list := v.(type) {
+		case []interface{}:
+			values = list
+		case []string:
+			for _, item := range list {
+				values = append(values, item)
 			}
-			continue
-		} else if k == "no-route" {
-			for _, route := range v.([]interface{}) {
-				if _, err := file.WriteString(fmt.Sprintf("no-route=%s\n", route)); err != nil {
-					return fmt.Errorf("failed to write to file: %w", err)
-				}
-			}
-			continue
-		} else if k == "split-dns" {
-			for _, dns := range v.([]interface{}) {
-				if _, err := file.WriteString(fmt.Sprintf("split-dns=%s\n", dns)); err != nil {
-					return fmt.Errorf("failed to write to file: %w", err)
-				}
-			}
-			continue
-		} else {
-			if _, err := file.WriteString(fmt.Sprintf("%s=%s\n", k, formatConfigValue(v))); err != nil {
+		default:
+			values = []interface{}{v}
+		}
+
+		for _, value := range values {
+			if _, err := fmt.Fprintf(file, "%s=%s\n", k, formatConfigValue(value)); err != nil {
 				return fmt.Errorf("failed to write to file: %w", err)
 			}
 		}
@@ -193,7 +187,8 @@ func GetUsersByGroup(groupName string) ([]string, error) {
 // ParseOcservConfigFile parses an ocserv config file into a map[string]interface{}.
 // Keys with multiple values (like dns, route, no-route, split-dns) are stored as slices.
 // Values are converted into bool, int, float64, or string via ParseTypedValue.
-// Comments and empty lines are ignored.
+// Comments and empty lines are ignored, and so are the sections such as
+// [vhost:corp] that follow the global directives.
 func ParseOcservConfigFile(filePath string) (map[string]interface{}, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -209,6 +204,9 @@ func ParseOcservConfigFile(filePath string) (map[string]interface{}, error) {
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		if _, ok := sectionHeader(line); ok {
+			break
+		}
 
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) != 2 {
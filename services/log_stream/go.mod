@@ -49,6 +49,7 @@ require (
 	golang.org/x/time v0.12.0 // indirect
 	gorm.io/driver/postgres v1.6.0 // indirect
 	gotest.tools/v3 v3.5.2 // indirect
+	software.sslmate.com/src/go-pkcs12 v0.5.0 // indirect
 )
 
 replace github.com/mmtaee/ocserv-dashboard/common => ./../common
@@ -0,0 +1,97 @@
+package authguard
+
+import (
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+)
+
+// Config holds the brute-force thresholds and the actions to take, read
+// from the AUTH_GUARD_* environment variables.
+type Config struct {
+	Enabled       bool
+	Window        time.Duration
+	UserThreshold int
+	IPThreshold   int
+	LockUser      bool
+	LockDuration  time.Duration
+	BanIP         bool
+	BanDuration   time.Duration
+	Alert         bool
+	// IgnoreIPs are never banned, e.g. the admin office network.
+	IgnoreIPs []netip.Prefix
+}
+
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Enabled:       envBool("AUTH_GUARD_ENABLED", false),
+		Window:        envDuration("AUTH_GUARD_WINDOW", 10*time.Minute),
+		UserThreshold: envInt("AUTH_GUARD_USER_THRESHOLD", 5),
+		IPThreshold:   envInt("AUTH_GUARD_IP_THRESHOLD", 10),
+		LockUser:      envBool("AUTH_GUARD_LOCK_USER", false),
+		LockDuration:  envDuration("AUTH_GUARD_LOCK_DURATION", 15*time.Minute),
+		BanIP:         envBool("AUTH_GUARD_BAN_IP", false),
+		BanDuration:   envDuration("AUTH_GUARD_BAN_DURATION", time.Hour),
+		Alert:         envBool("AUTH_GUARD_ALERT", true),
+	}
+
+	ignore := os.Getenv("AUTH_GUARD_IGNORE_IPS")
+	if ignore == "" {
+		ignore = "127.0.0.0/8,::1"
+	}
+	for _, value := range strings.Split(ignore, ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		cidr, err := firewall.NormalizeCIDR(value)
+		if err != nil {
+			logger.Warn("AUTH_GUARD_IGNORE_IPS: skipping %q: %v", value, err)
+			continue
+		}
+		cfg.IgnoreIPs = append(cfg.IgnoreIPs, netip.MustParsePrefix(cidr))
+	}
+	return cfg
+}
+
+// ignored reports whether ip is in IgnoreIPs.
+func (c Config) ignored(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, p := range c.IgnoreIPs {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func envBool(key string, def bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
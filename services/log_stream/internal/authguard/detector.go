@@ -0,0 +1,132 @@
+package authguard
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+// Failure is a single failed ocserv login. IP is empty when the line does
+// not carry the client address.
+type Failure struct {
+	Username string
+	IP       string
+}
+
+// Trigger is raised when a username or an address reaches its threshold.
+type Trigger struct {
+	Kind     string
+	Target   string
+	Failures int
+}
+
+// failureRe matches the worker lines ocserv logs on a rejected login, e.g.
+//
+//	worker[bob]: 203.0.113.7 failed authentication for 'bob'
+//	worker: 203.0.113.7:51234 worker-auth.c:1390: failed authentication attempt for user 'bob'
+var failureRe = regexp.MustCompile(`worker(?:\[[^\]]*\])?:\s*(\S+)\s+.*?failed authentication (?:attempt )?for (?:user )?'([^']*)'`)
+
+// ParseFailure extracts a failed login from an ocserv log line.
+func ParseFailure(line string) (Failure, bool) {
+	m := failureRe.FindStringSubmatch(line)
+	if m == nil {
+		return Failure{}, false
+	}
+	return Failure{Username: m[2], IP: parseIP(m[1])}, true
+}
+
+// parseIP accepts "ip", "ip:port" and "[ipv6]:port".
+func parseIP(endpoint string) string {
+	if ap, err := netip.ParseAddrPort(endpoint); err == nil {
+		return ap.Addr().Unmap().String()
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(endpoint, "[]")); err == nil {
+		return addr.Unmap().String()
+	}
+	return ""
+}
+
+// Detector counts failures per username and per address over a sliding
+// window. It is not safe for concurrent use.
+type Detector struct {
+	window        time.Duration
+	userThreshold int
+	ipThreshold   int
+	users         map[string][]time.Time
+	ips           map[string][]time.Time
+	lastSweep     time.Time
+}
+
+// NewDetector returns a Detector. A threshold of zero disables counting
+// for that key.
+func NewDetector(window time.Duration, userThreshold, ipThreshold int) *Detector {
+	return &Detector{
+		window:        window,
+		userThreshold: userThreshold,
+		ipThreshold:   ipThreshold,
+		users:         make(map[string][]time.Time),
+		ips:           make(map[string][]time.Time),
+	}
+}
+
+// Record adds f at now and returns the thresholds it crossed. The counter
+// of a triggered key starts over, so a sustained attack triggers again
+// every threshold failures.
+func (d *Detector) Record(f Failure, now time.Time) []Trigger {
+	if now.Sub(d.lastSweep) > d.window {
+		d.sweep(now)
+	}
+
+	var triggers []Trigger
+	if f.Username != "" && d.userThreshold > 0 {
+		if n := d.add(d.users, f.Username, now, d.userThreshold); n > 0 {
+			triggers = append(triggers, Trigger{Kind: models.AuthAlertKindUser, Target: f.Username, Failures: n})
+		}
+	}
+	if f.IP != "" && d.ipThreshold > 0 {
+		if n := d.add(d.ips, f.IP, now, d.ipThreshold); n > 0 {
+			triggers = append(triggers, Trigger{Kind: models.AuthAlertKindIP, Target: f.IP, Failures: n})
+		}
+	}
+	return triggers
+}
+
+// add records a failure of key and returns the failure count when it
+// reached threshold, zero otherwise.
+func (d *Detector) add(counters map[string][]time.Time, key string, now time.Time, threshold int) int {
+	times := prune(counters[key], now.Add(-d.window))
+	times = append(times, now)
+
+	if len(times) >= threshold {
+		delete(counters, key)
+		return len(times)
+	}
+	counters[key] = times
+	return 0
+}
+
+// sweep drops the keys without failures inside the window.
+func (d *Detector) sweep(now time.Time) {
+	cutoff := now.Add(-d.window)
+	for _, counters := range []map[string][]time.Time{d.users, d.ips} {
+		for key, times := range counters {
+			if times = prune(times, cutoff); len(times) == 0 {
+				delete(counters, key)
+			} else {
+				counters[key] = times
+			}
+		}
+	}
+	d.lastSweep = now
+}
+
+func prune(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
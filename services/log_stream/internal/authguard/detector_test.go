@@ -0,0 +1,99 @@
+package authguard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+)
+
+func TestParseFailure(t *testing.T) {
+	tests := []struct {
+		line string
+		want Failure
+		ok   bool
+	}{
+		{
+			line: "ocserv[812]: worker[bob]: 203.0.113.7 failed authentication for 'bob'",
+			want: Failure{Username: "bob", IP: "203.0.113.7"},
+			ok:   true,
+		},
+		{
+			line: "ocserv[812]: worker: 203.0.113.7:51234 worker-auth.c:1390: failed authentication attempt for user 'alice'",
+			want: Failure{Username: "alice", IP: "203.0.113.7"},
+			ok:   true,
+		},
+		{
+			line: "worker[carol]: [2001:db8::7]:443 failed authentication for 'carol'",
+			want: Failure{Username: "carol", IP: "2001:db8::7"},
+			ok:   true,
+		},
+		{
+			line: "worker[dave]: 203.0.113.7 sent periodic stats (in: 10, out: 20)",
+		},
+		{
+			line: "main[dave]:203.0.113.7:4433 user disconnected (reason: user disconnected, rx: 1, tx: 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseFailure(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseFailure(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetectorThresholds(t *testing.T) {
+	d := NewDetector(time.Minute, 3, 2)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := d.Record(Failure{Username: "bob", IP: "203.0.113.7"}, start); len(got) != 0 {
+		t.Fatalf("unexpected triggers %+v", got)
+	}
+
+	got := d.Record(Failure{Username: "bob", IP: "203.0.113.7"}, start.Add(time.Second))
+	if len(got) != 1 || got[0] != (Trigger{Kind: models.AuthAlertKindIP, Target: "203.0.113.7", Failures: 2}) {
+		t.Fatalf("expected ip trigger, got %+v", got)
+	}
+
+	got = d.Record(Failure{Username: "bob", IP: "198.51.100.1"}, start.Add(2*time.Second))
+	if len(got) != 1 || got[0] != (Trigger{Kind: models.AuthAlertKindUser, Target: "bob", Failures: 3}) {
+		t.Fatalf("expected user trigger, got %+v", got)
+	}
+
+	// The user counter starts over after triggering.
+	if got = d.Record(Failure{Username: "bob"}, start.Add(3*time.Second)); len(got) != 0 {
+		t.Fatalf("unexpected triggers %+v", got)
+	}
+}
+
+func TestDetectorWindow(t *testing.T) {
+	d := NewDetector(time.Minute, 2, 0)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d.Record(Failure{Username: "bob", IP: "203.0.113.7"}, start)
+	if got := d.Record(Failure{Username: "bob"}, start.Add(2*time.Minute)); len(got) != 0 {
+		t.Fatalf("failures outside the window must not add up, got %+v", got)
+	}
+	if len(d.ips) != 0 {
+		t.Fatalf("ip counting is disabled, got %v", d.ips)
+	}
+	if _, ok := d.users["bob"]; !ok || len(d.users["bob"]) != 1 {
+		t.Fatalf("expected one recent failure, got %v", d.users["bob"])
+	}
+
+	d.Record(Failure{Username: "eve"}, start.Add(10*time.Minute))
+	if _, ok := d.users["bob"]; ok {
+		t.Fatal("expected stale counters to be swept")
+	}
+}
+
+func TestConfigIgnored(t *testing.T) {
+	t.Setenv("AUTH_GUARD_IGNORE_IPS", "10.0.0.0/8, bogus")
+	cfg := ConfigFromEnv()
+
+	if !cfg.ignored("10.1.2.3") || cfg.ignored("203.0.113.7") || cfg.ignored("") {
+		t.Errorf("unexpected ignore result for %v", cfg.IgnoreIPs)
+	}
+}
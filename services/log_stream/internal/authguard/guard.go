@@ -0,0 +1,135 @@
+package authguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/ipban"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// LockFunc applies a user lock on the ocserv side, once the database
+// already marks the user locked.
+type LockFunc func(ctx context.Context, username string)
+
+// Guard turns ocserv authentication failures into temporary user locks,
+// IP bans and admin alerts.
+type Guard struct {
+	cfg      Config
+	detector *Detector
+	db       *gorm.DB
+	bans     *ipban.Manager
+	lock     LockFunc
+}
+
+func New(cfg Config, db *gorm.DB, bans *ipban.Manager, lock LockFunc) *Guard {
+	return &Guard{
+		cfg:      cfg,
+		detector: NewDetector(cfg.Window, cfg.UserThreshold, cfg.IPThreshold),
+		db:       db,
+		bans:     bans,
+		lock:     lock,
+	}
+}
+
+// Handle inspects an ocserv log line and reports whether it was an
+// authentication failure.
+func (g *Guard) Handle(ctx context.Context, line string) bool {
+	failure, ok := ParseFailure(line)
+	if !ok {
+		return false
+	}
+	if !g.cfg.Enabled {
+		return true
+	}
+
+	logger.Warn("Failed ocserv login for user=%s ip=%s", failure.Username, failure.IP)
+
+	for _, t := range g.detector.Record(failure, time.Now()) {
+		g.act(ctx, t)
+	}
+	return true
+}
+
+func (g *Guard) act(ctx context.Context, t Trigger) {
+	actions := models.CSVStringList{}
+
+	switch t.Kind {
+	case models.AuthAlertKindUser:
+		if g.cfg.LockUser {
+			locked, err := g.lockUser(ctx, t.Target)
+			if err != nil {
+				logger.Error("Failed to lock user %s after %d failed logins: %v", t.Target, t.Failures, err)
+			} else if locked {
+				actions = append(actions, models.AuthAlertActionLock)
+			}
+		}
+
+	case models.AuthAlertKindIP:
+		if g.cfg.BanIP && !g.cfg.ignored(t.Target) {
+			banned, err := g.banIP(ctx, t)
+			if err != nil {
+				logger.Error("Failed to ban %s after %d failed logins: %v", t.Target, t.Failures, err)
+			} else if banned {
+				actions = append(actions, models.AuthAlertActionBan)
+			}
+		}
+	}
+
+	logger.Warn("Brute-force %s %s: %d failed logins in %s, actions: %v", t.Kind, t.Target, t.Failures, g.cfg.Window, actions)
+
+	alert := models.OcservAuthAlert{
+		Kind:          t.Kind,
+		Target:        t.Target,
+		Failures:      t.Failures,
+		WindowSeconds: int(g.cfg.Window.Seconds()),
+		Actions:       &actions,
+		Notify:        g.cfg.Alert,
+	}
+	if err := g.db.WithContext(ctx).Create(&alert).Error; err != nil {
+		logger.Error("Failed to save auth alert for %s %s: %v", t.Kind, t.Target, err)
+	}
+}
+
+// lockUser locks an unlocked user until LockDuration passes; the
+// user_expiry service lifts it. Users locked for another reason, or
+// unknown to the database, are left alone.
+func (g *Guard) lockUser(ctx context.Context, username string) (bool, error) {
+	until := time.Now().Add(g.cfg.LockDuration)
+
+	result := g.db.WithContext(ctx).
+		Model(&models.OcservUser{}).
+		Where("username = ? AND is_locked = false", username).
+		Updates(map[string]interface{}{
+			"is_locked":         true,
+			"auth_locked_until": until,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	g.lock(ctx, username)
+	return true, nil
+}
+
+func (g *Guard) banIP(ctx context.Context, t Trigger) (bool, error) {
+	expiresAt := time.Now().Add(g.cfg.BanDuration)
+
+	_, err := g.bans.Ban(ctx, ipban.BanRequest{
+		IP:        t.Target,
+		Reason:    fmt.Sprintf("%d failed logins in %s", t.Failures, g.cfg.Window),
+		Source:    models.IPBanSourceAuto,
+		ExpiresAt: &expiresAt,
+	})
+	if errors.Is(err, ipban.ErrAlreadyBanned) {
+		return false, nil
+	}
+	return err == nil, err
+}
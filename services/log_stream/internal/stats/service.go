@@ -12,10 +12,13 @@ import (
 
 	"github.com/mmtaee/ocserv-dashboard/common/models"
 	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/ipban"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"github.com/mmtaee/ocserv-dashboard/log_stream/internal/authguard"
 	"gorm.io/gorm"
 )
 
@@ -24,8 +27,10 @@ type StatService struct {
 	stream              <-chan string
 	ocservUserRepo      user.OcservUserInterface
 	ocservOcctlRepo     occtl.OcservOcctlInterface
-	occtlDockerRepo     occtlDocker.OcservOcctlUsersDocker
+	outbox              *occtlDocker.Outbox
+	authGuard           *authguard.Guard
 	dockerMode          bool
+	node                string
 	sessionStats        map[string]UserStats
 	pendingMainSessions map[string][]pendingMainSession
 	workerSessionIDs    map[string]string
@@ -36,7 +41,9 @@ type pendingMainSession struct {
 	CreatedAt time.Time
 }
 
-func NewStatService(ctx context.Context, stream chan string, dockerMode bool) *StatService {
+// NewStatService accounts the log of the local ocserv. In docker mode locks
+// are queued in outbox, whose Run delivers them.
+func NewStatService(ctx context.Context, stream chan string, dockerMode bool, outbox *occtlDocker.Outbox) *StatService {
 	s := &StatService{
 		ctx:                 ctx,
 		stream:              stream,
@@ -45,13 +52,40 @@ func NewStatService(ctx context.Context, stream chan string, dockerMode bool) *S
 		pendingMainSessions: make(map[string][]pendingMainSession),
 		workerSessionIDs:    make(map[string]string),
 	}
+	db := database.GetConnection()
+
+	var fw firewall.Firewall
 	if dockerMode {
-		s.occtlDockerRepo = occtlDocker.NewOcservOcctlDocker()
+		s.outbox = outbox
+		fw = occtlDocker.NewOcservOcctlDocker()
 	} else {
 		s.ocservUserRepo = user.NewOcservUser()
-		s.ocservOcctlRepo = occtl.NewOcservOcctl()
+		s.ocservOcctlRepo = occtl.NewOcservOcctlClient()
+		fw = firewall.NewIptables()
 	}
 
+	s.authGuard = authguard.New(authguard.ConfigFromEnv(), db, ipban.NewManager(db, models.LocalNode, fw, nil), s.lockInOcserv)
+
+	return s
+}
+
+// NewNodeStatService accounts the log of a registered node streamed by its
+// agent. Locks are queued in outbox, which delivers them to the node of
+// the user, and auth guard bans go to the node through client.
+func NewNodeStatService(ctx context.Context, stream chan string, node string, client *occtlDocker.OcservOcctlDocker, outbox *occtlDocker.Outbox) *StatService {
+	s := &StatService{
+		ctx:                 ctx,
+		stream:              stream,
+		dockerMode:          true,
+		node:                node,
+		sessionStats:        make(map[string]UserStats),
+		pendingMainSessions: make(map[string][]pendingMainSession),
+		workerSessionIDs:    make(map[string]string),
+	}
+	db := database.GetConnection()
+	s.outbox = outbox
+	s.authGuard = authguard.New(authguard.ConfigFromEnv(), db, ipban.NewManager(db, node, client, nil), s.lockInOcserv)
+
 	return s
 }
 
@@ -71,12 +105,21 @@ func (s *StatService) CalculateUserStats() {
 			cleanLine := strings.TrimSpace(line) // remove whitespace/newlines and normalize case
 
 			if strings.Contains(cleanLine, "server shutdown complete") {
+				// a remote node restarts on its own, its agent stream reconnects
+				if s.node != "" {
+					logger.Warn("Ocserv server of node %s shutdown", s.node)
+					continue
+				}
 				logger.Error("Ocserv server shutdown abnormally")
 				p, _ := os.FindProcess(os.Getpid())
 				_ = p.Signal(syscall.SIGTERM)
 				return
 			}
 
+			if s.authGuard.Handle(s.ctx, cleanLine) {
+				continue
+			}
+
 			if !strings.Contains(cleanLine, "worker[") && !strings.Contains(cleanLine, "main[") {
 				continue
 			}
@@ -480,29 +523,8 @@ func (s *StatService) saveRxTx(ctx context.Context, u *UserStats) error {
 	}
 
 	now := time.Now()
-	if shouldLock && !wasLocked {
-		var (
-			disconnectFunc func(username string) (string, error)
-			lockFunc       func(username string) (string, error)
-		)
-		if s.dockerMode {
-			disconnectFunc = s.occtlDockerRepo.DisconnectUser
-			lockFunc = s.occtlDockerRepo.Lock
-		} else {
-			disconnectFunc = s.ocservOcctlRepo.DisconnectUser
-			lockFunc = s.ocservUserRepo.Lock
-		}
-
-		_, err = disconnectFunc(ocUser.Username)
-		if err != nil {
-			logger.Error("Error disconnecting user: %v", err)
-		}
-
-		_, err = lockFunc(ocUser.Username)
-		if err != nil {
-			logger.Error("Error locking user: %v", err)
-		}
-
+	lockNow := shouldLock && !wasLocked
+	if lockNow {
 		ocUser.DeactivatedAt = &now
 	}
 	err = db.Save(&ocUser).Error
@@ -510,9 +532,35 @@ func (s *StatService) saveRxTx(ctx context.Context, u *UserStats) error {
 		logger.Error("Error updating user stats: %v", err)
 		return err
 	}
+
+	if lockNow {
+		s.lockInOcserv(ctx, ocUser.Username)
+	}
 	return nil
 }
 
+// lockInOcserv disconnects and locks username in ocserv. It runs once the
+// database marks the user locked, so in docker mode the outbox reconciler
+// never undoes the queued lock. The outbox delivers the actions, the
+// ordered accounting loop only stores them.
+func (s *StatService) lockInOcserv(ctx context.Context, username string) {
+	if s.dockerMode {
+		for _, action := range []string{models.OcservActionDisconnect, models.OcservActionLock} {
+			if err := s.outbox.Enqueue(ctx, action, username); err != nil {
+				logger.Error("Error queueing %s for user %s: %v", action, username, err)
+			}
+		}
+		return
+	}
+
+	if _, err := s.ocservOcctlRepo.DisconnectUser(username); err != nil {
+		logger.Error("Error disconnecting user: %v", err)
+	}
+	if _, err := s.ocservUserRepo.Lock(username); err != nil {
+		logger.Error("Error locking user: %v", err)
+	}
+}
+
 func (s *StatService) saveSessionLog(ctx context.Context, log *models.OcservUserSessionLog) error {
 	db := database.GetConnection()
 	db = db.WithContext(ctx)
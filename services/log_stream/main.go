@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"github.com/joho/godotenv"
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/config"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
@@ -15,6 +17,17 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+)
+
+const (
+	// nodeRetryDelay is the wait before reconnecting to the log stream of a node.
+	nodeRetryDelay = 10 * time.Second
+	// nodeRefreshInterval is how often the node registry is read again.
+	nodeRefreshInterval = 30 * time.Second
+	// outboxFlushInterval is how often queued ocserv actions that failed
+	// are retried.
+	outboxFlushInterval = 15 * time.Second
 )
 
 var (
@@ -65,7 +78,18 @@ func main() {
 		}()
 	}
 
-	statService := stats.NewStatService(ctx, lineLogChan, dockerMode)
+	// locks are queued by the accounting loops and delivered here, to the
+	// node of each user
+	var outboxClient occtlDocker.OutboxClient
+	if dockerMode {
+		outboxClient = occtlDocker.NewOcservOcctlDocker()
+	} else {
+		outboxClient = occtlDocker.NewLocalOutboxClient()
+	}
+	outbox := occtlDocker.NewOutbox(database.GetConnection(), outboxClient)
+	go outbox.Run(ctx, outboxFlushInterval, 0)
+
+	statService := stats.NewStatService(ctx, lineLogChan, dockerMode, outbox)
 	go func() {
 		statService.CalculateUserStats()
 	}()
@@ -87,6 +111,8 @@ func main() {
 		start(ctx, streamChan, broadcastChan, lineLogChan)
 	}()
 
+	go watchNodes(ctx, occtlDocker.NewNodeClients(database.GetConnection()), outbox, broadcastChan)
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
@@ -126,3 +152,109 @@ func start(ctx context.Context, streamText <-chan string, broadcaster, lineLogCh
 		}
 	}
 }
+
+// nodeStream is the log stream of a registered node.
+type nodeStream struct {
+	client *occtlDocker.OcservOcctlDocker
+	cancel context.CancelFunc
+}
+
+// nodeStreams runs one stream per enabled registered node.
+type nodeStreams struct {
+	clients *occtlDocker.NodeClients
+	running map[string]nodeStream
+	start   func(ctx context.Context, node string, client *occtlDocker.OcservOcctlDocker)
+}
+
+// sync starts the streams of nodes not streamed yet, restarts the ones
+// whose URL or secret changed and stops the ones of nodes no longer
+// enabled.
+func (s *nodeStreams) sync(ctx context.Context, nodes []models.OcservNode) {
+	enabled := make(map[string]bool, len(nodes))
+	for i := range nodes {
+		name := nodes[i].Name
+		enabled[name] = true
+
+		// NodeClients keeps the client while the URL and secret stay the same
+		client := s.clients.For(&nodes[i])
+		stream, ok := s.running[name]
+		if ok && stream.client == client {
+			continue
+		}
+		if ok {
+			logger.Info("Restarting log stream of node %s", name)
+			stream.cancel()
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		s.running[name] = nodeStream{client: client, cancel: cancel}
+		go s.start(streamCtx, name, client)
+	}
+
+	for name, stream := range s.running {
+		if !enabled[name] {
+			logger.Info("Stopping log stream of node %s", name)
+			stream.cancel()
+			delete(s.running, name)
+		}
+	}
+}
+
+// watchNodes streams the log of every enabled registered node, following
+// the registry every nodeRefreshInterval until ctx is cancelled.
+func watchNodes(ctx context.Context, clients *occtlDocker.NodeClients, outbox *occtlDocker.Outbox, broadcaster chan<- string) {
+	streams := &nodeStreams{
+		clients: clients,
+		running: make(map[string]nodeStream),
+		start: func(ctx context.Context, node string, client *occtlDocker.OcservOcctlDocker) {
+			streamNode(ctx, node, client, outbox, broadcaster)
+		},
+	}
+
+	ticker := time.NewTicker(nodeRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		nodes, err := clients.Enabled(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("Failed to load ocserv nodes: %v", err)
+			}
+		} else {
+			streams.sync(ctx, nodes)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamNode accounts the log the agent of node streams, reconnecting until
+// ctx is cancelled.
+func streamNode(ctx context.Context, node string, client *occtlDocker.OcservOcctlDocker, outbox *occtlDocker.Outbox, broadcaster chan<- string) {
+	logger.Info("Streaming logs of node %s", node)
+
+	lineLogChan := make(chan string, 1000)
+	statService := stats.NewNodeStatService(ctx, lineLogChan, node, client, outbox)
+	go func() {
+		statService.CalculateUserStats()
+	}()
+
+	for {
+		lines, err := client.SubscribeLogs(ctx)
+		if err != nil {
+			logger.Error("Node %s Stream Logs Error: %v", node, err)
+		} else {
+			start(ctx, lines, broadcaster, lineLogChan)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(nodeRetryDelay):
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+)
+
+func TestNodeStreamsSync(t *testing.T) {
+	var mu sync.Mutex
+	started := make(map[string]int)
+	contexts := make(map[string]context.Context)
+
+	streams := &nodeStreams{
+		clients: occtlDocker.NewNodeClients(nil),
+		running: make(map[string]nodeStream),
+		start: func(ctx context.Context, node string, _ *occtlDocker.OcservOcctlDocker) {
+			mu.Lock()
+			defer mu.Unlock()
+			started[node]++
+			contexts[node] = ctx
+		},
+	}
+	startedCount := func(node string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return started[node]
+	}
+	streamContext := func(node string) context.Context {
+		mu.Lock()
+		defer mu.Unlock()
+		return contexts[node]
+	}
+	waitStarted := func(node string, n int) {
+		t.Helper()
+		for i := 0; i < 1000 && startedCount(node) < n; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		if got := startedCount(node); got != n {
+			t.Fatalf("stream of %s started %d times, want %d", node, got, n)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	edge1 := models.OcservNode{Name: "edge-1", URL: "https://edge-1:8888", Secret: "a"}
+	edge2 := models.OcservNode{Name: "edge-2", URL: "https://edge-2:8888", Secret: "b"}
+
+	streams.sync(ctx, []models.OcservNode{edge1})
+	waitStarted("edge-1", 1)
+
+	// a new node is streamed, a known one is left running
+	streams.sync(ctx, []models.OcservNode{edge1, edge2})
+	waitStarted("edge-2", 1)
+	waitStarted("edge-1", 1)
+	first := streamContext("edge-1")
+
+	// a changed secret restarts the stream
+	edge1.Secret = "rotated"
+	streams.sync(ctx, []models.OcservNode{edge1, edge2})
+	waitStarted("edge-1", 2)
+	if first.Err() == nil {
+		t.Error("stream with the previous secret still running")
+	}
+
+	// a node no longer enabled is stopped
+	streams.sync(ctx, []models.OcservNode{edge1})
+	if streamContext("edge-2").Err() == nil {
+		t.Error("stream of the removed node still running")
+	}
+	if _, ok := streams.running["edge-2"]; ok {
+		t.Error("removed node still tracked")
+	}
+	if streamContext("edge-1").Err() != nil {
+		t.Error("stream of the remaining node stopped")
+	}
+}
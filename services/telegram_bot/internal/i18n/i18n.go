@@ -68,6 +68,9 @@ const (
 	OcservDeactivated Key = "ocserv_deactivated"
 	RateLimited       Key = "rate_limited"
 
+	CertificateExpiring Key = "certificate_expiring"
+	CertificateRenewed  Key = "certificate_renewed"
+
 	AdminWelcome     Key = "admin_welcome"
 	AdminMenu        Key = "admin_menu"
 	BtnAdminPending  Key = "btn_admin_pending"
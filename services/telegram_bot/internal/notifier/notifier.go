@@ -2,6 +2,9 @@ package notifier
 
 import (
 	"context"
+	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"github.com/mmtaee/ocserv-dashboard/common/models"
@@ -12,7 +15,12 @@ import (
 
 const (
 	checkInterval    = 30 * time.Minute
+	alertInterval    = time.Minute
+	alertMaxAge      = time.Hour
+	alertBatch       = 20
 	notifyCooldown   = 24 * time.Hour
+	certWarnBefore   = 14 * 24 * time.Hour
+	certCooldown     = 7 * 24 * time.Hour
 	bytesPerMegabyte = 1024 * 1024
 	bytesPerGigabyte = 1024 * 1024 * 1024
 )
@@ -36,23 +44,72 @@ func New(sender Sender, repo *repository.Repository) *Notifier {
 	}
 }
 
-// Run performs an initial scan and then a periodic scan every checkInterval.
-// Returns when ctx is cancelled.
+// Run performs an initial scan and then a periodic scan every checkInterval,
+// and forwards failed login alerts every alertInterval. Returns when ctx is
+// cancelled.
 func (n *Notifier) Run(ctx context.Context) {
 	tick := time.NewTicker(checkInterval)
 	defer tick.Stop()
+	alertTick := time.NewTicker(alertInterval)
+	defer alertTick.Stop()
 
 	n.scan(ctx)
+	n.forwardAuthAlerts(ctx)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-tick.C:
 			n.scan(ctx)
+		case <-alertTick.C:
+			n.forwardAuthAlerts(ctx)
 		}
 	}
 }
 
+// forwardAuthAlerts sends the failed login alerts raised by log_stream to
+// the admin chat.
+func (n *Notifier) forwardAuthAlerts(ctx context.Context) {
+	settings, err := n.repo.Settings(ctx)
+	if err != nil || !settings.Enabled || settings.AdminChatID == 0 {
+		return
+	}
+
+	now := time.Now()
+	alerts, err := n.repo.PendingAuthAlerts(ctx, now.Add(-alertMaxAge), alertBatch)
+	if err != nil {
+		logger.Warn("telegram_bot: notifier list auth alerts: %v", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		if err = n.sender.Send(settings.AdminChatID, formatAuthAlert(alert)); err != nil {
+			logger.Warn("telegram_bot: notifier send auth alert failed: %v", err)
+			return
+		}
+		if err = n.repo.MarkAuthAlertNotified(ctx, alert.ID, now); err != nil {
+			logger.Warn("telegram_bot: notifier mark auth alert notified: %v", err)
+		}
+	}
+}
+
+func formatAuthAlert(alert models.OcservAuthAlert) string {
+	label := "User"
+	if alert.Kind == models.AuthAlertKindIP {
+		label = "IP"
+	}
+
+	actions := "none"
+	if alert.Actions != nil && len(*alert.Actions) > 0 {
+		actions = strings.Join(*alert.Actions, ", ")
+	}
+
+	return "🚨 <b>Repeated failed VPN logins</b>\n" +
+		"<b>" + label + ":</b> <code>" + html.EscapeString(alert.Target) + "</code>\n" +
+		fmt.Sprintf("<b>Failures:</b> %d in %s\n", alert.Failures, time.Duration(alert.WindowSeconds)*time.Second) +
+		"<b>Actions:</b> " + actions
+}
+
 func (n *Notifier) scan(ctx context.Context) {
 	settings, err := n.repo.Settings(ctx)
 	if err != nil || !settings.Enabled {
@@ -75,38 +132,81 @@ func (n *Notifier) scan(ctx context.Context) {
 		if user.IsLocked || user.DeactivatedAt != nil {
 			continue
 		}
-		if user.TrafficType == models.Free {
-			continue
-		}
 
-		quotaBytes := int64(user.TrafficSize)
-		var usedBytes int64
-		switch user.TrafficType {
-		case models.MonthlyTransmit, models.TotallyTransmit:
-			usedBytes = int64(user.Tx)
-		case models.MonthlyReceive, models.TotallyReceive:
-			usedBytes = int64(user.Rx)
-		case models.MonthlyRxTx, models.TotallyRxTx:
-			usedBytes = int64(user.Rx) + int64(user.Tx)
-		default:
-			continue
-		}
-		remaining := quotaBytes - usedBytes
-		if remaining <= 0 || remaining >= thresholdBytes {
-			continue
-		}
-		if account.LastLowQuotaNotifiedAt != nil && now.Sub(*account.LastLowQuotaNotifiedAt) < notifyCooldown {
-			continue
-		}
+		n.checkCertificate(ctx, account, user, now)
+		n.checkLowQuota(ctx, account, user, thresholdBytes, now)
+	}
+}
 
-		remainingMB := int(remaining / bytesPerMegabyte)
-		text := i18n.T(account.Language, i18n.LowQuotaWarning, user.Username, remainingMB)
-		if err := n.sender.Send(account.ChatID, text); err != nil {
-			logger.Warn("telegram_bot: notifier send failed: %v", err)
-			continue
-		}
-		if err := n.repo.MarkLowQuotaNotified(ctx, account.ID, now); err != nil {
-			logger.Warn("telegram_bot: notifier mark notified: %v", err)
+func (n *Notifier) checkLowQuota(ctx context.Context, account models.TelegramAccount, user *models.OcservUser, thresholdBytes int64, now time.Time) {
+	if user.TrafficType == models.Free {
+		return
+	}
+
+	quotaBytes := int64(user.TrafficSize)
+	var usedBytes int64
+	switch user.TrafficType {
+	case models.MonthlyTransmit, models.TotallyTransmit:
+		usedBytes = int64(user.Tx)
+	case models.MonthlyReceive, models.TotallyReceive:
+		usedBytes = int64(user.Rx)
+	case models.MonthlyRxTx, models.TotallyRxTx:
+		usedBytes = int64(user.Rx) + int64(user.Tx)
+	default:
+		return
+	}
+	remaining := quotaBytes - usedBytes
+	if remaining <= 0 || remaining >= thresholdBytes {
+		return
+	}
+	if account.LastLowQuotaNotifiedAt != nil && now.Sub(*account.LastLowQuotaNotifiedAt) < notifyCooldown {
+		return
+	}
+
+	remainingMB := int(remaining / bytesPerMegabyte)
+	text := i18n.T(account.Language, i18n.LowQuotaWarning, user.Username, remainingMB)
+	if err := n.sender.Send(account.ChatID, text); err != nil {
+		logger.Warn("telegram_bot: notifier send failed: %v", err)
+		return
+	}
+	if err := n.repo.MarkLowQuotaNotified(ctx, account.ID, now); err != nil {
+		logger.Warn("telegram_bot: notifier mark notified: %v", err)
+	}
+}
+
+// checkCertificate tells the customer their certificate was renewed, when
+// the user expiry moved past the one they know about, or warns them once a
+// week while it expires within certWarnBefore.
+func (n *Notifier) checkCertificate(ctx context.Context, account models.TelegramAccount, user *models.OcservUser, now time.Time) {
+	expiresAt := user.CertificateExpiresAt
+	if expiresAt == nil {
+		return
+	}
+
+	var key i18n.Key
+	switch {
+	case account.CertificateExpiresAt != nil && expiresAt.After(*account.CertificateExpiresAt):
+		key = i18n.CertificateRenewed
+	case expiresAt.Sub(now) < certWarnBefore &&
+		(account.LastCertificateNotifiedAt == nil || now.Sub(*account.LastCertificateNotifiedAt) >= certCooldown):
+		key = i18n.CertificateExpiring
+	}
+
+	if key == "" {
+		if account.CertificateExpiresAt == nil {
+			if err := n.repo.MarkCertificateSeen(ctx, account.ID, *expiresAt, nil); err != nil {
+				logger.Warn("telegram_bot: notifier mark certificate seen: %v", err)
+			}
 		}
+		return
+	}
+
+	text := i18n.T(account.Language, key, user.Username, expiresAt.Format("2006-01-02"))
+	if err := n.sender.Send(account.ChatID, text); err != nil {
+		logger.Warn("telegram_bot: notifier send failed: %v", err)
+		return
+	}
+	if err := n.repo.MarkCertificateSeen(ctx, account.ID, *expiresAt, &now); err != nil {
+		logger.Warn("telegram_bot: notifier mark certificate notified: %v", err)
 	}
 }
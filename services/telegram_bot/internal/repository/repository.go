@@ -133,6 +133,42 @@ func (r *Repository) MarkLowQuotaNotified(ctx context.Context, id uint, at time.
 		Update("last_low_quota_notified_at", at).Error
 }
 
+// MarkCertificateSeen records the certificate expiry the customer knows
+// about, and when they were last notified when notifiedAt is set.
+func (r *Repository) MarkCertificateSeen(ctx context.Context, id uint, expiresAt time.Time, notifiedAt *time.Time) error {
+	updates := map[string]interface{}{"certificate_expires_at": expiresAt}
+	if notifiedAt != nil {
+		updates["last_certificate_notified_at"] = *notifiedAt
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.TelegramAccount{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// =============================================================================
+// Auth alerts
+// =============================================================================
+
+// PendingAuthAlerts returns the failed login alerts to forward to the admin
+// chat, oldest first. Alerts older than since are skipped as stale.
+func (r *Repository) PendingAuthAlerts(ctx context.Context, since time.Time, limit int) ([]models.OcservAuthAlert, error) {
+	var alerts []models.OcservAuthAlert
+	err := r.db.WithContext(ctx).
+		Where("notify AND notified_at IS NULL AND created_at >= ?", since).
+		Order("id ASC").
+		Limit(limit).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *Repository) MarkAuthAlertNotified(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OcservAuthAlert{}).
+		Where("id = ?", id).
+		Update("notified_at", at).Error
+}
+
 // =============================================================================
 // Ocserv users (read-only convenience)
 // =============================================================================
@@ -4,8 +4,6 @@ import (
 	"context"
 	commonModels "github.com/mmtaee/ocserv-dashboard/common/models"
 	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
-	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
-	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/database"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
 	"github.com/mmtaee/ocserv-dashboard/user_expiry/internal/models"
@@ -21,27 +19,24 @@ import (
 //
 // It supports both docker-mode and native ocserv mode.
 type CornService struct {
-	occtlHandler      occtl.OcservOcctlInterface
-	ocservUserHandler user.OcservUserInterface
-	occtlDockerRepo   occtlDocker.OcservOcctlUsersDocker
-	dockerMode        bool
+	outbox *occtlDocker.Outbox
 }
 
 // NewCornService initializes cron service.
-// If dockerMode is true, docker-based occtl commands will be used.
-// Otherwise, native ocserv handlers are used.
+// ocserv actions are queued in the ocserv_actions outbox, which delivers
+// them to the node each user is provisioned on. If dockerMode is true,
+// actions of local users go through the docker webhook, otherwise to the
+// native ocserv handlers.
 func NewCornService(dockerMode bool) *CornService {
-	s := &CornService{
-		dockerMode: dockerMode,
-	}
-
+	var client occtlDocker.OutboxClient
 	if dockerMode {
-		s.occtlDockerRepo = occtlDocker.NewOcservOcctlDocker()
+		client = occtlDocker.NewOcservOcctlDocker()
 	} else {
-		s.occtlHandler = occtl.NewOcservOcctl()
-		s.ocservUserHandler = user.NewOcservUser()
+		client = occtlDocker.NewLocalOutboxClient()
+	}
+	return &CornService{
+		outbox: occtlDocker.NewOutbox(database.GetConnection(), client),
 	}
-	return s
 }
 
 // MissedCron checks whether daily or monthly cron jobs were missed
@@ -98,6 +93,9 @@ func (c *CornService) MissedCron() {
 // Monthly (1st & 2nd day at 00:01:00):
 //   - ActiveMonthlyUsers
 //
+// Every minute:
+//   - UnlockAuthLockedUsers
+//
 // The cron stops when context is canceled.
 func (c *CornService) UserExpiryCron(ctx context.Context) {
 	cronJob := cron.New(cron.WithSeconds())
@@ -148,6 +146,14 @@ func (c *CornService) UserExpiryCron(ctx context.Context) {
 	}
 	logger.Info("Running delete expired users cron...")
 
+	// Every minute — lift temporary failed login locks
+	_, err = cronJob.AddFunc("0 * * * * *", func() {
+		c.UnlockAuthLockedUsers(ctx, db)
+	})
+	if err != nil {
+		logger.Fatal("Failed to add cron job: %v", err)
+	}
+
 	//// Test: run every minute at second 0
 	//_, err = cronJob.AddFunc("0 * * * * *", func() {
 	//	c.DeleteExpiredUsers(ctx, db)
@@ -205,26 +211,8 @@ func (c *CornService) ExpireUsers(ctx context.Context, db *gorm.DB) {
 				return
 			}
 
-			var (
-				disconnect func(string) (string, error)
-				lock       func(string) (string, error)
-			)
-
-			if c.dockerMode {
-				disconnect = c.occtlDockerRepo.DisconnectUser
-				lock = c.occtlDockerRepo.Lock
-			} else {
-				disconnect = c.occtlHandler.DisconnectUser
-				lock = c.ocservUserHandler.Lock
-			}
-
-			if _, err3 := disconnect(u.Username); err3 != nil {
-				logger.Error("Failed to disconnect user %s: %v", u.Username, err3)
-			}
-			if _, err4 := lock(u.Username); err4 != nil {
-				logger.Error("Failed to lock user %s: %v", u.Username, err4)
-			}
-			return
+			c.enqueue(ctx, commonModels.OcservActionDisconnect, u.Username)
+			c.enqueue(ctx, commonModels.OcservActionLock, u.Username)
 		}(u)
 	}
 
@@ -287,23 +275,56 @@ func (c *CornService) ActiveMonthlyUsers(ctx context.Context, db *gorm.DB) {
 				return
 			}
 
-			var unlock func(string) (string, error)
-
-			if c.dockerMode {
-				unlock = c.occtlDockerRepo.Unlock
-			} else {
-				unlock = c.ocservUserHandler.UnLock
-			}
-			if _, err2 := unlock(u.Username); err2 != nil {
-				logger.Error("Failed to unlock user %s: %v", u.Username, err2)
-			}
-
+			c.enqueue(ctx, commonModels.OcservActionUnlock, u.Username)
 		}(u)
 	}
 
 	wg.Wait()
 }
 
+// enqueue queues an ocserv action. Delivery is retried by RunOutbox when
+// ocserv or the node of the user is unreachable.
+func (c *CornService) enqueue(ctx context.Context, action, username string) {
+	if err := c.outbox.Enqueue(ctx, action, username); err != nil {
+		logger.Error("Failed to queue %s for user %s: %v", action, username, err)
+	}
+}
+
+// RunOutbox retries queued ocserv actions and repairs ocpasswd lock drift
+// until ctx is cancelled.
+func (c *CornService) RunOutbox(ctx context.Context) {
+	logger.Info("Running ocserv actions outbox...")
+	c.outbox.Run(ctx, 15*time.Second, 10*time.Minute)
+}
+
+// UnlockAuthLockedUsers lifts the temporary locks log_stream sets after
+// repeated failed logins, once auth_locked_until has passed. Users
+// deactivated in the meantime, e.g. expired, stay locked.
+func (c *CornService) UnlockAuthLockedUsers(ctx context.Context, db *gorm.DB) {
+	var users []commonModels.OcservUser
+
+	err := db.WithContext(ctx).Raw(`
+		UPDATE ocserv_users
+		SET is_locked = CASE WHEN deactivated_at IS NULL THEN false ELSE is_locked END,
+			auth_locked_until = NULL
+		WHERE auth_locked_until IS NOT NULL AND auth_locked_until <= ?
+		RETURNING username, is_locked
+	`, time.Now()).Scan(&users).Error
+	if err != nil {
+		logger.Error("Failed to unlock temporarily locked users: %v", err)
+		return
+	}
+
+	for _, u := range users {
+		if u.IsLocked {
+			continue
+		}
+
+		logger.Info("Lifting failed login lock of user %s", u.Username)
+		c.enqueue(ctx, commonModels.OcservActionUnlock, u.Username)
+	}
+}
+
 // DeleteExpiredUsers permanently deletes users who:
 //
 //   - Are deactivated
@@ -39,6 +39,8 @@ func main() {
 		cronService.UserExpiryCron(ctx)
 	}()
 
+	go cronService.RunOutbox(ctx)
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
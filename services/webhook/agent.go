@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mmtaee/ocserv-dashboard/common/models"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+)
+
+// nodeName is the name this node is registered with in the dashboard,
+// AGENT_NODE_NAME or the hostname.
+func nodeName() string {
+	if name := os.Getenv("AGENT_NODE_NAME"); name != "" {
+		return name
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// agentInfo reports the node, its ocserv release and status. The status is
+// left out when occtl cannot reach ocserv.
+func agentInfo() *models.AgentInfo {
+	hostname, _ := os.Hostname()
+	info := &models.AgentInfo{
+		Node:         nodeName(),
+		Hostname:     hostname,
+		Version:      occtlHandler.Version(),
+		Capabilities: occtlHandler.Capabilities(),
+		Time:         time.Now(),
+	}
+
+	status, err := occtlHandler.ShowStatus()
+	if err != nil {
+		logger.Warn("Failed to get ocserv status: %v", err)
+		return info
+	}
+	info.Status = status
+	return info
+}
+
+// logCommand follows the ocserv log of this host: the file at
+// AGENT_LOG_FILE, written by the docker image, or the systemd journal of
+// the ocserv unit.
+func logCommand(r *http.Request) *exec.Cmd {
+	if path := os.Getenv("AGENT_LOG_FILE"); path != "" {
+		return exec.CommandContext(r.Context(), "tail", "-n", "0", "-F", path)
+	}
+	return exec.CommandContext(r.Context(), "journalctl", "-n", "0", "-fu", "ocserv", "--output=short")
+}
+
+// logsHandler serves POST /webhook/logs, streaming the ocserv log lines,
+// starting at "ocserv[pid]:" like the lines log_stream reads locally,
+// until the client goes away.
+func logsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	cmd := logCommand(r)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		http.Error(w, "Failed to read ocserv log: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err = cmd.Start(); err != nil {
+		http.Error(w, "Failed to read ocserv log: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer cmd.Wait()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		i := strings.Index(line, "ocserv[")
+		if i < 0 {
+			continue
+		}
+		if _, err = fmt.Fprintln(w, strings.TrimSpace(line[i:])); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -8,8 +8,10 @@ require (
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/oklog/ulid/v2 v2.1.1 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 	gorm.io/gorm v1.30.1 // indirect
+	software.sslmate.com/src/go-pkcs12 v0.5.0 // indirect
 )
 
 replace github.com/mmtaee/ocserv-dashboard/common => ./../common
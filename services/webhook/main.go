@@ -1,14 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/firewall"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/group"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/occtl"
 	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
 	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -23,30 +27,62 @@ type Webhook struct {
 }
 
 var (
-	occtlHandler      occtl.OcservOcctlInterface
-	ocservUserHandler user.OcservUserInterface
+	occtlHandler       occtl.OcservOcctlInterface
+	ocservUserHandler  user.OcservUserInterface
+	ocservGroupHandler group.OcservGroupInterface
+	firewallHandler    firewall.Firewall
+	verifier           *occtlDocker.Verifier
 )
 
+// maxBodySize bounds signed request bodies, certificate backups included.
+const maxBodySize = 1 << 20
+
 func init() {
-	occtlHandler = occtl.NewOcservOcctl()
+	occtlHandler = occtl.NewOcservOcctlClient()
 	ocservUserHandler = user.NewOcservUser()
+	ocservGroupHandler = group.NewOcservGroup()
+	firewallHandler = firewall.NewIptables()
 }
 
 func main() {
+	// without a secret every request is refused, exiting would only make
+	// the container restart forever on installs upgraded without one
+	if secret := os.Getenv("WEBHOOK_SECRET"); secret != "" {
+		verifier = occtlDocker.NewVerifier(secret, occtlDocker.DefaultSignatureWindow)
+	} else {
+		logger.Error("WEBHOOK_SECRET is not set, refusing every webhook request. Run ./scripts/update.sh or set it in .env")
+	}
+
+	tlsConfig, err := occtlDocker.TLSConfigFromEnv(true)
+	if err != nil {
+		logger.Fatal("Failed to load webhook TLS configuration: %v", err)
+	}
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("/webhook/", webhookHandler)
+	mux.HandleFunc("/webhook/", authenticate(webhookHandler))
+	mux.HandleFunc("/webhook/rpc/", authenticate(rpcHandler))
+	mux.HandleFunc("/webhook/events", authenticate(eventsHandler))
+	mux.HandleFunc("/webhook/logs", authenticate(logsHandler))
 
 	server := &http.Server{
-		Addr:    "0.0.0.0:8888",
-		Handler: mux,
+		Addr:      "0.0.0.0:8888",
+		Handler:   mux,
+		TLSConfig: tlsConfig,
 	}
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		logger.Info("Webhook server listening on: %s ", server.Addr)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Info("Webhook server of node %s listening on: %s (mTLS: %t)", nodeName(), server.Addr, tlsConfig != nil)
+		var err error
+		if tlsConfig != nil {
+			// certificates are already loaded into TLSConfig
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Fatal("Failed to start webhook server: %v", err)
 		}
 	}()
@@ -64,6 +100,33 @@ func main() {
 	logger.Info("Webhook server shutdown successfully")
 }
 
+// authenticate verifies the HMAC signature, timestamp and nonce set by
+// occtl_docker.Sign before handing the request, with its body restored,
+// to next. Without WEBHOOK_SECRET every request is refused.
+func authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if verifier == nil {
+			http.Error(w, "WEBHOOK_SECRET is not configured", http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			http.Error(w, "Invalid payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err = verifier.Verify(r, body); err != nil {
+			logger.Warn("Rejected webhook request from %s: %v", r.RemoteAddr, err)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(w, r)
+	}
+}
+
 // webhookHandler extracts action from path and handles requests
 func webhookHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+	"github.com/mmtaee/ocserv-dashboard/common/pkg/logger"
+)
+
+type rpcMethod func(r *http.Request, p *occtlDocker.RPCParams) (interface{}, error)
+
+var errUsernameRequired = errors.New("username is required")
+
+// configUsername checks a username naming a per-user config file.
+func configUsername(username string) error {
+	if username == "" {
+		return errUsernameRequired
+	}
+	if filepath.Base(username) != username || username == "." || username == ".." {
+		return fmt.Errorf("invalid username: %s", username)
+	}
+	return nil
+}
+
+// certificateUsername checks a username naming a certificate directory.
+func certificateUsername(username string) error {
+	if username == "" {
+		return errUsernameRequired
+	}
+	if !user.ValidCertificateUsername(username) {
+		return fmt.Errorf("invalid username: %s", username)
+	}
+	return nil
+}
+
+// groupName checks the name of a group config file. The defaults group is
+// part of ocserv.conf and not synced.
+func groupName(name string) error {
+	if name == "" || name == "defaults" {
+		return errors.New("a group other than defaults is required")
+	}
+	if filepath.Base(name) != name || name == "." || name == ".." {
+		return fmt.Errorf("invalid group: %s", name)
+	}
+	return nil
+}
+
+// rpcMethods maps every occtl_docker RPC method to the local occtl and
+// ocpasswd implementations.
+var rpcMethods = map[string]rpcMethod{
+	occtlDocker.MethodOnlineSessions: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.OnlineSessions()
+	},
+	occtlDocker.MethodShowUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowUser(p.Username)
+	},
+	occtlDocker.MethodShowUserByID: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowUserByID(p.ID)
+	},
+	occtlDocker.MethodDisconnectUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.DisconnectUser(p.Username)
+	},
+	occtlDocker.MethodDisconnectSession: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.DisconnectSession(p.ID)
+	},
+	occtlDocker.MethodTerminateUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.TerminateUser(p.Username)
+	},
+	occtlDocker.MethodTerminateSession: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.TerminateSession(p.ID)
+	},
+	occtlDocker.MethodShowSession: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowSession(p.ID)
+	},
+	occtlDocker.MethodShowSessionAll: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowSessionAll()
+	},
+	occtlDocker.MethodShowSessionsValid: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowSessionsValid()
+	},
+	occtlDocker.MethodShowIPBans: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowIPBans()
+	},
+	occtlDocker.MethodUnbanIP: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.UnbanIP(p.IP)
+	},
+	occtlDocker.MethodShowStatus: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowStatus()
+	},
+	occtlDocker.MethodShowStatusRaw: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowStatusRaw()
+	},
+	occtlDocker.MethodReloadConfigs: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ReloadConfigs()
+	},
+	occtlDocker.MethodShowIRoutes: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowIRoutes()
+	},
+	occtlDocker.MethodShowEvent: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.ShowEvent(), nil
+	},
+	occtlDocker.MethodVersion: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.Version(), nil
+	},
+	occtlDocker.MethodCapabilities: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return occtlHandler.Capabilities(), nil
+	},
+	occtlDocker.MethodAgentInfo: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return agentInfo(), nil
+	},
+
+	occtlDocker.MethodCreateUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.Create(p.Group, p.Username, p.Password, p.Config)
+	},
+	occtlDocker.MethodApplyAuthMode: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.ApplyAuthMode(p.Group, p.Username, p.Password, p.AuthMode, p.Config)
+	},
+	occtlDocker.MethodLockUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.Lock(p.Username)
+	},
+	occtlDocker.MethodUnlockUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.UnLock(p.Username)
+	},
+	occtlDocker.MethodDeleteUser: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.Delete(p.Username)
+	},
+	occtlDocker.MethodSetGroup: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.SetGroup(p.Username, p.Group)
+	},
+	occtlDocker.MethodSyncConfig: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.SyncConfig(p.Username, p.Group, p.Config)
+	},
+	occtlDocker.MethodCreateConfig: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.CreateConfig(p.Username, p.Config)
+	},
+	occtlDocker.MethodDeleteConfig: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.DeleteConfig(p.Username)
+	},
+	occtlDocker.MethodCreateGroup: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := groupName(p.Group); err != nil {
+			return nil, err
+		}
+		if p.GroupConfig == nil {
+			return nil, errors.New("group config is required")
+		}
+		return nil, ocservGroupHandler.Create(p.Group, p.GroupConfig)
+	},
+	occtlDocker.MethodDeleteGroup: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := groupName(p.Group); err != nil {
+			return nil, err
+		}
+		return nil, ocservGroupHandler.Delete(p.Group)
+	},
+	occtlDocker.MethodSaveUserState: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.SaveState(p.Username)
+	},
+	occtlDocker.MethodRestoreUserState: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		if p.State == nil {
+			return nil, errors.New("state is required")
+		}
+		return nil, ocservUserHandler.RestoreState(p.Username, p.State)
+	},
+	occtlDocker.MethodRestoreConfig: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.RestoreConfig(p.Username)
+	},
+	occtlDocker.MethodConfigList: func(r *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return ocservUserHandler.ConfigList(r.Context())
+	},
+	occtlDocker.MethodEffectiveConfig: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := configUsername(p.Username); err != nil {
+			return nil, err
+		}
+		if p.Group != "" && p.Group != "defaults" {
+			if err := groupName(p.Group); err != nil {
+				return nil, err
+			}
+		}
+		return ocservUserHandler.EffectiveConfig(p.Username, p.Group, p.VHost)
+	},
+	occtlDocker.MethodOcpasswd: func(r *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		users, total, err := ocservUserHandler.Ocpasswd(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		return occtlDocker.OcpasswdResult{Users: users, Total: total}, nil
+	},
+	occtlDocker.MethodCreateCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.CreateCertificate(p.Username, p.Password)
+	},
+	occtlDocker.MethodRevokeCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.RevokeCertificate(p.Username)
+	},
+	occtlDocker.MethodRenewCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.RenewCertificate(p.Username, p.Password)
+	},
+	occtlDocker.MethodSuspendCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.SuspendCertificate(p.Username)
+	},
+	occtlDocker.MethodUnsuspendCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return nil, ocservUserHandler.UnsuspendCertificate(p.Username)
+	},
+	occtlDocker.MethodCertificateStatus: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		status := ocservUserHandler.CertificateStatus(p.Username)
+		return occtlDocker.CertificateStatusResult{Available: status.Available, Enabled: status.Enabled}, nil
+	},
+	occtlDocker.MethodCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.Certificate(p.Username)
+	},
+	occtlDocker.MethodCertificateNotAfter: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.CertificateNotAfter(p.Username)
+	},
+	occtlDocker.MethodCertificateBackup: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		return ocservUserHandler.CertificateBackup(p.Username)
+	},
+	occtlDocker.MethodRestoreCertificateBackup: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		if p.Certificate == nil {
+			return nil, errors.New("certificate is required")
+		}
+		return nil, ocservUserHandler.RestoreCertificateBackup(p.Username, p.Certificate)
+	},
+	occtlDocker.MethodCAStatus: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return ocservUserHandler.CAStatus()
+	},
+	occtlDocker.MethodRotateCA: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return nil, ocservUserHandler.RotateCA()
+	},
+	occtlDocker.MethodReissueCertificate: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		if err := certificateUsername(p.Username); err != nil {
+			return nil, err
+		}
+		reissued, err := ocservUserHandler.ReissueCertificate(p.Username, p.Password)
+		return occtlDocker.ReissueCertificateResult{Reissued: reissued}, err
+	},
+	occtlDocker.MethodRetireCA: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return nil, ocservUserHandler.RetireCA(p.Force)
+	},
+	occtlDocker.MethodCertificateInventory: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return ocservUserHandler.CertificateInventory()
+	},
+	occtlDocker.MethodCACertificate: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return ocservUserHandler.CACertificate()
+	},
+	occtlDocker.MethodCRL: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return ocservUserHandler.CRL()
+	},
+
+	occtlDocker.MethodFirewallBan: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return nil, firewallHandler.Ban(p.IP)
+	},
+	occtlDocker.MethodFirewallUnban: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return nil, firewallHandler.Unban(p.IP)
+	},
+	occtlDocker.MethodFirewallBans: func(_ *http.Request, _ *occtlDocker.RPCParams) (interface{}, error) {
+		return firewallHandler.Bans()
+	},
+	occtlDocker.MethodFirewallSync: func(_ *http.Request, p *occtlDocker.RPCParams) (interface{}, error) {
+		return nil, firewallHandler.Sync(p.CIDRs)
+	},
+}
+
+// rpcHandler serves POST /webhook/rpc/<method>
+func rpcHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(r.URL.Path, "/webhook/rpc/"))
+	method, ok := rpcMethods[name]
+	if !ok {
+		writeRPC(w, http.StatusNotFound, nil, errors.New("unknown method: "+name))
+		return
+	}
+
+	params := occtlDocker.RPCParams{}
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeRPC(w, http.StatusBadRequest, nil, errors.New("invalid payload: "+err.Error()))
+		return
+	}
+
+	logger.Info("Received webhook rpc: %s", name)
+
+	result, err := method(r, &params)
+	if err != nil {
+		logger.Error("Webhook rpc %s failed: %v", name, err)
+		writeRPC(w, http.StatusBadRequest, nil, err)
+		return
+	}
+	writeRPC(w, http.StatusOK, result, nil)
+}
+
+func writeRPC(w http.ResponseWriter, status int, result interface{}, err error) {
+	resp := occtlDocker.RPCResponse{}
+	if err != nil {
+		resp.Error = err.Error()
+	} else if result != nil {
+		data, mErr := json.Marshal(result)
+		if mErr != nil {
+			status = http.StatusInternalServerError
+			resp.Error = "marshal result: " + mErr.Error()
+		} else {
+			resp.Result = data
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// eventsHandler serves POST /webhook/events, streaming occtl events as one
+// JSON object per line until the client goes away.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, err := occtlHandler.SubscribeEvents(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to subscribe to events: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	for event := range events {
+		if err = encoder.Encode(event); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
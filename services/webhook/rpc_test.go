@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	occtlDocker "github.com/mmtaee/ocserv-dashboard/common/occtl_docker"
+	"github.com/mmtaee/ocserv-dashboard/common/ocserv/user"
+)
+
+// refusingUserHandler panics on any call: a rejected username must never
+// reach the ocpasswd and file handlers.
+type refusingUserHandler struct {
+	user.OcservUserInterface
+}
+
+func TestRPCRejectsUsernamesOutsideTheirDirectory(t *testing.T) {
+	saved := ocservUserHandler
+	t.Cleanup(func() { ocservUserHandler = saved })
+	ocservUserHandler = refusingUserHandler{}
+
+	methods := []string{
+		occtlDocker.MethodCreateUser,
+		occtlDocker.MethodApplyAuthMode,
+		occtlDocker.MethodLockUser,
+		occtlDocker.MethodUnlockUser,
+		occtlDocker.MethodDeleteUser,
+		occtlDocker.MethodSetGroup,
+		occtlDocker.MethodSyncConfig,
+		occtlDocker.MethodCreateConfig,
+		occtlDocker.MethodDeleteConfig,
+		occtlDocker.MethodRestoreConfig,
+		occtlDocker.MethodEffectiveConfig,
+		occtlDocker.MethodSaveUserState,
+		occtlDocker.MethodRestoreUserState,
+		occtlDocker.MethodCreateCertificate,
+		occtlDocker.MethodRevokeCertificate,
+		occtlDocker.MethodRenewCertificate,
+		occtlDocker.MethodCertificate,
+		occtlDocker.MethodReissueCertificate,
+	}
+	for _, method := range methods {
+		for _, username := range []string{"../x", "a/b", ""} {
+			p := &occtlDocker.RPCParams{Username: username, Group: "staff", State: &user.UserState{}}
+			req := httptest.NewRequest(http.MethodPost, "/webhook/rpc/"+method, nil)
+
+			_, err := rpcMethods[method](req, p)
+			if err == nil {
+				t.Errorf("%s accepted username %q", method, username)
+				continue
+			}
+			if username != "" && !strings.Contains(err.Error(), "invalid username") {
+				t.Errorf("%s(%q): unexpected error %v", method, username, err)
+			}
+		}
+	}
+}
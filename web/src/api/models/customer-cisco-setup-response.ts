@@ -25,13 +25,19 @@ export interface CustomerCiscoSetupResponse {
      * @type {string}
      * @memberof CustomerCiscoSetupResponse
      */
-    'certificate_import_uri': string;
+    'auth_mode': CustomerCiscoSetupResponseAuthModeEnum;
     /**
      * 
      * @type {string}
      * @memberof CustomerCiscoSetupResponse
      */
-    'certificate_password': string;
+    'certificate_import_uri'?: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof CustomerCiscoSetupResponse
+     */
+    'certificate_password'?: string;
     /**
      * 
      * @type {string}
@@ -64,3 +70,11 @@ export interface CustomerCiscoSetupResponse {
     'server_port': number;
 }
 
+export const CustomerCiscoSetupResponseAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type CustomerCiscoSetupResponseAuthModeEnum = typeof CustomerCiscoSetupResponseAuthModeEnum[keyof typeof CustomerCiscoSetupResponseAuthModeEnum];
+
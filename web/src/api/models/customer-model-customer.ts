@@ -20,6 +20,12 @@
  * @interface CustomerModelCustomer
  */
 export interface CustomerModelCustomer {
+    /**
+     * 
+     * @type {string}
+     * @memberof CustomerModelCustomer
+     */
+    'auth_mode': CustomerModelCustomerAuthModeEnum;
     /**
      * 
      * @type {boolean}
@@ -100,4 +106,11 @@ export const CustomerModelCustomerTrafficTypeEnum = {
 
 export type CustomerModelCustomerTrafficTypeEnum = typeof CustomerModelCustomerTrafficTypeEnum[keyof typeof CustomerModelCustomerTrafficTypeEnum];
 
+export const CustomerModelCustomerAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type CustomerModelCustomerAuthModeEnum = typeof CustomerModelCustomerAuthModeEnum[keyof typeof CustomerModelCustomerAuthModeEnum];
 
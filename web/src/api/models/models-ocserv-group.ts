@@ -23,6 +23,12 @@ import type { ModelsOcservGroupConfig } from './models-ocserv-group-config';
  * @interface ModelsOcservGroup
  */
 export interface ModelsOcservGroup {
+    /**
+     * empty means both
+     * @type {string}
+     * @memberof ModelsOcservGroup
+     */
+    'auth_mode'?: ModelsOcservGroupAuthModeEnum;
     /**
      * 
      * @type {ModelsOcservGroupConfig}
@@ -49,3 +55,11 @@ export interface ModelsOcservGroup {
     'owner': string;
 }
 
+export const ModelsOcservGroupAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type ModelsOcservGroupAuthModeEnum = typeof ModelsOcservGroupAuthModeEnum[keyof typeof ModelsOcservGroupAuthModeEnum];
+
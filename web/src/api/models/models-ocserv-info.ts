@@ -23,6 +23,12 @@ import type { ModelsServerVersion } from './models-server-version';
  * @interface ModelsOcservInfo
  */
 export interface ModelsOcservInfo {
+    /**
+     * 
+     * @type {{ [key: string]: boolean; }}
+     * @memberof ModelsOcservInfo
+     */
+    'capabilities': { [key: string]: boolean; };
     /**
      * 
      * @type {string}
@@ -29,6 +29,18 @@ import type { ModelsOnlineUserSession } from './models-online-user-session';
  * @interface ModelsOcservUser
  */
 export interface ModelsOcservUser {
+    /**
+     * temporary lock after failed logins
+     * @type {string}
+     * @memberof ModelsOcservUser
+     */
+    'auth_locked_until'?: string;
+    /**
+     * empty inherits the group mode
+     * @type {string}
+     * @memberof ModelsOcservUser
+     */
+    'auth_mode'?: ModelsOcservUserAuthModeEnum;
     /**
      * 
      * @type {ModelsOcservUserCertificateBackup}
@@ -47,6 +59,12 @@ export interface ModelsOcservUser {
      * @memberof ModelsOcservUser
      */
     'certificate_enabled'?: boolean;
+    /**
+     * 
+     * @type {string}
+     * @memberof ModelsOcservUser
+     */
+    'certificate_expires_at'?: string;
     /**
      * 
      * @type {ModelsOcservUserConfig}
@@ -169,4 +187,11 @@ export const ModelsOcservUserTrafficTypeEnum = {
 
 export type ModelsOcservUserTrafficTypeEnum = typeof ModelsOcservUserTrafficTypeEnum[keyof typeof ModelsOcservUserTrafficTypeEnum];
 
+export const ModelsOcservUserAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type ModelsOcservUserAuthModeEnum = typeof ModelsOcservUserAuthModeEnum[keyof typeof ModelsOcservUserAuthModeEnum];
 
@@ -32,5 +32,11 @@ export interface ModelsServerVersion {
      * @memberof ModelsServerVersion
      */
     'ocserv_version'?: string;
+    /**
+     * Semantic is the parsed ocserv release (e.g. 1.2.4), empty when unknown
+     * @type {string}
+     * @memberof ModelsServerVersion
+     */
+    'semantic'?: string;
 }
 
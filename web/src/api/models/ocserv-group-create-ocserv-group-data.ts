@@ -23,6 +23,12 @@ import type { ModelsOcservGroupConfig } from './models-ocserv-group-config';
  * @interface OcservGroupCreateOcservGroupData
  */
 export interface OcservGroupCreateOcservGroupData {
+    /**
+     * 
+     * @type {string}
+     * @memberof OcservGroupCreateOcservGroupData
+     */
+    'auth_mode'?: OcservGroupCreateOcservGroupDataAuthModeEnum;
     /**
      * 
      * @type {ModelsOcservGroupConfig}
@@ -37,3 +43,11 @@ export interface OcservGroupCreateOcservGroupData {
     'name': string;
 }
 
+export const OcservGroupCreateOcservGroupDataAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type OcservGroupCreateOcservGroupDataAuthModeEnum = typeof OcservGroupCreateOcservGroupDataAuthModeEnum[keyof typeof OcservGroupCreateOcservGroupDataAuthModeEnum];
+
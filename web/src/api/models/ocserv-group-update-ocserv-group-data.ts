@@ -23,6 +23,12 @@ import type { ModelsOcservGroupConfig } from './models-ocserv-group-config';
  * @interface OcservGroupUpdateOcservGroupData
  */
 export interface OcservGroupUpdateOcservGroupData {
+    /**
+     * 
+     * @type {string}
+     * @memberof OcservGroupUpdateOcservGroupData
+     */
+    'auth_mode'?: OcservGroupUpdateOcservGroupDataAuthModeEnum;
     /**
      * 
      * @type {ModelsOcservGroupConfig}
@@ -31,3 +37,11 @@ export interface OcservGroupUpdateOcservGroupData {
     'config': ModelsOcservGroupConfig;
 }
 
+export const OcservGroupUpdateOcservGroupDataAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type OcservGroupUpdateOcservGroupDataAuthModeEnum = typeof OcservGroupUpdateOcservGroupDataAuthModeEnum[keyof typeof OcservGroupUpdateOcservGroupDataAuthModeEnum];
+
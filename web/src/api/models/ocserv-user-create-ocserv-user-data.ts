@@ -23,6 +23,12 @@ import type { ModelsOcservUserConfig } from './models-ocserv-user-config';
  * @interface OcservUserCreateOcservUserData
  */
 export interface OcservUserCreateOcservUserData {
+    /**
+     * 
+     * @type {string}
+     * @memberof OcservUserCreateOcservUserData
+     */
+    'auth_mode'?: OcservUserCreateOcservUserDataAuthModeEnum;
     /**
      * 
      * @type {ModelsOcservUserConfig}
@@ -91,4 +97,11 @@ export const OcservUserCreateOcservUserDataTrafficTypeEnum = {
 
 export type OcservUserCreateOcservUserDataTrafficTypeEnum = typeof OcservUserCreateOcservUserDataTrafficTypeEnum[keyof typeof OcservUserCreateOcservUserDataTrafficTypeEnum];
 
+export const OcservUserCreateOcservUserDataAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type OcservUserCreateOcservUserDataAuthModeEnum = typeof OcservUserCreateOcservUserDataAuthModeEnum[keyof typeof OcservUserCreateOcservUserDataAuthModeEnum];
 
@@ -23,6 +23,12 @@ import type { ModelsOcservUserConfig } from './models-ocserv-user-config';
  * @interface OcservUserUpdateOcservUserData
  */
 export interface OcservUserUpdateOcservUserData {
+    /**
+     * AuthMode set to an empty string inherits the group mode again.
+     * @type {string}
+     * @memberof OcservUserUpdateOcservUserData
+     */
+    'auth_mode'?: OcservUserUpdateOcservUserDataAuthModeEnum;
     /**
      * 
      * @type {ModelsOcservUserConfig}
@@ -85,4 +91,11 @@ export const OcservUserUpdateOcservUserDataTrafficTypeEnum = {
 
 export type OcservUserUpdateOcservUserDataTrafficTypeEnum = typeof OcservUserUpdateOcservUserDataTrafficTypeEnum[keyof typeof OcservUserUpdateOcservUserDataTrafficTypeEnum];
 
+export const OcservUserUpdateOcservUserDataAuthModeEnum = {
+    PASSWORD: 'password',
+    CERTIFICATE: 'certificate',
+    BOTH: 'both'
+} as const;
+
+export type OcservUserUpdateOcservUserDataAuthModeEnum = typeof OcservUserUpdateOcservUserDataAuthModeEnum[keyof typeof OcservUserUpdateOcservUserDataAuthModeEnum];
 
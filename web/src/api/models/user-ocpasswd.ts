@@ -26,6 +26,12 @@ export interface UserOcpasswd {
      * @memberof UserOcpasswd
      */
     'group'?: string;
+    /**
+     * 
+     * @type {boolean}
+     * @memberof UserOcpasswd
+     */
+    'locked'?: boolean;
     /**
      * 
      * @type {string}
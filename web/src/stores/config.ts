@@ -6,6 +6,7 @@ export const useServerStore = defineStore('server', {
     state: (): ServerState => ({
         OcservVersion: '',
         OcctlVersion: '',
+        Capabilities: {},
         Status: '',
         Release: {
             Current: '',
@@ -21,6 +22,7 @@ export const useServerStore = defineStore('server', {
                     if (res.data) {
                         this.OcservVersion = res.data.version.ocserv_version || '';
                         this.OcctlVersion = (res.data.version.occtl_version || '').replace(/\n/g, '<br />');
+                        this.Capabilities = res.data.capabilities || {};
                     }
                 })
                 .catch(() => {});
@@ -44,6 +46,8 @@ export const useServerStore = defineStore('server', {
         getOcservVersion: (state) => state.OcservVersion,
         getOcctlVersion: (state) => state.OcctlVersion,
         getStatus: (state) => state.Status,
+        // unknown capabilities are treated as supported until server info is loaded
+        supports: (state) => (capability: string) => state.Capabilities[capability] !== false,
         getDashboardRelease: (state) => state.Release
     }
 });
@@ -12,6 +12,7 @@ export interface Release {
 export interface ServerState {
     OcservVersion: string;
     OcctlVersion: string;
+    Capabilities: Record<string, boolean>;
     Status: string;
     Release: Release;
 }